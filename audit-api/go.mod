@@ -0,0 +1,19 @@
+module audit-api
+
+go 1.21
+
+require (
+	github.com/rabbitmq/amqp091-go v1.9.0
+	gorm.io/driver/mysql v1.5.2
+	gorm.io/gorm v1.25.5
+	shared v0.0.0
+)
+
+require (
+	github.com/go-sql-driver/mysql v1.7.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.0 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+)
+
+replace shared => ../shared
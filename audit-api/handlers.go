@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw"
+	"shared/rabbitmq"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// eventsResponse es la respuesta de GET /audit/events
+type eventsResponse struct {
+	Events []Record `json:"events"`
+	Total  int64    `json:"total"`
+	Page   int      `json:"page"`
+	Limit  int      `json:"limit"`
+}
+
+// parseFilter lee los filtros de la query
+// Ejemplo: ?service=users-api&action=login.failed&from=2026-01-01T00:00:00Z&page=2
+// impersonator_id lista todo lo que hizo un admin actuando como otros usuarios
+func parseFilter(query url.Values) (Filter, error) {
+	f := Filter{
+		Service: query.Get("service"),
+		Action:  query.Get("action"),
+		Outcome: query.Get("outcome"),
+		Page:    1,
+		Limit:   defaultLimit,
+	}
+
+	for key, target := range map[string]**uint{"actor_id": &f.ActorID, "impersonator_id": &f.ImpersonatorID} {
+		if value := query.Get(key); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return f, apperrors.BadRequest(key + " must be a number")
+			}
+			userID := uint(id)
+			*target = &userID
+		}
+	}
+
+	for key, target := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		if value := query.Get(key); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return f, apperrors.BadRequest(key + " must be an RFC 3339 date (2026-01-02T15:04:05Z)")
+			}
+			*target = t
+		}
+	}
+
+	if value := query.Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return f, apperrors.BadRequest("page must be a positive number")
+		}
+		f.Page = page
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return f, apperrors.BadRequest("limit must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+		f.Limit = limit
+	}
+	return f, nil
+}
+
+// eventsHandler maneja GET /audit/events (solo admins)
+func eventsHandler(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			httpmw.WriteError(w, r, err)
+			return
+		}
+
+		records, total, err := store.Query(r.Context(), filter)
+		if err != nil {
+			httpmw.WriteError(w, r, apperrors.Internal(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(eventsResponse{
+			Events: records,
+			Total:  total,
+			Page:   filter.Page,
+			Limit:  filter.Limit,
+		})
+	})
+}
+
+// errIncompleteEvent marca eventos sin ID o sin acción (van a la DLQ)
+var errIncompleteEvent = errors.New("audit event without id or action")
+
+// messageHandler guarda cada evento de spotly.audit
+// Un JSON inválido o incompleto va directo a la DLQ; un error de la base se reintenta
+func messageHandler(store *Store) rabbitmq.Handler {
+	return func(ctx context.Context, msg amqp.Delivery) error {
+		var event audit.Event
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			return rabbitmq.Permanent(err)
+		}
+		if event.ID == "" || event.Action == "" {
+			return rabbitmq.Permanent(errIncompleteEvent)
+		}
+		return store.Save(ctx, event)
+	}
+}
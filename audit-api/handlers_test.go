@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Test: filtros válidos y valores por defecto
+func TestParseFilter(t *testing.T) {
+	query, _ := url.ParseQuery("service=users-api&action=login.failed&actor_id=7&impersonator_id=1&from=2026-01-01T00:00:00Z&page=2")
+
+	f, err := parseFilter(query)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.Service != "users-api" || f.Action != "login.failed" || f.ActorID == nil || *f.ActorID != 7 {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+	if f.ImpersonatorID == nil || *f.ImpersonatorID != 1 {
+		t.Errorf("Expected impersonator 1, got %v", f.ImpersonatorID)
+	}
+	if !f.From.Equal(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)) || !f.To.IsZero() {
+		t.Errorf("Unexpected dates: from=%s to=%s", f.From, f.To)
+	}
+	if f.Page != 2 || f.Limit != defaultLimit {
+		t.Errorf("Expected page 2 with the default limit, got %d/%d", f.Page, f.Limit)
+	}
+}
+
+// Test: valores inválidos dan 400
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, raw := range []string{"actor_id=abc", "impersonator_id=-1", "from=yesterday", "page=0", "limit=1000"} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parseFilter(query); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+}
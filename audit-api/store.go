@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"shared/audit"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Record es un evento de auditoría guardado (tabla audit_events)
+// Los registros no se modifican nunca: solo se insertan
+type Record struct {
+	ID         uint            `gorm:"primaryKey" json:"-"`
+	EventID    string          `gorm:"size:32;uniqueIndex" json:"id"`
+	Service    string          `gorm:"size:64;index:idx_service_action" json:"service"`
+	Action     string          `gorm:"size:64;index:idx_service_action" json:"action"`
+	Outcome    string          `gorm:"size:16" json:"outcome"`
+	ActorID    *uint           `gorm:"index" json:"actor_id,omitempty"`
+	ActorName  string          `gorm:"size:255" json:"actor_name,omitempty"`
+	TargetType string          `gorm:"size:64" json:"target_type,omitempty"`
+	TargetID   string          `gorm:"size:255" json:"target_id,omitempty"`
+	IP         string          `gorm:"size:64" json:"ip,omitempty"`
+	RequestID  string          `gorm:"size:64" json:"request_id,omitempty"`
+	Metadata   json.RawMessage `gorm:"type:json" json:"metadata,omitempty"`
+	OccurredAt time.Time       `gorm:"index" json:"occurred_at"`
+	CreatedAt  time.Time       `json:"received_at"`
+
+	// Admin que hizo la request con un token de impersonación
+	ImpersonatorID *uint `gorm:"index" json:"impersonator_id,omitempty"`
+}
+
+// TableName fija el nombre de la tabla
+func (Record) TableName() string {
+	return "audit_events"
+}
+
+// Filter son los criterios de búsqueda de GET /audit/events
+// Los campos vacíos no filtran
+type Filter struct {
+	Service string
+	Action  string
+	Outcome string
+	ActorID *uint
+	From    time.Time
+	To      time.Time
+	Page    int
+	Limit   int
+
+	// Solo lo que hizo este admin impersonando a otros usuarios
+	ImpersonatorID *uint
+}
+
+// Store guarda y consulta los eventos en MySQL
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore crea el store sobre la conexión
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Save guarda el evento; si ya estaba (mensaje reentregado) no hace nada
+func (s *Store) Save(ctx context.Context, event audit.Event) error {
+	record := Record{
+		EventID:    event.ID,
+		Service:    event.Service,
+		Action:     event.Action,
+		Outcome:    event.Outcome,
+		ActorID:    event.ActorID,
+		ActorName:  event.ActorName,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IP:         event.IP,
+		RequestID:  event.RequestID,
+		OccurredAt: event.OccurredAt,
+
+		ImpersonatorID: event.ImpersonatorID,
+	}
+	if len(event.Metadata) > 0 {
+		metadata, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return err
+		}
+		record.Metadata = metadata
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error
+}
+
+// Query devuelve una página de eventos (los más nuevos primero) y el total
+func (s *Store) Query(ctx context.Context, f Filter) ([]Record, int64, error) {
+	query := s.db.WithContext(ctx).Model(&Record{})
+	if f.Service != "" {
+		query = query.Where("service = ?", f.Service)
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.Outcome != "" {
+		query = query.Where("outcome = ?", f.Outcome)
+	}
+	if f.ActorID != nil {
+		query = query.Where("actor_id = ?", *f.ActorID)
+	}
+	if f.ImpersonatorID != nil {
+		query = query.Where("impersonator_id = ?", *f.ImpersonatorID)
+	}
+	if !f.From.IsZero() {
+		query = query.Where("occurred_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		query = query.Where("occurred_at < ?", f.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	records := []Record{}
+	err := query.Order("occurred_at DESC, id DESC").
+		Offset((f.Page - 1) * f.Limit).
+		Limit(f.Limit).
+		Find(&records).Error
+	return records, total, err
+}
@@ -0,0 +1,7 @@
+-- Bases de datos extra (users_db la crea MYSQL_DATABASE)
+-- Solo se ejecuta la primera vez que se crea el volumen de MySQL
+CREATE DATABASE IF NOT EXISTS notifications_db;
+GRANT ALL PRIVILEGES ON notifications_db.* TO 'spotly_user'@'%';
+CREATE DATABASE IF NOT EXISTS audit_db;
+GRANT ALL PRIVILEGES ON audit_db.* TO 'spotly_user'@'%';
+FLUSH PRIVILEGES;
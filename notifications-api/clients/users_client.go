@@ -0,0 +1,71 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"notifications-api/domain"
+	"time"
+
+	"shared/httpmw"
+	"shared/requestid"
+)
+
+// ErrUserNotFound indica que users-api no conoce al usuario
+var ErrUserNotFound = errors.New("user not found")
+
+// UsersClient define las llamadas que hacemos a users-api
+type UsersClient interface {
+	GetPreferences(ctx context.Context, userID uint) (*domain.Preferences, error)
+}
+
+// usersClient es el cliente HTTP de users-api
+type usersClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewUsersClient crea un cliente para users-api (ej: "http://users-api:8080")
+// token es el INTERNAL_API_TOKEN de users-api: sus rutas internas lo piden
+func NewUsersClient(baseURL, token string) UsersClient {
+	return &usersClient{
+		baseURL: baseURL,
+		token:   token,
+		client: &http.Client{
+			Timeout:   5 * time.Second,
+			Transport: requestid.NewTransport(nil), // propaga el X-Request-ID del ctx
+		},
+	}
+}
+
+// GetPreferences hace GET /v1/users/:id/preferences (ruta interna)
+func (c *usersClient) GetPreferences(ctx context.Context, userID uint) (*domain.Preferences, error) {
+	url := fmt.Sprintf("%s/v1/users/%d/preferences", c.baseURL, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(httpmw.ServiceTokenHeader, c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("users-api returned status %d", resp.StatusCode)
+	}
+
+	var prefs domain.Preferences
+	if err := json.NewDecoder(resp.Body).Decode(&prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+
+	"shared/apperrors"
+	"shared/httpmw"
+	"shared/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError responde un error del servicio con el status que le corresponde
+// según su código (404 not_found, 409 conflict, 401 unauthorized, etc.)
+// Los errores internos se loguean con su causa y al cliente le llega un mensaje genérico
+// El request_id del cuerpo permite encontrar ese log a partir de la respuesta
+func respondError(c *gin.Context, err error) {
+	status, body := httpmw.ErrorResponse(c.Request, err)
+
+	if errors.Is(err, apperrors.ErrInternal) || !errors.As(err, new(*apperrors.Error)) {
+		requestid.Logf(c.Request.Context(), "❌ %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+
+	c.JSON(status, body)
+}
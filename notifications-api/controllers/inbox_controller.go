@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"notifications-api/dto"
+	"notifications-api/services"
+	"strconv"
+
+	"shared/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InboxController maneja los endpoints de la bandeja in-app
+// Todas las rutas usan el user_id del JWT (nunca uno de la URL)
+type InboxController struct {
+	service services.InboxService
+}
+
+// NewInboxController crea una nueva instancia del controlador
+func NewInboxController(service services.InboxService) *InboxController {
+	return &InboxController{service: service}
+}
+
+// List maneja GET /users/me/notifications?unread=true&page=1&size=20
+func (ctrl *InboxController) List(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	// 1. Leer paginación (con valores por defecto y un máximo)
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", "20"))
+	if err != nil || size < 1 || size > 100 {
+		size = 20
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	// 2. Llamar al servicio
+	response, err := ctrl.service.List(userID, unreadOnly, page, size)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UnreadCount maneja GET /users/me/notifications/unread-count
+// Es el endpoint liviano que el frontend consulta para la campanita
+func (ctrl *InboxController) UnreadCount(c *gin.Context) {
+	count, err := ctrl.service.UnreadCount(c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// MarkRead maneja PUT /users/me/notifications/:id/read
+func (ctrl *InboxController) MarkRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid notification ID"))
+		return
+	}
+
+	if err := ctrl.service.MarkRead(c.GetUint("user_id"), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Notification marked as read",
+	})
+}
+
+// MarkAllRead maneja PUT /users/me/notifications/read-all
+func (ctrl *InboxController) MarkAllRead(c *gin.Context) {
+	if err := ctrl.service.MarkAllRead(c.GetUint("user_id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "All notifications marked as read",
+	})
+}
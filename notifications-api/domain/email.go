@@ -0,0 +1,8 @@
+package domain
+
+// Email representa un email listo para enviar
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Event representa un evento de dominio publicado en RabbitMQ
+// La routing key del mensaje es igual al Type (ej: "user.created")
+type Event struct {
+	ID         string                 `json:"id,omitempty"` // Opcional, identifica el evento para deduplicar
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// String obtiene un campo de texto del payload del evento
+// Si el campo no existe o no es string, devuelve ""
+func (e Event) String(key string) string {
+	value, ok := e.Data[key].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// Uint obtiene un campo numérico del payload (JSON decodifica números como float64)
+// Si el campo no existe o no es un número, devuelve 0
+func (e Event) Uint(key string) uint {
+	value, ok := e.Data[key].(float64)
+	if !ok || value < 0 {
+		return 0
+	}
+	return uint(value)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// InboxNotification es una notificación guardada en la bandeja in-app del usuario
+// La muestra el frontend en el ícono de la campanita
+type InboxNotification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_inbox_user_read" json:"user_id"`
+	EventID   *string    `gorm:"size:64;uniqueIndex" json:"-"` // Evita duplicados cuando un evento se reintenta
+	Type      string     `gorm:"size:64;not null" json:"type"` // Tipo de evento (ej: "booking.confirmed")
+	Title     string     `gorm:"not null" json:"title"`
+	Body      string     `gorm:"type:text" json:"body"`
+	ReadAt    *time.Time `gorm:"index:idx_inbox_user_read" json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (InboxNotification) TableName() string {
+	return "inbox_notifications"
+}
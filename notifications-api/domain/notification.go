@@ -0,0 +1,35 @@
+package domain
+
+// Category agrupa notificaciones para que el usuario las active o desactive juntas
+type Category string
+
+const (
+	CategoryTransactional Category = "transactional" // Reservas, cuenta, seguridad
+	CategoryMarketing     Category = "marketing"     // Promociones y novedades
+)
+
+// Channel es por dónde sale la notificación
+type Channel string
+
+const (
+	ChannelEmail Channel = ""    // por defecto
+	ChannelSMS   Channel = "sms" // To es un teléfono E.164 y solo se usa el body del template
+)
+
+// Notification es lo que arma un handler a partir de un evento
+// El servicio después chequea preferencias, renderiza el template y envía
+type Notification struct {
+	UserID   uint   // 0 si el destinatario no es un usuario registrado
+	To       string // Email del destinatario (o teléfono si Channel es SMS)
+	Category Category
+	Locale   string // "" => locale por defecto
+	Template string // Nombre del template (ej: "user.created")
+	Data     map[string]interface{}
+
+	// Credential indica que el email lleva una credencial que el usuario
+	// acaba de pedir (ej: magic link): no se guarda en la bandeja y se manda
+	// sin mirar las preferencias
+	Credential bool
+
+	Channel Channel
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Preferences son las preferencias de notificación que devuelve users-api
+type Preferences struct {
+	UserID             uint `json:"user_id"`
+	EmailTransactional bool `json:"email_transactional"`
+	EmailMarketing     bool `json:"email_marketing"`
+}
+
+// Allows indica si el usuario acepta emails de una categoría
+func (p Preferences) Allows(category Category) bool {
+	switch category {
+	case CategoryTransactional:
+		return p.EmailTransactional
+	case CategoryMarketing:
+		return p.EmailMarketing
+	}
+	return false
+}
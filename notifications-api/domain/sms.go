@@ -0,0 +1,7 @@
+package domain
+
+// SMS representa un mensaje de texto listo para enviar
+type SMS struct {
+	To   string // teléfono en formato E.164 ("+5493511234567")
+	Body string
+}
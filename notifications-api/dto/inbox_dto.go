@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"notifications-api/domain"
+
+	"shared/apperrors"
+)
+
+// InboxResponse es la respuesta de GET /users/me/notifications
+type InboxResponse struct {
+	Notifications []domain.InboxNotification `json:"notifications"`
+	UnreadCount   int64                      `json:"unread_count"`
+	Page          int                        `json:"page"`
+	Size          int                        `json:"size"`
+}
+
+// ErrorResponse representa una respuesta de error
+// Es el sobre común de shared/apperrors: {"code": "<código>", "message": "...", "details": ..., "request_id": "..."}
+type ErrorResponse = apperrors.Response
+
+// SuccessResponse representa una respuesta exitosa
+type SuccessResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"notifications-api/domain"
+)
+
+// BookingConfirmed avisa al huésped que su reserva fue confirmada
+// Payload esperado: {"user_id", "email", "property_title", "check_in", "check_out"}
+func BookingConfirmed(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: booking.confirmed without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
+
+// BookingCancelled avisa al huésped que su reserva fue cancelada
+// Payload esperado: {"user_id", "email", "property_title"}
+func BookingCancelled(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: booking.cancelled without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
+
+// BookingCheckinReminder le recuerda al huésped el check-in (48h antes)
+// Payload esperado: {"user_id", "email", "property_title", "check_in"}
+func BookingCheckinReminder(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: booking.checkin_reminder without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
+
+// BookingReviewRequest le pide al huésped una reseña después del checkout
+// Payload esperado: {"user_id", "email", "property_title", "review_url"}
+func BookingReviewRequest(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: booking.review_request without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
+
+// BookingPaymentPending le avisa al huésped que la reserva sigue sin pagar
+// Payload esperado: {"user_id", "email", "property_title", "expires_at"}
+func BookingPaymentPending(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: booking.payment_pending without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
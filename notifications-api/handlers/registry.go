@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"notifications-api/domain"
+)
+
+// ErrInvalidEvent indica que el evento no tiene los datos necesarios
+// Reintentarlo no sirve de nada, va directo a la DLQ
+var ErrInvalidEvent = errors.New("invalid event payload")
+
+// Handler arma la notificación para un tipo de evento
+// Si devuelve (nil, nil) el evento se ignora sin enviar nada
+type Handler func(event domain.Event) (*domain.Notification, error)
+
+// Registry guarda un handler por tipo de evento (ej: "user.created")
+type Registry struct {
+	handlers map[string]Handler
+}
+
+// NewRegistry crea un registry vacío
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]Handler)}
+}
+
+// Register asocia un handler a un tipo de evento
+func (r *Registry) Register(eventType string, handler Handler) {
+	r.handlers[eventType] = handler
+}
+
+// Get devuelve el handler de un tipo de evento, si existe
+func (r *Registry) Get(eventType string) (Handler, bool) {
+	handler, ok := r.handlers[eventType]
+	return handler, ok
+}
+
+// NewDefaultRegistry crea el registry con todos los handlers del servicio
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("user.created", UserCreated)
+	r.Register("user.security_alert", UserSecurityAlert)
+	r.Register("user.magic_link", UserMagicLink)
+	r.Register("user.invitation", UserInvitation)
+	r.Register("user.phone_verification", UserPhoneVerification)
+	r.Register("booking.confirmed", BookingConfirmed)
+	r.Register("booking.cancelled", BookingCancelled)
+	r.Register("booking.checkin_reminder", BookingCheckinReminder)
+	r.Register("booking.review_request", BookingReviewRequest)
+	r.Register("booking.payment_pending", BookingPaymentPending)
+	r.Register("review.created", ReviewCreated)
+	return r
+}
+
+// newNotification arma una notificación usando el tipo de evento como template
+// El locale sale del payload ("locale"); si no viene se usa el por defecto
+func newNotification(event domain.Event, userID uint, to string, category domain.Category) *domain.Notification {
+	return &domain.Notification{
+		UserID:   userID,
+		To:       to,
+		Category: category,
+		Locale:   event.String("locale"),
+		Template: event.Type,
+		Data:     event.Data,
+	}
+}
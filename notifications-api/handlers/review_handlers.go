@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"fmt"
+	"notifications-api/domain"
+)
+
+// ReviewCreated avisa al anfitrión que recibió una reseña
+// Payload esperado: {"host_id", "host_email", "property_title", "rating"}
+func ReviewCreated(event domain.Event) (*domain.Notification, error) {
+	to := event.String("host_email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: review.created without host_email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("host_id"), to, domain.CategoryTransactional), nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"notifications-api/domain"
+)
+
+// UserCreated envía el email de bienvenida
+// Payload esperado: {"user_id", "email", "first_name"}
+func UserCreated(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: user.created without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
+
+// UserMagicLink manda el link de login sin contraseña
+// Payload esperado: {"user_id", "email", "first_name", "url", "expires_in_minutes"}
+// El link es una credencial: no va a la bandeja in-app
+func UserMagicLink(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" || event.String("url") == "" {
+		return nil, fmt.Errorf("%w: user.magic_link without email or url", ErrInvalidEvent)
+	}
+
+	notification := newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional)
+	notification.Credential = true
+	return notification, nil
+}
+
+// UserInvitation manda el código para registrarse a alguien que todavía no
+// tiene cuenta (sin user_id: no hay preferencias ni bandeja)
+// Payload esperado: {"email", "code", "url", "user_type", "expires_in_hours", "expires_at"}
+func UserInvitation(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" || event.String("code") == "" {
+		return nil, fmt.Errorf("%w: user.invitation without email or code", ErrInvalidEvent)
+	}
+
+	notification := newNotification(event, 0, to, domain.CategoryTransactional)
+	notification.Credential = true
+	return notification, nil
+}
+
+// UserPhoneVerification manda por SMS el código para verificar el teléfono
+// Payload esperado: {"user_id", "phone", "first_name", "code", "expires_in_minutes"}
+// El código es una credencial: no va a la bandeja in-app
+func UserPhoneVerification(event domain.Event) (*domain.Notification, error) {
+	to := event.String("phone")
+	if to == "" || event.String("code") == "" {
+		return nil, fmt.Errorf("%w: user.phone_verification without phone or code", ErrInvalidEvent)
+	}
+
+	notification := newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional)
+	notification.Credential = true
+	notification.Channel = domain.ChannelSMS
+	return notification, nil
+}
+
+// UserSecurityAlert avisa al usuario de un login desde un dispositivo o red nuevos
+// Payload esperado: {"user_id", "email", "first_name", "ip", "user_agent", "login_at"}
+// y opcionales "device" ("Chrome (Windows)") y "country" (ISO 3166-1)
+func UserSecurityAlert(event domain.Event) (*domain.Notification, error) {
+	to := event.String("email")
+	if to == "" {
+		return nil, fmt.Errorf("%w: user.security_alert without email", ErrInvalidEvent)
+	}
+
+	return newNotification(event, event.Uint("user_id"), to, domain.CategoryTransactional), nil
+}
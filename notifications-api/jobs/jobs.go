@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"notifications-api/services"
+	"time"
+
+	"shared/scheduler"
+)
+
+// Register agrega al scheduler los jobs recurrentes de notifications-api
+// Cada job corre en una sola instancia gracias al lock en MySQL
+func Register(s *scheduler.Scheduler, inboxService services.InboxService, readRetention time.Duration) error {
+	// Todos los días a las 04:00: borrar notificaciones leídas viejas
+	return s.Register("purge_read_notifications", "0 4 * * *", func(ctx context.Context) error {
+		deleted, err := inboxService.PurgeRead(readRetention)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d notificaciones leídas borradas (retención %s)", deleted, readRetention)
+		return nil
+	})
+}
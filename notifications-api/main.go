@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"notifications-api/app"
+
+	"shared/config"
+	"shared/diagnostics"
+	"shared/graceful"
+	"shared/httpmw"
+	"shared/logging"
+	"shared/secrets"
+)
+
+func main() {
+	// ============================================
+	// 1. CONFIGURACIÓN - Leer variables de entorno
+	// ============================================
+	env, err := config.Load(".env")
+	if err != nil {
+		log.Fatal("❌ Failed to read .env:", err)
+	}
+
+	// Credenciales desde el gestor de secretos (SECRETS_PROVIDER, ver shared/secrets)
+	secretStore, err := secrets.Open(context.Background(), env,
+		"DB_PASSWORD", "JWT_SECRET", "RABBITMQ_URL", "SMTP_PASSWORD", "SENDGRID_API_KEY", "TWILIO_AUTH_TOKEN", "USERS_API_TOKEN")
+	if err != nil {
+		log.Fatal("❌ Failed to load secrets:", err)
+	}
+	cfg := app.ConfigFromEnv(env)
+
+	// Si algo está mal se muestran todos los errores juntos
+	if err := env.Err(); err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	// LOG_LEVEL; se cambia en caliente con PUT /admin/loglevel o SIGUSR1
+	logging.Install(cfg.LogLevel)
+
+	if cfg.JWKSURL == "" && cfg.JWTSecret == app.DefaultJWTSecret {
+		log.Println("⚠️  JWT_SECRET no definido: se usa el secret de desarrollo")
+	}
+
+	log.Println("🔧 Configuración cargada:")
+	log.Printf("   - DB Host: %s:%s", cfg.DBHost, cfg.DBPort)
+	log.Printf("   - DB Name: %s", cfg.DBName)
+	log.Printf("   - Email provider: %s", cfg.EmailProvider)
+	log.Printf("   - users-api: %s", cfg.UsersAPIURL)
+	log.Printf("   - Locale por defecto: %s", cfg.DefaultLocale)
+	log.Printf("   - Reintentos: %d cada %s", cfg.MaxRetries, cfg.RetryDelay)
+
+	// ============================================
+	// 2. CONECTAR A MYSQL (bandeja in-app), RABBITMQ Y ELEGIR EL SENDER
+	// ============================================
+	infra, err := app.Open(cfg)
+	if err != nil {
+		log.Fatal("❌ Failed to initialize infrastructure:", err)
+	}
+	defer infra.Close()
+
+	// ============================================
+	// 3. ARMAR LAS CAPAS (ver paquete app)
+	// ============================================
+	application, err := app.Build(cfg, infra)
+	if err != nil {
+		log.Fatal("❌ Failed to build application:", err)
+	}
+	defer application.Close()
+
+	// El JWT_SECRET rota sin reiniciar; el resto de las credenciales
+	// se toman en el próximo reinicio
+	if application.JWTSecret != nil {
+		secretStore.OnChange("JWT_SECRET", func(secret string) {
+			if err := application.JWTSecret.Set(secret); err != nil {
+				log.Printf("❌ JWT_SECRET rotado rechazado: %v", err)
+			}
+		})
+	}
+	go secretStore.Run(context.Background())
+
+	// ============================================
+	// 4. CONSUMIR EVENTOS DE RABBITMQ
+	// ============================================
+	consumer := application.NewConsumer()
+	go func() {
+		if err := consumer.Start(); err != nil {
+			log.Fatal("❌ Consumer stopped:", err)
+		}
+	}()
+
+	// ============================================
+	// 5. SERVIDOR HTTP (bandeja in-app y jobs programados)
+	// ============================================
+	log.Printf("🚀 Notifications API corriendo en puerto %s", cfg.Port)
+	// pprof/expvar en un puerto interno (además de /debug/* con auth de admin)
+	if cfg.DebugAddr != "" {
+		diagnostics.Serve(cfg.DebugAddr)
+	}
+
+	// SIGTERM/SIGUSR2 drenan las requests en curso y frenan el consumidor
+	// después del mensaje que esté procesando (ver shared/graceful)
+	srv := httpmw.NewServer(":"+cfg.Port, application.Handler, cfg.HTTP)
+	if err := graceful.Run(srv, cfg.DrainTimeout, consumer.Stop); err != nil {
+		log.Println("❌ Server stopped:", err)
+	}
+}
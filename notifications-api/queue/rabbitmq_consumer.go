@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"notifications-api/domain"
+	"notifications-api/handlers"
+	"notifications-api/services"
+	"time"
+
+	"shared/rabbitmq"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventsExchange es el exchange topic donde todos los servicios publican eventos
+const EventsExchange = "spotly.events"
+
+// bindings son las routing keys que escucha el servicio
+var bindings = []string{"user.*", "booking.*", "review.*"}
+
+// RabbitMQConsumer consume eventos de dominio y los pasa al servicio
+//
+// Topología (la declara shared/rabbitmq):
+//   - notifications: cola principal, bindeada a spotly.events
+//   - notifications.retry: cola con TTL, al expirar vuelve a notifications
+//   - notifications.dlq: mensajes inválidos o que agotaron los reintentos
+type RabbitMQConsumer struct {
+	consumer *rabbitmq.Consumer
+	service  services.NotificationService
+
+	// Stop cancela ctx y espera a que Start termine el mensaje en curso
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRabbitMQConsumer arma el consumidor sobre la conexión (con reconexión automática)
+// La conexión la abre y la cierra quien llama (ver app.Open)
+func NewRabbitMQConsumer(conn *rabbitmq.Connection, service services.NotificationService, maxRetries int, retryDelay time.Duration) *RabbitMQConsumer {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &RabbitMQConsumer{service: service, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+	c.consumer = rabbitmq.NewConsumer(conn, rabbitmq.ConsumerConfig{
+		Exchange:   EventsExchange,
+		Queue:      "notifications",
+		Bindings:   bindings,
+		Tag:        "notifications-api",
+		Prefetch:   1, // de a un mensaje por vez: un email lento no acapara la cola
+		MaxRetries: maxRetries,
+		RetryDelay: retryDelay,
+	}, c.processMessage)
+	return c
+}
+
+// Start empieza a consumir mensajes (bloquea hasta Stop o hasta que se cierre la conexión)
+func (c *RabbitMQConsumer) Start() error {
+	defer close(c.done)
+	return c.consumer.Run(c.ctx)
+}
+
+// Stop deja de recibir mensajes y espera a que termine el que se está
+// procesando, así un reinicio no corta un email a la mitad
+// Tiene la firma de graceful.StopFunc
+func (c *RabbitMQConsumer) Stop(ctx context.Context) error {
+	c.cancel()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// processMessage decodifica el evento y lo pasa al servicio
+// Un JSON inválido o un payload que el handler rechaza van directo a la DLQ;
+// el resto de los errores (SMTP caído, users-api caído) se reintentan
+func (c *RabbitMQConsumer) processMessage(ctx context.Context, msg amqp.Delivery) error {
+	var event domain.Event
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		return rabbitmq.Permanent(err)
+	}
+
+	err := c.service.Process(ctx, event)
+	if errors.Is(err, handlers.ErrInvalidEvent) {
+		return rabbitmq.Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"errors"
+	"notifications-api/domain"
+	"time"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InboxRepository define el acceso a la bandeja de notificaciones
+type InboxRepository interface {
+	Create(notification *domain.InboxNotification) error
+	ListByUser(userID uint, unreadOnly bool, limit, offset int) ([]domain.InboxNotification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) error
+	DeleteReadBefore(before time.Time) (int64, error)
+}
+
+// inboxRepository es la implementación con GORM
+type inboxRepository struct {
+	db *gorm.DB
+}
+
+// NewInboxRepository crea una nueva instancia del repositorio
+func NewInboxRepository(db *gorm.DB) InboxRepository {
+	return &inboxRepository{db: db}
+}
+
+// Create guarda una notificación
+// Si ya existe una con el mismo EventID (reintento), no hace nada
+func (r *inboxRepository) Create(notification *domain.InboxNotification) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notification).Error
+}
+
+// ListByUser lista las notificaciones de un usuario, de la más nueva a la más vieja
+func (r *inboxRepository) ListByUser(userID uint, unreadOnly bool, limit, offset int) ([]domain.InboxNotification, error) {
+	var notifications []domain.InboxNotification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread cuenta las notificaciones sin leer (el número de la campanita)
+func (r *inboxRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.InboxNotification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marca una notificación como leída
+// Filtra por user_id para que nadie marque notificaciones ajenas
+func (r *inboxRepository) MarkRead(userID, id uint) error {
+	var notification domain.InboxNotification
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NotFound("notification not found")
+		}
+		return err
+	}
+
+	// Si ya estaba leída no pisamos la fecha original
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&notification).Update("read_at", time.Now()).Error
+}
+
+// MarkAllRead marca todas las notificaciones del usuario como leídas
+func (r *inboxRepository) MarkAllRead(userID uint) error {
+	return r.db.Model(&domain.InboxNotification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+// DeleteReadBefore borra las notificaciones leídas antes de la fecha dada
+// Las no leídas se conservan siempre
+func (r *inboxRepository) DeleteReadBefore(before time.Time) (int64, error) {
+	result := r.db.Where("read_at IS NOT NULL AND read_at < ?", before).Delete(&domain.InboxNotification{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,21 @@
+package senders
+
+import (
+	"log"
+	"notifications-api/domain"
+)
+
+// logSender no envía nada, solo imprime el email en el log
+// Útil en desarrollo cuando no hay SMTP configurado
+type logSender struct{}
+
+// NewLogSender crea un sender que solo loguea
+func NewLogSender() EmailSender {
+	return &logSender{}
+}
+
+// Send imprime el email en el log
+func (s *logSender) Send(email domain.Email) error {
+	log.Printf("📧 [email] to=%s subject=%q\n%s", email.To, email.Subject, email.Body)
+	return nil
+}
@@ -0,0 +1,9 @@
+package senders
+
+import "notifications-api/domain"
+
+// EmailSender define la interfaz para enviar emails
+// Hay una implementación por proveedor (SMTP, SendGrid, log)
+type EmailSender interface {
+	Send(email domain.Email) error
+}
@@ -0,0 +1,66 @@
+package senders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"notifications-api/domain"
+	"time"
+)
+
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// sendGridSender envía emails usando la API HTTP de SendGrid
+type sendGridSender struct {
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+// NewSendGridSender crea un sender de SendGrid
+func NewSendGridSender(apiKey, from string) EmailSender {
+	return &sendGridSender{
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send hace POST a /v3/mail/send
+// SendGrid devuelve 202 Accepted cuando el email quedó encolado
+func (s *sendGridSender) Send(email domain.Email) error {
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": email.To}}},
+		},
+		"from":    map[string]string{"email": s.from},
+		"subject": email.Subject,
+		"content": []map[string]string{
+			{"type": "text/plain", "value": email.Body},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sendGridURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid returned status %d", resp.StatusCode)
+	}
+	return nil
+}
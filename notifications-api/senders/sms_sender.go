@@ -0,0 +1,27 @@
+package senders
+
+import (
+	"log"
+	"notifications-api/domain"
+)
+
+// SMSSender define la interfaz para enviar SMS
+// Hay una implementación por proveedor (Twilio, log)
+type SMSSender interface {
+	Send(sms domain.SMS) error
+}
+
+// logSMSSender no envía nada, solo imprime el SMS en el log
+// Útil en desarrollo cuando no hay proveedor configurado
+type logSMSSender struct{}
+
+// NewLogSMSSender crea un sender de SMS que solo loguea
+func NewLogSMSSender() SMSSender {
+	return &logSMSSender{}
+}
+
+// Send imprime el SMS en el log
+func (s *logSMSSender) Send(sms domain.SMS) error {
+	log.Printf("📱 [sms] to=%s\n%s", sms.To, sms.Body)
+	return nil
+}
@@ -0,0 +1,41 @@
+package senders
+
+import (
+	"fmt"
+	"net/smtp"
+	"notifications-api/domain"
+)
+
+// smtpSender envía emails usando un servidor SMTP
+type smtpSender struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender crea un sender SMTP
+// Si username está vacío, se envía sin autenticación (ej: MailHog en desarrollo)
+func NewSMTPSender(host, port, username, password, from string) EmailSender {
+	return &smtpSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send arma el mensaje MIME mínimo y lo envía
+func (s *smtpSender) Send(email domain.Email) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s",
+		s.from, email.To, email.Subject, email.Body)
+
+	return smtp.SendMail(s.host+":"+s.port, auth, s.from, []string{email.To}, []byte(msg))
+}
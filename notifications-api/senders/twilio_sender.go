@@ -0,0 +1,60 @@
+package senders
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"notifications-api/domain"
+	"strings"
+	"time"
+)
+
+const twilioURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// twilioSender envía SMS usando la API HTTP de Twilio
+type twilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSender crea un sender de Twilio
+// from es el número (E.164) o el Messaging Service SID que envía
+func NewTwilioSender(accountSID, authToken, from string) SMSSender {
+	return &twilioSender{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send hace POST a /Accounts/{sid}/Messages.json (formulario, basic auth)
+// Twilio devuelve 201 Created cuando el mensaje quedó encolado
+func (s *twilioSender) Send(sms domain.SMS) error {
+	form := url.Values{"To": {sms.To}, "Body": {sms.Body}}
+	if strings.HasPrefix(s.from, "MG") {
+		form.Set("MessagingServiceSid", s.from)
+	} else {
+		form.Set("From", s.from)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(twilioURL, s.accountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	"notifications-api/dto"
+
+	"shared/diagnostics"
+	"shared/health"
+	"shared/logging"
+	"shared/openapi"
+)
+
+// unreadCountResponse es la forma de la respuesta de GET /users/me/notifications/unread-count
+type unreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// apiSpec documenta las rutas de notifications-api (GET /openapi.json)
+// server_test.go verifica que cada ruta del router esté acá
+func apiSpec() *openapi.Spec {
+	spec := openapi.New("notifications-api", "1.0.0")
+
+	spec.Add(openapi.Operation{Method: "GET", Path: "/health", Summary: "El servicio está corriendo", Tags: []string{"health"},
+		Reply: map[string]string{}})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/readyz", Summary: "Chequeo de MySQL y RabbitMQ", Tags: []string{"health"},
+		Reply: health.Report{}, Extra: map[int]interface{}{http.StatusServiceUnavailable: health.Report{}}})
+	diagnostics.AddOperations(spec) // /debug/* (admins)
+	logging.AddOperations(spec)     // /admin/loglevel (admins)
+
+	// Bandeja in-app del usuario logueado
+	spec.Add(openapi.Operation{Method: "GET", Path: "/users/me/notifications", Summary: "Bandeja in-app paginada", Tags: []string{"inbox"},
+		Auth: true, Query: []string{"unread", "page", "size"}, Reply: dto.InboxResponse{}, Errors: []int{http.StatusUnauthorized}})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/users/me/notifications/unread-count", Summary: "Contador de no leídas", Tags: []string{"inbox"},
+		Auth: true, Reply: unreadCountResponse{}, Errors: []int{http.StatusUnauthorized}})
+	spec.Add(openapi.Operation{Method: "PUT", Path: "/users/me/notifications/read-all", Summary: "Marcar todas como leídas", Tags: []string{"inbox"},
+		Auth: true, Reply: dto.SuccessResponse{}, Errors: []int{http.StatusUnauthorized}})
+	spec.Add(openapi.Operation{Method: "PUT", Path: "/users/me/notifications/:id/read", Summary: "Marcar una como leída", Tags: []string{"inbox"},
+		Auth: true, Reply: dto.SuccessResponse{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound}})
+
+	return spec
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"notifications-api/controllers"
+	"notifications-api/jobs"
+	"notifications-api/services"
+
+	"shared/audit"
+	"shared/auth"
+	"shared/diagnostics"
+	"shared/health"
+	"shared/httpmw"
+	"shared/httpmw/ginmw"
+	"shared/logging"
+	"shared/openapi"
+	"shared/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config reúne las dependencias y parámetros del servidor HTTP de notifications-api
+// El consumidor de RabbitMQ no es parte del servidor (ver app.App.NewConsumer)
+type Config struct {
+	InboxService services.InboxService
+	Validator    *auth.Validator // valida los JWT emitidos por users-api
+
+	// Locker de los jobs programados; nil = no se programan jobs (tests)
+	Locker        scheduler.Locker
+	ReadRetention time.Duration
+
+	Health *health.Checker // chequeos de GET /readyz; nil = no se expone
+
+	// Loguear headers y bodies de cada request (LOG_HTTP_BODIES, solo se
+	// ven con nivel debug); contraseñas, tokens y Authorization van tapados
+	LogBodies bool
+
+	// Tope para que responda cada ruta de la bandeja; 0 = sin tope
+	HandlerTimeout time.Duration
+
+	// Eventos de auditoría (spotly.audit); nil = solo se loguean
+	Audit audit.Emitter
+}
+
+// NewServer arma el controller de la bandeja in-app y el router
+// Devuelve el handler y una función para liberar lo que el servidor arrancó
+// (por ahora el scheduler de jobs)
+func NewServer(cfg Config) (http.Handler, func() error) {
+	inboxController := controllers.NewInboxController(cfg.InboxService)
+	if cfg.Audit == nil {
+		cfg.Audit = audit.NewLogEmitter("notifications-api")
+	}
+
+	// Jobs programados
+	closeFn := func() error { return nil }
+	if cfg.Locker != nil {
+		jobScheduler := scheduler.New(cfg.Locker)
+		if err := jobs.Register(jobScheduler, cfg.InboxService, cfg.ReadRetention); err != nil {
+			// Las expresiones cron son constantes: si fallan es un bug
+			panic("notifications-api: invalid job spec: " + err.Error())
+		}
+		jobScheduler.Start()
+		closeFn = func() error {
+			jobScheduler.Stop()
+			return nil
+		}
+	}
+
+	router := gin.New()
+
+	// Middlewares comunes (shared/httpmw): request ID primero para que
+	// el log de acceso y las respuestas de error lo incluyan
+	router.Use(ginmw.RequestID(), ginmw.Logger(), ginmw.Recovery())
+	if cfg.LogBodies {
+		router.Use(ginmw.BodyLog(httpmw.DefaultBodyLogMax))
+	}
+
+	// Auditoría: cada 401/403 queda como permission.denied
+	router.Use(ginmw.AuditDenied(cfg.Audit))
+
+	// CORS - Permitir requests desde el frontend (campanita de notificaciones)
+	router.Use(ginmw.CORS(httpmw.DefaultCORS("GET", "PUT")))
+
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":  "healthy",
+			"service": "notifications-api",
+		})
+	})
+	if cfg.Health != nil {
+		router.GET("/readyz", gin.WrapH(cfg.Health.Handler())) // MySQL y RabbitMQ
+	}
+
+	// Bandeja in-app del usuario logueado (requiere JWT de users-api)
+	inbox := router.Group("/users/me/notifications")
+	inbox.Use(ginmw.Timeout(cfg.HandlerTimeout), ginmw.Auth(cfg.Validator))
+	{
+		inbox.GET("", inboxController.List)
+		inbox.GET("/unread-count", inboxController.UnreadCount)
+		inbox.PUT("/read-all", inboxController.MarkAllRead)
+		inbox.PUT("/:id/read", inboxController.MarkRead)
+	}
+
+	// Diagnóstico (pprof, expvar): solo admins. También puede ir en un
+	// puerto interno sin auth con DEBUG_ADDR (ver shared/diagnostics)
+	debug := router.Group("/debug", ginmw.Auth(cfg.Validator), ginmw.Admin())
+	{
+		diag := gin.WrapH(diagnostics.Handler())
+		debug.GET("/pprof/*profile", diag)
+		debug.POST("/pprof/symbol", diag)
+		debug.GET("/vars", diag)
+	}
+
+	// Nivel de log en caliente: solo admins (también con SIGUSR1)
+	logLevel := gin.WrapH(logging.Handler())
+	router.GET("/admin/loglevel", ginmw.Auth(cfg.Validator), ginmw.Admin(), logLevel)
+	router.PUT("/admin/loglevel", ginmw.Auth(cfg.Validator), ginmw.Admin(), logLevel)
+
+	// Documentación: OpenAPI 3 y Swagger UI
+	router.GET("/openapi.json", gin.WrapH(apiSpec().Handler()))
+	router.GET("/docs", gin.WrapH(openapi.SwaggerUI("notifications-api", "/openapi.json")))
+
+	return router, closeFn
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"shared/auth"
+	"shared/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test: todas las rutas del router están en /openapi.json
+func TestNewServer_OpenAPICoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, closeFn := NewServer(Config{
+		Validator: auth.NewHMACValidator([]byte("test-secret"), 0),
+		Health:    health.New(time.Second),
+	})
+	defer closeFn()
+
+	spec := apiSpec()
+	for _, route := range handler.(*gin.Engine).Routes() {
+		if route.Path == "/openapi.json" || route.Path == "/docs" {
+			continue
+		}
+		if !spec.Has(route.Method, route.Path) {
+			t.Errorf("%s %s is not documented in apiSpec()", route.Method, route.Path)
+		}
+	}
+}
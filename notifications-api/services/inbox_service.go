@@ -0,0 +1,71 @@
+package services
+
+import (
+	"notifications-api/domain"
+	"notifications-api/dto"
+	"notifications-api/repositories"
+	"time"
+)
+
+// InboxService define la interfaz de la bandeja in-app
+type InboxService interface {
+	List(userID uint, unreadOnly bool, page, size int) (*dto.InboxResponse, error)
+	UnreadCount(userID uint) (int64, error)
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) error
+	PurgeRead(retention time.Duration) (int64, error)
+}
+
+// inboxService es la implementación real del servicio
+type inboxService struct {
+	repo repositories.InboxRepository
+}
+
+// NewInboxService crea una nueva instancia del servicio
+func NewInboxService(repo repositories.InboxRepository) InboxService {
+	return &inboxService{repo: repo}
+}
+
+// List devuelve una página de notificaciones junto con el total sin leer
+func (s *inboxService) List(userID uint, unreadOnly bool, page, size int) (*dto.InboxResponse, error) {
+	notifications, err := s.repo.ListByUser(userID, unreadOnly, size, (page-1)*size)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []domain.InboxNotification{}
+	}
+	return &dto.InboxResponse{
+		Notifications: notifications,
+		UnreadCount:   unread,
+		Page:          page,
+		Size:          size,
+	}, nil
+}
+
+// UnreadCount devuelve cuántas notificaciones sin leer tiene el usuario
+func (s *inboxService) UnreadCount(userID uint) (int64, error) {
+	return s.repo.CountUnread(userID)
+}
+
+// MarkRead marca una notificación del usuario como leída
+func (s *inboxService) MarkRead(userID, id uint) error {
+	return s.repo.MarkRead(userID, id)
+}
+
+// MarkAllRead marca todas las notificaciones del usuario como leídas
+func (s *inboxService) MarkAllRead(userID uint) error {
+	return s.repo.MarkAllRead(userID)
+}
+
+// PurgeRead borra las notificaciones leídas hace más de retention
+// Lo ejecuta el job programado "purge_read_notifications"
+func (s *inboxService) PurgeRead(retention time.Duration) (int64, error) {
+	return s.repo.DeleteReadBefore(time.Now().Add(-retention))
+}
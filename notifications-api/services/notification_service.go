@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notifications-api/clients"
+	"notifications-api/domain"
+	"notifications-api/handlers"
+	"notifications-api/repositories"
+	"notifications-api/senders"
+	"notifications-api/templates"
+
+	"shared/requestid"
+)
+
+// NotificationService define la interfaz del servicio
+type NotificationService interface {
+	Process(ctx context.Context, event domain.Event) error
+}
+
+// notificationService busca el handler del evento, renderiza el template,
+// lo guarda en la bandeja in-app y envía el email si el usuario lo permite
+type notificationService struct {
+	registry *handlers.Registry
+	renderer *templates.Renderer
+	inbox    repositories.InboxRepository
+	users    clients.UsersClient
+	sender   senders.EmailSender
+	sms      senders.SMSSender
+}
+
+// NewNotificationService crea una nueva instancia del servicio
+func NewNotificationService(registry *handlers.Registry, renderer *templates.Renderer, inbox repositories.InboxRepository, users clients.UsersClient, sender senders.EmailSender, sms senders.SMSSender) NotificationService {
+	return &notificationService{
+		registry: registry,
+		renderer: renderer,
+		inbox:    inbox,
+		users:    users,
+		sender:   sender,
+		sms:      sms,
+	}
+}
+
+// Process procesa un evento de dominio
+// 1. Si no hay handler para el tipo, se ignora (no es un error)
+// 2. El handler arma la notificación (errores de payload => handlers.ErrInvalidEvent)
+// 3. Se renderiza el template en el locale del usuario
+// 4. Se guarda en la bandeja in-app (siempre salvo las credenciales, ver Notification.Credential)
+// 5. Si el usuario desactivó esa categoría de emails, no se envía (salvo las credenciales)
+// 6. El sender lo envía por email o SMS (errores del proveedor => se reintentan)
+//
+// ctx trae el request ID que originó el evento (para los logs y la llamada a users-api)
+func (s *notificationService) Process(ctx context.Context, event domain.Event) error {
+	handler, ok := s.registry.Get(event.Type)
+	if !ok {
+		requestid.Logf(ctx, "ℹ️  Evento %s sin handler, se ignora", event.Type)
+		return nil
+	}
+
+	notification, err := handler(event)
+	if err != nil {
+		return err
+	}
+	if notification == nil {
+		return nil
+	}
+
+	subject, body, err := s.renderer.Render(notification.Template, notification.Locale, notification.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", handlers.ErrInvalidEvent, err)
+	}
+
+	if notification.UserID != 0 && !notification.Credential {
+		if err := s.saveToInbox(event, notification, subject, body); err != nil {
+			return err
+		}
+	}
+
+	allowed, err := s.isAllowed(ctx, notification)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		requestid.Logf(ctx, "ℹ️  Usuario %d desactivó emails %s, no se envía %s", notification.UserID, notification.Category, event.Type)
+		return nil
+	}
+
+	if notification.Channel == domain.ChannelSMS {
+		return s.sms.Send(domain.SMS{To: notification.To, Body: body})
+	}
+	return s.sender.Send(domain.Email{
+		To:      notification.To,
+		Subject: subject,
+		Body:    body,
+	})
+}
+
+// isAllowed consulta las preferencias en users-api
+// Los destinatarios sin usuario (UserID = 0) y las credenciales siempre
+// reciben el email
+func (s *notificationService) isAllowed(ctx context.Context, notification *domain.Notification) (bool, error) {
+	if notification.UserID == 0 || notification.Credential {
+		return true, nil
+	}
+
+	prefs, err := s.users.GetPreferences(ctx, notification.UserID)
+	if errors.Is(err, clients.ErrUserNotFound) {
+		// El usuario ya no existe: no tiene sentido reintentar
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return prefs.Allows(notification.Category), nil
+}
+
+// saveToInbox guarda la notificación en la bandeja del usuario
+// Con event.ID el guardado es idempotente: un reintento no la duplica
+func (s *notificationService) saveToInbox(event domain.Event, notification *domain.Notification, title, body string) error {
+	item := &domain.InboxNotification{
+		UserID: notification.UserID,
+		Type:   event.Type,
+		Title:  title,
+		Body:   body,
+	}
+	if event.ID != "" {
+		item.EventID = &event.ID
+	}
+	return s.inbox.Create(item)
+}
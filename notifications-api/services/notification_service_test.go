@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"notifications-api/clients"
+	"notifications-api/domain"
+	"notifications-api/handlers"
+	"notifications-api/templates"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ============================================
+// MOCK del sender para los tests
+// ============================================
+type mockSender struct {
+	sent []domain.Email
+	err  error
+}
+
+func (m *mockSender) Send(email domain.Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+// mockSMSSender guarda los SMS enviados
+type mockSMSSender struct {
+	sent []domain.SMS
+}
+
+func (m *mockSMSSender) Send(sms domain.SMS) error {
+	m.sent = append(m.sent, sms)
+	return nil
+}
+
+// ============================================
+// MOCK del cliente de users-api
+// ============================================
+type mockUsersClient struct {
+	prefs map[uint]*domain.Preferences
+}
+
+func (m *mockUsersClient) GetPreferences(ctx context.Context, userID uint) (*domain.Preferences, error) {
+	prefs, exists := m.prefs[userID]
+	if !exists {
+		return nil, clients.ErrUserNotFound
+	}
+	return prefs, nil
+}
+
+// ============================================
+// MOCK del repositorio de la bandeja
+// ============================================
+type mockInboxRepository struct {
+	items []domain.InboxNotification
+}
+
+func (m *mockInboxRepository) Create(notification *domain.InboxNotification) error {
+	if notification.EventID != nil {
+		for _, item := range m.items {
+			if item.EventID != nil && *item.EventID == *notification.EventID {
+				return nil
+			}
+		}
+	}
+	m.items = append(m.items, *notification)
+	return nil
+}
+
+func (m *mockInboxRepository) ListByUser(userID uint, unreadOnly bool, limit, offset int) ([]domain.InboxNotification, error) {
+	return m.items, nil
+}
+
+func (m *mockInboxRepository) CountUnread(userID uint) (int64, error) {
+	return int64(len(m.items)), nil
+}
+
+func (m *mockInboxRepository) MarkRead(userID, id uint) error {
+	return nil
+}
+
+func (m *mockInboxRepository) MarkAllRead(userID uint) error {
+	return nil
+}
+
+func (m *mockInboxRepository) DeleteReadBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestService(t *testing.T, users *mockUsersClient, sender *mockSender) NotificationService {
+	return newTestServiceWithInbox(t, users, sender, &mockInboxRepository{})
+}
+
+func newTestServiceWithInbox(t *testing.T, users *mockUsersClient, sender *mockSender, inbox *mockInboxRepository) NotificationService {
+	return newTestServiceWithSMS(t, users, sender, inbox, &mockSMSSender{})
+}
+
+func newTestServiceWithSMS(t *testing.T, users *mockUsersClient, sender *mockSender, inbox *mockInboxRepository, sms *mockSMSSender) NotificationService {
+	renderer, err := templates.NewRenderer("es")
+	if err != nil {
+		t.Fatalf("Failed to parse templates: %v", err)
+	}
+	if users == nil {
+		users = &mockUsersClient{}
+	}
+	return NewNotificationService(handlers.NewDefaultRegistry(), renderer, inbox, users, sender, sms)
+}
+
+// ============================================
+// TESTS
+// ============================================
+
+// Test: user.created envía el email de bienvenida
+func TestProcess_UserCreated(t *testing.T) {
+	sender := &mockSender{}
+	service := newTestService(t, nil, sender)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.created",
+		Data: map[string]interface{}{"email": "test@example.com", "first_name": "Test"},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("Expected 1 email, got %d", len(sender.sent))
+	}
+	if sender.sent[0].To != "test@example.com" {
+		t.Errorf("Expected recipient test@example.com, got %s", sender.sent[0].To)
+	}
+	if !strings.Contains(sender.sent[0].Body, "Hola Test") {
+		t.Errorf("Expected rendered spanish body, got %q", sender.sent[0].Body)
+	}
+}
+
+// Test: se usa el template del locale del evento
+func TestProcess_UsesEventLocale(t *testing.T) {
+	sender := &mockSender{}
+	service := newTestService(t, nil, sender)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.created",
+		Data: map[string]interface{}{"email": "test@example.com", "first_name": "Test", "locale": "en-US"},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sender.sent[0].Subject != "Welcome to Spotly!" {
+		t.Errorf("Expected english subject, got %q", sender.sent[0].Subject)
+	}
+}
+
+// Test: no se envía si el usuario desactivó la categoría
+func TestProcess_RespectsPreferences(t *testing.T) {
+	sender := &mockSender{}
+	users := &mockUsersClient{prefs: map[uint]*domain.Preferences{
+		1: {UserID: 1, EmailTransactional: false},
+	}}
+	service := newTestService(t, users, sender)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "booking.confirmed",
+		Data: map[string]interface{}{"user_id": float64(1), "email": "test@example.com"},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no emails, got %d", len(sender.sent))
+	}
+}
+
+// Test: los recordatorios de reservas tienen template en cada locale
+func TestProcess_BookingReminders(t *testing.T) {
+	for _, eventType := range []string{"booking.checkin_reminder", "booking.review_request", "booking.payment_pending"} {
+		for _, locale := range []string{"es", "en"} {
+			sender := &mockSender{}
+			service := newTestService(t, nil, sender)
+
+			err := service.Process(context.Background(), domain.Event{
+				Type: eventType,
+				Data: map[string]interface{}{"email": "test@example.com", "property_title": "Casa", "locale": locale},
+			})
+
+			if err != nil {
+				t.Fatalf("%s/%s: expected no error, got %v", eventType, locale, err)
+			}
+			if len(sender.sent) != 1 || !strings.Contains(sender.sent[0].Body, "Casa") {
+				t.Errorf("%s/%s: expected an email about the property, got %+v", eventType, locale, sender.sent)
+			}
+		}
+	}
+}
+
+// Test: eventos sin handler se ignoran sin error
+func TestProcess_UnknownEventIgnored(t *testing.T) {
+	sender := &mockSender{}
+	service := newTestService(t, nil, sender)
+
+	err := service.Process(context.Background(), domain.Event{Type: "user.something_else"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no emails, got %d", len(sender.sent))
+	}
+}
+
+// Test: payload sin destinatario => ErrInvalidEvent (va a la DLQ)
+func TestProcess_MissingRecipient(t *testing.T) {
+	sender := &mockSender{}
+	service := newTestService(t, nil, sender)
+
+	err := service.Process(context.Background(), domain.Event{Type: "booking.confirmed", Data: map[string]interface{}{}})
+
+	if !errors.Is(err, handlers.ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent, got %v", err)
+	}
+}
+
+// Test: error del proveedor se propaga (el consumer lo reintenta)
+func TestProcess_SenderError(t *testing.T) {
+	sender := &mockSender{err: errors.New("smtp down")}
+	service := newTestService(t, nil, sender)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.created",
+		Data: map[string]interface{}{"email": "test@example.com"},
+	})
+
+	if err == nil || errors.Is(err, handlers.ErrInvalidEvent) {
+		t.Errorf("Expected sender error, got %v", err)
+	}
+}
+
+// Test: la bandeja guarda la notificación aunque el email esté desactivado,
+// y un reintento del mismo evento no la duplica
+func TestProcess_SavesToInboxOnce(t *testing.T) {
+	sender := &mockSender{}
+	inbox := &mockInboxRepository{}
+	users := &mockUsersClient{prefs: map[uint]*domain.Preferences{
+		1: {UserID: 1, EmailTransactional: false},
+	}}
+	service := newTestServiceWithInbox(t, users, sender, inbox)
+
+	event := domain.Event{
+		ID:   "evt-1",
+		Type: "booking.cancelled",
+		Data: map[string]interface{}{"user_id": float64(1), "email": "test@example.com", "property_title": "Casa"},
+	}
+	service.Process(context.Background(), event)
+	service.Process(context.Background(), event)
+
+	if len(inbox.items) != 1 {
+		t.Fatalf("Expected 1 inbox item, got %d", len(inbox.items))
+	}
+	if inbox.items[0].UserID != 1 || inbox.items[0].Title != "Tu reserva fue cancelada" {
+		t.Errorf("Unexpected inbox item: %+v", inbox.items[0])
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no emails, got %d", len(sender.sent))
+	}
+}
+
+// Test: el magic link se manda aunque el usuario haya desactivado los
+// emails transaccionales y no queda en la bandeja
+func TestProcess_MagicLinkIsCredential(t *testing.T) {
+	sender := &mockSender{}
+	inbox := &mockInboxRepository{}
+	users := &mockUsersClient{prefs: map[uint]*domain.Preferences{
+		1: {UserID: 1, EmailTransactional: false},
+	}}
+	service := newTestServiceWithInbox(t, users, sender, inbox)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.magic_link",
+		Data: map[string]interface{}{"user_id": float64(1), "email": "test@example.com", "first_name": "Test",
+			"url": "http://localhost:3000/login/magic?token=abc", "expires_in_minutes": float64(15)},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 1 || !strings.Contains(sender.sent[0].Body, "token=abc") {
+		t.Errorf("Expected the link emailed, got %+v", sender.sent)
+	}
+	if len(inbox.items) != 0 {
+		t.Errorf("Expected nothing in the inbox, got %+v", inbox.items)
+	}
+}
+
+// Test: la invitación va por email a alguien sin cuenta (sin preferencias ni bandeja)
+func TestProcess_InvitationWithoutUser(t *testing.T) {
+	sender := &mockSender{}
+	inbox := &mockInboxRepository{}
+	service := newTestServiceWithInbox(t, &mockUsersClient{}, sender, inbox)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.invitation",
+		Data: map[string]interface{}{"email": "ana@example.com", "code": "ABCD2345EFGH6789",
+			"url": "http://localhost:3000/register?code=ABCD2345EFGH6789", "expires_in_hours": float64(168)},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].To != "ana@example.com" || !strings.Contains(sender.sent[0].Body, "ABCD2345EFGH6789") {
+		t.Errorf("Expected the code emailed, got %+v", sender.sent)
+	}
+	if len(inbox.items) != 0 {
+		t.Errorf("Expected nothing in the inbox, got %+v", inbox.items)
+	}
+}
+
+// Test: el código de verificación del teléfono sale por SMS, no por email ni a la bandeja
+func TestProcess_PhoneVerificationSendsSMS(t *testing.T) {
+	sender := &mockSender{}
+	inbox := &mockInboxRepository{}
+	sms := &mockSMSSender{}
+	service := newTestServiceWithSMS(t, nil, sender, inbox, sms)
+
+	err := service.Process(context.Background(), domain.Event{
+		Type: "user.phone_verification",
+		Data: map[string]interface{}{"user_id": float64(1), "phone": "+5493511234567", "first_name": "Test",
+			"code": "123456", "expires_in_minutes": float64(10)},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sms.sent) != 1 || sms.sent[0].To != "+5493511234567" || !strings.Contains(sms.sent[0].Body, "123456") {
+		t.Errorf("Expected the code sent by SMS, got %+v", sms.sent)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no email, got %+v", sender.sent)
+	}
+	if len(inbox.items) != 0 {
+		t.Errorf("Expected nothing in the inbox, got %+v", inbox.items)
+	}
+}
@@ -0,0 +1,82 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Los templates viven en locales/<locale>/<evento>.tmpl
+// Cada archivo define dos bloques: {{define "subject"}} y {{define "body"}}
+//
+//go:embed locales
+var files embed.FS
+
+// Renderer renderiza templates por locale con fallback al locale por defecto
+type Renderer struct {
+	defaultLocale string
+	templates     map[string]*template.Template // clave: "<locale>/<nombre>"
+}
+
+// NewRenderer parsea todos los templates embebidos
+// Falla al arrancar si algún template tiene errores de sintaxis
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]*template.Template),
+	}
+
+	err := fs.WalkDir(files, "locales", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".tmpl" {
+			return err
+		}
+
+		tmpl, err := template.ParseFS(files, p)
+		if err != nil {
+			return err
+		}
+
+		locale := path.Base(path.Dir(p))
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+		r.templates[locale+"/"+name] = tmpl
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Render devuelve el asunto y el cuerpo del email
+// Si no hay template para el locale pedido, usa el locale por defecto
+func (r *Renderer) Render(name, locale string, data map[string]interface{}) (string, string, error) {
+	tmpl, ok := r.templates[normalizeLocale(locale)+"/"+name]
+	if !ok {
+		tmpl, ok = r.templates[r.defaultLocale+"/"+name]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("template %q not found", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()), nil
+}
+
+// normalizeLocale reduce "es-AR" o "en_US" al idioma ("es", "en")
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
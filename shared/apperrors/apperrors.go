@@ -0,0 +1,210 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code es el código de error que ven los clientes en el campo "error"
+type Code string
+
+const (
+	CodeBadRequest   Code = "bad_request"
+	CodeValidation   Code = "validation_error"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeNotFound     Code = "not_found"
+	CodeConflict     Code = "conflict"
+	CodeRateLimited  Code = "too_many_requests"
+	CodeTimeout      Code = "timeout"
+	CodeInternal     Code = "internal_error"
+)
+
+// Errores "sentinela" para comparar con errors.Is sin mirar el mensaje
+// Ejemplo: errors.Is(err, apperrors.ErrNotFound)
+var (
+	ErrBadRequest   = &Error{Code: CodeBadRequest, Message: "bad request"}
+	ErrValidation   = &Error{Code: CodeValidation, Message: "validation error"}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized, Message: "unauthorized"}
+	ErrForbidden    = &Error{Code: CodeForbidden, Message: "forbidden"}
+	ErrNotFound     = &Error{Code: CodeNotFound, Message: "not found"}
+	ErrConflict     = &Error{Code: CodeConflict, Message: "conflict"}
+	ErrRateLimited  = &Error{Code: CodeRateLimited, Message: "too many requests, try again later"}
+	ErrTimeout      = &Error{Code: CodeTimeout, Message: "the request took too long, try again later"}
+	ErrInternal     = &Error{Code: CodeInternal, Message: "internal error"}
+)
+
+// httpStatus mapea cada código a su status HTTP
+var httpStatus = map[Code]int{
+	CodeBadRequest:   http.StatusBadRequest,
+	CodeValidation:   http.StatusBadRequest,
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeForbidden:    http.StatusForbidden,
+	CodeNotFound:     http.StatusNotFound,
+	CodeConflict:     http.StatusConflict,
+	CodeRateLimited:  http.StatusTooManyRequests,
+	CodeTimeout:      http.StatusServiceUnavailable,
+	CodeInternal:     http.StatusInternalServerError,
+}
+
+// Error es un error de la aplicación con código tipado
+// Message es el texto que ve el cliente; Details (opcional) son datos extra
+// para el cliente (ej: qué campos fallaron); Err (opcional) es la causa interna
+type Error struct {
+	Code    Code
+	Message string
+	Details interface{}
+	Err     error
+
+	// format y args guardan el mensaje sin armar (ver Newf) para poder
+	// traducir el formato y no el mensaje ya armado
+	format string
+	args   []interface{}
+}
+
+// Error devuelve el mensaje para el cliente
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap permite llegar a la causa con errors.Is / errors.As
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is hace que dos *Error con el mismo código sean "iguales" para errors.Is
+// Así errors.Is(NotFound("user not found"), ErrNotFound) es true
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails devuelve una copia del error con detalles para el cliente
+func (e *Error) WithDetails(details interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// FieldError describe un campo inválido en los detalles de un error de validación
+// Message es el texto para mostrar al lado del campo (ej: "email must be a valid email address")
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// formato sin armar, para traducirlo igual que en Newf
+	format string
+	args   []interface{}
+}
+
+// NewFieldError crea el detalle de un campo con el mensaje armado con fmt.Sprintf
+// Como en Newf, Translate traduce el formato y no el mensaje ya armado
+func NewFieldError(field, rule, param, format string, args ...interface{}) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: fmt.Sprintf(format, args...),
+		format:  format,
+		args:    args,
+	}
+}
+
+// New crea un error con código y mensaje
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf crea un error con un mensaje armado con fmt.Sprintf
+// Usarlo en vez de New(code, fmt.Sprintf(...)) para que Translate traduzca
+// el formato (ej: "%s must be between 1 and %d")
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), format: format, args: args}
+}
+
+// Translate devuelve una copia del error con el mensaje traducido por translate,
+// que recibe el mensaje original en inglés (o el formato, si se creó con Newf)
+// Si los detalles son []FieldError, sus mensajes también se traducen
+func (e *Error) Translate(translate func(message string) string) *Error {
+	copied := *e
+	copied.Message = translateMessage(translate, e.Message, e.format, e.args)
+	if fields, ok := e.Details.([]FieldError); ok {
+		translated := make([]FieldError, len(fields))
+		for i, field := range fields {
+			field.Message = translateMessage(translate, field.Message, field.format, field.args)
+			translated[i] = field
+		}
+		copied.Details = translated
+	}
+	return &copied
+}
+
+// translateMessage traduce el formato si lo hay, o si no el mensaje armado
+func translateMessage(translate func(string) string, message, format string, args []interface{}) string {
+	if format != "" {
+		return fmt.Sprintf(translate(format), args...)
+	}
+	return translate(message)
+}
+
+// Wrap crea un error con código y mensaje que conserva la causa
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Atajos para los códigos más usados
+func BadRequest(message string) *Error   { return New(CodeBadRequest, message) }
+func Validation(message string) *Error   { return New(CodeValidation, message) }
+func Unauthorized(message string) *Error { return New(CodeUnauthorized, message) }
+func Forbidden(message string) *Error    { return New(CodeForbidden, message) }
+func NotFound(message string) *Error     { return New(CodeNotFound, message) }
+func Conflict(message string) *Error     { return New(CodeConflict, message) }
+
+// Internal envuelve un error inesperado (ej: de la base de datos)
+// El cliente ve un mensaje genérico; la causa queda para los logs
+func Internal(err error) *Error {
+	return Wrap(CodeInternal, "internal server error", err)
+}
+
+// Response es el sobre JSON de error que devuelven todos los servicios
+// Los clientes solo necesitan un parser: miran "code" y muestran "message"
+//
+//	{"code": "not_found", "message": "user not found", "request_id": "4f1c..."}
+//	{"code": "validation_error", "message": "...", "details": [{"field": "email", "rule": "email"}]}
+type Response struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// From convierte cualquier error en un *Error
+// Los errores que no son *Error se tratan como internos
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+// HTTPStatus devuelve el status HTTP que corresponde al error
+func HTTPStatus(err error) int {
+	if status, ok := httpStatus[From(err).Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ToResponse devuelve el status HTTP y el cuerpo JSON para un error
+func ToResponse(err error) (int, Response) {
+	appErr := From(err)
+	return HTTPStatus(appErr), Response{
+		Code:    string(appErr.Code),
+		Message: appErr.Message,
+		Details: appErr.Details,
+	}
+}
@@ -0,0 +1,113 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Test: errors.Is compara por código, no por mensaje
+func TestIs_ComparesByCode(t *testing.T) {
+	err := NotFound("user not found")
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected NotFound to match ErrNotFound")
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Error("Expected NotFound not to match ErrConflict")
+	}
+	if !errors.Is(fmt.Errorf("loading: %w", err), ErrNotFound) {
+		t.Error("Expected wrapped error to match ErrNotFound")
+	}
+}
+
+// Test: mapeo de código a status HTTP
+func TestToResponse(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{NotFound("user not found"), http.StatusNotFound, "not_found"},
+		{Conflict("email already exists"), http.StatusConflict, "conflict"},
+		{Unauthorized("invalid credentials"), http.StatusUnauthorized, "unauthorized"},
+		{errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		status, body := ToResponse(tt.err)
+		if status != tt.status || body.Code != tt.code {
+			t.Errorf("ToResponse(%v) = %d %s, expected %d %s", tt.err, status, body.Code, tt.status, tt.code)
+		}
+	}
+}
+
+// Test: los errores internos no filtran la causa al cliente
+func TestInternal_HidesCause(t *testing.T) {
+	cause := errors.New("dial tcp 10.0.0.5:3306: connection refused")
+	_, body := ToResponse(Internal(cause))
+
+	if body.Message == cause.Error() {
+		t.Error("Expected internal error message not to leak the cause")
+	}
+	if !errors.Is(Internal(cause), cause) {
+		t.Error("Expected cause to be reachable with errors.Is")
+	}
+}
+
+// Test: los detalles viajan en la respuesta sin modificar el error original
+func TestWithDetails(t *testing.T) {
+	details := []FieldError{{Field: "email", Rule: "email"}}
+	err := ErrValidation.WithDetails(details)
+
+	status, body := ToResponse(err)
+	if status != http.StatusBadRequest || body.Code != "validation_error" {
+		t.Errorf("Unexpected response: %d %+v", status, body)
+	}
+	if got, ok := body.Details.([]FieldError); !ok || got[0].Field != "email" {
+		t.Errorf("Expected field details, got %#v", body.Details)
+	}
+	if ErrValidation.Details != nil {
+		t.Error("Expected sentinel error not to be modified")
+	}
+}
+
+// Test: Translate traduce el formato de Newf y conserva el código
+func TestTranslate(t *testing.T) {
+	spanish := map[string]string{
+		"user not found":              "usuario no encontrado",
+		"%s must be between 1 and %d": "%s tiene que estar entre 1 y %d",
+		"%s is required":              "%s es obligatorio",
+	}
+	translate := func(message string) string {
+		if translated, ok := spanish[message]; ok {
+			return translated
+		}
+		return message
+	}
+
+	err := Newf(CodeBadRequest, "%s must be between 1 and %d", "days", 365)
+	if err.Error() != "days must be between 1 and 365" {
+		t.Errorf("Unexpected message: %q", err.Error())
+	}
+	if got := err.Translate(translate); got.Message != "days tiene que estar entre 1 y 365" || !errors.Is(got, ErrBadRequest) {
+		t.Errorf("Unexpected translation: %+v", got)
+	}
+	if got := NotFound("user not found").Translate(translate); got.Message != "usuario no encontrado" {
+		t.Errorf("Unexpected translation: %q", got.Message)
+	}
+	if got := Conflict("email already exists").Translate(translate); got.Message != "email already exists" {
+		t.Errorf("Expected untranslated message to stay as is, got %q", got.Message)
+	}
+
+	// Los mensajes de cada campo también se traducen, sin tocar el original
+	fields := []FieldError{NewFieldError("email", "required", "", "%s is required", "email")}
+	got := Validation("invalid request body").WithDetails(fields).Translate(translate)
+	if details := got.Details.([]FieldError); details[0].Message != "email es obligatorio" || details[0].Field != "email" {
+		t.Errorf("Unexpected field translation: %+v", details)
+	}
+	if fields[0].Message != "email is required" {
+		t.Errorf("Expected original details not to be modified, got %q", fields[0].Message)
+	}
+}
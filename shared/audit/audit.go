@@ -0,0 +1,162 @@
+// Package audit define los eventos de auditoría de seguridad que emiten
+// todos los servicios (logins, permisos denegados, cambios hechos por admins)
+//
+// Los eventos van al exchange spotly.audit, separado de spotly.events: los
+// consume audit-api, que los guarda para consultas de compliance. La routing
+// key es "<servicio>.<acción>" (ej: "users-api.login.failed")
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"shared/rabbitmq"
+	"shared/requestid"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Exchange es el exchange topic de los eventos de auditoría
+const Exchange = "spotly.audit"
+
+// Acciones estándar (el mismo nombre en todos los servicios)
+const (
+	ActionLoginSucceeded   = "login.succeeded"
+	ActionLoginFailed      = "login.failed"
+	ActionPermissionDenied = "permission.denied"
+	ActionAdminUserUpdated = "admin.user_updated"
+	ActionAdminUserDeleted = "admin.user_deleted"
+	ActionAdminImpersonate = "admin.user_impersonated"
+	ActionAdminUsersMerged = "admin.users_merged"
+	ActionTokensRevoked    = "admin.tokens_revoked"
+	ActionUserBanned       = "admin.user_banned"
+	ActionUserUnbanned     = "admin.user_unbanned"
+	ActionUserInvited      = "admin.user_invited"
+	ActionWebhookCreated   = "admin.webhook_created"
+	ActionWebhookDeleted   = "admin.webhook_deleted"
+	ActionWebhookDelivered = "webhook.delivered"
+	ActionIPDenied         = "ip.denied"
+)
+
+// Resultados posibles de una acción
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+// Event es un evento de auditoría
+// ID, Service, RequestID y OccurredAt los completa el Emitter si vienen vacíos
+type Event struct {
+	ID         string    `json:"id"`
+	Service    string    `json:"service"`
+	Action     string    `json:"action"`
+	Outcome    string    `json:"outcome"`
+	ActorID    *uint     `json:"actor_id,omitempty"` // nil = anónimo
+	ActorName  string    `json:"actor_name,omitempty"`
+	TargetType string    `json:"target_type,omitempty"` // ej: "user", "route"
+	TargetID   string    `json:"target_id,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+
+	// ImpersonatorID es el admin detrás del actor cuando la request usó un
+	// token de impersonación (ver WithImpersonator)
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Emitter publica eventos de auditoría
+// Emit no devuelve error: la auditoría no puede romper la request. Si el
+// evento no se puede publicar queda en el log del servicio
+type Emitter interface {
+	Emit(ctx context.Context, event Event)
+}
+
+type impersonatorKey struct{}
+
+// WithImpersonator marca el contexto de una request hecha con un token de
+// impersonación: todos los eventos emitidos con ese contexto llevan el admin
+// en ImpersonatorID, sin que cada handler tenga que acordarse
+func WithImpersonator(ctx context.Context, adminID uint) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, adminID)
+}
+
+// complete llena los campos que pone el emisor
+func complete(ctx context.Context, service string, event Event) Event {
+	if event.ID == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		event.ID = hex.EncodeToString(b)
+	}
+	if event.Service == "" {
+		event.Service = service
+	}
+	if event.RequestID == "" {
+		event.RequestID = requestid.FromContext(ctx)
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if adminID, ok := ctx.Value(impersonatorKey{}).(uint); ok && event.ImpersonatorID == nil {
+		event.ImpersonatorID = &adminID
+	}
+	return event
+}
+
+// rabbitMQEmitter publica en spotly.audit con confirmación del broker
+type rabbitMQEmitter struct {
+	service   string
+	publisher *rabbitmq.Publisher
+}
+
+// NewRabbitMQEmitter declara el exchange sobre la conexión
+// service es el nombre del servicio que emite (ej: "users-api")
+func NewRabbitMQEmitter(conn *rabbitmq.Connection, service string) (Emitter, error) {
+	publisher, err := rabbitmq.NewPublisher(conn, Exchange)
+	if err != nil {
+		return nil, err
+	}
+	return &rabbitMQEmitter{service: service, publisher: publisher}, nil
+}
+
+// Emit publica el evento; si falla lo deja en el log para no perderlo
+func (e *rabbitMQEmitter) Emit(ctx context.Context, event Event) {
+	event = complete(ctx, e.service, event)
+	body, err := json.Marshal(event)
+	if err != nil {
+		requestid.Logf(ctx, "❌ Evento de auditoría inválido %s: %v", event.Action, err)
+		return
+	}
+
+	err = e.publisher.Publish(ctx, event.Service+"."+event.Action, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID,
+		Body:         body,
+	})
+	if err != nil {
+		requestid.Logf(ctx, "❌ No se pudo publicar el evento de auditoría: %v: %s", err, body)
+	}
+}
+
+// logEmitter solo loguea (cuando el servicio corre sin RabbitMQ)
+type logEmitter struct {
+	service string
+}
+
+// NewLogEmitter crea un emisor que escribe los eventos en el log
+func NewLogEmitter(service string) Emitter {
+	return &logEmitter{service: service}
+}
+
+// Emit loguea el evento en JSON
+func (e *logEmitter) Emit(ctx context.Context, event Event) {
+	body, _ := json.Marshal(complete(ctx, e.service, event))
+	log.Printf("🧾 audit %s", body)
+}
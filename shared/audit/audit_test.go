@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"shared/requestid"
+)
+
+// Test: el emisor completa ID, servicio, request ID y fecha sin pisar lo que viene
+func TestComplete(t *testing.T) {
+	ctx := requestid.WithContext(context.Background(), "req-1")
+
+	event := complete(ctx, "users-api", Event{Action: ActionLoginFailed, Outcome: OutcomeFailure})
+	if len(event.ID) != 32 || event.Service != "users-api" || event.RequestID != "req-1" || event.OccurredAt.IsZero() {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	at := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	event = complete(ctx, "users-api", Event{ID: "fixed", Service: "other", OccurredAt: at})
+	if event.ID != "fixed" || event.Service != "other" || !event.OccurredAt.Equal(at) {
+		t.Errorf("Expected given fields to be kept, got %+v", event)
+	}
+}
+
+// Test: con un contexto de impersonación el evento lleva al admin
+func TestComplete_Impersonator(t *testing.T) {
+	ctx := WithImpersonator(context.Background(), 7)
+
+	event := complete(ctx, "users-api", Event{Action: ActionAdminUserUpdated})
+	if event.ImpersonatorID == nil || *event.ImpersonatorID != 7 {
+		t.Errorf("Expected impersonator 7, got %v", event.ImpersonatorID)
+	}
+
+	event = complete(context.Background(), "users-api", Event{Action: ActionAdminUserUpdated})
+	if event.ImpersonatorID != nil {
+		t.Errorf("Expected no impersonator, got %v", *event.ImpersonatorID)
+	}
+}
@@ -0,0 +1,34 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Claims es la estructura de los datos que users-api guarda en el token
+// Todos los servicios la usan para saber quién hizo la request
+type Claims struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	UserType string `json:"user_type"`
+
+	// ImpersonatorID es el admin que pidió el token para actuar como este
+	// usuario (POST /admin/users/:id/impersonate); nil = login normal
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+
+	// Locale (BCP 47, ej: "es-AR") y Timezone (IANA, ej:
+	// "America/Argentina/Cordoba") del perfil, para que cada servicio
+	// localice sus respuestas sin consultar a users-api. "" = sin definir;
+	// cambian recién en el próximo login
+	Locale   string `json:"locale,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+
+	jwt.RegisteredClaims
+}
+
+// IsAdmin indica si el token es de un administrador
+func (c *Claims) IsAdmin() bool {
+	return c.UserType == "admin"
+}
+
+// Impersonated indica si el token lo generó un admin actuando como el usuario
+func (c *Claims) Impersonated() bool {
+	return c.ImpersonatorID != nil
+}
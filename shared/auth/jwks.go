@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRefetch limita cuán seguido se vuelve a pedir el JWKS cuando llega
+// un kid desconocido (evita que tokens basura generen una request por token)
+const minRefetch = 30 * time.Second
+
+// JWKS descarga y cachea las llaves públicas RSA de un endpoint JWKS
+type JWKS struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKS crea el cliente; las llaves se descargan en el primer uso
+func NewJWKS(url string, refreshInterval time.Duration) *JWKS {
+	return &JWKS{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 5 * time.Second},
+		keys:            make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Keyfunc busca la llave del token por su "kid"
+// Si el kid no está en cache (rotación de llaves) vuelve a descargar el JWKS
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token without kid")
+	}
+
+	j.mu.RLock()
+	key, ok := j.keys[kid]
+	stale := time.Since(j.fetchedAt) > j.refreshInterval
+	canRefetch := time.Since(j.fetchedAt) > minRefetch
+	j.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && !stale && !canRefetch {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	if err := j.refresh(); err != nil {
+		// Si la descarga falla pero teníamos la llave, seguimos usándola
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	key, ok = j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	return key, nil
+}
+
+// jwkSet es el formato JSON del endpoint JWKS (RFC 7517)
+type jwkSet struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// refresh descarga el JWKS y reemplaza las llaves en cache
+func (j *JWKS) refresh() error {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks returned status %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		key, err := parseRSAKey(k.N, k.E)
+		if err != nil {
+			return fmt.Errorf("jwks key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mu.Unlock()
+	return nil
+}
+
+// parseRSAKey arma la llave pública a partir del módulo y exponente en base64url
+func parseRSAKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
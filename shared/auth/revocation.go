@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTokenRevoked se devuelve para un token válido que fue revocado antes de
+// vencer (ej: logout)
+var ErrTokenRevoked = errors.New("token revoked")
+
+// RevocationChecker dice si un token que pasó la validación fue revocado
+// Lo implementa el servicio que emite los tokens (users-api), que es el que
+// sabe de logouts
+type RevocationChecker interface {
+	TokenRevoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+// WithRevocation devuelve una copia del validador que, en ValidateContext,
+// además rechaza los tokens revocados
+func (v *Validator) WithRevocation(checker RevocationChecker) *Validator {
+	copied := *v
+	copied.revocations = checker
+	return &copied
+}
+
+// ValidateContext es Validate más el chequeo de revocación (si el validador
+// tiene uno). Un token revocado da ErrTokenRevoked; si el chequeo falla (ej:
+// la base no responde) se devuelve ese error y el token no se acepta
+func (v *Validator) ValidateContext(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := v.Validate(tokenString)
+	if err != nil || v.revocations == nil {
+		return claims, err
+	}
+
+	revoked, err := v.revocations.TokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MinSecretLength es el largo mínimo de un secret HMAC: el mismo que se
+// exige al arrancar, también para los que llegan rotados
+const MinSecretLength = 32
+
+// DefaultRotationGrace es cuánto se acepta el secret anterior después de
+// una rotación si no se configura (lo que dura un token de login por defecto)
+const DefaultRotationGrace = 24 * time.Hour
+
+// ErrWeakSecret se devuelve al rotar a un secret más corto que MinSecretLength
+var ErrWeakSecret = errors.New("JWT secret must be at least 32 characters")
+
+// Secret es el secret HMAC compartido, que puede rotar sin reiniciar
+// Después de una rotación se sigue aceptando el anterior durante grace (el
+// token más largo más la tolerancia de reloj), así los tokens ya emitidos
+// valen hasta vencer; los nuevos se firman con el actual. Pasado ese tiempo
+// un secret filtrado que se rotó ya no firma tokens válidos
+type Secret struct {
+	mu        sync.RWMutex
+	current   []byte
+	previous  []byte
+	rotatedAt time.Time
+	grace     time.Duration
+	now       func() time.Time // reloj (los tests lo reemplazan)
+}
+
+// NewSecret crea el secret con su valor inicial
+func NewSecret(value string) *Secret {
+	return &Secret{current: []byte(value), grace: DefaultRotationGrace, now: time.Now}
+}
+
+// WithRotationGrace define cuánto se acepta el secret anterior después de
+// rotar; tiene que cubrir el token más largo que se emite más el leeway
+func (s *Secret) WithRotationGrace(grace time.Duration) *Secret {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grace = grace
+	return s
+}
+
+// Set rota el secret (si cambió); el valor actual pasa a ser el anterior
+// Un valor más corto que MinSecretLength se rechaza y sigue el actual
+func (s *Secret) Set(value string) error {
+	if len(value) < MinSecretLength {
+		return ErrWeakSecret
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if string(s.current) == value {
+		return nil
+	}
+	s.previous = s.current
+	s.current = []byte(value)
+	s.rotatedAt = s.now()
+	return nil
+}
+
+// Bytes devuelve el secret actual (para firmar)
+func (s *Secret) Bytes() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// verificationKey devuelve las llaves con las que se aceptan tokens: el
+// actual y, hasta que pase grace desde la rotación, el anterior
+func (s *Secret) verificationKey() interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.previous == nil || s.now().Sub(s.rotatedAt) > s.grace {
+		return s.current
+	}
+	return jwt.VerificationKeySet{Keys: []jwt.VerificationKey{s.current, s.previous}}
+}
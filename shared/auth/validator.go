@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken se devuelve para cualquier token que no pase la validación
+var ErrInvalidToken = errors.New("invalid token")
+
+// Emisor y audiencia por defecto de los tokens de users-api (JWT_ISSUER y
+// JWT_AUDIENCE): los servicios que no los configuran esperan estos
+const (
+	DefaultIssuer   = "users-api"
+	DefaultAudience = "spotly"
+)
+
+// Validator valida tokens emitidos por users-api
+//
+// Soporta dos formas de obtener la llave:
+//   - HMAC (HS256) con un secret compartido (JWT_SECRET)
+//   - RSA (RS256) con las llaves públicas de un endpoint JWKS
+//
+// leeway tolera diferencias de reloj entre servicios al chequear exp/nbf/iat
+type Validator struct {
+	keyFunc jwt.Keyfunc
+	methods []string
+	leeway  time.Duration
+
+	// issuer y audience son opcionales (ver WithIssuer); "" = no se chequea
+	issuer   string
+	audience string
+
+	// revocations es opcional (ver WithRevocation)
+	revocations RevocationChecker
+}
+
+// NewHMACValidator crea un validador para tokens firmados con HS256
+func NewHMACValidator(secret []byte, leeway time.Duration) *Validator {
+	return NewSecretValidator(NewSecret(string(secret)), leeway)
+}
+
+// NewSecretValidator es NewHMACValidator con un secret que puede rotar
+// (ej: cuando lo refresca shared/secrets)
+func NewSecretValidator(secret *Secret, leeway time.Duration) *Validator {
+	return &Validator{
+		keyFunc: func(token *jwt.Token) (interface{}, error) {
+			return secret.verificationKey(), nil
+		},
+		methods: []string{jwt.SigningMethodHS256.Alg()},
+		leeway:  leeway,
+	}
+}
+
+// NewJWKSValidator crea un validador para tokens RS256 cuyas llaves
+// públicas se descargan (y cachean) desde jwksURL
+func NewJWKSValidator(jwksURL string, refreshInterval, leeway time.Duration) *Validator {
+	jwks := NewJWKS(jwksURL, refreshInterval)
+	return &Validator{
+		keyFunc: jwks.Keyfunc,
+		methods: []string{jwt.SigningMethodRS256.Alg()},
+		leeway:  leeway,
+	}
+}
+
+// WithIssuer devuelve una copia del validador que además exige el claim iss
+// igual a issuer y que aud incluya audience ("" = ese claim no se chequea)
+// Así un token firmado con el mismo secret para otro sistema no sirve acá
+func (v *Validator) WithIssuer(issuer, audience string) *Validator {
+	copied := *v
+	copied.issuer = issuer
+	copied.audience = audience
+	return &copied
+}
+
+// Validate parsea el token, verifica firma, algoritmo, vencimiento y, si
+// están configurados, emisor y audiencia, y devuelve los claims
+func (v *Validator) Validate(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	// WithValidMethods evita que un token firmado con otro algoritmo
+	// (ej: "none" o RS256 con el secret como llave pública) sea aceptado
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+	token, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc, options...)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newClaims(expiresIn time.Duration) *Claims {
+	return &Claims{
+		UserID:   7,
+		Username: "testuser",
+		UserType: "normal",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+// Test: token HS256 válido
+func TestHMACValidator_Valid(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(time.Hour)).SignedString(secret)
+
+	claims, err := NewHMACValidator(secret, 0).Validate(token)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.UserID != 7 || claims.Username != "testuser" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+}
+
+// Test: se rechaza un token firmado con otro algoritmo
+func TestHMACValidator_RejectsOtherMethods(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS512, newClaims(time.Hour)).SignedString(secret)
+
+	if _, err := NewHMACValidator(secret, 0).Validate(token); err == nil {
+		t.Error("Expected HS512 token to be rejected")
+	}
+
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, newClaims(time.Hour)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if _, err := NewHMACValidator(secret, 0).Validate(unsigned); err == nil {
+		t.Error("Expected alg=none token to be rejected")
+	}
+}
+
+// revokedUsers es un RevocationChecker de prueba: revoca por usuario
+type revokedUsers map[uint]bool
+
+func (r revokedUsers) TokenRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	return r[claims.UserID], nil
+}
+
+// Test: con WithRevocation, ValidateContext rechaza los tokens revocados
+// y el validador original no cambia
+func TestValidator_WithRevocation(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(time.Hour)).SignedString(secret)
+	validator := NewHMACValidator(secret, 0)
+
+	revoking := validator.WithRevocation(revokedUsers{7: true})
+	if _, err := revoking.ValidateContext(context.Background(), token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+	if _, err := validator.WithRevocation(revokedUsers{}).ValidateContext(context.Background(), token); err != nil {
+		t.Errorf("Expected a token that is not revoked to be valid, got %v", err)
+	}
+	if _, err := validator.ValidateContext(context.Background(), token); err != nil {
+		t.Errorf("Expected the original validator not to check revocations, got %v", err)
+	}
+}
+
+// Test: al rotar el secret se aceptan el nuevo y el anterior, pero no otros
+func TestSecretValidator_Rotation(t *testing.T) {
+	old := strings.Repeat("o", MinSecretLength)
+	secret := NewSecret(old)
+	validator := NewSecretValidator(secret, 0)
+	oldToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(time.Hour)).SignedString([]byte(old))
+
+	if err := secret.Set(strings.Repeat("n", MinSecretLength)); err != nil {
+		t.Fatal(err)
+	}
+	newToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(time.Hour)).SignedString(secret.Bytes())
+	otherToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(time.Hour)).SignedString([]byte("other"))
+
+	if _, err := validator.Validate(newToken); err != nil {
+		t.Errorf("Expected token signed with the new secret to be valid, got %v", err)
+	}
+	if _, err := validator.Validate(oldToken); err != nil {
+		t.Errorf("Expected token signed with the previous secret to be valid, got %v", err)
+	}
+	if _, err := validator.Validate(otherToken); err == nil {
+		t.Error("Expected token signed with an unknown secret to be rejected")
+	}
+}
+
+// Test: el secret anterior deja de valer pasado el grace de la rotación, y
+// no se rota a un secret corto
+func TestSecretValidator_RotationGrace(t *testing.T) {
+	now := time.Now()
+	old := strings.Repeat("o", MinSecretLength)
+	secret := NewSecret(old).WithRotationGrace(time.Hour)
+	secret.now = func() time.Time { return now }
+	validator := NewSecretValidator(secret, 0)
+	oldToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(3*time.Hour)).SignedString([]byte(old))
+
+	if err := secret.Set("corto"); !errors.Is(err, ErrWeakSecret) || string(secret.Bytes()) != old {
+		t.Fatalf("Expected a short secret to be rejected, got %v", err)
+	}
+
+	secret.Set(strings.Repeat("n", MinSecretLength))
+	now = now.Add(59 * time.Minute)
+	if _, err := validator.Validate(oldToken); err != nil {
+		t.Errorf("Expected the previous secret to be valid within the grace, got %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := validator.Validate(oldToken); err == nil {
+		t.Error("Expected the previous secret to be rejected after the grace")
+	}
+}
+
+// Test: el leeway tolera tokens recién vencidos (relojes desfasados)
+func TestHMACValidator_Leeway(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(-10*time.Second)).SignedString(secret)
+
+	if _, err := NewHMACValidator(secret, 0).Validate(token); err == nil {
+		t.Error("Expected expired token to be rejected without leeway")
+	}
+	if _, err := NewHMACValidator(secret, 30*time.Second).Validate(token); err != nil {
+		t.Errorf("Expected token accepted with leeway, got %v", err)
+	}
+}
+
+// Test: con WithIssuer solo pasan los tokens de ese emisor y para esa audiencia
+func TestHMACValidator_IssuerAndAudience(t *testing.T) {
+	secret := []byte("secret")
+	validator := NewHMACValidator(secret, 0).WithIssuer(DefaultIssuer, DefaultAudience)
+	sign := func(issuer string, audience ...string) string {
+		claims := newClaims(time.Hour)
+		claims.Issuer = issuer
+		claims.Audience = audience
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		return token
+	}
+
+	if _, err := validator.Validate(sign(DefaultIssuer, "other", DefaultAudience)); err != nil {
+		t.Errorf("Expected token accepted, got %v", err)
+	}
+	for name, token := range map[string]string{
+		"otro emisor":    sign("other-system", DefaultAudience),
+		"otra audiencia": sign(DefaultIssuer, "other"),
+		"sin iss ni aud": sign(""),
+	} {
+		if _, err := validator.Validate(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	// Sin WithIssuer no se chequean
+	if _, err := NewHMACValidator(secret, 0).Validate(sign("")); err != nil {
+		t.Errorf("Expected token accepted without issuer checks, got %v", err)
+	}
+}
+
+// Test: token RS256 validado con las llaves del endpoint JWKS
+func TestJWKSValidator_Valid(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, newClaims(time.Hour))
+	token.Header["kid"] = "key-1"
+	signed, _ := token.SignedString(key)
+
+	claims, err := NewJWKSValidator(server.URL, time.Hour, 0).Validate(signed)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.UserID != 7 {
+		t.Errorf("Expected user 7, got %d", claims.UserID)
+	}
+}
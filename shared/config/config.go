@@ -0,0 +1,254 @@
+// Package config reemplaza los getEnv de cada servicio: lee variables de
+// entorno (y opcionalmente archivos .env), las parsea con tipo y junta todos
+// los errores para mostrarlos juntos al arrancar
+//
+//	env, err := config.Load(".env")
+//	port := env.String("SERVER_PORT", "8080")
+//	retries := env.Int("MAX_RETRIES", 5)
+//	if err := env.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Env lee variables de entorno tipadas
+// Una variable vacía cuenta como no definida (igual que los viejos getEnv)
+type Env struct {
+	lookup func(key string) (string, bool)
+	errs   []string
+}
+
+// New crea un Env que lee del entorno del proceso
+func New() *Env {
+	return &Env{lookup: os.LookupEnv}
+}
+
+// Load carga los archivos .env indicados y devuelve un Env
+// Los archivos que no existen se ignoran (en Docker no hay .env)
+func Load(files ...string) (*Env, error) {
+	for _, file := range files {
+		if err := LoadDotEnv(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return New(), nil
+}
+
+// LoadDotEnv carga un archivo con líneas KEY=VALUE en el entorno
+// Acepta comentarios (#), líneas vacías, "export KEY=VALUE" y valores entre
+// comillas. Nunca pisa una variable que ya esté definida en el entorno
+func LoadDotEnv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, unquote(strings.TrimSpace(value))); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// unquote saca las comillas que envuelven un valor ("x" o 'x')
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// Overlay hace que las variables se busquen primero en lookup y después en
+// el entorno (ej: secretos de Vault, ver shared/secrets)
+func (e *Env) Overlay(lookup func(key string) (string, bool)) {
+	base := e.lookup
+	e.lookup = func(key string) (string, bool) {
+		if value, ok := lookup(key); ok {
+			return value, true
+		}
+		return base(key)
+	}
+}
+
+// get devuelve el valor y si está definido (no vacío)
+func (e *Env) get(key string) (string, bool) {
+	value, ok := e.lookup(key)
+	if !ok || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// invalid registra un error de parseo
+func (e *Env) invalid(key, value, expected string) {
+	e.errs = append(e.errs, fmt.Sprintf("%s=%q: expected %s", key, value, expected))
+}
+
+// String devuelve la variable o defaultValue si no está definida
+func (e *Env) String(key, defaultValue string) string {
+	if value, ok := e.get(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// Required devuelve la variable y registra un error si no está definida
+func (e *Env) Required(key string) string {
+	value, ok := e.get(key)
+	if !ok {
+		e.errs = append(e.errs, fmt.Sprintf("%s is required", key))
+	}
+	return value
+}
+
+// OneOf devuelve la variable si es uno de los valores permitidos
+func (e *Env) OneOf(key, defaultValue string, allowed ...string) string {
+	value := e.String(key, defaultValue)
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	e.invalid(key, value, "one of "+strings.Join(allowed, ", "))
+	return defaultValue
+}
+
+// Int parsea un entero
+func (e *Env) Int(key string, defaultValue int) int {
+	value, ok := e.get(key)
+	if !ok {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		e.invalid(key, value, "an integer")
+		return defaultValue
+	}
+	return n
+}
+
+// PositiveInt parsea un entero mayor a cero
+func (e *Env) PositiveInt(key string, defaultValue int) int {
+	value, ok := e.get(key)
+	n := e.Int(key, defaultValue)
+	if ok && n <= 0 {
+		e.invalid(key, value, "a positive integer")
+		return defaultValue
+	}
+	return n
+}
+
+// Bool parsea true/false (también 1/0)
+func (e *Env) Bool(key string, defaultValue bool) bool {
+	value, ok := e.get(key)
+	if !ok {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		e.invalid(key, value, "true or false")
+		return defaultValue
+	}
+	return b
+}
+
+// Duration parsea una duración de Go (ej: "30s", "5m", "24h")
+func (e *Env) Duration(key string, defaultValue time.Duration) time.Duration {
+	value, ok := e.get(key)
+	if !ok {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		e.invalid(key, value, `a duration like "30s" or "5m"`)
+		return defaultValue
+	}
+	return d
+}
+
+// Date parsea una fecha "2006-01-02" (UTC); sin valor devuelve la fecha cero
+func (e *Env) Date(key string) time.Time {
+	value, ok := e.get(key)
+	if !ok {
+		return time.Time{}
+	}
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		e.invalid(key, value, `a date like "2027-03-01"`)
+		return time.Time{}
+	}
+	return d
+}
+
+// Seconds parsea un número entero de segundos (para variables *_SECONDS)
+func (e *Env) Seconds(key string, defaultValue int) time.Duration {
+	return time.Duration(e.PositiveInt(key, defaultValue)) * time.Second
+}
+
+// Days parsea un número entero de días (para variables *_DAYS)
+func (e *Env) Days(key string, defaultValue int) time.Duration {
+	return time.Duration(e.PositiveInt(key, defaultValue)) * 24 * time.Hour
+}
+
+// List parsea una lista separada por comas (ignora los elementos vacíos)
+func (e *Env) List(key string, defaultValue []string) []string {
+	value, ok := e.get(key)
+	if !ok {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Check registra un error de validación si ok es false
+// Sirve para reglas entre variables: env.Check(key != "" || url != "", "...")
+func (e *Env) Check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		e.errs = append(e.errs, fmt.Sprintf(format, args...))
+	}
+}
+
+// Err devuelve todos los problemas encontrados juntos (nil si no hubo)
+func (e *Env) Err() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(e.errs, "\n  - "))
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestEnv crea un Env que lee de un mapa en lugar del entorno
+func newTestEnv(values map[string]string) *Env {
+	return &Env{lookup: func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}}
+}
+
+// Test: valores tipados y valores por defecto
+func TestEnv_TypedValues(t *testing.T) {
+	env := newTestEnv(map[string]string{
+		"PORT":    "9090",
+		"DEBUG":   "true",
+		"TIMEOUT": "2m",
+		"RETRY":   "15",
+		"SUNSET":  "2027-03-01",
+		"ORIGINS": "http://a.com, http://b.com,,",
+		"EMPTY":   "",
+	})
+
+	if got := env.String("PORT", "8080"); got != "9090" {
+		t.Errorf("Expected PORT 9090, got %s", got)
+	}
+	if got := env.String("EMPTY", "fallback"); got != "fallback" {
+		t.Errorf("Expected empty value to use default, got %s", got)
+	}
+	if !env.Bool("DEBUG", false) {
+		t.Error("Expected DEBUG true")
+	}
+	if got := env.Duration("TIMEOUT", time.Second); got != 2*time.Minute {
+		t.Errorf("Expected 2m, got %s", got)
+	}
+	if got := env.Seconds("RETRY", 30); got != 15*time.Second {
+		t.Errorf("Expected 15s, got %s", got)
+	}
+	if got := env.Date("SUNSET"); !got.Equal(time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2027-03-01, got %s", got)
+	}
+	if got := env.Date("MISSING"); !got.IsZero() {
+		t.Errorf("Expected zero date, got %s", got)
+	}
+	if got := env.List("ORIGINS", nil); len(got) != 2 || got[1] != "http://b.com" {
+		t.Errorf("Unexpected list: %v", got)
+	}
+	if err := env.Err(); err != nil {
+		t.Errorf("Expected no errors, got %v", err)
+	}
+}
+
+// Test: todos los errores se reportan juntos
+func TestEnv_CollectsErrors(t *testing.T) {
+	env := newTestEnv(map[string]string{
+		"RETRIES":  "abc",
+		"PROVIDER": "fax",
+		"DAYS":     "-1",
+	})
+
+	if got := env.Int("RETRIES", 5); got != 5 {
+		t.Errorf("Expected default on invalid int, got %d", got)
+	}
+	env.OneOf("PROVIDER", "log", "log", "smtp")
+	env.Days("DAYS", 90)
+	env.Required("JWT_SECRET")
+
+	err := env.Err()
+	if err == nil {
+		t.Fatal("Expected configuration error")
+	}
+	for _, key := range []string{"RETRIES", "PROVIDER", "DAYS", "JWT_SECRET"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Expected error to mention %s, got: %v", key, err)
+		}
+	}
+}
+
+// Test: Overlay tiene prioridad sobre el entorno y cae al entorno si no tiene la variable
+func TestEnv_Overlay(t *testing.T) {
+	env := newTestEnv(map[string]string{"DB_PASSWORD": "from-env", "DB_USER": "spotly"})
+	env.Overlay(func(key string) (string, bool) {
+		if key == "DB_PASSWORD" {
+			return "from-vault", true
+		}
+		return "", false
+	})
+
+	if got := env.String("DB_PASSWORD", ""); got != "from-vault" {
+		t.Errorf("Expected overlay value, got %s", got)
+	}
+	if got := env.String("DB_USER", ""); got != "spotly" {
+		t.Errorf("Expected env value, got %s", got)
+	}
+}
+
+// Test: .env carga valores sin pisar el entorno
+func TestLoadDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comentario\n\nCONFIG_TEST_A=1\nexport CONFIG_TEST_B=\"con espacios\"\nCONFIG_TEST_C=del-archivo\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_TEST_C", "del-entorno")
+
+	if err := LoadDotEnv(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		os.Unsetenv("CONFIG_TEST_A")
+		os.Unsetenv("CONFIG_TEST_B")
+	})
+
+	if os.Getenv("CONFIG_TEST_A") != "1" || os.Getenv("CONFIG_TEST_B") != "con espacios" {
+		t.Errorf("Unexpected values: A=%q B=%q", os.Getenv("CONFIG_TEST_A"), os.Getenv("CONFIG_TEST_B"))
+	}
+	if os.Getenv("CONFIG_TEST_C") != "del-entorno" {
+		t.Errorf("Expected existing env to win, got %q", os.Getenv("CONFIG_TEST_C"))
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.env")); err != nil {
+		t.Errorf("Expected missing file to be ignored, got %v", err)
+	}
+}
+
+// Test: línea inválida en el .env
+func TestLoadDotEnv_InvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("NOT_A_PAIR\n"), 0o600)
+
+	err := LoadDotEnv(path)
+	if err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("Expected error with line number, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Package diagnostics expone net/http/pprof y expvar para diagnosticar un
+// servicio en producción (perfiles de CPU y heap, goroutines, memstats)
+//
+// Hay dos formas de montarlo:
+//   - en el router del servicio detrás de auth de admin (Handler)
+//   - en un puerto interno sin auth que no se publica (Serve con DEBUG_ADDR)
+//
+// Un perfil de CPU dura lo que pida ?seconds=; en el router del servicio
+// queda limitado por HTTP_WRITE_TIMEOUT, en el puerto interno no
+package diagnostics
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"shared/openapi"
+)
+
+func init() {
+	// memstats y cmdline ya los publica expvar
+	expvar.Publish("goroutines", expvar.Func(func() interface{} {
+		return runtime.NumGoroutine()
+	}))
+	started := time.Now().UTC()
+	expvar.Publish("uptime_seconds", expvar.Func(func() interface{} {
+		return int64(time.Since(started).Seconds())
+	}))
+}
+
+// Handler sirve /debug/pprof/* y /debug/vars
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index) // heap, goroutine, allocs, block, mutex...
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// Serve levanta Handler en addr (ej: "127.0.0.1:6060") en una goroutine
+// No tiene auth: addr tiene que ser una interfaz o un puerto no publicado
+// Sin WriteTimeout para que entren perfiles largos
+func Serve(addr string) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		log.Printf("🩺 Diagnóstico (pprof, expvar) en %s", addr)
+		if err := srv.ListenAndServe(); err != nil {
+			log.Printf("❌ Diagnóstico detenido: %v", err)
+		}
+	}()
+}
+
+// AddOperations documenta las rutas de Handler en el OpenAPI del servicio
+// (montadas detrás de auth de admin)
+func AddOperations(spec *openapi.Spec) {
+	errors := []int{http.StatusUnauthorized, http.StatusForbidden}
+	spec.Add(openapi.Operation{Method: "GET", Path: "/debug/pprof/*profile", Summary: "Perfiles de pprof (heap, goroutine, profile?seconds=30...)", Tags: []string{"debug"},
+		Auth: true, Errors: errors})
+	spec.Add(openapi.Operation{Method: "POST", Path: "/debug/pprof/symbol", Summary: "Resolver símbolos de pprof", Tags: []string{"debug"},
+		Auth: true, Errors: errors})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/debug/vars", Summary: "Variables de expvar (memstats, goroutines, uptime)", Tags: []string{"debug"},
+		Auth: true, Reply: map[string]interface{}{}, Errors: errors})
+}
@@ -0,0 +1,34 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test: /debug/vars incluye las variables propias y las de expvar
+func TestHandler_Vars(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	var vars map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&vars); err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	for _, name := range []string{"goroutines", "uptime_seconds", "memstats"} {
+		if _, ok := vars[name]; !ok {
+			t.Errorf("Expected %s in /debug/vars", name)
+		}
+	}
+}
+
+// Test: los perfiles de pprof responden
+func TestHandler_Pprof(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Errorf("Expected a heap profile, got %d", rec.Code)
+	}
+}
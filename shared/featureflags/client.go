@@ -0,0 +1,88 @@
+package featureflags
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Client evalúa flags y los cachea en memoria
+// Cada refreshInterval vuelve a leer la fuente, así un cambio en el
+// archivo (o en la base) se aplica sin reiniciar el servicio
+type Client struct {
+	source          Source
+	refreshInterval time.Duration
+
+	mu       sync.RWMutex
+	flags    map[string]Flag
+	loadedAt time.Time
+}
+
+// NewClient crea el cliente y hace la primera carga
+// Si la primera carga falla devuelve el error (mejor fallar al arrancar)
+func NewClient(source Source, refreshInterval time.Duration) (*Client, error) {
+	flags, err := source.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		source:          source,
+		refreshInterval: refreshInterval,
+		flags:           flags,
+		loadedAt:        time.Now(),
+	}, nil
+}
+
+// IsEnabled indica si el flag está prendido para el usuario
+// Un flag que no existe está apagado
+func (c *Client) IsEnabled(name, userKey string) bool {
+	flag, ok := c.snapshot()[name]
+	if !ok {
+		return false
+	}
+	return flag.IsEnabledFor(name, userKey)
+}
+
+// EnabledFlags devuelve los nombres de los flags prendidos para el usuario (ordenados)
+// Lo usa el endpoint que le dice al frontend qué mostrar
+func (c *Client) EnabledFlags(userKey string) []string {
+	enabled := []string{}
+	for name, flag := range c.snapshot() {
+		if flag.IsEnabledFor(name, userKey) {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
+// snapshot devuelve los flags cacheados, recargándolos si están vencidos
+// Si la recarga falla se siguen usando los últimos flags válidos
+func (c *Client) snapshot() map[string]Flag {
+	c.mu.RLock()
+	flags, stale := c.flags, time.Since(c.loadedAt) >= c.refreshInterval
+	c.mu.RUnlock()
+
+	if !stale {
+		return flags
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Otra goroutine pudo haber recargado mientras esperábamos el lock
+	if time.Since(c.loadedAt) < c.refreshInterval {
+		return c.flags
+	}
+
+	fresh, err := c.source.Load()
+	c.loadedAt = time.Now()
+	if err != nil {
+		log.Printf("⚠️  No se pudieron recargar los feature flags, se usan los anteriores: %v", err)
+		return c.flags
+	}
+	c.flags = fresh
+	return c.flags
+}
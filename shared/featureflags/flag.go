@@ -0,0 +1,57 @@
+package featureflags
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// Flag es la configuración de un feature flag
+//
+//	{"enabled": true, "percentage": 25, "users": ["1", "42"]}
+//
+// - enabled: interruptor general; si es false el flag está apagado para todos
+// - percentage: porcentaje de usuarios (0-100) que lo ven prendido
+// - users: usuarios que siempre lo ven prendido (targeting), sin importar el porcentaje
+type Flag struct {
+	Enabled    bool     `json:"enabled"`
+	Percentage int      `json:"percentage"`
+	Users      []string `json:"users,omitempty"`
+}
+
+// IsEnabledFor evalúa el flag para un usuario
+// userKey vacío representa un usuario anónimo: solo ve flags al 100%
+func (f Flag) IsEnabledFor(name, userKey string) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	for _, user := range f.Users {
+		if user == userKey && userKey != "" {
+			return true
+		}
+	}
+
+	if f.Percentage >= 100 {
+		return true
+	}
+	if f.Percentage <= 0 || userKey == "" {
+		return false
+	}
+	return bucket(name, userKey) < f.Percentage
+}
+
+// bucket asigna al usuario un número estable entre 0 y 99 para el flag
+// Usa el nombre del flag en el hash para que cada flag tenga su propio 25%
+func bucket(name, userKey string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userKey))
+	return int(h.Sum32() % 100)
+}
+
+// UserKey convierte un ID numérico de usuario en la clave usada para evaluar flags
+func UserKey(userID uint) string {
+	if userID == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,79 @@
+package featureflags
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Test: un flag apagado no se prende ni para usuarios targeteados
+func TestIsEnabledFor_Disabled(t *testing.T) {
+	flag := Flag{Enabled: false, Percentage: 100, Users: []string{"1"}}
+
+	if flag.IsEnabledFor("new_search", "1") {
+		t.Error("Expected disabled flag to be off")
+	}
+}
+
+// Test: los usuarios targeteados lo ven aunque el porcentaje sea 0
+func TestIsEnabledFor_TargetedUser(t *testing.T) {
+	flag := Flag{Enabled: true, Percentage: 0, Users: []string{"42"}}
+
+	if !flag.IsEnabledFor("new_search", "42") {
+		t.Error("Expected targeted user to see the flag")
+	}
+	if flag.IsEnabledFor("new_search", "43") {
+		t.Error("Expected non-targeted user not to see the flag")
+	}
+}
+
+// Test: el rollout por porcentaje es estable y aproximado
+func TestIsEnabledFor_PercentageRollout(t *testing.T) {
+	flag := Flag{Enabled: true, Percentage: 25}
+
+	enabled := 0
+	for i := 1; i <= 10000; i++ {
+		key := strconv.Itoa(i)
+		first := flag.IsEnabledFor("new_search", key)
+		if first != flag.IsEnabledFor("new_search", key) {
+			t.Fatalf("Expected stable result for user %s", key)
+		}
+		if first {
+			enabled++
+		}
+	}
+
+	if enabled < 2200 || enabled > 2800 {
+		t.Errorf("Expected about 25%% of users enabled, got %d/10000", enabled)
+	}
+}
+
+// Test: los anónimos solo ven flags al 100%
+func TestIsEnabledFor_Anonymous(t *testing.T) {
+	if (Flag{Enabled: true, Percentage: 50}).IsEnabledFor("new_search", "") {
+		t.Error("Expected anonymous user not to see a partial rollout")
+	}
+	if !(Flag{Enabled: true, Percentage: 100}).IsEnabledFor("new_search", "") {
+		t.Error("Expected anonymous user to see a full rollout")
+	}
+}
+
+// Test: el cliente devuelve los flags prendidos ordenados
+func TestClient_EnabledFlags(t *testing.T) {
+	client, err := NewClient(NewStaticSource(map[string]Flag{
+		"b_flag": {Enabled: true, Percentage: 100},
+		"a_flag": {Enabled: true, Percentage: 100},
+		"off":    {Enabled: false, Percentage: 100},
+	}), time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	enabled := client.EnabledFlags("1")
+	if len(enabled) != 2 || enabled[0] != "a_flag" || enabled[1] != "b_flag" {
+		t.Errorf("Expected [a_flag b_flag], got %v", enabled)
+	}
+	if client.IsEnabled("missing", "1") {
+		t.Error("Expected unknown flag to be off")
+	}
+}
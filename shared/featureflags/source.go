@@ -0,0 +1,53 @@
+package featureflags
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Source es de donde se leen los flags (archivo, base de datos, servicio de config)
+type Source interface {
+	Load() (map[string]Flag, error)
+}
+
+// fileSource lee los flags de un archivo JSON: {"nombre_flag": {...}, ...}
+type fileSource struct {
+	path string
+}
+
+// NewFileSource crea una fuente que lee un archivo JSON
+// Si el archivo no existe no es un error: no hay flags definidos
+func NewFileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+// Load lee y parsea el archivo
+func (s *fileSource) Load() (map[string]Flag, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Flag{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	flags := map[string]Flag{}
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// staticSource devuelve siempre los mismos flags (útil en tests)
+type staticSource map[string]Flag
+
+// NewStaticSource crea una fuente con flags fijos
+func NewStaticSource(flags map[string]Flag) Source {
+	return staticSource(flags)
+}
+
+// Load devuelve los flags fijos
+func (s staticSource) Load() (map[string]Flag, error) {
+	return s, nil
+}
@@ -0,0 +1,75 @@
+// Package graceful arranca y frena los servidores HTTP sin cortar requests
+//
+// Run escucha con SO_REUSEPORT (en Linux), así un binario nuevo puede tomar
+// el mismo puerto mientras el viejo sigue atendiendo. Al recibir SIGTERM,
+// SIGINT o SIGUSR2 el servidor deja de aceptar conexiones, espera a que
+// terminen las requests en curso y llama a las funciones de stop (ej: frenar
+// el consumidor de RabbitMQ después del mensaje que está procesando)
+//
+// Deploy sin downtime en la misma máquina:
+//
+//	./users-api &            # el nuevo toma el puerto junto al viejo
+//	kill -USR2 <pid viejo>   # el viejo drena y sale
+package graceful
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// StopFunc libera algo al apagar; debe volver antes de que venza ctx
+type StopFunc func(ctx context.Context) error
+
+// Listen abre el puerto TCP, con SO_REUSEPORT donde el sistema lo soporta
+func Listen(addr string) (net.Listener, error) {
+	lc := net.ListenConfig{Control: reusePort}
+	return lc.Listen(context.Background(), "tcp", addr)
+}
+
+// Run sirve srv hasta recibir una señal de apagado y después drena:
+//  1. deja de aceptar conexiones y espera las requests en curso (srv.Shutdown)
+//  2. llama a cada stop en orden
+//
+// Todo tiene que terminar dentro de drainTimeout; lo que quede se corta
+// Devuelve nil si el apagado fue limpio
+func Run(srv *http.Server, drainTimeout time.Duration, stops ...StopFunc) error {
+	listener, err := Listen(srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, stopSignals...)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-signals:
+		log.Printf("🛑 Señal %s: drenando (hasta %s)...", sig, drainTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	errs := []error{srv.Shutdown(ctx)}
+	for _, stop := range stops {
+		errs = append(errs, stop(ctx))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	log.Println("👋 Apagado limpio")
+	return nil
+}
@@ -0,0 +1,79 @@
+package graceful
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Test: con SO_REUSEPORT dos procesos (acá dos listeners) comparten el puerto
+func TestListen_ReusePort(t *testing.T) {
+	first, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := Listen(first.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected the port to be shared, got %v", err)
+	}
+	second.Close()
+}
+
+// Test: al llegar SIGUSR2 la request en curso termina y después se llama a los stops
+func TestRun_DrainsInFlightRequests(t *testing.T) {
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	started := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, "done")
+	})}
+
+	stopped := false
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- Run(srv, 5*time.Second, func(ctx context.Context) error {
+			stopped = true
+			return nil
+		})
+	}()
+
+	response := make(chan string, 1)
+	go func() {
+		for {
+			resp, err := http.Get("http://" + addr)
+			if err != nil {
+				time.Sleep(10 * time.Millisecond) // todavía no escucha
+				continue
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			response <- string(body)
+			return
+		}
+	}()
+
+	<-started
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+
+	if got := <-response; got != "done" {
+		t.Errorf("Expected the in-flight request to finish, got %q", got)
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if !stopped {
+		t.Error("Expected the stop function to be called")
+	}
+}
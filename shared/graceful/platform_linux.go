@@ -0,0 +1,25 @@
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// soReusePort es SO_REUSEPORT en Linux (el paquete syscall no lo define)
+const soReusePort = 0xf
+
+// stopSignals disparan el drenado; SIGUSR2 es la de los reinicios sin downtime
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGUSR2}
+
+// reusePort deja que otro proceso escuche en el mismo puerto a la vez
+// El kernel reparte las conexiones nuevas entre los dos
+func reusePort(network, address string, conn syscall.RawConn) error {
+	var sockErr error
+	err := conn.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
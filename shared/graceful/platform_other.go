@@ -0,0 +1,16 @@
+//go:build !linux
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// Fuera de Linux no hay SO_REUSEPORT ni SIGUSR2: el drenado igual funciona
+// con SIGTERM/SIGINT, pero el binario nuevo tiene que esperar al puerto
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+func reusePort(network, address string, conn syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package health arma los chequeos de disponibilidad de los servicios
+//
+// Cada servicio expone GET /readyz con un Checker (base, broker, etc.) y
+// status-api junta los /readyz de todos en un único documento de estado.
+// Un chequeo "requerido" que falla deja el reporte en down (503); uno
+// "opcional" solo lo deja en degraded (200): el servicio sigue atendiendo
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Estados posibles de un chequeo o de un reporte
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// CheckFunc devuelve nil si la dependencia está disponible
+type CheckFunc func(ctx context.Context) error
+
+// Result es el resultado de un chequeo
+type Result struct {
+	Status    string `json:"status"`
+	Required  bool   `json:"required"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report es el documento que devuelve /readyz (y /status en status-api)
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// HTTPStatus es 503 si el reporte está down y 200 en otro caso
+func (r Report) HTTPStatus() int {
+	if r.Status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+type check struct {
+	fn       CheckFunc
+	required bool
+}
+
+// Checker corre un conjunto de chequeos con nombre
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]check
+}
+
+// New crea un Checker; timeout es el máximo por chequeo
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: make(map[string]check)}
+}
+
+// Add registra un chequeo requerido: si falla el reporte queda down
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.add(name, fn, true)
+}
+
+// AddOptional registra un chequeo opcional: si falla el reporte queda degraded
+func (c *Checker) AddOptional(name string, fn CheckFunc) {
+	c.add(name, fn, false)
+}
+
+func (c *Checker) add(name string, fn CheckFunc, required bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check{fn: fn, required: required}
+}
+
+// Names devuelve los nombres de los chequeos en orden alfabético
+func (c *Checker) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run corre todos los chequeos en paralelo y arma el reporte
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]check, len(c.checks))
+	for name, chk := range c.checks {
+		checks[name] = chk
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks)), CheckedAt: time.Now().UTC()}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, chk := range checks {
+		wg.Add(1)
+		go func(name string, chk check) {
+			defer wg.Done()
+			result := c.runOne(ctx, chk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status == StatusDown {
+				if chk.required {
+					report.Status = StatusDown
+				} else if report.Status == StatusUp {
+					report.Status = StatusDegraded
+				}
+			}
+		}(name, chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runOne corre un chequeo con timeout; un panic cuenta como falla
+func (c *Checker) runOne(ctx context.Context, chk check) (result Result) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result = Result{Status: StatusUp, Required: chk.required}
+	defer func() {
+		if rec := recover(); rec != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("panic: %v", rec)
+		}
+		result.LatencyMS = time.Since(start).Milliseconds()
+	}()
+
+	if err := chk.fn(ctx); err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler responde el reporte en JSON (503 si está down)
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteReport(w, c.Run(r.Context()))
+	})
+}
+
+// WriteReport escribe el reporte como JSON con su status HTTP
+func WriteReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(report.HTTPStatus())
+	json.NewEncoder(w).Encode(report)
+}
+
+// Pinger es lo que implementa *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingCheck chequea una base de datos con PingContext
+func PingCheck(db Pinger) CheckFunc {
+	return db.PingContext
+}
+
+// TCPCheck chequea que se pueda abrir una conexión TCP (RabbitMQ, Memcached)
+func TCPCheck(addr string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// HTTPCheck hace un GET y espera un 2xx (ej: el /readyz de otro servicio, Solr)
+// client puede ser nil (usa http.DefaultClient)
+func HTTPCheck(client *http.Client, url string) CheckFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
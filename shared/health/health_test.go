@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func ok(ctx context.Context) error   { return nil }
+func fail(ctx context.Context) error { return errors.New("boom") }
+
+func TestRun_AllUp(t *testing.T) {
+	c := New(time.Second)
+	c.Add("db", ok)
+	c.AddOptional("cache", ok)
+
+	report := c.Run(context.Background())
+	if report.Status != StatusUp {
+		t.Errorf("expected up, got %s", report.Status)
+	}
+	if len(report.Checks) != 2 {
+		t.Errorf("expected 2 checks, got %d", len(report.Checks))
+	}
+}
+
+func TestRun_OptionalDownIsDegraded(t *testing.T) {
+	c := New(time.Second)
+	c.Add("db", ok)
+	c.AddOptional("cache", fail)
+
+	report := c.Run(context.Background())
+	if report.Status != StatusDegraded {
+		t.Errorf("expected degraded, got %s", report.Status)
+	}
+	if report.Checks["cache"].Error != "boom" {
+		t.Errorf("expected error to be reported, got %+v", report.Checks["cache"])
+	}
+	if report.HTTPStatus() != http.StatusOK {
+		t.Errorf("expected 200, got %d", report.HTTPStatus())
+	}
+}
+
+func TestRun_RequiredDownIsDown(t *testing.T) {
+	c := New(time.Second)
+	c.Add("db", fail)
+	c.AddOptional("cache", fail)
+
+	report := c.Run(context.Background())
+	if report.Status != StatusDown {
+		t.Errorf("expected down, got %s", report.Status)
+	}
+	if report.HTTPStatus() != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", report.HTTPStatus())
+	}
+}
+
+func TestRun_TimeoutAndPanic(t *testing.T) {
+	c := New(20 * time.Millisecond)
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	c.Add("panics", func(ctx context.Context) error { panic("oops") })
+
+	report := c.Run(context.Background())
+	if report.Checks["slow"].Status != StatusDown || report.Checks["panics"].Status != StatusDown {
+		t.Errorf("expected both checks down, got %+v", report.Checks)
+	}
+}
+
+func TestHandler_And_HTTPCheck(t *testing.T) {
+	upstream := New(time.Second)
+	upstream.Add("db", fail)
+	srv := httptest.NewServer(upstream.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var report Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Errorf("expected 503/down, got %d/%s", resp.StatusCode, report.Status)
+	}
+
+	// Un /readyz en 503 hace fallar el HTTPCheck del agregador
+	if err := HTTPCheck(nil, srv.URL)(context.Background()); err == nil {
+		t.Error("expected HTTPCheck to fail on 503")
+	}
+}
@@ -0,0 +1,153 @@
+package httpmw
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"shared/apperrors"
+	"shared/audit"
+)
+
+// ErrIPNotAllowed es la respuesta a una IP fuera de la allowlist
+var ErrIPNotAllowed = apperrors.Forbidden("your IP address is not allowed to access this route")
+
+// IPAllowlist deja pasar solo a las IPs de ciertas redes (CIDR)
+// Es una defensa extra para las rutas de admin: un token de admin robado no
+// sirve desde fuera de la red de la oficina o la VPN
+//
+// La IP sale de RemoteAddr. X-Forwarded-For solo se usa si la conexión viene
+// de un proxy de confianza: se recorre de derecha a izquierda salteando los
+// proxies y la primera IP que no es proxy es el cliente (las de la izquierda
+// las puede inventar cualquiera)
+type IPAllowlist struct {
+	allowed []netip.Prefix
+	proxies []netip.Prefix
+}
+
+// NewIPAllowlist parsea las redes permitidas y los proxies de confianza
+// Acepta CIDRs ("10.0.0.0/8") o IPs sueltas ("203.0.113.7")
+// Sin redes permitidas devuelve nil: una allowlist nil no restringe nada
+func NewIPAllowlist(allowed, trustedProxies []string) (*IPAllowlist, error) {
+	if len(allowed) == 0 {
+		return nil, nil
+	}
+
+	l := &IPAllowlist{}
+	var err error
+	if l.allowed, err = parsePrefixes(allowed); err != nil {
+		return nil, err
+	}
+	if l.proxies, err = parsePrefixes(trustedProxies); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// parsePrefixes parsea CIDRs o IPs sueltas (como /32 o /128)
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP or CIDR %q", value)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR %q", value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// contains indica si la IP está en alguna de las redes
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP devuelve la IP del cliente según los proxies de confianza
+// (inválida si RemoteAddr o X-Forwarded-For no se pueden parsear)
+func (l *IPAllowlist) ClientIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	addr = addr.Unmap()
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0 && contains(l.proxies, addr); i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if addr, err = netip.ParseAddr(hop); err != nil {
+			return netip.Addr{}
+		}
+		addr = addr.Unmap()
+	}
+	return addr
+}
+
+// Allows indica si la request viene de una red permitida y con qué IP
+// Una allowlist nil permite todo
+func (l *IPAllowlist) Allows(r *http.Request) (string, bool) {
+	if l == nil {
+		return ClientIP(r), true
+	}
+	addr := l.ClientIP(r)
+	if !addr.IsValid() {
+		return r.RemoteAddr, false
+	}
+	return addr.String(), contains(l.allowed, addr)
+}
+
+// IPDeniedEvent arma el evento de una request cortada por la allowlist
+func IPDeniedEvent(r *http.Request, ip string) audit.Event {
+	return audit.Event{
+		Action:     audit.ActionIPDenied,
+		Outcome:    audit.OutcomeDenied,
+		TargetType: "route",
+		TargetID:   r.Method + " " + r.URL.Path,
+		IP:         ip,
+		Metadata:   map[string]interface{}{"status": http.StatusForbidden},
+	}
+}
+
+// AllowIPs responde 403 a las requests de fuera de la allowlist, antes de
+// mirar el token, y emite un evento ip.denied (AuditDenied no lo repite)
+// Con una allowlist nil no hace nada
+func AllowIPs(list *IPAllowlist, emitter audit.Emitter) Middleware {
+	return func(next http.Handler) http.Handler {
+		if list == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, ok := list.Allows(r)
+			if !ok {
+				emitter.Emit(r.Context(), IPDeniedEvent(r, ip))
+				MarkAudited(r.Context())
+				WriteError(w, r, ErrIPNotAllowed)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"shared/audit"
+)
+
+// DeniedEvent arma el evento permission.denied de una request rechazada
+// con 401 o 403; el usuario sale de los claims si llegó a autenticarse
+func DeniedEvent(r *http.Request, status int) audit.Event {
+	event := audit.Event{
+		Action:     audit.ActionPermissionDenied,
+		Outcome:    audit.OutcomeDenied,
+		TargetType: "route",
+		TargetID:   r.Method + " " + r.URL.Path,
+		IP:         ClientIP(r),
+		Metadata:   map[string]interface{}{"status": status},
+	}
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		event.ActorID = &claims.UserID
+		event.ActorName = claims.Username
+	}
+	return event
+}
+
+type auditedKey struct{}
+
+// WithAuditMark agrega al contexto una marca que los middlewares de adentro
+// prenden con MarkAudited
+func WithAuditMark(ctx context.Context) (context.Context, *bool) {
+	audited := new(bool)
+	return context.WithValue(ctx, auditedKey{}, audited), audited
+}
+
+// MarkAudited avisa a AuditDenied que el rechazo ya quedó auditado con un
+// evento más específico, así no emite además el permission.denied genérico
+func MarkAudited(ctx context.Context) {
+	if audited, ok := ctx.Value(auditedKey{}).(*bool); ok {
+		*audited = true
+	}
+}
+
+// AuditDenied emite un evento de auditoría por cada 401 o 403
+// Va antes de Auth/Admin para ver las respuestas que ellos cortan. En net/http
+// Auth guarda los claims en una request nueva: el evento sale sin usuario
+// (la versión de ginmw sí lo ve)
+func AuditDenied(emitter audit.Emitter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, audited := WithAuditMark(r.Context())
+			r = r.WithContext(ctx)
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if *audited {
+				return
+			}
+			if recorder.status == http.StatusUnauthorized || recorder.status == http.StatusForbidden {
+				emitter.Emit(r.Context(), DeniedEvent(r, recorder.status))
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package httpmw
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/auth"
+)
+
+type claimsKey struct{}
+
+// WithClaims guarda los claims del JWT en el contexto
+// Con un token de impersonación marca además el contexto para que los
+// eventos de auditoría lleven al admin (ver audit.WithImpersonator)
+func WithClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	if claims.Impersonated() {
+		ctx = audit.WithImpersonator(ctx, *claims.ImpersonatorID)
+	}
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext devuelve los claims del usuario autenticado (si hay)
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*auth.Claims)
+	return claims, ok
+}
+
+// Authenticate lee el header "Authorization: Bearer <token>" y valida el JWT
+// (y que no esté revocado, si el validador chequea revocaciones)
+// Los errores ya vienen como apperrors.Unauthorized con el mensaje para el
+// cliente, salvo que falle el chequeo de revocación (error interno)
+func Authenticate(ctx context.Context, validator *auth.Validator, header string) (*auth.Claims, error) {
+	if header == "" {
+		return nil, apperrors.Unauthorized("authorization header required")
+	}
+
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, apperrors.Unauthorized("invalid authorization header format")
+	}
+
+	claims, err := validator.ValidateContext(ctx, parts[1])
+	switch {
+	case errors.Is(err, auth.ErrTokenRevoked):
+		return nil, apperrors.Unauthorized("token has been revoked")
+	case errors.Is(err, auth.ErrInvalidToken):
+		return nil, apperrors.Unauthorized("invalid or expired token")
+	case err != nil:
+		return nil, apperrors.Internal(err)
+	}
+	return claims, nil
+}
+
+// ErrImpersonationReadOnly es la respuesta a un token de impersonación en
+// una request que cambia datos
+var ErrImpersonationReadOnly = apperrors.Forbidden("impersonation tokens are read-only")
+
+// CheckScope valida que el token sirva para el método de la request
+// Un token de impersonación (soporte viendo lo que ve el usuario) es de solo
+// lectura en todos los servicios: pasa en GET, HEAD y OPTIONS y nada más,
+// así el admin no puede cambiar el perfil, el teléfono ni cerrar la sesión
+// en nombre del usuario
+func CheckScope(claims *auth.Claims, method string) error {
+	if !claims.Impersonated() {
+		return nil
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+	return ErrImpersonationReadOnly
+}
+
+// Auth exige un JWT válido (y que alcance para el método, ver CheckScope) y
+// guarda los claims en el contexto
+func Auth(validator *auth.Validator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := Authenticate(r.Context(), validator, r.Header.Get("Authorization"))
+			if err == nil {
+				err = CheckScope(claims, r.Method)
+			}
+			if err != nil {
+				WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// OptionalAuth es como Auth pero no rechaza la request:
+// con un token válido guarda los claims, si no sigue como anónimo (también
+// con un token de impersonación en una request que cambia datos)
+func OptionalAuth(validator *auth.Validator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims, err := Authenticate(r.Context(), validator, r.Header.Get("Authorization")); err == nil && CheckScope(claims, r.Method) == nil {
+				r = r.WithContext(WithClaims(r.Context(), claims))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CheckAdmin valida que los claims sean de un administrador
+func CheckAdmin(claims *auth.Claims, ok bool) error {
+	if !ok {
+		return apperrors.Unauthorized("user type not found")
+	}
+	if !claims.IsAdmin() {
+		return apperrors.Forbidden("admin privileges required")
+	}
+	return nil
+}
+
+// Admin exige que el usuario sea admin (va después de Auth)
+func Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := CheckAdmin(ClaimsFromContext(r.Context())); err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServiceTokenHeader lleva el token con el que un servicio interno llama a
+// otro (ej: notifications-api pidiendo preferencias a users-api)
+const ServiceTokenHeader = "X-Service-Token"
+
+// CheckServiceToken compara el token recibido con el configurado (en tiempo
+// constante). Sin token configurado rechaza todo: la ruta queda cerrada
+func CheckServiceToken(expected, got string) error {
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(got)) != 1 {
+		return apperrors.Unauthorized("valid service token required")
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package httpmw
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"shared/requestid"
+)
+
+// DefaultBodyLogMax es cuánto de cada body se loguea por defecto
+const DefaultBodyLogMax = 4 << 10
+
+// redacted reemplaza a los valores sensibles en el log
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders nunca se loguean con su valor
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", ServiceTokenHeader}
+
+// sensitiveKey indica si un campo JSON, de formulario o de query es sensible
+// Compara sin mayúsculas y por contenido: "password", "new_password",
+// "access_token", "client_secret"... "code" (códigos por SMS) va exacto para
+// no tapar "postal_code" o "country_code"
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "code" || key == "otp" {
+		return true
+	}
+	for _, part := range []string{"password", "token", "secret", "authorization", "api_key", "apikey", "cookie"} {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeaders devuelve una copia de los headers con los sensibles tapados
+func RedactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// RedactQuery tapa los parámetros de query sensibles (ej: ?token=...)
+func RedactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for key := range values {
+		if sensitiveKey(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
+
+// RedactBody devuelve el body listo para loguear
+// JSON y formularios se loguean con los campos sensibles tapados; cualquier
+// otra cosa (o un body que no se puede parsear, por ejemplo porque se cortó
+// en el máximo) se resume en su tamaño, así nunca se filtra nada sin revisar
+func RedactBody(contentType string, body []byte, truncated bool) string {
+	if len(body) == 0 {
+		return ""
+	}
+	summary := fmt.Sprintf("<%d bytes %s>", len(body), contentType)
+	if truncated {
+		return strings.Replace(summary, " bytes", "+ bytes", 1)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return summary
+		}
+		out, err := json.Marshal(redactValue(value))
+		if err != nil {
+			return summary
+		}
+		return string(out)
+	case mediaType == "application/x-www-form-urlencoded":
+		return RedactQuery(string(body))
+	}
+	return summary
+}
+
+// redactValue recorre el JSON y tapa los campos sensibles a cualquier nivel
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// BodyCapture guarda hasta max bytes de lo que se escribe
+type BodyCapture struct {
+	max       int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+// NewBodyCapture crea una captura de hasta max bytes (<= 0 = DefaultBodyLogMax)
+func NewBodyCapture(max int) *BodyCapture {
+	if max <= 0 {
+		max = DefaultBodyLogMax
+	}
+	return &BodyCapture{max: max}
+}
+
+// Write nunca falla: lo que pasa del máximo se descarta
+func (c *BodyCapture) Write(p []byte) (int, error) {
+	if room := c.max - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		p = p[:max(room, 0)]
+	}
+	c.buf.Write(p)
+	return len(p), nil
+}
+
+// ReadRequestBody lee hasta max bytes del body y lo deja intacto para el handler
+func ReadRequestBody(r *http.Request, max int) *BodyCapture {
+	capture := NewBodyCapture(max)
+	if r.Body == nil || r.Body == http.NoBody {
+		return capture
+	}
+	head, _ := io.ReadAll(io.LimitReader(r.Body, int64(capture.max)+1))
+	capture.Write(head)
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	return capture
+}
+
+// LogBodies escribe la request y la respuesta con lo sensible tapado
+// Las líneas llevan 🐛: con shared/logging solo se ven en nivel debug
+func LogBodies(r *http.Request, reqBody *BodyCapture, status int, respHeader http.Header, respBody *BodyCapture) {
+	target := r.URL.Path
+	if r.URL.RawQuery != "" {
+		target += "?" + RedactQuery(r.URL.RawQuery)
+	}
+	requestid.Logf(r.Context(), "🐛 [HTTP] --> %s %s headers=%v body=%s", r.Method, target,
+		RedactHeaders(r.Header), RedactBody(r.Header.Get("Content-Type"), reqBody.buf.Bytes(), reqBody.truncated))
+	requestid.Logf(r.Context(), "🐛 [HTTP] <-- %d %s headers=%v body=%s", status, target,
+		RedactHeaders(respHeader), RedactBody(respHeader.Get("Content-Type"), respBody.buf.Bytes(), respBody.truncated))
+}
+
+// bodyRecorder copia la respuesta a una captura además de escribirla
+type bodyRecorder struct {
+	http.ResponseWriter
+	status  int
+	capture *BodyCapture
+}
+
+func (w *bodyRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.capture.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// BodyLog loguea headers y bodies de cada request y respuesta, con
+// contraseñas, tokens y el header Authorization tapados
+// Es para diagnosticar integraciones de clientes: se prende con
+// LOG_HTTP_BODIES y las líneas solo salen con LOG_LEVEL=debug
+// Se loguean hasta maxBytes de cada body (<= 0 = DefaultBodyLogMax)
+func BodyLog(maxBytes int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBody := ReadRequestBody(r, maxBytes)
+			recorder := &bodyRecorder{ResponseWriter: w, status: http.StatusOK, capture: NewBodyCapture(maxBytes)}
+
+			next.ServeHTTP(recorder, r)
+
+			LogBodies(r, reqBody, recorder.status, w.Header(), recorder.capture)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package httpmw
+
+import (
+	"net/http"
+	"strings"
+
+	"shared/requestid"
+)
+
+// CORSConfig define qué orígenes, métodos y headers se aceptan
+type CORSConfig struct {
+	AllowedOrigins []string // "*" acepta cualquiera
+	AllowedMethods []string
+	AllowedHeaders []string
+	ExposedHeaders []string
+}
+
+// DefaultCORS es la configuración de todos los servicios: cualquier origen,
+// los headers que manda el frontend (incluido Idempotency-Key) y el
+// X-Request-ID visible desde JS
+func DefaultCORS(methods ...string) CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: append(methods, http.MethodOptions),
+		AllowedHeaders: []string{"Content-Type", "Authorization", "Idempotency-Key", requestid.Header},
+		ExposedHeaders: []string{requestid.Header},
+	}
+}
+
+// Apply escribe los headers CORS e indica si la request es un preflight
+// (OPTIONS), que se responde con 204 sin llegar al handler
+func (cfg CORSConfig) Apply(w http.ResponseWriter, r *http.Request) (preflight bool) {
+	origin := cfg.allowedOrigin(r.Header.Get("Origin"))
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if origin != "*" {
+			w.Header().Add("Vary", "Origin")
+		}
+	}
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+	if len(cfg.ExposedHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+	}
+
+	return r.Method == http.MethodOptions
+}
+
+// allowedOrigin devuelve el valor de Access-Control-Allow-Origin ("" si no se permite)
+func (cfg CORSConfig) allowedOrigin(origin string) string {
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// CORS aplica la configuración y responde los preflight
+func CORS(cfg CORSConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.Apply(w, r) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package httpmw
+
+import (
+	"net/http"
+	"time"
+)
+
+// SetDeprecationHeaders marca una respuesta como de una ruta deprecada
+//   - Deprecation: true
+//   - Link: </v1/users/5>; rel="successor-version" (la misma ruta con el prefijo nuevo)
+//   - Sunset: fecha en la que se va a dar de baja (si se conoce)
+func SetDeprecationHeaders(h http.Header, path, successorPrefix string, sunset time.Time) {
+	h.Set("Deprecation", "true")
+	h.Set("Link", "<"+successorPrefix+path+`>; rel="successor-version"`)
+	if !sunset.IsZero() {
+		h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Deprecated agrega los headers de deprecación a todas las respuestas
+// successorPrefix es el prefijo de la versión que reemplaza a la ruta (ej: "/v1")
+// sunset puede ser cero si todavía no hay fecha de baja
+func Deprecated(successorPrefix string, sunset time.Time) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			SetDeprecationHeaders(w.Header(), r.URL.Path, successorPrefix, sunset)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
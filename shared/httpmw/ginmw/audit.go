@@ -0,0 +1,45 @@
+package ginmw
+
+import (
+	"net/http"
+
+	"shared/audit"
+	"shared/httpmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditDenied emite un evento de auditoría por cada 401 o 403 (ver httpmw.AuditDenied)
+// Gin comparte c.Request con los middlewares de adentro, así el evento
+// incluye al usuario cuando el token era válido pero le faltaban permisos
+func AuditDenied(emitter audit.Emitter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, audited := httpmw.WithAuditMark(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if *audited {
+			return
+		}
+		status := c.Writer.Status()
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			emitter.Emit(c.Request.Context(), httpmw.DeniedEvent(c.Request, status))
+		}
+	}
+}
+
+// AllowIPs responde 403 a las IPs de fuera de la allowlist (ver httpmw.AllowIPs)
+// Con una allowlist nil no hace nada
+func AllowIPs(list *httpmw.IPAllowlist, emitter audit.Emitter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip, ok := list.Allows(c.Request)
+		if !ok {
+			emitter.Emit(c.Request.Context(), httpmw.IPDeniedEvent(c.Request, ip))
+			httpmw.MarkAudited(c.Request.Context())
+			Error(c, httpmw.ErrIPNotAllowed)
+			return
+		}
+		c.Next()
+	}
+}
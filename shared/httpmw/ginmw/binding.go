@@ -0,0 +1,147 @@
+package ginmw
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"shared/apperrors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// BindingError convierte el error de c.ShouldBindJSON en un error de validación
+// con el detalle de cada campo inválido:
+//
+//	{"code": "validation_error", "message": "invalid request body",
+//	 "details": [{"field": "email", "rule": "email", "message": "email must be a valid email address"},
+//	             {"field": "password", "rule": "min", "param": "6", "message": "password must be at least 6 characters long"}]}
+//
+// Un valor del tipo equivocado (ej: "age": "diez") se informa igual, con rule
+// "type". Si el body ni siquiera es JSON válido se devuelve un bad_request
+// El error original no llega al cliente: nombra structs y tipos de Go
+func BindingError(err error) error {
+	var validationErrors validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrors):
+		details := make([]apperrors.FieldError, 0, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			details = append(details, fieldError(fieldErr))
+		}
+		return apperrors.Validation("invalid request body").WithDetails(details)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		kind := jsonKind(typeErr.Type.Kind())
+		return apperrors.Validation("invalid request body").WithDetails([]apperrors.FieldError{
+			apperrors.NewFieldError(typeErr.Field, "type", kind, "%s must be a %s", typeErr.Field, kind),
+		})
+	case errors.Is(err, io.EOF):
+		return apperrors.BadRequest("request body is required")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperrors.BadRequest("invalid request body: malformed JSON")
+	default:
+		return apperrors.BadRequest("invalid request body")
+	}
+}
+
+// fieldError arma el detalle de un campo con un mensaje legible según la regla
+func fieldError(fieldErr validator.FieldError) apperrors.FieldError {
+	field, rule, param := jsonFieldName(fieldErr), fieldErr.Tag(), fieldErr.Param()
+	detail := func(format string, args ...interface{}) apperrors.FieldError {
+		return apperrors.NewFieldError(field, rule, param, format, append([]interface{}{field}, args...)...)
+	}
+
+	switch rule {
+	case "required", "required_if", "required_with":
+		return detail("%s is required")
+	case "email":
+		return detail("%s must be a valid email address")
+	case "numeric":
+		return detail("%s must contain only digits")
+	case "url":
+		return detail("%s must be a valid URL")
+	case "oneof":
+		return detail("%s must be one of: %s", strings.ReplaceAll(param, " ", ", "))
+	case "min", "max", "len":
+		return detail(lengthFormat(rule, fieldErr.Kind()), param)
+	default:
+		return detail("%s is invalid")
+	}
+}
+
+// lengthFormats son los mensajes de min/max/len según el tipo del campo:
+// en textos se cuentan caracteres, en listas elementos y en números el valor
+var lengthFormats = map[string][3]string{
+	"min": {"%s must be at least %s characters long", "%s must have at least %s items", "%s must be at least %s"},
+	"max": {"%s must be at most %s characters long", "%s must have at most %s items", "%s must be at most %s"},
+	"len": {"%s must be exactly %s characters long", "%s must have exactly %s items", "%s must be exactly %s"},
+}
+
+// lengthFormat elige el mensaje de min/max/len para el tipo del campo
+func lengthFormat(rule string, kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return lengthFormats[rule][0]
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return lengthFormats[rule][1]
+	default:
+		return lengthFormats[rule][2]
+	}
+}
+
+// jsonKind es el nombre del tipo JSON que se esperaba ("number", "string"...)
+func jsonKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldTagName)
+	}
+}
+
+// fieldTagName hace que el validador de gin nombre los campos como el
+// cliente los manda (tag json, o form en los query params) y no como el
+// campo de Go ("IDToken" => "id_token")
+func fieldTagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// jsonFieldName es el nombre del campo para el cliente (ver fieldTagName)
+// Si el campo no tiene tag se pasa el nombre de Go a snake_case
+// ("FirstName" => "first_name"), que es la convención de los DTOs
+func jsonFieldName(fieldErr validator.FieldError) string {
+	var b strings.Builder
+	for i, r := range fieldErr.Field() {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package ginmw
+
+import (
+	"shared/httpmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyWriter copia la respuesta a una captura además de escribirla
+type bodyWriter struct {
+	gin.ResponseWriter
+	capture *httpmw.BodyCapture
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// BodyLog loguea headers y bodies con lo sensible tapado (ver httpmw.BodyLog)
+func BodyLog(maxBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqBody := httpmw.ReadRequestBody(c.Request, maxBytes)
+		writer := &bodyWriter{ResponseWriter: c.Writer, capture: httpmw.NewBodyCapture(maxBytes)}
+		c.Writer = writer
+
+		c.Next()
+
+		httpmw.LogBodies(c.Request, reqBody, writer.Status(), writer.Header(), writer.capture)
+	}
+}
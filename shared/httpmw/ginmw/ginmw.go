@@ -0,0 +1,182 @@
+// Package ginmw expone los middlewares de shared/httpmw para Gin
+// Usan la misma lógica que las versiones net/http, y además guardan los datos
+// del usuario en el contexto de Gin (user_id, username, user_type) como
+// esperan los controllers
+package ginmw
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"shared/apperrors"
+	"shared/auth"
+	"shared/httpmw"
+	"shared/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error corta la cadena de handlers con el sobre de error común
+func Error(c *gin.Context, err error) {
+	status, body := httpmw.ErrorResponse(c.Request, err)
+	c.AbortWithStatusJSON(status, body)
+}
+
+// RequestID reutiliza el X-Request-ID entrante (o genera uno), lo guarda en
+// el contexto de la request y en el de Gin ("request_id") y lo devuelve
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := requestid.Ensure(c.GetHeader(requestid.Header))
+
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+
+		c.Next()
+	}
+}
+
+// Logger loguea cada request con el formato común (ver httpmw.AccessLog)
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		log.Println(httpmw.AccessLog{
+			Time:      start,
+			Status:    c.Writer.Status(),
+			Latency:   time.Since(start),
+			ClientIP:  c.ClientIP(),
+			Method:    c.Request.Method,
+			Path:      path,
+			RequestID: requestid.FromContext(c.Request.Context()),
+		})
+	}
+}
+
+// Recovery convierte un panic en un 500 con el sobre de error común
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				httpmw.LogPanic(c.Request, recovered)
+				Error(c, apperrors.ErrInternal)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+// CORS aplica la configuración y responde los preflight con 204
+func CORS(cfg httpmw.CORSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Apply(c.Writer, c.Request) {
+			c.AbortWithStatus(204)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimit limita por IP del cliente y responde 429 al pasarse
+func RateLimit(limiter *httpmw.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, wait := limiter.Allow(c.ClientIP()); !ok {
+			httpmw.SetRetryAfter(c.Writer, wait)
+			Error(c, apperrors.ErrRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Auth exige un JWT válido; uno de impersonación solo en lectura (ver httpmw.CheckScope)
+func Auth(validator *auth.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := httpmw.Authenticate(c.Request.Context(), validator, c.GetHeader("Authorization"))
+		if err == nil {
+			err = httpmw.CheckScope(claims, c.Request.Method)
+		}
+		if err != nil {
+			Error(c, err)
+			return
+		}
+
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuth guarda el usuario si hay un token válido; si no, sigue como anónimo
+func OptionalAuth(validator *auth.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := httpmw.Authenticate(c.Request.Context(), validator, c.GetHeader("Authorization")); err == nil && httpmw.CheckScope(claims, c.Request.Method) == nil {
+			setClaims(c, claims)
+		}
+		c.Next()
+	}
+}
+
+// ServiceToken exige el token de servicio en X-Service-Token (rutas que solo
+// llaman otros servicios); con token "" rechaza todo
+func ServiceToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := httpmw.CheckServiceToken(token, c.GetHeader(httpmw.ServiceTokenHeader)); err != nil {
+			Error(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Admin exige que el usuario sea admin (va después de Auth)
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := httpmw.CheckAdmin(httpmw.ClaimsFromContext(c.Request.Context())); err != nil {
+			Error(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// setClaims guarda los claims en el contexto de la request y en el de Gin
+func setClaims(c *gin.Context, claims *auth.Claims) {
+	c.Request = c.Request.WithContext(httpmw.WithClaims(c.Request.Context(), claims))
+	c.Set("user_id", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("user_type", claims.UserType)
+}
+
+// Deprecated agrega los headers de deprecación (ver httpmw.Deprecated)
+func Deprecated(successorPrefix string, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpmw.SetDeprecationHeaders(c.Writer.Header(), c.Request.URL.Path, successorPrefix, sunset)
+		c.Next()
+	}
+}
+
+// Timeout pone un deadline al contexto de la request (ver httpmw.Timeout)
+// Si el handler vuelve sin haber escrito nada y el deadline venció, responde
+// 503 con el sobre de error común
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if httpmw.TimedOut(c.Request) && !c.Writer.Written() {
+			Error(c, apperrors.ErrTimeout)
+		}
+	}
+}
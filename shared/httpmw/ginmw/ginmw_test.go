@@ -0,0 +1,386 @@
+package ginmw
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/auth"
+	"shared/httpmw"
+	"shared/idempotency"
+	"shared/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Test: Auth guarda el usuario en el contexto de Gin y el request ID se propaga
+func TestAuth_SetsGinContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-secret")
+
+	router := gin.New()
+	router.Use(RequestID(), Recovery())
+	router.GET("/me", Auth(auth.NewHMACValidator(secret, 0)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("user_id")})
+	})
+
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+		UserID: 42,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString(secret)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	req.Header.Set(requestid.Header, "req-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"user_id":42}` {
+		t.Errorf("Unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get(requestid.Header) != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", rec.Header().Get(requestid.Header))
+	}
+
+	// Sin token: 401 con el request ID en el cuerpo
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(requestid.Header, "req-2")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", rec.Code)
+	}
+	if want := `"request_id":"req-2"`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("Expected body to contain %s, got %s", want, rec.Body.String())
+	}
+}
+
+// Test: un token de impersonación sirve para leer pero no para cambiar datos;
+// un token normal pasa en los dos casos
+func TestAuth_ImpersonationIsReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("test-secret")
+	validator := auth.NewHMACValidator(secret, 0)
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/users/me", Auth(validator), ok)
+	router.PUT("/users/me", Auth(validator), ok)
+	router.POST("/users/logout", Auth(validator), ok)
+
+	sign := func(impersonatorID *uint) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+			UserID:           42,
+			ImpersonatorID:   impersonatorID,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}).SignedString(secret)
+		return token
+	}
+	admin := uint(1)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"impersonación lee", http.MethodGet, "/users/me", sign(&admin), http.StatusOK},
+		{"impersonación edita el perfil", http.MethodPut, "/users/me", sign(&admin), http.StatusForbidden},
+		{"impersonación cierra la sesión", http.MethodPost, "/users/logout", sign(&admin), http.StatusForbidden},
+		{"token normal edita el perfil", http.MethodPut, "/users/me", sign(nil), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// Test: un reintento con la misma Idempotency-Key recibe la misma respuesta,
+// con los headers que puso el handler (ej: Location)
+func TestIdempotency_ReplaysGinResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+
+	router := gin.New()
+	router.POST("/users", Idempotency(idempotency.New(idempotency.NewMemoryStore(), time.Hour)), func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/users/"+strconv.Itoa(calls))
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"username":"juan"}`))
+		req.Header.Set(idempotency.Header, "key-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first, second := send(), send()
+
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("Expected replay of %q, got %d %q", first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Error("Expected replayed header on the retry")
+	}
+	for _, header := range []string{"Location", "Content-Type"} {
+		if got, want := second.Header().Get(header), first.Header().Get(header); got != want {
+			t.Errorf("Expected %s %q on the replay, got %q", header, want, got)
+		}
+	}
+}
+
+// Test: los errores de binding traen el detalle por campo con el nombre del
+// JSON y un mensaje, sin nombres de structs ni tipos de Go
+func TestBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	type request struct {
+		FirstName string `json:"first_name" binding:"required"`
+		Password  string `json:"password" binding:"required,min=6"`
+		IDToken   string `json:"id_token" binding:"omitempty,len=4"`
+		Age       int    `json:"age" binding:"omitempty,max=120"`
+	}
+	bind := func(body string) apperrors.Response {
+		var got error
+		router := gin.New()
+		router.POST("/", func(c *gin.Context) {
+			var req request
+			got = BindingError(c.ShouldBindJSON(&req))
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		_, response := apperrors.ToResponse(got)
+		return response
+	}
+
+	body := bind(`{"password":"123","id_token":"abc","age":130}`)
+	details, _ := body.Details.([]apperrors.FieldError)
+	if body.Code != "validation_error" || len(details) != 4 {
+		t.Fatalf("Unexpected response: %+v", body)
+	}
+	want := []apperrors.FieldError{
+		{Field: "first_name", Rule: "required", Message: "first_name is required"},
+		{Field: "password", Rule: "min", Param: "6", Message: "password must be at least 6 characters long"},
+		{Field: "id_token", Rule: "len", Param: "4", Message: "id_token must be exactly 4 characters long"},
+		{Field: "age", Rule: "max", Param: "120", Message: "age must be at most 120"},
+	}
+	for i, w := range want {
+		if d := details[i]; d.Field != w.Field || d.Rule != w.Rule || d.Param != w.Param || d.Message != w.Message {
+			t.Errorf("Expected %+v, got %+v", w, d)
+		}
+	}
+
+	// Tipo equivocado: se informa el campo, sin el struct de Go
+	body = bind(`{"first_name":"ana","password":"123456","age":"diez"}`)
+	details, _ = body.Details.([]apperrors.FieldError)
+	if len(details) != 1 || details[0].Field != "age" || details[0].Rule != "type" || details[0].Message != "age must be a number" {
+		t.Errorf("Unexpected type error: %+v", body)
+	}
+
+	// Body vacío o que no es JSON
+	if body = bind(``); body.Code != "bad_request" || body.Message != "request body is required" {
+		t.Errorf("Unexpected empty body response: %+v", body)
+	}
+	if body = bind(`{"first_name":`); body.Code != "bad_request" || body.Message != "invalid request body: malformed JSON" {
+		t.Errorf("Unexpected malformed body response: %+v", body)
+	}
+}
+
+// Test: Timeout responde 503 si el handler no llegó a escribir
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(10 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"code":"timeout"`) {
+		t.Errorf("Expected 503 timeout, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", rec.Code)
+	}
+}
+
+// recordingEmitter guarda los eventos de auditoría emitidos
+type recordingEmitter struct {
+	events []audit.Event
+}
+
+func (r *recordingEmitter) Emit(ctx context.Context, event audit.Event) {
+	r.events = append(r.events, event)
+}
+
+// Test: un 403 de Admin genera permission.denied con el usuario del token
+func TestAuditDenied(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("secret")
+	emitter := &recordingEmitter{}
+
+	router := gin.New()
+	router.Use(AuditDenied(emitter))
+	router.GET("/admin", Auth(auth.NewHMACValidator(secret, 0)), Admin(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+		UserID:           3,
+		Username:         "maria",
+		UserType:         "normal",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString(secret)
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(emitter.events) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(emitter.events))
+	}
+	event := emitter.events[0]
+	if event.Action != audit.ActionPermissionDenied || event.ActorID == nil || *event.ActorID != 3 || event.TargetID != "GET /admin" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
+
+// Test: una IP fuera de la allowlist recibe 403 antes de Auth y genera un solo evento
+func TestAllowIPs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	emitter := &recordingEmitter{}
+	list, err := httpmw.NewIPAllowlist([]string{"10.0.0.0/8"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse allowlist: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(AuditDenied(emitter))
+	router.GET("/admin", AllowIPs(list, emitter), Auth(auth.NewHMACValidator([]byte("secret"), 0)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = "203.0.113.7:4000"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1") // sin proxies de confianza no cuenta
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", rec.Code)
+	}
+	if len(emitter.events) != 1 || emitter.events[0].Action != audit.ActionIPDenied || emitter.events[0].IP != "203.0.113.7" {
+		t.Fatalf("Expected one ip.denied event, got %+v", emitter.events)
+	}
+
+	// Desde la red permitida sigue hasta Auth (401 sin token, auditado aparte)
+	req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = "10.1.2.3:4000"
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", rec.Code)
+	}
+	if len(emitter.events) != 2 || emitter.events[1].Action != audit.ActionPermissionDenied {
+		t.Errorf("Expected a permission.denied event, got %+v", emitter.events)
+	}
+}
+
+// Test: ServiceToken deja pasar solo con el token configurado, y sin token
+// configurado la ruta queda cerrada
+func TestServiceToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		configured string
+		sent       string
+		want       int
+	}{
+		{"token correcto", "internal-token", "internal-token", http.StatusOK},
+		{"token incorrecto", "internal-token", "other-token", http.StatusUnauthorized},
+		{"sin token", "internal-token", "", http.StatusUnauthorized},
+		{"ruta cerrada", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/internal", ServiceToken(tt.configured), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+			if tt.sent != "" {
+				req.Header.Set(httpmw.ServiceTokenHeader, tt.sent)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+// Test: BodyLog deja el body para el binding de Gin y tapa la contraseña
+func TestBodyLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	router := gin.New()
+	router.Use(BodyLog(0))
+	router.POST("/login", func(c *gin.Context) {
+		var req struct {
+			Password string `json:"password"`
+		}
+		c.ShouldBindJSON(&req)
+		c.JSON(http.StatusOK, gin.H{"password_ok": req.Password == "secreta", "token": "jwt-firmado"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"password":"secreta"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"password_ok":true`) {
+		t.Fatalf("Handler did not receive the body: %s", rec.Body.String())
+	}
+	if logged := out.String(); strings.Contains(logged, "secreta") || strings.Contains(logged, "jwt-firmado") || !strings.Contains(logged, "<-- 200") {
+		t.Errorf("Unexpected log: %s", logged)
+	}
+}
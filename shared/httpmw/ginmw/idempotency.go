@@ -0,0 +1,75 @@
+package ginmw
+
+import (
+	"bytes"
+	"io"
+
+	"shared/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency hace que el endpoint se ejecute una sola vez por Idempotency-Key
+// (ver shared/idempotency). Sin el header la request pasa directo
+func Idempotency(i *idempotency.Idempotency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !idempotency.ValidKey(key) {
+			Error(c, idempotency.ErrInvalidKey)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			Error(c, err)
+			return
+		}
+
+		storeKey := idempotency.StoreKey(c.Request.Method, c.Request.URL.Path, c.GetHeader("Authorization"), key)
+		original := c.Writer
+		response, replayed, err := i.Do(storeKey, idempotency.HashBody(body), func() *idempotency.Response {
+			capture := &captureWriter{ResponseWriter: original}
+			c.Writer = capture
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			c.Next()
+
+			return &idempotency.Response{
+				Status: capture.Status(),
+				Header: capture.Header().Clone(),
+				Body:   capture.body.Bytes(),
+			}
+		})
+		c.Writer = original
+		if err != nil {
+			Error(c, err)
+			return
+		}
+
+		// La ejecución original ya escribió la respuesta; los reintentos la copian
+		if replayed {
+			c.Abort()
+			idempotency.Write(c.Writer, response, true)
+		}
+	}
+}
+
+// captureWriter escribe la respuesta normalmente y además guarda una copia
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
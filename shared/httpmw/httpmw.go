@@ -0,0 +1,53 @@
+// Package httpmw reúne los middlewares HTTP comunes a todos los servicios:
+// request ID, log de acceso, recuperación de panics, CORS, rate limit, auth
+// y timeouts (del servidor y por request)
+//
+// Los middlewares de este paquete son de net/http (func(http.Handler) http.Handler)
+// y sirven tal cual para chi o el mux estándar. El subpaquete ginmw expone los
+// mismos middlewares para Gin, con la misma lógica, así el comportamiento es
+// idéntico en todos los servicios
+package httpmw
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"shared/apperrors"
+	"shared/i18n"
+	"shared/requestid"
+)
+
+// Middleware es la firma estándar de un middleware de net/http
+type Middleware func(http.Handler) http.Handler
+
+// Chain aplica los middlewares en orden: el primero es el más externo
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// RequestID reutiliza el X-Request-ID entrante o genera uno (ver shared/requestid)
+func RequestID(next http.Handler) http.Handler {
+	return requestid.Middleware(next)
+}
+
+// ErrorResponse arma el status y el sobre de error común con el request ID
+// El mensaje sale en el idioma del Accept-Language de la request (ver shared/i18n)
+func ErrorResponse(r *http.Request, err error) (int, apperrors.Response) {
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	status, body := apperrors.ToResponse(apperrors.From(err).Translate(func(message string) string {
+		return i18n.Translate(lang, message)
+	}))
+	body.RequestID = requestid.FromContext(r.Context())
+	return status, body
+}
+
+// WriteError responde un error con el sobre JSON común
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := ErrorResponse(r, err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
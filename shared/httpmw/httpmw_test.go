@@ -0,0 +1,350 @@
+package httpmw
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/apperrors"
+	"shared/auth"
+	"shared/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+// Test: el preflight se responde sin llegar al handler
+func TestCORS_Preflight(t *testing.T) {
+	handler := CORS(DefaultCORS(http.MethodGet))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not run on preflight")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Expected wildcard origin, got %q", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Access-Control-Allow-Methods") != "GET, OPTIONS" {
+		t.Errorf("Unexpected methods: %q", rec.Header().Get("Access-Control-Allow-Methods"))
+	}
+}
+
+// Test: con orígenes explícitos se devuelve el origen pedido solo si está permitido
+func TestCORS_AllowedOrigins(t *testing.T) {
+	cfg := CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	cfg.Apply(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" {
+		t.Errorf("Expected allowed origin, got %q", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	rec = httptest.NewRecorder()
+	req.Header.Set("Origin", "http://evil.com")
+	cfg.Apply(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no origin header, got %q", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+// Test: token bucket por clave
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(60, 2) // 1 ficha por segundo, hasta 2
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("1.1.1.1"); !ok {
+			t.Fatalf("Request %d should be allowed", i+1)
+		}
+	}
+	ok, wait := limiter.Allow("1.1.1.1")
+	if ok || wait <= 0 || wait > time.Second {
+		t.Errorf("Expected third request to be limited with wait <= 1s, got ok=%v wait=%s", ok, wait)
+	}
+	if ok, _ := limiter.Allow("2.2.2.2"); !ok {
+		t.Error("Expected other clients not to be affected")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := limiter.Allow("1.1.1.1"); !ok {
+		t.Error("Expected a token to be refilled after 1s")
+	}
+}
+
+// Test: el middleware responde 429 con Retry-After
+func TestRateLimit_Middleware(t *testing.T) {
+	handler := RateLimit(NewRateLimiter(1, 1))(okHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/login", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header")
+	}
+}
+
+// Test: un panic se convierte en 500 con el sobre común
+func TestRecovery(t *testing.T) {
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), RequestID, Recovery)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", rec.Code)
+	}
+	var body apperrors.Response
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Code != "internal_error" || body.RequestID == "" {
+		t.Errorf("Unexpected body: %+v", body)
+	}
+}
+
+// Test: las rutas deprecadas apuntan a su reemplazo versionado
+func TestDeprecated(t *testing.T) {
+	sunset := time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)
+	handler := Deprecated("/v1", sunset)(okHandler)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/5", nil))
+
+	if rec.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected Deprecation header, got %q", rec.Header().Get("Deprecation"))
+	}
+	if rec.Header().Get("Link") != `</v1/users/5>; rel="successor-version"` {
+		t.Errorf("Unexpected Link: %q", rec.Header().Get("Link"))
+	}
+	if rec.Header().Get("Sunset") != "Mon, 01 Mar 2027 00:00:00 GMT" {
+		t.Errorf("Unexpected Sunset: %q", rec.Header().Get("Sunset"))
+	}
+}
+
+// Test: un handler que respeta el contexto se corta y responde 503
+func TestTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	handler := Chain(slow, RequestID, Timeout(10*time.Millisecond))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d", rec.Code)
+	}
+	var body apperrors.Response
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.Code != "timeout" || body.RequestID == "" {
+		t.Errorf("Unexpected body: %+v", body)
+	}
+
+	// Un handler rápido no se toca
+	rec = httptest.NewRecorder()
+	Timeout(time.Second)(okHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
+
+// Test: el handler timeout tiene que ser menor al write timeout
+func TestServerTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("HTTP_WRITE_TIMEOUT", "10s")
+	t.Setenv("HTTP_HANDLER_TIMEOUT", "10s")
+	env := config.New()
+
+	timeouts := ServerTimeoutsFromEnv(env)
+	if env.Err() == nil {
+		t.Error("Expected an error when the handler timeout is not lower than the write timeout")
+	}
+
+	server := NewServer(":0", okHandler, timeouts)
+	if server.WriteTimeout != 10*time.Second || server.ReadHeaderTimeout != 5*time.Second {
+		t.Errorf("Unexpected server timeouts: write=%s read_header=%s", server.WriteTimeout, server.ReadHeaderTimeout)
+	}
+}
+
+// Test: Auth y Admin
+func TestAuthAndAdmin(t *testing.T) {
+	secret := []byte("test-secret")
+	validator := auth.NewHMACValidator(secret, 0)
+	token := func(userType string) string {
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+			UserID:   7,
+			UserType: userType,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}).SignedString(secret)
+		return "Bearer " + signed
+	}
+	handler := Chain(okHandler, Auth(validator), Admin)
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"bad format", "Token abc", http.StatusUnauthorized},
+		{"not admin", token("user"), http.StatusForbidden},
+		{"admin", token("admin"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+}
+
+// Test: el mensaje de error sale en el idioma del Accept-Language
+func TestWriteError_Language(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                       "authorization header required",
+		"es-AR,es;q=0.9":         "falta el header Authorization",
+		"fr, en;q=0.5":           "authorization header required",
+		"de, es;q=0.8, en;q=0.5": "falta el header Authorization",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+		req.Header.Set("Accept-Language", header)
+		rec := httptest.NewRecorder()
+		WriteError(rec, req, apperrors.Unauthorized("authorization header required"))
+
+		var body apperrors.Response
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body.Message != want || body.Code != "unauthorized" {
+			t.Errorf("Accept-Language %q: expected %q, got %+v", header, want, body)
+		}
+	}
+}
+
+// Test: contraseñas y tokens se tapan a cualquier nivel del JSON
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"username":"maria","password":"secreta","data":{"access_token":"abc","items":[{"api_key":"k"}]}}`)
+	got := RedactBody("application/json; charset=utf-8", body, false)
+	if strings.Contains(got, "secreta") || strings.Contains(got, "abc") || strings.Contains(got, `"k"`) || !strings.Contains(got, "maria") {
+		t.Errorf("Unexpected redacted body: %s", got)
+	}
+
+	if got := RedactBody("application/x-www-form-urlencoded", []byte("user=maria&password=secreta"), false); strings.Contains(got, "secreta") {
+		t.Errorf("Form password not redacted: %s", got)
+	}
+	// Lo que no se puede revisar no se loguea
+	if got := RedactBody("application/json", []byte(`{"password":"sec`), true); strings.Contains(got, "sec") {
+		t.Errorf("Truncated body logged: %s", got)
+	}
+	if got := RedactBody("text/plain", []byte("password=secreta"), false); strings.Contains(got, "secreta") {
+		t.Errorf("Plain text body logged: %s", got)
+	}
+
+	// "code" exacto (SMS) sí, "postal_code" no
+	if got := RedactBody("application/json", []byte(`{"code":"123456","postal_code":"5000"}`), false); strings.Contains(got, "123456") || !strings.Contains(got, "5000") {
+		t.Errorf("Unexpected redacted codes: %s", got)
+	}
+}
+
+// Test: BodyLog no le cambia el body al handler y no loguea secretos
+func TestBodyLog(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"jwt-firmado","received":` + strconv.Itoa(len(body)) + `}`))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users/login?token=abc", strings.NewReader(`{"username":"maria","password":"secreta"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer xyz")
+	rec := httptest.NewRecorder()
+	BodyLog(0)(echo).ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"received":41`) {
+		t.Fatalf("Handler did not receive the full body: %s", rec.Body.String())
+	}
+	logged := out.String()
+	for _, secret := range []string{"secreta", "jwt-firmado", "xyz", "abc"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("Log contains %q: %s", secret, logged)
+		}
+	}
+	if !strings.Contains(logged, "🐛 [HTTP] -->") || !strings.Contains(logged, "🐛 [HTTP] <-- 200") {
+		t.Errorf("Unexpected log: %s", logged)
+	}
+}
+
+// Test: la allowlist solo cree en X-Forwarded-For si la conexión viene de un proxy de confianza
+func TestIPAllowlist(t *testing.T) {
+	list, err := NewIPAllowlist([]string{"10.0.0.0/8", "2001:db8::/32", "203.0.113.7"}, []string{"172.16.0.0/12"})
+	if err != nil {
+		t.Fatalf("Failed to parse allowlist: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		wantIP     string
+		wantOK     bool
+	}{
+		{"direct allowed", "10.1.2.3:5000", "", "10.1.2.3", true},
+		{"single IP", "203.0.113.7:5000", "", "203.0.113.7", true},
+		{"IPv6", "[2001:db8::1]:5000", "", "2001:db8::1", true},
+		{"direct denied", "198.51.100.1:5000", "", "198.51.100.1", false},
+		{"spoofed header ignored", "198.51.100.1:5000", "10.0.0.1", "198.51.100.1", false},
+		{"through trusted proxy", "172.16.0.2:5000", "10.0.0.1", "10.0.0.1", true},
+		{"spoofed hop behind proxy", "172.16.0.2:5000", "10.0.0.1, 198.51.100.1", "198.51.100.1", false},
+		{"chain of proxies", "172.16.0.2:5000", "10.0.0.1, 172.16.0.3", "10.0.0.1", true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if ip, ok := list.Allows(req); ip != tt.wantIP || ok != tt.wantOK {
+			t.Errorf("%s: expected (%s, %v), got (%s, %v)", tt.name, tt.wantIP, tt.wantOK, ip, ok)
+		}
+	}
+
+	if _, err := NewIPAllowlist([]string{"10.0.0.0/33"}, nil); err == nil {
+		t.Error("Expected an error for an invalid CIDR")
+	}
+	if list, err := NewIPAllowlist(nil, nil); list != nil || err != nil {
+		t.Errorf("Expected a nil allowlist without networks, got %v, %v", list, err)
+	}
+}
@@ -0,0 +1,106 @@
+package httpmw
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// AccessLog es una línea del log de acceso
+type AccessLog struct {
+	Time      time.Time
+	Status    int
+	Latency   time.Duration
+	ClientIP  string
+	Method    string
+	Path      string
+	RequestID string
+}
+
+// String da el formato común a todos los servicios
+// Ejemplo: [HTTP] 2024/05/01 - 12:00:00 | 200 |  1.2ms | 172.18.0.1 | GET "/users/1" | req=4f1c...
+func (l AccessLog) String() string {
+	return fmt.Sprintf("[HTTP] %s | %3d | %13v | %15s | %-7s %q | req=%s",
+		l.Time.Format("2006/01/02 - 15:04:05"),
+		l.Status,
+		l.Latency.Round(time.Microsecond),
+		l.ClientIP,
+		l.Method,
+		l.Path,
+		l.RequestID,
+	)
+}
+
+// statusRecorder guarda el status que escribió el handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Logger loguea cada request con su status, latencia y request ID
+// Va después de RequestID para que el ID esté en el contexto
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		log.Println(AccessLog{
+			Time:      start,
+			Status:    recorder.status,
+			Latency:   time.Since(start),
+			ClientIP:  ClientIP(r),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			RequestID: requestid.FromContext(r.Context()),
+		})
+	})
+}
+
+// ClientIP devuelve la IP del cliente (primera de X-Forwarded-For si viene de un proxy)
+func ClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(ip)
+	}
+	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return ip
+	}
+	return r.RemoteAddr
+}
+
+// LogPanic loguea un panic recuperado con su stack trace
+func LogPanic(r *http.Request, recovered interface{}) {
+	requestid.Logf(r.Context(), "💥 panic en %s %s: %v\n%s", r.Method, r.URL.Path, recovered, debug.Stack())
+}
+
+// Recovery convierte un panic en un 500 con el sobre de error común
+// en lugar de cortar la conexión
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				LogPanic(r, recovered)
+				WriteError(w, r, apperrors.ErrInternal)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,103 @@
+package httpmw
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"shared/apperrors"
+)
+
+// RateLimiter limita requests por clave (normalmente la IP) con token bucket:
+// cada clave tiene hasta Burst fichas y recupera PerMinute fichas por minuto
+// Es en memoria, así que el límite es por instancia del servicio
+type RateLimiter struct {
+	perMinute int
+	burst     int
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter crea un limitador (burst <= 0 usa perMinute)
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &RateLimiter{
+		perMinute: perMinute,
+		burst:     burst,
+		buckets:   make(map[string]*bucket),
+		now:       time.Now,
+	}
+}
+
+// Allow consume una ficha de la clave
+// Si no quedan devuelve false y cuánto falta para la próxima
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	refill := float64(l.perMinute) / float64(time.Minute)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	} else {
+		b.tokens += float64(now.Sub(b.last)) * refill
+		if b.tokens > float64(l.burst) {
+			b.tokens = float64(l.burst)
+		}
+		b.last = now
+	}
+	l.cleanup(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / refill)
+}
+
+// cleanup borra los buckets llenos para que el mapa no crezca sin límite
+// Se llama con l.mu tomado
+func (l *RateLimiter) cleanup(now time.Time) {
+	if len(l.buckets) < 10000 {
+		return
+	}
+	full := time.Duration(float64(l.burst) / float64(l.perMinute) * float64(time.Minute))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// SetRetryAfter agrega el header Retry-After (en segundos, redondeado para arriba)
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// RateLimit limita por IP del cliente y responde 429 al pasarse
+func RateLimit(limiter *RateLimiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, wait := limiter.Allow(ClientIP(r)); !ok {
+				SetRetryAfter(w, wait)
+				WriteError(w, r, apperrors.ErrRateLimited)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package httpmw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"shared/apperrors"
+	"shared/config"
+)
+
+// ServerTimeouts son los límites de tiempo del servidor HTTP
+// Sin ellos un cliente lento (o malicioso) puede tener una conexión abierta
+// para siempre mandando o leyendo de a un byte
+type ServerTimeouts struct {
+	ReadHeader time.Duration // leer los headers de la request
+	Read       time.Duration // leer la request completa (headers + body)
+	Write      time.Duration // desde que se terminó de leer hasta escribir la respuesta
+	Idle       time.Duration // conexión keep-alive sin requests
+	Handler    time.Duration // tope para que un handler responda (ver Timeout)
+}
+
+// ServerTimeoutsFromEnv lee HTTP_READ_HEADER_TIMEOUT, HTTP_READ_TIMEOUT,
+// HTTP_WRITE_TIMEOUT, HTTP_IDLE_TIMEOUT y HTTP_HANDLER_TIMEOUT (ej: "15s")
+// El write timeout tiene que ser mayor al del handler: si no, el cliente
+// pierde la conexión antes de recibir el 503
+func ServerTimeoutsFromEnv(env *config.Env) ServerTimeouts {
+	t := ServerTimeouts{
+		ReadHeader: env.Duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		Read:       env.Duration("HTTP_READ_TIMEOUT", 15*time.Second),
+		Write:      env.Duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		Idle:       env.Duration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		Handler:    env.Duration("HTTP_HANDLER_TIMEOUT", 20*time.Second),
+	}
+	env.Check(t.Handler < t.Write, "HTTP_HANDLER_TIMEOUT (%s) must be lower than HTTP_WRITE_TIMEOUT (%s)", t.Handler, t.Write)
+	return t
+}
+
+// NewServer arma un http.Server con los timeouts aplicados
+// Reemplaza a http.ListenAndServe, que no pone ningún límite
+func NewServer(addr string, handler http.Handler, t ServerTimeouts) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: t.ReadHeader,
+		ReadTimeout:       t.Read,
+		WriteTimeout:      t.Write,
+		IdleTimeout:       t.Idle,
+	}
+}
+
+// TimedOut indica si la request se cortó por el deadline que puso Timeout
+func TimedOut(r *http.Request) bool {
+	return errors.Is(r.Context().Err(), context.DeadlineExceeded)
+}
+
+// writeTracker recuerda si el handler ya empezó a escribir la respuesta
+type writeTracker struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (w *writeTracker) WriteHeader(status int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *writeTracker) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Timeout pone un deadline al contexto de la request
+// Lo que respeta el contexto (clientes HTTP, Solr, queries con ctx) se corta
+// solo al vencer; si el handler vuelve sin haber escrito nada se responde
+// 503 con el sobre de error común (code "timeout")
+// d <= 0 desactiva el middleware
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tracker := &writeTracker{ResponseWriter: w}
+			next.ServeHTTP(tracker, r)
+
+			if TimedOut(r) && !tracker.wrote {
+				WriteError(w, r, apperrors.ErrTimeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package i18n traduce los mensajes que ven los clientes (ej: el "message"
+// de los errores) según el header Accept-Language
+//
+// Los mensajes se escriben en inglés en el código y funcionan como clave del
+// catálogo, así un mensaje sin traducción sale tal cual en inglés. Cada
+// servicio registra al arrancar las traducciones de sus propios mensajes; las
+// de los paquetes de shared ya vienen registradas
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage es el idioma de los mensajes en el código y el que se usa
+// si el cliente no pide ninguno soportado
+const DefaultLanguage = "en"
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{
+		"es": sharedSpanish,
+	}
+)
+
+// Register agrega traducciones al catálogo de un idioma ("es")
+// Las claves son los mensajes en inglés (o el formato, si el error se creó con
+// apperrors.Newf); una clave repetida pisa la anterior
+func Register(lang string, messages map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[lang] = catalog
+	}
+	for message, translation := range messages {
+		catalog[message] = translation
+	}
+}
+
+// Translate devuelve el mensaje traducido al idioma, o el original si no hay traducción
+func Translate(lang, message string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if translation, ok := catalogs[lang][message]; ok {
+		return translation
+	}
+	return message
+}
+
+// supported dice si hay catálogo para el idioma
+func supported(lang string) bool {
+	if lang == DefaultLanguage {
+		return true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate elige el idioma de la respuesta a partir de un Accept-Language
+// (ej: "es-AR,es;q=0.9,en;q=0.8"): el soportado con mayor q, comparando solo
+// el idioma ("es-AR" => "es"). Sin coincidencias devuelve DefaultLanguage
+func Negotiate(acceptLanguage string) string {
+	type option struct {
+		lang string
+		q    float64
+	}
+
+	var options []option
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "" || lang == "*" || q <= 0 {
+			continue
+		}
+		options = append(options, option{lang: lang, q: q})
+	}
+
+	// Estable: con el mismo q gana el que el cliente puso primero
+	sort.SliceStable(options, func(i, j int) bool { return options[i].q > options[j].q })
+	for _, o := range options {
+		if supported(o.lang) {
+			return o.lang
+		}
+	}
+	return DefaultLanguage
+}
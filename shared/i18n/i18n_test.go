@@ -0,0 +1,46 @@
+package i18n
+
+import "testing"
+
+// Test: se elige el idioma soportado con mayor q
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"es", "es"},
+		{"es-AR,es;q=0.9,en;q=0.8", "es"},
+		{"en-US,en;q=0.9,es;q=0.8", "en"},
+		{"fr-FR,es;q=0.5", "es"},
+		{"fr, de", "en"},
+		{"en;q=0.2, ES;q=0.7", "es"},
+		{"es;q=0, en", "en"},
+		{"*", "en"},
+		{"es;q=abc", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, expected %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// Test: traducción con fallback al mensaje original
+func TestTranslate(t *testing.T) {
+	Register("es", map[string]string{"thing not found": "cosa no encontrada"})
+
+	if got := Translate("es", "thing not found"); got != "cosa no encontrada" {
+		t.Errorf("Expected registered translation, got %q", got)
+	}
+	if got := Translate("es", "not found"); got != "no encontrado" {
+		t.Errorf("Expected shared translation, got %q", got)
+	}
+	if got := Translate("es", "something new"); got != "something new" {
+		t.Errorf("Expected untranslated message, got %q", got)
+	}
+	if got := Translate("en", "thing not found"); got != "thing not found" {
+		t.Errorf("Expected english message, got %q", got)
+	}
+}
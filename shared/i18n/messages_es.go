@@ -0,0 +1,53 @@
+package i18n
+
+// sharedSpanish traduce los mensajes de los paquetes de shared
+// (apperrors, httpmw, ginmw, idempotency, logging)
+var sharedSpanish = map[string]string{
+	// apperrors
+	"bad request":                        "solicitud inválida",
+	"validation error":                   "error de validación",
+	"unauthorized":                       "no autorizado",
+	"forbidden":                          "acceso denegado",
+	"not found":                          "no encontrado",
+	"conflict":                           "conflicto",
+	"too many requests, try again later": "demasiadas solicitudes, probá de nuevo más tarde",
+	"the request took too long, try again later": "la solicitud tardó demasiado, probá de nuevo más tarde",
+	"internal error":        "error interno",
+	"internal server error": "error interno del servidor",
+
+	// httpmw / ginmw
+	"authorization header required":                       "falta el header Authorization",
+	"invalid authorization header format":                 "formato inválido del header Authorization",
+	"invalid or expired token":                            "token inválido o vencido",
+	"token has been revoked":                              "el token fue revocado, volvé a iniciar sesión",
+	"admin privileges required":                           "se necesitan permisos de administrador",
+	"your IP address is not allowed to access this route": "tu dirección IP no tiene acceso a esta ruta",
+	"invalid request body":                                "cuerpo de la solicitud inválido",
+	"invalid request body: malformed JSON":                "cuerpo de la solicitud inválido: el JSON está mal formado",
+	"request body is required":                            "falta el cuerpo de la solicitud",
+
+	// ginmw: mensajes de cada campo en los errores de validación
+	"%s is required":                         "%s es obligatorio",
+	"%s must be a valid email address":       "%s tiene que ser un email válido",
+	"%s must be a valid URL":                 "%s tiene que ser una URL válida",
+	"%s must contain only digits":            "%s solo puede tener dígitos",
+	"%s must be one of: %s":                  "%s tiene que ser uno de: %s",
+	"%s is invalid":                          "%s es inválido",
+	"%s must be a %s":                        "%s tiene un tipo inválido (se esperaba %s)",
+	"%s must be at least %s characters long": "%s tiene que tener al menos %s caracteres",
+	"%s must be at most %s characters long":  "%s puede tener como máximo %s caracteres",
+	"%s must be exactly %s characters long":  "%s tiene que tener exactamente %s caracteres",
+	"%s must have at least %s items":         "%s tiene que tener al menos %s elementos",
+	"%s must have at most %s items":          "%s puede tener como máximo %s elementos",
+	"%s must have exactly %s items":          "%s tiene que tener exactamente %s elementos",
+	"%s must be at least %s":                 "%s tiene que ser al menos %s",
+	"%s must be at most %s":                  "%s puede ser como máximo %s",
+	"%s must be exactly %s":                  "%s tiene que ser exactamente %s",
+
+	// idempotency
+	"idempotency key too long":                              "la clave de idempotencia es demasiado larga",
+	"idempotency key already used with a different request": "la clave de idempotencia ya se usó con otra solicitud",
+
+	// logging
+	`"for" must be a duration like "15m" (max 24h)`: `"for" tiene que ser una duración como "15m" (máximo 24h)`,
+}
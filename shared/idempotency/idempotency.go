@@ -0,0 +1,145 @@
+// Package idempotency permite que un endpoint que modifica datos sea seguro
+// de reintentar: si el cliente manda el header Idempotency-Key, la primera
+// respuesta se guarda por un tiempo y los reintentos con la misma clave
+// reciben esa misma respuesta sin volver a ejecutar el handler
+//
+// Si llegan dos requests con la misma clave a la vez, la segunda espera a
+// que termine la primera y recibe su respuesta (no se ejecuta dos veces)
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"shared/apperrors"
+)
+
+// Header es el header que manda el cliente (ej: un UUID por operación)
+const Header = "Idempotency-Key"
+
+// ReplayedHeader marca las respuestas que salen del cache
+const ReplayedHeader = "Idempotent-Replayed"
+
+// maxKeyLength limita el largo de la clave que manda el cliente
+const maxKeyLength = 255
+
+// ErrKeyReused indica que la clave ya se usó con otra request (otro body)
+var ErrKeyReused = apperrors.Conflict("idempotency key already used with a different request")
+
+// ErrInvalidKey indica que la clave es demasiado larga
+var ErrInvalidKey = apperrors.BadRequest("idempotency key too long")
+
+// Response es la respuesta guardada para una clave
+type Response struct {
+	Status      int
+	Header      http.Header
+	Body        []byte
+	RequestHash string // hash del body de la request original
+}
+
+// Store guarda las respuestas por clave con vencimiento
+// La implementación en memoria sirve para una instancia; con varias
+// instancias hace falta un store compartido (ej: Memcached)
+type Store interface {
+	Get(key string) (*Response, bool)
+	Set(key string, response *Response, ttl time.Duration)
+}
+
+// Idempotency coordina el cache de respuestas y las requests en curso
+type Idempotency struct {
+	store Store
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+// call es una request en curso con una clave
+type call struct {
+	done     chan struct{}
+	response *Response
+}
+
+// New crea el coordinador (ttl: cuánto se recuerda cada respuesta)
+func New(store Store, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		store:    store,
+		ttl:      ttl,
+		inflight: make(map[string]*call),
+	}
+}
+
+// StoreKey arma la clave interna: la misma Idempotency-Key de dos usuarios
+// distintos (o en dos endpoints distintos) no debe chocar
+func StoreKey(method, path, authorization, key string) string {
+	sum := sha256.Sum256([]byte(method + " " + path + "\n" + authorization + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashBody resume el body de la request para detectar claves reutilizadas
+func HashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidKey indica si la clave que mandó el cliente es aceptable
+func ValidKey(key string) bool {
+	return len(key) <= maxKeyLength
+}
+
+// Do ejecuta run una sola vez por clave:
+//   - si hay una respuesta guardada la devuelve (replayed = true)
+//   - si hay otra request en curso con la misma clave, la espera (y si
+//     esa no deja respuesta guardada, por un 5xx o un panic, se ejecuta)
+//   - si no, ejecuta run y guarda la respuesta (salvo errores 5xx,
+//     para que el cliente pueda reintentar)
+//
+// Si la clave ya se usó con un body distinto devuelve ErrKeyReused
+func (i *Idempotency) Do(key, requestHash string, run func() *Response) (response *Response, replayed bool, err error) {
+	for {
+		if cached, ok := i.store.Get(key); ok {
+			if cached.RequestHash != requestHash {
+				return nil, false, ErrKeyReused
+			}
+			return cached, true, nil
+		}
+
+		i.mu.Lock()
+		if current, ok := i.inflight[key]; ok {
+			i.mu.Unlock()
+			<-current.done
+			if current.response == nil || current.response.Status >= 500 {
+				// La primera falló (o entró en panic sin respuesta) y no se
+				// guardó: esta se ejecuta de nuevo
+				continue
+			}
+			if current.response.RequestHash != requestHash {
+				return nil, false, ErrKeyReused
+			}
+			return current.response, true, nil
+		}
+
+		current := &call{done: make(chan struct{})}
+		i.inflight[key] = current
+		i.mu.Unlock()
+
+		defer func() {
+			i.mu.Lock()
+			delete(i.inflight, key)
+			i.mu.Unlock()
+			close(current.done)
+		}()
+
+		current.response = run()
+		if current.response != nil {
+			current.response.RequestHash = requestHash
+			if current.response.Status < 500 {
+				i.store.Set(key, current.response, i.ttl)
+			}
+		}
+		return current.response, false, nil
+	}
+}
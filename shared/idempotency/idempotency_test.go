@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test: el mismo body con la misma clave se ejecuta una vez y se repite
+func TestMiddleware_ReplaysResponse(t *testing.T) {
+	var calls int32
+	handler := New(NewMemoryStore(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":` + string(rune('0'+n)) + `}`))
+	}))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set(Header, "key-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send(`{"username":"juan"}`)
+	second := send(`{"username":"juan"}`)
+
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("Expected replayed response, got %d %s", second.Code, second.Body.String())
+	}
+	if second.Header().Get(ReplayedHeader) != "true" || first.Header().Get(ReplayedHeader) != "" {
+		t.Error("Expected only the retry to be marked as replayed")
+	}
+
+	if reused := send(`{"username":"otro"}`); reused.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a reused key, got %d", reused.Code)
+	}
+}
+
+// Test: sin header no se cachea nada
+func TestMiddleware_NoKeyPassesThrough(t *testing.T) {
+	var calls int32
+	handler := New(NewMemoryStore(), time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls without key, got %d", calls)
+	}
+}
+
+// Test: requests concurrentes con la misma clave se ejecutan una vez
+func TestDo_CoalescesInFlight(t *testing.T) {
+	i := New(NewMemoryStore(), time.Hour)
+	release := make(chan struct{})
+	var calls int32
+
+	var wg sync.WaitGroup
+	results := make([]*Response, 5)
+	for n := range results {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			results[n], _, _ = i.Do("k", "h", func() *Response {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return &Response{Status: http.StatusCreated, Body: []byte("ok")}
+			})
+		}(n)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected one execution, got %d", calls)
+	}
+	for n, r := range results {
+		if r == nil || r.Status != http.StatusCreated {
+			t.Errorf("Result %d: unexpected response %+v", n, r)
+		}
+	}
+}
+
+// Test: los errores 5xx no se guardan (el cliente puede reintentar)
+func TestDo_DoesNotStoreServerErrors(t *testing.T) {
+	i := New(NewMemoryStore(), time.Hour)
+	status := http.StatusInternalServerError
+
+	run := func() *Response { return &Response{Status: status} }
+	i.Do("k", "h", run)
+	status = http.StatusOK
+	response, replayed, err := i.Do("k", "h", run)
+
+	if err != nil || replayed || response.Status != http.StatusOK {
+		t.Errorf("Expected a fresh execution, got %+v replayed=%v err=%v", response, replayed, err)
+	}
+	if _, _, err := i.Do("k", "other", run); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("Expected ErrKeyReused, got %v", err)
+	}
+}
+
+// Test: si la primera request entra en panic, la que la esperaba se ejecuta
+// de nuevo en lugar de recibir una respuesta nil
+func TestDo_WaiterRunsAfterPanic(t *testing.T) {
+	i := New(NewMemoryStore(), time.Hour)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	go func() {
+		defer func() { recover() }()
+		i.Do("k", "h", func() *Response {
+			close(started)
+			<-release
+			panic("handler failed")
+		})
+	}()
+	<-started
+
+	done := make(chan struct{})
+	var response *Response
+	var replayed bool
+	var err error
+	go func() {
+		defer close(done)
+		response, replayed, err = i.Do("k", "h", func() *Response {
+			return &Response{Status: http.StatusCreated}
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond) // la segunda queda esperando a la primera
+	close(release)
+	<-done
+
+	if err != nil || replayed || response == nil || response.Status != http.StatusCreated {
+		t.Errorf("Expected a fresh execution, got %+v replayed=%v err=%v", response, replayed, err)
+	}
+}
+
+// Test: las entradas vencen
+func TestMemoryStore_Expires(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Set("k", &Response{Status: 200}, time.Minute)
+	if _, ok := store.Get("k"); !ok {
+		t.Fatal("Expected entry before TTL")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.Get("k"); ok {
+		t.Error("Expected entry to expire")
+	}
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore es un Store en memoria (por instancia del servicio)
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	response  *Response
+	expiresAt time.Time
+}
+
+// NewMemoryStore crea un store vacío
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Get devuelve la respuesta si existe y no venció
+func (s *MemoryStore) Get(key string) (*Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if s.now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set guarda la respuesta y aprovecha para limpiar las vencidas
+func (s *MemoryStore) Set(key string, response *Response, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryEntry{response: response, expiresAt: now.Add(ttl)}
+}
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"shared/httpmw"
+)
+
+// Middleware es la versión net/http: sin header Idempotency-Key la request
+// pasa directo; con header se ejecuta una sola vez por clave
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !ValidKey(key) {
+			httpmw.WriteError(w, r, ErrInvalidKey)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			httpmw.WriteError(w, r, err)
+			return
+		}
+
+		storeKey := StoreKey(r.Method, r.URL.Path, r.Header.Get("Authorization"), key)
+		response, replayed, err := i.Do(storeKey, HashBody(body), func() *Response {
+			recorder := &recorder{header: http.Header{}, status: http.StatusOK}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(recorder, r)
+			return &Response{Status: recorder.status, Header: recorder.header, Body: recorder.body.Bytes()}
+		})
+		if err != nil {
+			httpmw.WriteError(w, r, err)
+			return
+		}
+
+		Write(w, response, replayed)
+	})
+}
+
+// Write copia una respuesta guardada al cliente
+// Los headers que ya puso esta request (ej: su propio X-Request-ID) no se pisan
+func Write(w http.ResponseWriter, response *Response, replayed bool) {
+	for k, values := range response.Header {
+		if _, exists := w.Header()[k]; exists {
+			continue
+		}
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	if replayed {
+		w.Header().Set(ReplayedHeader, "true")
+	}
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+// recorder captura la respuesta del handler para poder guardarla
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *recorder) WriteHeader(status int)      { r.status = status }
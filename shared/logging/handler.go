@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"shared/apperrors"
+	"shared/httpmw"
+	"shared/openapi"
+)
+
+// maxTemporary limita cuánto puede durar un cambio temporal de nivel
+const maxTemporary = 24 * time.Hour
+
+// LevelRequest es el body de PUT /admin/loglevel
+// For (opcional, ej: "15m") vuelve al nivel anterior después de ese tiempo
+type LevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"`
+	For   string `json:"for,omitempty"`
+}
+
+// LevelResponse es la respuesta de GET y PUT /admin/loglevel
+type LevelResponse struct {
+	Level string `json:"level"`
+}
+
+// Handler sirve GET (nivel actual) y PUT (cambiarlo) de /admin/loglevel
+// La auth de admin la pone quien lo monta
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if err := applyRequest(r); err != nil {
+				httpmw.WriteError(w, r, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(LevelResponse{Level: CurrentLevel().String()})
+	})
+}
+
+// applyRequest valida el body y cambia el nivel
+func applyRequest(r *http.Request) error {
+	var req LevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apperrors.BadRequest("invalid JSON body")
+	}
+
+	level, err := ParseLevel(req.Level)
+	if err != nil {
+		return apperrors.Validation(err.Error())
+	}
+	if req.For == "" {
+		SetLevel(level)
+		notice.Printf("🔧 Nivel de log cambiado a %s", level)
+		return nil
+	}
+
+	d, err := time.ParseDuration(req.For)
+	if err != nil || d <= 0 || d > maxTemporary {
+		return apperrors.Validation(`"for" must be a duration like "15m" (max 24h)`)
+	}
+	SetLevelFor(level, d)
+	notice.Printf("🔧 Nivel de log cambiado a %s por %s", level, d)
+	return nil
+}
+
+// AddOperations documenta GET y PUT /admin/loglevel en el OpenAPI del
+// servicio (montadas detrás de auth de admin)
+func AddOperations(spec *openapi.Spec) {
+	errors := []int{http.StatusUnauthorized, http.StatusForbidden}
+	spec.Add(openapi.Operation{Method: "GET", Path: "/admin/loglevel", Summary: "Nivel de log actual", Tags: []string{"admin"},
+		Auth: true, Reply: LevelResponse{}, Errors: errors})
+	spec.Add(openapi.Operation{Method: "PUT", Path: "/admin/loglevel", Summary: "Cambiar el nivel de log (opcional: por un tiempo)", Tags: []string{"admin"},
+		Auth: true, Request: LevelRequest{}, Reply: LevelResponse{}, Errors: append(errors, http.StatusBadRequest)})
+}
@@ -0,0 +1,179 @@
+// Package logging agrega niveles (debug, info, warn, error) al log estándar
+// que usan todos los servicios, y permite cambiarlos con el servicio corriendo
+//
+// Los log.Printf existentes no cambian: el nivel de cada línea sale del emoji
+// que ya usan por convención (❌/💥 error, ⚠️ warn, 🐛 debug, el resto info).
+// Install reemplaza la salida del log por una que descarta lo que está por
+// debajo del nivel actual. El nivel se cambia con:
+//   - LOG_LEVEL al arrancar
+//   - PUT /admin/loglevel {"level": "debug", "for": "15m"} (Handler, solo admins)
+//   - SIGUSR1: alterna entre debug y el nivel anterior
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"shared/config"
+)
+
+// Level es el nivel mínimo de las líneas que se escriben
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// Names son los niveles válidos (para LOG_LEVEL)
+func Names() []string {
+	return append([]string(nil), levelNames...)
+}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int32(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel convierte "debug", "info", "warn" o "error" en un Level
+func ParseLevel(name string) (Level, error) {
+	for i, candidate := range levelNames {
+		if strings.EqualFold(name, candidate) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (expected one of %s)", name, strings.Join(levelNames, ", "))
+}
+
+var (
+	current atomic.Int32 // Level actual
+
+	mu       sync.Mutex
+	revert   *time.Timer // vuelve al nivel anterior después de un cambio temporal
+	previous = LevelInfo // nivel al que vuelve SIGUSR1
+
+	// notice escribe los cambios de nivel sin pasar por el filtro: se tienen
+	// que ver aunque el nivel sea warn o error
+	notice = log.New(os.Stderr, "", log.LstdFlags)
+)
+
+func init() {
+	current.Store(int32(LevelInfo))
+}
+
+// CurrentLevel devuelve el nivel actual
+func CurrentLevel() Level {
+	return Level(current.Load())
+}
+
+// Enabled indica si las líneas de ese nivel se escriben
+func Enabled(level Level) bool {
+	return level >= CurrentLevel()
+}
+
+// SetLevel cambia el nivel (cancela un cambio temporal pendiente)
+func SetLevel(level Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	setLocked(level)
+}
+
+// SetLevelFor cambia el nivel durante d y después vuelve al que había
+// Sirve para subir a debug durante un incidente sin olvidarse de bajarlo
+func SetLevelFor(level Level, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	back := CurrentLevel()
+	setLocked(level)
+	revert = time.AfterFunc(d, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		setLocked(back)
+		notice.Printf("🔧 Nivel de log restaurado a %s", back)
+	})
+}
+
+func setLocked(level Level) {
+	if revert != nil {
+		revert.Stop()
+		revert = nil
+	}
+	if old := CurrentLevel(); old != level {
+		previous = old
+	}
+	current.Store(int32(level))
+}
+
+// LevelFromEnv lee LOG_LEVEL (debug, info, warn o error; info por defecto)
+func LevelFromEnv(env *config.Env) Level {
+	level, _ := ParseLevel(env.OneOf("LOG_LEVEL", "info", levelNames...))
+	return level
+}
+
+// Install fija el nivel inicial, filtra la salida del log estándar y
+// escucha SIGUSR1 (donde el sistema lo soporta)
+func Install(level Level) {
+	SetLevel(level)
+	log.SetOutput(&filterWriter{out: os.Stderr})
+	watchSignal()
+}
+
+// toggleDebug alterna entre debug y el nivel anterior (SIGUSR1)
+func toggleDebug() Level {
+	mu.Lock()
+	defer mu.Unlock()
+
+	next := LevelDebug
+	if CurrentLevel() == LevelDebug {
+		next = previous
+	}
+	setLocked(next)
+	return next
+}
+
+// Debugf escribe una línea de debug (se descarta salvo con nivel debug)
+func Debugf(format string, args ...interface{}) {
+	if Enabled(LevelDebug) {
+		log.Printf("🐛 "+format, args...)
+	}
+}
+
+// classify deduce el nivel de una línea por su emoji
+func classify(line []byte) Level {
+	switch {
+	case bytes.Contains(line, []byte("❌")), bytes.Contains(line, []byte("💥")):
+		return LevelError
+	case bytes.Contains(line, []byte("⚠️")):
+		return LevelWarn
+	case bytes.Contains(line, []byte("🐛")):
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// filterWriter descarta las líneas por debajo del nivel actual
+// El paquete log hace un Write por línea, así se puede decidir línea a línea
+type filterWriter struct {
+	out io.Writer
+}
+
+func (w *filterWriter) Write(p []byte) (int, error) {
+	if !Enabled(classify(p)) {
+		return len(p), nil
+	}
+	return w.out.Write(p)
+}
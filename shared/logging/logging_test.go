@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test: el nivel de cada línea sale del emoji
+func TestFilterWriter(t *testing.T) {
+	defer SetLevel(LevelInfo)
+	var out bytes.Buffer
+	w := &filterWriter{out: &out}
+
+	SetLevel(LevelWarn)
+	for _, line := range []string{"✅ conectado\n", "⚠️  reintento\n", "❌ falló\n", "🐛 detalle\n"} {
+		w.Write([]byte(line))
+	}
+	if got := out.String(); got != "⚠️  reintento\n❌ falló\n" {
+		t.Errorf("Unexpected output with level warn: %q", got)
+	}
+
+	out.Reset()
+	SetLevel(LevelDebug)
+	w.Write([]byte("🐛 detalle\n"))
+	if out.Len() == 0 {
+		t.Error("Expected debug lines with level debug")
+	}
+}
+
+// Test: un cambio temporal vuelve solo al nivel anterior
+func TestSetLevelFor(t *testing.T) {
+	defer SetLevel(LevelInfo)
+	SetLevel(LevelWarn)
+
+	SetLevelFor(LevelDebug, 20*time.Millisecond)
+	if CurrentLevel() != LevelDebug {
+		t.Fatalf("Expected debug, got %s", CurrentLevel())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if CurrentLevel() != LevelWarn {
+		t.Errorf("Expected level to go back to warn, got %s", CurrentLevel())
+	}
+}
+
+// Test: SIGUSR1 alterna entre debug y el nivel anterior
+func TestToggleDebug(t *testing.T) {
+	defer SetLevel(LevelInfo)
+	SetLevel(LevelError)
+
+	if got := toggleDebug(); got != LevelDebug {
+		t.Errorf("Expected debug, got %s", got)
+	}
+	if got := toggleDebug(); got != LevelError {
+		t.Errorf("Expected error, got %s", got)
+	}
+}
+
+// Test: PUT cambia el nivel y valida el body
+func TestHandler(t *testing.T) {
+	defer SetLevel(LevelInfo)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(`{"level":"debug"}`)))
+	if rec.Code != http.StatusOK || CurrentLevel() != LevelDebug {
+		t.Fatalf("Expected level debug, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	for _, body := range []string{`{"level":"verbose"}`, `{"level":"warn","for":"forever"}`, `not json`} {
+		rec = httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/loglevel", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
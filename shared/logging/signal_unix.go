@@ -0,0 +1,25 @@
+//go:build !windows
+
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var watchOnce sync.Once
+
+// watchSignal alterna debug con cada SIGUSR1: kill -USR1 <pid>
+func watchSignal() {
+	watchOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGUSR1)
+		go func() {
+			for range signals {
+				notice.Printf("🔧 SIGUSR1: nivel de log %s", toggleDebug())
+			}
+		}()
+	})
+}
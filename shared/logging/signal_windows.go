@@ -0,0 +1,4 @@
+package logging
+
+// En Windows no hay SIGUSR1: el nivel se cambia solo por LOG_LEVEL o el endpoint
+func watchSignal() {}
@@ -0,0 +1,223 @@
+// Package openapi arma el documento OpenAPI 3 de cada servicio a partir de
+// sus rutas y de los mismos structs (DTOs, modelos) que usan los handlers
+//
+// Los schemas salen por reflection de los tags json y binding, así un campo
+// nuevo o una validación nueva en un DTO aparecen solos en /openapi.json.
+// Las respuestas de error usan siempre el sobre de shared/apperrors
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"shared/apperrors"
+)
+
+// Version es la versión de OpenAPI que genera el paquete
+const Version = "3.0.3"
+
+// errorSchemaName es el nombre del schema del sobre de error común
+const errorSchemaName = "ErrorResponse"
+
+// Operation describe un endpoint
+type Operation struct {
+	Method  string // GET, POST...
+	Path    string // con la sintaxis de Gin: /users/:id
+	Summary string
+	Tags    []string
+
+	Auth       bool     // requiere JWT (Bearer)
+	Query      []string // parámetros de query opcionales (ej: "page")
+	Deprecated bool     // ruta vieja que se mantiene por compatibilidad
+
+	Request interface{}         // body JSON (nil si no tiene)
+	Status  int                 // status de la respuesta exitosa (200 por defecto)
+	Reply   interface{}         // body de la respuesta exitosa (nil = sin schema)
+	Errors  []int               // status de error posibles (usan el sobre común)
+	Extra   map[int]interface{} // otras respuestas con body propio (ej: 503 de /readyz)
+}
+
+// Spec es el documento de un servicio
+type Spec struct {
+	title   string
+	version string
+
+	paths   map[string]map[string]interface{}
+	schemas *schemaRegistry
+}
+
+// New crea un documento vacío (ej: New("users-api", "1.0.0"))
+func New(title, version string) *Spec {
+	s := &Spec{
+		title:   title,
+		version: version,
+		paths:   make(map[string]map[string]interface{}),
+		schemas: newSchemaRegistry(),
+	}
+	s.schemas.register(errorSchemaName, reflect.TypeOf(apperrors.Response{}))
+	return s
+}
+
+// Add agrega un endpoint al documento
+func (s *Spec) Add(op Operation) {
+	path, params := convertPath(op.Path)
+	for _, name := range op.Query {
+		params = append(params, map[string]interface{}{
+			"name":   name,
+			"in":     "query",
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+
+	operation := map[string]interface{}{
+		"summary":     op.Summary,
+		"operationId": operationID(op.Method, op.Path),
+		"responses":   s.responses(op),
+	}
+	if len(op.Tags) > 0 {
+		operation["tags"] = op.Tags
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+	if op.Deprecated {
+		operation["deprecated"] = true
+	}
+	if op.Auth {
+		operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+	}
+	if op.Request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(s.schemas.schemaFor(reflect.TypeOf(op.Request))),
+		}
+	}
+
+	if s.paths[path] == nil {
+		s.paths[path] = make(map[string]interface{})
+	}
+	s.paths[path][strings.ToLower(op.Method)] = operation
+}
+
+// responses arma la respuesta exitosa y las de error
+func (s *Spec) responses(op Operation) map[string]interface{} {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	responses := map[string]interface{}{}
+	ok := map[string]interface{}{"description": http.StatusText(status)}
+	if op.Reply != nil {
+		ok["content"] = jsonContent(s.schemas.schemaFor(reflect.TypeOf(op.Reply)))
+	}
+	responses[statusKey(status)] = ok
+
+	for _, code := range op.Errors {
+		responses[statusKey(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content":     jsonContent(refTo(errorSchemaName)),
+		}
+	}
+	for code, body := range op.Extra {
+		responses[statusKey(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content":     jsonContent(s.schemas.schemaFor(reflect.TypeOf(body))),
+		}
+	}
+	return responses
+}
+
+// Has indica si el documento tiene el endpoint (con la sintaxis de Gin)
+// Sirve para testear que todas las rutas del router están documentadas
+func (s *Spec) Has(method, ginPath string) bool {
+	path, _ := convertPath(ginPath)
+	_, ok := s.paths[path][strings.ToLower(method)]
+	return ok
+}
+
+// Document devuelve el documento completo listo para serializar
+func (s *Spec) Document() map[string]interface{} {
+	return map[string]interface{}{
+		"openapi": Version,
+		"info": map[string]interface{}{
+			"title":   s.title,
+			"version": s.version,
+		},
+		"paths": s.paths,
+		"components": map[string]interface{}{
+			"schemas": s.schemas.schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+}
+
+// Handler sirve el documento en JSON (para GET /openapi.json)
+// El JSON se arma una sola vez: las rutas no cambian con el servicio corriendo
+func (s *Spec) Handler() http.Handler {
+	body, err := json.MarshalIndent(s.Document(), "", "  ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(body)
+	})
+}
+
+// convertPath pasa /users/:id a /users/{id} y arma los parámetros de path
+// Los que terminan en "id" son enteros, el resto strings
+func convertPath(ginPath string) (string, []map[string]interface{}) {
+	var params []map[string]interface{}
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+
+		paramType := "string"
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			paramType = "integer"
+		}
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": paramType},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID arma un ID estable a partir del método y el path
+// Ejemplo: GET /users/:id -> get_users_id
+func operationID(method, ginPath string) string {
+	id := strings.ToLower(method) + strings.NewReplacer("/", "_", ":", "", "-", "_", "*", "").Replace(ginPath)
+	return strings.TrimSuffix(id, "_")
+}
+
+func statusKey(status int) string {
+	return strconv.Itoa(status)
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+func refTo(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
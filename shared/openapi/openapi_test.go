@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type createThing struct {
+	Name    string    `json:"name" binding:"required,min=3,max=50"`
+	Email   string    `json:"email" binding:"required,email"`
+	Count   int       `json:"count,omitempty" binding:"omitempty,min=1"`
+	Secret  string    `json:"-"`
+	Owner   *owner    `json:"owner,omitempty"`
+	Tags    []string  `json:"tags"`
+	Created time.Time `json:"created_at"`
+}
+
+type owner struct {
+	ID uint `json:"id"`
+}
+
+// decode serializa el documento y lo vuelve a leer como JSON genérico
+func decode(t *testing.T, spec *Spec) map[string]interface{} {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	spec.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func get(doc interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = m[key]
+	}
+	return doc
+}
+
+func TestSchemaFromTags(t *testing.T) {
+	spec := New("test-api", "1.0.0")
+	spec.Add(Operation{Method: "POST", Path: "/things", Request: createThing{}, Status: 201, Reply: createThing{}, Errors: []int{400, 409}})
+	doc := decode(t, spec)
+
+	thing := get(doc, "components", "schemas", "createThing")
+	if thing == nil {
+		t.Fatal("expected createThing schema")
+	}
+	props := get(thing, "properties").(map[string]interface{})
+
+	if _, ok := props["Secret"]; ok {
+		t.Error(`json:"-" field must be hidden`)
+	}
+	if get(props, "name", "minLength") != float64(3) || get(props, "name", "maxLength") != float64(50) {
+		t.Errorf("expected name length limits, got %v", props["name"])
+	}
+	if get(props, "email", "format") != "email" {
+		t.Errorf("expected email format, got %v", props["email"])
+	}
+	if get(props, "count", "minimum") != float64(1) {
+		t.Errorf("expected count minimum, got %v", props["count"])
+	}
+	if get(props, "created_at", "format") != "date-time" {
+		t.Errorf("expected date-time, got %v", props["created_at"])
+	}
+	if get(props, "owner", "nullable") != true {
+		t.Errorf("expected nullable owner ref, got %v", props["owner"])
+	}
+
+	required := get(thing, "required").([]interface{})
+	if len(required) != 2 || required[0] != "name" || required[1] != "email" {
+		t.Errorf("expected [name email] required, got %v", required)
+	}
+}
+
+func TestOperationPathsAndErrors(t *testing.T) {
+	spec := New("test-api", "1.0.0")
+	spec.Add(Operation{Method: "PUT", Path: "/things/:id", Auth: true, Request: createThing{}, Errors: []int{404}})
+	doc := decode(t, spec)
+
+	op := get(doc, "paths", "/things/{id}", "put")
+	if op == nil {
+		t.Fatal("expected PUT /things/{id}")
+	}
+	if get(op, "operationId") != "put_things_id" {
+		t.Errorf("unexpected operationId %v", get(op, "operationId"))
+	}
+	params := get(op, "parameters").([]interface{})
+	if get(params[0], "in") != "path" || get(params[0], "schema", "type") != "integer" {
+		t.Errorf("expected integer path param, got %v", params[0])
+	}
+	if get(op, "responses", "404", "content", "application/json", "schema", "$ref") != "#/components/schemas/ErrorResponse" {
+		t.Errorf("expected error envelope on 404, got %v", get(op, "responses", "404"))
+	}
+	if get(op, "security") == nil {
+		t.Error("expected bearer security")
+	}
+
+	if !spec.Has("PUT", "/things/:id") || spec.Has("GET", "/things/:id") {
+		t.Error("Has does not match the added operations")
+	}
+}
@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry guarda los schemas con nombre (components/schemas)
+// Cada struct se registra una vez y después se referencia con $ref
+type schemaRegistry struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]interface{}),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// register agrega un struct con un nombre elegido
+func (r *schemaRegistry) register(name string, t reflect.Type) {
+	r.names[t] = name
+	r.schemas[name] = r.structSchema(t)
+}
+
+// schemaFor devuelve el schema de un tipo (un $ref si es un struct)
+func (r *schemaRegistry) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		return refTo(r.nameFor(t))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": r.schemaFor(t.Elem())}
+	}
+
+	// interface{} y cualquier otra cosa: cualquier valor JSON
+	return map[string]interface{}{}
+}
+
+// nameFor registra el struct si hace falta y devuelve su nombre
+// Si dos paquetes tienen un struct con el mismo nombre se agrega el paquete
+func (r *schemaRegistry) nameFor(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = "Object"
+	}
+	if _, taken := r.schemas[name]; taken {
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Se reserva el nombre antes de recorrer los campos (structs recursivos)
+	r.names[t] = name
+	r.schemas[name] = map[string]interface{}{}
+	r.schemas[name] = r.structSchema(t)
+	return name
+}
+
+// structSchema arma el schema de un struct a partir de sus tags
+//   - json: nombre del campo, "-" lo oculta
+//   - binding: required, email, min, max, oneof (las mismas reglas que valida Gin)
+//
+// Los structs embebidos sin tag json se aplanan, igual que en encoding/json
+func (r *schemaRegistry) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	r.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (r *schemaRegistry) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			r.addFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := r.schemaFor(field.Type)
+		if field.Type.Kind() == reflect.Ptr {
+			schema = withNullable(schema)
+		}
+		if applyBinding(schema, field.Tag.Get("binding")) {
+			*required = append(*required, name)
+		}
+		properties[name] = schema
+	}
+}
+
+// applyBinding traduce las reglas de validación a restricciones del schema
+// Devuelve true si el campo es requerido
+func applyBinding(schema map[string]interface{}, binding string) bool {
+	if binding == "" {
+		return false
+	}
+
+	required := false
+	isString := schema["type"] == "string"
+	for _, rule := range strings.Split(binding, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			schema["format"] = "email"
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			switch {
+			case isString && key == "min":
+				schema["minLength"] = n
+			case isString:
+				schema["maxLength"] = n
+			case key == "min":
+				schema["minimum"] = n
+			default:
+				schema["maximum"] = n
+			}
+		case "oneof":
+			schema["enum"] = strings.Fields(param)
+		}
+	}
+	return required
+}
+
+// withNullable marca un schema como opcional a nivel JSON (puntero)
+// A un $ref no se le pueden agregar claves en OpenAPI 3.0: se envuelve en allOf
+func withNullable(schema map[string]interface{}) map[string]interface{} {
+	if _, isRef := schema["$ref"]; isRef {
+		return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+	}
+	schema["nullable"] = true
+	return schema
+}
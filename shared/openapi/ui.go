@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// swaggerUI es la página de Swagger UI; los assets vienen del CDN
+var swaggerUI = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}} - API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUI sirve la página de Swagger UI que lee el documento de specURL
+// Ejemplo: router.GET("/docs", gin.WrapH(openapi.SwaggerUI("users-api", "/openapi.json")))
+func SwaggerUI(title, specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		swaggerUI.Execute(w, struct{ Title, SpecURL string }{title, specURL})
+	})
+}
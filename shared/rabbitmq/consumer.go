@@ -0,0 +1,270 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"shared/requestid"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// retryHeader cuenta cuántas veces se reintentó un mensaje
+const retryHeader = "x-retry-count"
+
+// Handler procesa un mensaje. Si devuelve nil se hace Ack; si devuelve un
+// error se reintenta, salvo que sea Permanent(err), que va directo a la DLQ
+// El ctx trae el request ID que venía en el mensaje (o uno nuevo)
+type Handler func(ctx context.Context, msg amqp.Delivery) error
+
+// permanentError marca errores que no tiene sentido reintentar
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent envuelve un error para que el mensaje vaya a la DLQ sin reintentos
+// Ejemplo: un payload inválido va a fallar igual las próximas veces
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent indica si el error (o alguno de los que envuelve) es permanente
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// ConsumerConfig describe la cola de un servicio y su topología
+//
+// Para Queue = "notifications" se declaran:
+//   - notifications: cola principal, bindeada a Exchange con Bindings
+//   - notifications.retry: cola con TTL RetryDelay, al expirar vuelve a la principal
+//   - notifications.dlx / notifications.dlq: mensajes permanentes o sin más reintentos
+type ConsumerConfig struct {
+	Exchange   string
+	Queue      string
+	Bindings   []string
+	Tag        string        // nombre del consumer en el panel de RabbitMQ
+	Prefetch   int           // mensajes en vuelo (1 si es 0)
+	MaxRetries int           // reintentos antes de la DLQ
+	RetryDelay time.Duration // espera entre reintentos
+}
+
+func (cfg ConsumerConfig) retryQueue() string   { return cfg.Queue + ".retry" }
+func (cfg ConsumerConfig) deadExchange() string { return cfg.Queue + ".dlx" }
+func (cfg ConsumerConfig) deadQueue() string    { return cfg.Queue + ".dlq" }
+
+// Consumer consume una cola y sobrevive a las reconexiones:
+// si se cae el canal vuelve a declarar la topología y a consumir
+type Consumer struct {
+	conn    *Connection
+	cfg     ConsumerConfig
+	handler Handler
+}
+
+// NewConsumer crea un consumer (no consume hasta llamar a Run)
+func NewConsumer(conn *Connection, cfg ConsumerConfig, handler Handler) *Consumer {
+	if cfg.Prefetch <= 0 {
+		cfg.Prefetch = 1
+	}
+	return &Consumer{conn: conn, cfg: cfg, handler: handler}
+}
+
+// Run consume hasta que se cancele ctx o se cierre la conexión
+// Cancelar ctx no corta el mensaje en curso: termina, se hace Ack y recién
+// ahí Run vuelve (los que RabbitMQ había mandado por prefetch vuelven a la cola)
+// Un error al configurar la primera vez se devuelve; después de una caída
+// se reintenta con backoff hasta que la conexión vuelva
+func (c *Consumer) Run(ctx context.Context) error {
+	ch, msgs, err := c.subscribe()
+	if err != nil {
+		return err
+	}
+	log.Printf("📥 Escuchando %v en la cola %s", c.cfg.Bindings, c.cfg.Queue)
+
+	for {
+		c.consume(ctx, ch, msgs)
+		ch.Close()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Se cayó el canal: esperamos a que la conexión vuelva
+		delay := initialReconnectDelay
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(delay):
+			}
+
+			ch, msgs, err = c.subscribe()
+			if errors.Is(err, ErrClosed) {
+				return nil
+			}
+			if err == nil {
+				log.Printf("✅ Cola %s suscripta de nuevo", c.cfg.Queue)
+				break
+			}
+			delay = nextDelay(delay)
+		}
+	}
+}
+
+// subscribe abre un canal, declara la topología y empieza a consumir
+func (c *Consumer) subscribe() (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.declareTopology(ch); err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+	if err := ch.Qos(c.cfg.Prefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+
+	msgs, err := ch.Consume(c.cfg.Queue, c.cfg.Tag, false, false, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+	return ch, msgs, nil
+}
+
+// declareTopology crea exchanges, colas y bindings (es idempotente)
+func (c *Consumer) declareTopology(ch *amqp.Channel) error {
+	cfg := c.cfg
+	if err := ch.ExchangeDeclare(cfg.Exchange, "topic", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.ExchangeDeclare(cfg.deadExchange(), "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	// Cola principal: los Nack sin requeue van a la DLQ
+	if _, err := ch.QueueDeclare(cfg.Queue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange": cfg.deadExchange(),
+	}); err != nil {
+		return err
+	}
+	for _, key := range cfg.Bindings {
+		if err := ch.QueueBind(cfg.Queue, key, cfg.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	// Cola de reintentos: nadie la consume, al vencer el TTL
+	// el mensaje vuelve a la cola principal por el default exchange
+	if _, err := ch.QueueDeclare(cfg.retryQueue(), true, false, false, false, amqp.Table{
+		"x-message-ttl":             int64(cfg.RetryDelay / time.Millisecond),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": cfg.Queue,
+	}); err != nil {
+		return err
+	}
+
+	// DLQ: queda para inspección manual desde el panel de RabbitMQ
+	if _, err := ch.QueueDeclare(cfg.deadQueue(), true, false, false, false, nil); err != nil {
+		return err
+	}
+	return ch.QueueBind(cfg.deadQueue(), "", cfg.deadExchange(), false, nil)
+}
+
+// consume procesa mensajes hasta que se cierre el canal o se cancele ctx
+func (c *Consumer) consume(ctx context.Context, ch *amqp.Channel, msgs <-chan amqp.Delivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			c.handle(ctx, ch, msg)
+		}
+	}
+}
+
+// handle decide qué hacer con cada mensaje:
+// Ack si se procesó, reintento si falló, DLQ si es permanente o agotó los reintentos
+// Un panic en el handler no corta el loop: el mensaje va a la DLQ
+// (lo más probable es que vuelva a explotar si se reintenta)
+func (c *Consumer) handle(ctx context.Context, ch *amqp.Channel, msg amqp.Delivery) {
+	id, _ := msg.Headers[requestid.Header].(string)
+	ctx = requestid.WithContext(context.WithoutCancel(ctx), requestid.Ensure(id))
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			requestid.Logf(ctx, "💥 panic procesando %s, va a la DLQ: %v\n%s", msg.RoutingKey, recovered, debug.Stack())
+			msg.Nack(false, false)
+		}
+	}()
+
+	err := c.handler(ctx, msg)
+	if err == nil {
+		msg.Ack(false)
+		return
+	}
+
+	if IsPermanent(err) {
+		requestid.Logf(ctx, "❌ Mensaje %s inválido, va a la DLQ: %v", msg.RoutingKey, err)
+		msg.Nack(false, false)
+		return
+	}
+
+	retries := RetryCount(msg)
+	if retries >= c.cfg.MaxRetries {
+		requestid.Logf(ctx, "❌ Mensaje %s agotó %d reintentos, va a la DLQ: %v", msg.RoutingKey, retries, err)
+		msg.Nack(false, false)
+		return
+	}
+
+	requestid.Logf(ctx, "⚠️  Error procesando %s (intento %d/%d): %v", msg.RoutingKey, retries+1, c.cfg.MaxRetries, err)
+	if err := c.scheduleRetry(ch, msg, retries+1); err != nil {
+		// Si no pudimos publicar el reintento, lo devolvemos a la cola
+		requestid.Logf(ctx, "❌ No se pudo programar el reintento: %v", err)
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// scheduleRetry republica el mensaje en la cola de reintentos
+func (c *Consumer) scheduleRetry(ch *amqp.Channel, msg amqp.Delivery, attempt int) error {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[retryHeader] = int32(attempt)
+
+	return ch.Publish("", c.cfg.retryQueue(), false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Headers:      headers,
+		Body:         msg.Body,
+	})
+}
+
+// RetryCount lee cuántas veces se reintentó el mensaje
+func RetryCount(msg amqp.Delivery) int {
+	switch v := msg.Headers[retryHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"shared/requestid"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNotConfirmed indica que el broker rechazó (nack) el mensaje
+var ErrNotConfirmed = errors.New("rabbitmq: message not confirmed by broker")
+
+// confirmTimeout es cuánto esperamos el ack del broker si el ctx no tiene deadline
+const confirmTimeout = 5 * time.Second
+
+// Publisher publica mensajes persistentes en un exchange topic
+// Usa publisher confirms: Publish vuelve recién cuando el broker guardó el
+// mensaje, así un error acá significa que el evento NO se publicó
+type Publisher struct {
+	conn     *Connection
+	exchange string
+
+	mu sync.Mutex
+	ch *amqp.Channel
+}
+
+// NewPublisher declara el exchange y abre el canal en modo confirmación
+func NewPublisher(conn *Connection, exchange string) (*Publisher, error) {
+	p := &Publisher{conn: conn, exchange: exchange}
+	if _, err := p.channel(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// channel devuelve el canal actual o abre uno nuevo si se cerró
+// (por ejemplo después de una reconexión). Se llama con p.mu tomado
+func (p *Publisher) channel() (*amqp.Channel, error) {
+	if p.ch != nil && !p.ch.IsClosed() {
+		return p.ch, nil
+	}
+
+	ch, err := p.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.ExchangeDeclare(p.exchange, "topic", true, false, false, false, nil); err != nil {
+		ch.Close()
+		return nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	p.ch = ch
+	return ch, nil
+}
+
+// Publish publica el mensaje con la routing key y espera la confirmación
+// Si ctx trae un request ID se agrega en el header X-Request-ID
+func (p *Publisher) Publish(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		headers := amqp.Table{requestid.Header: id}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		msg.Headers = headers
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, confirmTimeout)
+		defer cancel()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, err := p.channel()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, routingKey, false, false, msg)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+// Close cierra el canal del publisher (la conexión la cierra quien la abrió)
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ch == nil {
+		return nil
+	}
+	return p.ch.Close()
+}
@@ -0,0 +1,112 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Test: Permanent se detecta aunque esté envuelto
+func TestIsPermanent(t *testing.T) {
+	base := errors.New("invalid payload")
+
+	if IsPermanent(base) {
+		t.Error("Expected plain error not to be permanent")
+	}
+	if !IsPermanent(Permanent(base)) {
+		t.Error("Expected Permanent error to be permanent")
+	}
+	if !IsPermanent(fmt.Errorf("handling: %w", Permanent(base))) {
+		t.Error("Expected wrapped Permanent error to be permanent")
+	}
+	if !errors.Is(Permanent(base), base) {
+		t.Error("Expected Permanent to keep the original error")
+	}
+}
+
+// Test: el contador de reintentos acepta los tipos que devuelve el broker
+func TestRetryCount(t *testing.T) {
+	tests := []struct {
+		headers amqp.Table
+		want    int
+	}{
+		{nil, 0},
+		{amqp.Table{retryHeader: int32(2)}, 2},
+		{amqp.Table{retryHeader: int64(3)}, 3},
+		{amqp.Table{retryHeader: "x"}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := RetryCount(amqp.Delivery{Headers: tt.headers}); got != tt.want {
+			t.Errorf("RetryCount(%v) = %d, want %d", tt.headers, got, tt.want)
+		}
+	}
+}
+
+// Test: la topología se nombra a partir de la cola
+func TestConsumerConfigNames(t *testing.T) {
+	cfg := ConsumerConfig{Queue: "notifications"}
+
+	if cfg.retryQueue() != "notifications.retry" || cfg.deadExchange() != "notifications.dlx" || cfg.deadQueue() != "notifications.dlq" {
+		t.Errorf("Unexpected topology names: %s %s %s", cfg.retryQueue(), cfg.deadExchange(), cfg.deadQueue())
+	}
+}
+
+// Test: el backoff de reconexión se duplica hasta el máximo
+func TestNextDelay(t *testing.T) {
+	if got := nextDelay(time.Second); got != 2*time.Second {
+		t.Errorf("Expected 2s, got %s", got)
+	}
+	if got := nextDelay(20 * time.Second); got != maxReconnectDelay {
+		t.Errorf("Expected %s, got %s", maxReconnectDelay, got)
+	}
+}
+
+// fakeAcknowledger registra qué se hizo con el mensaje
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeue = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// Test: Ack si se procesó; DLQ si es permanente o si el handler hace panic
+func TestConsumerHandle(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler Handler
+		acked   bool
+	}{
+		{"success", func(ctx context.Context, msg amqp.Delivery) error { return nil }, true},
+		{"permanent", func(ctx context.Context, msg amqp.Delivery) error { return Permanent(errors.New("bad")) }, false},
+		{"panic", func(ctx context.Context, msg amqp.Delivery) error { panic("boom") }, false},
+	}
+
+	for _, tt := range tests {
+		ack := &fakeAcknowledger{}
+		consumer := NewConsumer(nil, ConsumerConfig{Queue: "test", MaxRetries: 3}, tt.handler)
+
+		consumer.handle(context.Background(), nil, amqp.Delivery{Acknowledger: ack, RoutingKey: "user.created"})
+
+		if ack.acked != tt.acked || ack.nacked == tt.acked || ack.requeue {
+			t.Errorf("%s: unexpected ack state %+v", tt.name, ack)
+		}
+	}
+}
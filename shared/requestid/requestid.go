@@ -0,0 +1,115 @@
+// Package requestid maneja el header X-Request-ID que identifica una request
+// de punta a punta: se genera en el primer servicio que la recibe y viaja en
+// cada llamada HTTP saliente y en los headers de los mensajes de RabbitMQ,
+// así todos los logs de una misma operación se pueden correlacionar
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Header es el nombre del header HTTP (y del header AMQP) que lleva el ID
+const Header = "X-Request-ID"
+
+// maxLength limita el largo de un ID recibido de afuera
+const maxLength = 128
+
+type contextKey struct{}
+
+// New genera un ID aleatorio de 32 caracteres hex
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid indica si un ID recibido de afuera se puede reutilizar
+// Solo se aceptan letras, números, '-', '_' y '.' para no ensuciar los logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Ensure devuelve el ID si es válido o uno nuevo si no
+func Ensure(id string) string {
+	if Valid(id) {
+		return id
+	}
+	return New()
+}
+
+// WithContext guarda el ID en el contexto
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext devuelve el ID guardado en el contexto ("" si no hay)
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logf loguea con el ID de la request como prefijo
+// Ejemplo: "[req=4f1c...] ⚠️  Error chequeando login"
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if id := FromContext(ctx); id != "" {
+		format = fmt.Sprintf("[req=%s] %s", id, format)
+	}
+	log.Printf(format, args...)
+}
+
+// Middleware es el equivalente para net/http del middleware de Gin:
+// reutiliza el X-Request-ID entrante (o genera uno), lo guarda en el
+// contexto y lo devuelve en la respuesta
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := Ensure(r.Header.Get(Header))
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), id)))
+	})
+}
+
+// Transport agrega el X-Request-ID del contexto a las requests salientes
+// Uso: &http.Client{Transport: requestid.NewTransport(nil)}
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport envuelve base (http.DefaultTransport si es nil)
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	// Un RoundTripper no debe modificar la request original
+	clone := req.Clone(req.Context())
+	clone.Header.Set(Header, id)
+	return t.Base.RoundTrip(clone)
+}
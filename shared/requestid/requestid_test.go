@@ -0,0 +1,69 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test: solo se reutilizan IDs entrantes "limpios"
+func TestEnsure(t *testing.T) {
+	if got := Ensure("abc-123_x.y"); got != "abc-123_x.y" {
+		t.Errorf("Expected valid ID to be kept, got %q", got)
+	}
+
+	for _, id := range []string{"", "bad id", "line\nbreak", strings.Repeat("a", maxLength+1)} {
+		got := Ensure(id)
+		if got == id || len(got) != 32 {
+			t.Errorf("Expected a new ID for %q, got %q", id, got)
+		}
+	}
+}
+
+// Test: el middleware reutiliza el header entrante y lo devuelve
+func TestMiddleware_PropagatesIncomingID(t *testing.T) {
+	var seen string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "req-1" {
+		t.Errorf("Expected context ID req-1, got %q", seen)
+	}
+	if rec.Header().Get(Header) != "req-1" {
+		t.Errorf("Expected response header req-1, got %q", rec.Header().Get(Header))
+	}
+}
+
+// Test: el transport agrega el header a las llamadas salientes
+func TestTransport_SetsHeaderFromContext(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(Header)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+	ctx := WithContext(context.Background(), "req-2")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if received != "req-2" {
+		t.Errorf("Expected outbound header req-2, got %q", received)
+	}
+	if req.Header.Get(Header) != "" {
+		t.Error("Expected original request not to be modified")
+	}
+}
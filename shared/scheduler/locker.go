@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Locker asegura que un job corra en una sola instancia a la vez
+// TryLock no bloquea: si otro tiene el lock devuelve ok=false
+type Locker interface {
+	TryLock(ctx context.Context, name string) (release func(), ok bool, err error)
+}
+
+// localLocker es un lock en memoria: sirve cuando hay una sola instancia
+type localLocker struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// NewLocalLocker crea un locker en memoria (una sola instancia o tests)
+func NewLocalLocker() Locker {
+	return &localLocker{running: make(map[string]bool)}
+}
+
+// TryLock toma el lock si nadie lo tiene
+func (l *localLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running[name] {
+		return nil, false, nil
+	}
+	l.running[name] = true
+
+	return func() {
+		l.mu.Lock()
+		delete(l.running, name)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// mysqlLocker usa GET_LOCK de MySQL, que es compartido por todas las instancias
+// El lock pertenece a la conexión, por eso se reserva una conexión del pool
+// mientras dura el job y se libera al terminar (o si la instancia muere)
+type mysqlLocker struct {
+	db     *sql.DB
+	prefix string
+}
+
+// NewMySQLLocker crea un locker distribuido sobre MySQL
+// prefix evita choques entre servicios (ej: "users-api:")
+func NewMySQLLocker(db *sql.DB, prefix string) Locker {
+	return &mysqlLocker{db: db, prefix: prefix}
+}
+
+// TryLock hace SELECT GET_LOCK(nombre, 0): timeout 0 => no espera
+func (l *mysqlLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lockName := l.prefix + name
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", lockName).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+		conn.Close()
+	}, true, nil
+}
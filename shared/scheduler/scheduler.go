@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// JobFunc es el trabajo a ejecutar; recibe un context que se cancela al apagar
+type JobFunc func(ctx context.Context) error
+
+// Scheduler corre jobs recurrentes con sintaxis cron
+// ("0 3 * * *", "@every 1h", "@daily", etc.)
+// Antes de cada ejecución toma el lock del job: si otra instancia
+// lo está corriendo, esta ejecución se saltea
+type Scheduler struct {
+	cron   *cron.Cron
+	locker Locker
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New crea un scheduler que usa locker para coordinar instancias
+func New(locker Locker) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:   cron.New(),
+		locker: locker,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register agrega un job; devuelve error si la expresión cron es inválida
+func (s *Scheduler) Register(name, spec string, job JobFunc) error {
+	_, err := s.cron.AddFunc(spec, func() { s.run(name, job) })
+	return err
+}
+
+// Start arranca el scheduler en background
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop deja de programar jobs, cancela el context de los que están
+// corriendo y espera a que terminen
+func (s *Scheduler) Stop() {
+	done := s.cron.Stop()
+	s.cancel()
+	<-done.Done()
+}
+
+// run ejecuta un job con lock, logging y recover
+// Un panic en un job no tira abajo el servicio
+func (s *Scheduler) run(name string, job JobFunc) {
+	release, ok, err := s.locker.TryLock(s.ctx, name)
+	if err != nil {
+		log.Printf("❌ [job %s] no se pudo tomar el lock: %v", name, err)
+		return
+	}
+	if !ok {
+		log.Printf("⏭️  [job %s] ya está corriendo en otra instancia, se saltea", name)
+		return
+	}
+	defer release()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ [job %s] panic: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+
+	start := time.Now()
+	if err := job(s.ctx); err != nil {
+		log.Printf("❌ [job %s] falló después de %s: %v", name, time.Since(start), err)
+		return
+	}
+	log.Printf("✅ [job %s] terminó en %s", name, time.Since(start))
+}
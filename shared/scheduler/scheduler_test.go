@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Test: el local locker no deja tomar dos veces el mismo lock
+func TestLocalLocker_TryLock(t *testing.T) {
+	locker := NewLocalLocker()
+
+	release, ok, err := locker.TryLock(context.Background(), "job")
+	if err != nil || !ok {
+		t.Fatalf("Expected lock acquired, got ok=%v err=%v", ok, err)
+	}
+
+	if _, ok, _ := locker.TryLock(context.Background(), "job"); ok {
+		t.Error("Expected second lock to fail while the first is held")
+	}
+	if _, ok, _ := locker.TryLock(context.Background(), "other"); !ok {
+		t.Error("Expected a different job to get its own lock")
+	}
+
+	release()
+	if _, ok, _ := locker.TryLock(context.Background(), "job"); !ok {
+		t.Error("Expected lock to be available after release")
+	}
+}
+
+// Test: si otra instancia tiene el lock, el job no corre
+func TestRun_SkipsWhenLocked(t *testing.T) {
+	locker := NewLocalLocker()
+	s := New(locker)
+	locker.TryLock(context.Background(), "job")
+
+	ran := false
+	s.run("job", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	if ran {
+		t.Error("Expected job to be skipped while locked")
+	}
+}
+
+// Test: un panic o un error en el job no rompen el scheduler y liberan el lock
+func TestRun_RecoversAndReleases(t *testing.T) {
+	locker := NewLocalLocker()
+	s := New(locker)
+
+	s.run("job", func(ctx context.Context) error { panic("boom") })
+	s.run("job", func(ctx context.Context) error { return errors.New("failed") })
+
+	if _, ok, _ := locker.TryLock(context.Background(), "job"); !ok {
+		t.Error("Expected lock released after panic and error")
+	}
+}
+
+// Test: expresiones cron inválidas se rechazan al registrar
+func TestRegister_InvalidSpec(t *testing.T) {
+	s := New(NewLocalLocker())
+
+	if err := s.Register("job", "not a cron", func(ctx context.Context) error { return nil }); err == nil {
+		t.Error("Expected error for invalid spec")
+	}
+	if err := s.Register("job", "@every 1h", func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Expected valid spec, got %v", err)
+	}
+}
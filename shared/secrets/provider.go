@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Provider es de donde se leen los secretos (entorno, archivos, Vault...)
+// Load devuelve los que encontró de keys; los que faltan no son un error
+// (el servicio usa el valor del entorno o su default)
+type Provider interface {
+	Load(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// envProvider lee las variables de entorno (el comportamiento de siempre)
+type envProvider struct{}
+
+// NewEnvProvider crea un provider que lee del entorno del proceso
+func NewEnvProvider() Provider {
+	return envProvider{}
+}
+
+// Load devuelve las variables definidas y no vacías
+func (envProvider) Load(ctx context.Context, keys []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// fileProvider lee un archivo por secreto (Docker/Kubernetes secrets)
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider crea un provider que lee dir/KEY (o dir/key en minúsculas)
+// Ejemplo: /run/secrets/jwt_secret para JWT_SECRET
+func NewFileProvider(dir string) Provider {
+	return &fileProvider{dir: dir}
+}
+
+// Load lee los archivos que existen (sin el salto de línea final)
+func (p *fileProvider) Load(ctx context.Context, keys []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range keys {
+		for _, name := range []string{key, strings.ToLower(key)} {
+			data, err := os.ReadFile(filepath.Join(p.dir, name))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			values[key] = strings.TrimRight(string(data), "\r\n")
+			break
+		}
+	}
+	return values, nil
+}
+
+// vaultProvider lee un secreto KV v2 de HashiCorp Vault por su API HTTP
+type vaultProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewVaultProvider crea un provider para el secreto mount/path de Vault
+// Ejemplo: NewVaultProvider("http://vault:8200", token, "secret", "spotly/users-api")
+// lee GET /v1/secret/data/spotly/users-api; cada clave del secreto es una variable
+func NewVaultProvider(addr, token, mount, path string) Provider {
+	return &vaultProvider{
+		url:    strings.TrimSuffix(addr, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.Trim(path, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Load pide el secreto completo (una request) y se queda con keys
+func (p *vaultProvider) Load(ctx context.Context, keys []string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string]string{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault: %s returned %d", p.url, resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	values := map[string]string{}
+	for _, key := range keys {
+		if value, ok := body.Data.Data[key].(string); ok && value != "" {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
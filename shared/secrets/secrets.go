@@ -0,0 +1,138 @@
+// Package secrets lee credenciales (passwords de la base, JWT_SECRET, URL del
+// broker) de un gestor de secretos en vez de dejarlas en variables de entorno
+//
+// El provider se elige con SECRETS_PROVIDER:
+//   - env (default): variables de entorno, como siempre
+//   - file: un archivo por secreto en SECRETS_DIR (Docker/Kubernetes secrets)
+//   - vault: HashiCorp Vault KV v2 (VAULT_ADDR, VAULT_TOKEN, VAULT_KV_MOUNT, VAULT_SECRET_PATH)
+//
+// Open carga los secretos y los pone por delante del entorno en config.Env,
+// así ConfigFromEnv los lee sin cambios. Run los vuelve a leer cada
+// SECRETS_REFRESH y avisa a quien se suscribió con OnChange
+package secrets
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"shared/config"
+)
+
+// Store guarda los últimos valores leídos del provider
+type Store struct {
+	provider Provider
+	keys     []string
+	refresh  time.Duration
+
+	mu        sync.RWMutex
+	values    map[string]string
+	listeners map[string][]func(string)
+}
+
+// NewStore crea el store para keys (no lee nada hasta Load)
+// refresh <= 0 desactiva la recarga periódica
+func NewStore(provider Provider, refresh time.Duration, keys ...string) *Store {
+	return &Store{
+		provider:  provider,
+		keys:      keys,
+		refresh:   refresh,
+		values:    map[string]string{},
+		listeners: map[string][]func(string){},
+	}
+}
+
+// Open lee la configuración del provider de env, carga keys y hace que env
+// las lea del store antes que del entorno
+// Si el provider no responde al arrancar devuelve el error (mejor fallar que
+// arrancar con los defaults de desarrollo)
+func Open(ctx context.Context, env *config.Env, keys ...string) (*Store, error) {
+	provider := NewEnvProvider()
+	switch env.OneOf("SECRETS_PROVIDER", "env", "env", "file", "vault") {
+	case "file":
+		provider = NewFileProvider(env.String("SECRETS_DIR", "/run/secrets"))
+	case "vault":
+		provider = NewVaultProvider(
+			env.Required("VAULT_ADDR"),
+			env.Required("VAULT_TOKEN"),
+			env.String("VAULT_KV_MOUNT", "secret"),
+			env.Required("VAULT_SECRET_PATH"),
+		)
+	}
+	refresh := env.Duration("SECRETS_REFRESH", 5*time.Minute)
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+
+	store := NewStore(provider, refresh, keys...)
+	if err := store.Load(ctx); err != nil {
+		return nil, err
+	}
+	env.Overlay(store.Lookup)
+	return store, nil
+}
+
+// Load lee los secretos y avisa a los suscriptos de los que cambiaron
+func (s *Store) Load(ctx context.Context) error {
+	fresh, err := s.provider.Load(ctx, s.keys)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	var notify []func()
+	for key, value := range fresh {
+		if old, ok := s.values[key]; ok && old != value {
+			for _, fn := range s.listeners[key] {
+				fn, value := fn, value
+				notify = append(notify, func() { fn(value) })
+			}
+		}
+		s.values[key] = value
+	}
+	s.mu.Unlock()
+
+	// Los callbacks corren sin el lock: pueden llamar a Lookup
+	for _, fn := range notify {
+		fn()
+	}
+	return nil
+}
+
+// Lookup devuelve el secreto si el provider lo tiene (firma de config.Env.Overlay)
+func (s *Store) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// OnChange registra fn para cuando key cambie en una recarga
+// Ejemplo: rotar el secret de los JWT sin reiniciar
+func (s *Store) OnChange(key string, fn func(value string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners[key] = append(s.listeners[key], fn)
+}
+
+// Run recarga los secretos cada SECRETS_REFRESH hasta que se cancele ctx
+// Si una recarga falla se siguen usando los valores anteriores
+func (s *Store) Run(ctx context.Context) {
+	if s.refresh <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil {
+				log.Printf("⚠️  No se pudieron recargar los secretos, se usan los anteriores: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeVault simula el endpoint KV v2 de Vault con un secreto mutable
+func fakeVault(data map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/spotly/users-api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		body := `{"data":{"data":{`
+		first := true
+		for key, value := range data {
+			if !first {
+				body += ","
+			}
+			body += `"` + key + `":"` + value + `"`
+			first = false
+		}
+		w.Write([]byte(body + `},"metadata":{"version":1}}}`))
+	}))
+}
+
+// Test: Vault devuelve solo las claves pedidas
+func TestVaultProvider(t *testing.T) {
+	server := fakeVault(map[string]string{"JWT_SECRET": "s3cret", "OTHER": "x"})
+	defer server.Close()
+
+	values, err := NewVaultProvider(server.URL, "token", "secret", "spotly/users-api").
+		Load(context.Background(), []string{"JWT_SECRET", "DB_PASSWORD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 1 || values["JWT_SECRET"] != "s3cret" {
+		t.Errorf("Unexpected values: %v", values)
+	}
+
+	if _, err := NewVaultProvider(server.URL, "wrong", "secret", "spotly/users-api").
+		Load(context.Background(), []string{"JWT_SECRET"}); err == nil {
+		t.Error("Expected an error with an invalid token")
+	}
+}
+
+// Test: el file provider acepta el nombre tal cual o en minúsculas
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "jwt_secret"), []byte("s3cret\n"), 0o600)
+
+	values, err := NewFileProvider(dir).Load(context.Background(), []string{"JWT_SECRET", "DB_PASSWORD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 1 || values["JWT_SECRET"] != "s3cret" {
+		t.Errorf("Unexpected values: %v", values)
+	}
+}
+
+// Test: una recarga con un valor distinto avisa a los suscriptos
+func TestStore_OnChange(t *testing.T) {
+	data := map[string]string{"JWT_SECRET": "old"}
+	server := fakeVault(data)
+	defer server.Close()
+
+	store := NewStore(NewVaultProvider(server.URL, "token", "secret", "spotly/users-api"), 0, "JWT_SECRET")
+	if err := store.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var rotated []string
+	store.OnChange("JWT_SECRET", func(value string) { rotated = append(rotated, value) })
+
+	store.Load(context.Background()) // sin cambios
+	data["JWT_SECRET"] = "new"
+	store.Load(context.Background())
+
+	if len(rotated) != 1 || rotated[0] != "new" {
+		t.Errorf("Expected one rotation to \"new\", got %v", rotated)
+	}
+	if value, _ := store.Lookup("JWT_SECRET"); value != "new" {
+		t.Errorf("Expected Lookup to return the new value, got %s", value)
+	}
+}
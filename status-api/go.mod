@@ -0,0 +1,12 @@
+module status-api
+
+go 1.21
+
+require shared v0.0.0
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.0 // indirect
+	github.com/rabbitmq/amqp091-go v1.9.0 // indirect
+)
+
+replace shared => ../shared
@@ -0,0 +1,97 @@
+// status-api junta el estado de todos los servicios e infraestructura en un
+// solo documento (GET /status). Lo consultan el banner de estado del
+// frontend y los monitores de uptime
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"shared/config"
+	"shared/graceful"
+	"shared/health"
+	"shared/httpmw"
+	"shared/logging"
+)
+
+func main() {
+	// ============================================
+	// 1. CONFIGURACIÓN - Leer variables de entorno
+	// ============================================
+	env, err := config.Load(".env")
+	if err != nil {
+		log.Fatal("❌ Failed to read .env:", err)
+	}
+
+	usersAPIURL := env.String("USERS_API_URL", "http://localhost:8080")
+	notificationsAPIURL := env.String("NOTIFICATIONS_API_URL", "http://localhost:8083")
+	auditAPIURL := env.String("AUDIT_API_URL", "http://localhost:8085")
+	propertiesAPIURL := env.String("PROPERTIES_API_URL", "http://localhost:8081")
+	searchAPIURL := env.String("SEARCH_API_URL", "http://localhost:8082")
+	solrURL := env.String("SOLR_URL", "http://localhost:8983/solr")
+	rabbitAddr := env.String("RABBITMQ_ADDR", "localhost:5672")
+	memcachedAddr := env.String("MEMCACHED_ADDR", "localhost:11211")
+	interval := env.Seconds("STATUS_POLL_INTERVAL_SECONDS", 15)
+	timeout := env.Seconds("STATUS_CHECK_TIMEOUT_SECONDS", 3)
+	port := env.String("SERVER_PORT", "8084")
+	timeouts := httpmw.ServerTimeoutsFromEnv(env)
+	logLevel := logging.LevelFromEnv(env)
+	drainTimeout := env.Duration("SHUTDOWN_DRAIN_TIMEOUT", 10*time.Second)
+
+	if err := env.Err(); err != nil {
+		log.Fatal("❌ ", err)
+	}
+	logging.Install(logLevel) // SIGUSR1 alterna debug
+
+	// ============================================
+	// 2. CHEQUEOS
+	// ============================================
+	// Requeridos: sin ellos no se puede usar el sitio (login, eventos)
+	// Opcionales: el sitio funciona con menos features (degraded)
+	// properties-api y search-api todavía no tienen /readyz: se usa /health
+	client := &http.Client{Timeout: timeout}
+	checker := health.New(timeout)
+	checker.Add("users-api", health.HTTPCheck(client, usersAPIURL+"/readyz"))
+	checker.Add("rabbitmq", health.TCPCheck(rabbitAddr))
+	checker.AddOptional("notifications-api", health.HTTPCheck(client, notificationsAPIURL+"/readyz"))
+	checker.AddOptional("audit-api", health.HTTPCheck(client, auditAPIURL+"/readyz"))
+	checker.AddOptional("properties-api", health.HTTPCheck(client, propertiesAPIURL+"/health"))
+	checker.AddOptional("search-api", health.HTTPCheck(client, searchAPIURL+"/health"))
+	checker.AddOptional("solr", health.HTTPCheck(client, strings.TrimSuffix(solrURL, "/")+"/admin/info/system"))
+	checker.AddOptional("memcached", health.TCPCheck(memcachedAddr))
+
+	log.Printf("🔧 Chequeando %s cada %s", strings.Join(checker.Names(), ", "), interval)
+
+	poller := NewPoller(checker, interval)
+	go poller.Run(context.Background())
+
+	// ============================================
+	// 3. RUTAS
+	// ============================================
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"healthy","service":"status-api"}`))
+	})
+
+	// Documento consolidado: 200 si está up o degraded, 503 si está down
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		health.WriteReport(w, poller.Report(r.Context()))
+	})
+
+	handler := httpmw.Chain(mux,
+		httpmw.RequestID,
+		httpmw.Logger,
+		httpmw.Recovery,
+		httpmw.CORS(httpmw.DefaultCORS("GET")),
+	)
+
+	log.Printf("🚀 Status API corriendo en puerto %s", port)
+	srv := httpmw.NewServer(":"+port, handler, timeouts)
+	if err := graceful.Run(srv, drainTimeout); err != nil {
+		log.Fatal("❌ Server stopped:", err)
+	}
+}
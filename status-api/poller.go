@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"shared/health"
+)
+
+// Poller corre los chequeos cada cierto intervalo y guarda el último reporte
+// Así /status responde al instante aunque lo consulten muchos monitores
+// y los servicios de atrás no reciben más tráfico que un chequeo por intervalo
+type Poller struct {
+	checker  *health.Checker
+	interval time.Duration
+
+	mu   sync.RWMutex
+	last *health.Report
+}
+
+// NewPoller crea el poller (no arranca hasta llamar a Run)
+func NewPoller(checker *health.Checker, interval time.Duration) *Poller {
+	return &Poller{checker: checker, interval: interval}
+}
+
+// Run chequea enseguida y después cada intervalo, hasta que se cancele ctx
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll corre los chequeos y loguea solo los cambios de estado general
+func (p *Poller) poll(ctx context.Context) health.Report {
+	report := p.checker.Run(ctx)
+
+	p.mu.Lock()
+	previous := p.last
+	p.last = &report
+	p.mu.Unlock()
+
+	if previous == nil || previous.Status != report.Status {
+		log.Printf("📊 Estado del sistema: %s", report.Status)
+	}
+	return report
+}
+
+// Report devuelve el último reporte (o chequea ahora si todavía no hay)
+func (p *Poller) Report(ctx context.Context) health.Report {
+	p.mu.RLock()
+	last := p.last
+	p.mu.RUnlock()
+
+	if last != nil {
+		return *last
+	}
+	return p.poll(ctx)
+}
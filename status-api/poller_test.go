@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"shared/health"
+)
+
+// upstream levanta un servicio de prueba que responde status después de delay
+func upstream(t *testing.T, status int, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Test: el estado general sale de los chequeos requeridos y opcionales,
+// y un servicio lento cuenta como caído
+func TestPoller_Aggregates(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		required []int           // status de cada upstream requerido
+		optional []int           // status de cada upstream opcional
+		slow     map[string]bool // upstreams que tardan más que el timeout
+		want     string
+	}{
+		{"todo arriba", []int{200}, []int{200}, nil, health.StatusUp},
+		{"opcional caído", []int{200}, []int{503}, nil, health.StatusDegraded},
+		{"requerido caído", []int{503}, []int{200}, nil, health.StatusDown},
+		{"requerido y opcional caídos", []int{500}, []int{503}, nil, health.StatusDown},
+		{"opcional lento", []int{200}, []int{200}, map[string]bool{"optional-0": true}, health.StatusDegraded},
+		{"requerido lento", []int{200}, []int{200}, map[string]bool{"required-0": true}, health.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Timeout: time.Second}
+			checker := health.New(timeout)
+			add := func(prefix string, statuses []int, register func(string, health.CheckFunc)) {
+				for n, status := range statuses {
+					name := prefix + "-" + strconv.Itoa(n)
+					delay := time.Duration(0)
+					if tt.slow[name] {
+						delay = 5 * timeout
+					}
+					register(name, health.HTTPCheck(client, upstream(t, status, delay).URL+"/readyz"))
+				}
+			}
+			add("required", tt.required, checker.Add)
+			add("optional", tt.optional, checker.AddOptional)
+
+			start := time.Now()
+			report := NewPoller(checker, time.Hour).Report(context.Background())
+
+			if report.Status != tt.want {
+				t.Errorf("Expected %s, got %s (%+v)", tt.want, report.Status, report.Checks)
+			}
+			if elapsed := time.Since(start); elapsed > 3*timeout {
+				t.Errorf("Expected the slow check to be cut at the timeout, took %s", elapsed)
+			}
+			for name, slow := range tt.slow {
+				if result := report.Checks[name]; slow && (result.Status != health.StatusDown || result.Error == "") {
+					t.Errorf("Expected %s down with an error, got %+v", name, result)
+				}
+			}
+		})
+	}
+}
+
+// Test: Report devuelve el último reporte sin volver a chequear, y Run lo
+// actualiza en cada intervalo hasta que se cancela
+func TestPoller_CachesBetweenPolls(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	checker := health.New(time.Second)
+	checker.Add("users-api", health.HTTPCheck(srv.Client(), srv.URL+"/readyz"))
+	poller := NewPoller(checker, 20*time.Millisecond)
+
+	if report := poller.Report(context.Background()); report.Status != health.StatusUp {
+		t.Fatalf("Expected up, got %s", report.Status)
+	}
+	status.Store(http.StatusServiceUnavailable)
+	if report := poller.Report(context.Background()); report.Status != health.StatusUp || hits.Load() != 1 {
+		t.Errorf("Expected the cached report without a new check, got %s after %d checks", report.Status, hits.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		poller.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for poller.Report(context.Background()).Status != health.StatusDown {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected Run to pick up the down dependency")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected Run to stop when the context is canceled")
+	}
+}
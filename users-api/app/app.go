@@ -0,0 +1,540 @@
+// Package app es la raíz de composición de users-api: lee la configuración,
+// abre la infraestructura y arma repositories → services → servidor HTTP
+// main solo lo llama; los tests pueden usar Build con su propia Infra
+package app
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"users-api/clients"
+	"users-api/database"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/server"
+	"users-api/services"
+	"users-api/storage"
+	"users-api/utils"
+
+	"shared/audit"
+	"shared/auth"
+	"shared/config"
+	"shared/featureflags"
+	"shared/health"
+	"shared/httpmw"
+	"shared/i18n"
+	"shared/logging"
+	"shared/rabbitmq"
+	"shared/scheduler"
+
+	"gorm.io/gorm"
+)
+
+// Config es todo lo que users-api lee del entorno
+type Config struct {
+	Database     database.Config
+	RabbitURL    string // opcional: sin RabbitMQ no se publican eventos
+	FlagsFile    string
+	FlagsRefresh time.Duration
+
+	SecurityEventsRetention time.Duration
+	JWTSecret               string
+	JWTClockSkew            time.Duration
+	JWTIssuer               string        // claim iss que se firma y se exige
+	JWTAudience             string        // claim aud que se firma y se exige
+	JWTTTL                  time.Duration // cuánto vale el token de un login
+	LoginRateLimit          int
+	IdempotencyTTL          time.Duration
+	MagicLinkURL            string        // página del frontend que canjea el magic link
+	MagicLinkTTL            time.Duration // cuánto vale un magic link
+	ImpersonationTTL        time.Duration // cuánto vale un token de impersonación
+	LegacySunset            time.Time     // fecha de baja de las rutas sin /v1
+	Port                    string
+	HTTP                    httpmw.ServerTimeouts
+	DrainTimeout            time.Duration // cuánto se espera a las requests en curso al apagar
+	DebugAddr               string        // puerto interno de pprof/expvar sin auth ("" = apagado)
+	LogLevel                logging.Level // nivel inicial; se cambia con PUT /admin/loglevel o SIGUSR1
+	LogHTTPBodies           bool          // loguear bodies (con lo sensible tapado) en nivel debug
+
+	// Redes desde las que se aceptan las rutas de admin; nil = cualquiera
+	AdminAllowlist *httpmw.IPAllowlist
+
+	// Header con el país de la IP que agrega el proxy o CDN ("" = sin geo)
+	GeoCountryHeader string
+
+	// Token con el que los otros servicios llaman a las rutas internas
+	// (INTERNAL_API_TOKEN, ej: notifications-api pidiendo preferencias)
+	// "" = esas rutas rechazan todo
+	InternalAPIToken string
+
+	// Pepper de las contraseñas (del gestor de secretos); "" = sin pepper
+	// El anterior solo verifica, mientras se migran los hashes en cada login
+	PasswordPepper   string
+	PasswordPepperID string
+	PreviousPepper   string
+	PreviousPepperID string
+
+	// OAuth client IDs de la app para el login con Google; vacío = apagado
+	GoogleClientIDs []string
+
+	// Proveedores OIDC (OIDC_PROVIDERS y OIDC_<NOMBRE>_*); vacío = apagado
+	OIDCProviders []utils.OIDCProviderConfig
+
+	// Bucket S3/MinIO de los avatares; sin S3_ENDPOINT no se aceptan
+	Storage storage.S3Config
+
+	// Costo de bcrypt de las contraseñas nuevas (BCRYPT_COST)
+	BcryptCost int
+
+	// Algoritmo de las contraseñas nuevas: bcrypt o argon2id (PASSWORD_HASHER)
+	PasswordHasher string
+
+	// Orígenes que acepta CORS (CORS_ALLOWED_ORIGINS); "*" = cualquiera
+	CORSOrigins []string
+
+	// "ana+promo@x.com" cuenta como "ana@x.com" (EMAIL_STRIP_PLUS_ADDRESSING)
+	EmailStripPlus bool
+
+	// Registro abierto o solo con invitación (REGISTRATION_MODE); las
+	// invitaciones llevan a INVITATION_URL y valen INVITATION_TTL
+	RegistrationMode string
+	InvitationURL    string
+	InvitationTTL    time.Duration
+
+	// Admin inicial (ADMIN_USERNAME, ADMIN_PASSWORD y ADMIN_EMAIL): main lo
+	// crea al arrancar si no hay ningún admin; sin ADMIN_USERNAME no se crea
+	BootstrapAdmin dto.CreateUserRequest
+}
+
+// ConfigFromEnv lee la configuración; los errores quedan acumulados en env.Err()
+// y main no arranca si hay alguno. Lo que no tiene un default seguro (ej:
+// JWT_SECRET) es obligatorio en lugar de caer en un valor de desarrollo
+func ConfigFromEnv(env *config.Env) Config {
+	cfg := Config{
+		Database:                database.ConfigFromEnv(env),
+		RabbitURL:               env.String("RABBITMQ_URL", ""),
+		FlagsFile:               env.String("FEATURE_FLAGS_FILE", "feature_flags.json"),
+		FlagsRefresh:            env.Seconds("FEATURE_FLAGS_REFRESH_SECONDS", 30),
+		SecurityEventsRetention: env.Days("SECURITY_EVENTS_RETENTION_DAYS", 90),
+		JWTSecret:               env.Required("JWT_SECRET"),
+		JWTClockSkew:            time.Duration(env.Int("JWT_CLOCK_SKEW_SECONDS", 30)) * time.Second,
+		JWTIssuer:               env.String("JWT_ISSUER", auth.DefaultIssuer),
+		JWTAudience:             env.String("JWT_AUDIENCE", auth.DefaultAudience),
+		JWTTTL:                  env.Duration("JWT_EXPIRATION", utils.DefaultTokenTTL),
+		LoginRateLimit:          env.PositiveInt("LOGIN_RATE_LIMIT_PER_MINUTE", 10),
+		IdempotencyTTL:          env.Duration("IDEMPOTENCY_TTL", 24*time.Hour),
+		MagicLinkURL:            env.String("MAGIC_LINK_URL", "http://localhost:3000/login/magic"),
+		MagicLinkTTL:            env.Duration("MAGIC_LINK_TTL", 15*time.Minute),
+		ImpersonationTTL:        env.Duration("IMPERSONATION_TTL", 15*time.Minute),
+		LegacySunset:            env.Date("LEGACY_ROUTES_SUNSET"),
+		Port:                    env.String("SERVER_PORT", "8080"),
+		HTTP:                    httpmw.ServerTimeoutsFromEnv(env),
+		DrainTimeout:            env.Duration("SHUTDOWN_DRAIN_TIMEOUT", 25*time.Second),
+		DebugAddr:               env.String("DEBUG_ADDR", ""),
+		LogLevel:                logging.LevelFromEnv(env),
+		LogHTTPBodies:           env.Bool("LOG_HTTP_BODIES", false),
+	}
+
+	// Con el secret de desarrollo (o uno corto) cualquiera puede firmar tokens de admin
+	env.Check(cfg.JWTSecret == "" || (len(cfg.JWTSecret) >= auth.MinSecretLength && cfg.JWTSecret != utils.DefaultJWTSecret),
+		"JWT_SECRET must be at least 32 characters and not the development default")
+
+	// Las revocaciones por usuario se purgan después del token más largo:
+	// uno de impersonación más largo que el de login quedaría sin revocar
+	env.Check(cfg.JWTTTL >= 5*time.Minute && cfg.JWTTTL <= 30*24*time.Hour, "JWT_EXPIRATION must be between 5m and 720h")
+	env.Check(cfg.ImpersonationTTL > 0 && cfg.ImpersonationTTL <= cfg.JWTTTL, "IMPERSONATION_TTL must be positive and not longer than JWT_EXPIRATION")
+
+	cfg.BcryptCost = env.Int("BCRYPT_COST", utils.MinBcryptCost)
+	env.Check(cfg.BcryptCost >= utils.MinBcryptCost && cfg.BcryptCost <= utils.MaxBcryptCost,
+		"BCRYPT_COST must be between %d and %d", utils.MinBcryptCost, utils.MaxBcryptCost)
+	cfg.PasswordHasher = env.OneOf("PASSWORD_HASHER", utils.HasherBcrypt, utils.HasherBcrypt, utils.HasherArgon2id)
+
+	cfg.EmailStripPlus = env.Bool("EMAIL_STRIP_PLUS_ADDRESSING", false)
+
+	cfg.RegistrationMode = env.OneOf("REGISTRATION_MODE", services.RegistrationOpen, services.RegistrationOpen, services.RegistrationInviteOnly)
+	cfg.InvitationURL = env.String("INVITATION_URL", "http://localhost:3000/register")
+	cfg.InvitationTTL = env.Duration("INVITATION_TTL", 7*24*time.Hour)
+
+	cfg.CORSOrigins = env.List("CORS_ALLOWED_ORIGINS", []string{"*"})
+	for _, origin := range cfg.CORSOrigins {
+		env.Check(validOrigin(origin), "CORS_ALLOWED_ORIGINS: %q is not \"*\" or an origin like https://spotly.com", origin)
+	}
+
+	// ADMIN_ALLOWED_CIDRS vacío = sin restricción por IP (solo el rol del JWT)
+	// TRUSTED_PROXY_CIDRS son los proxies cuyo X-Forwarded-For se cree
+	allowlist, err := httpmw.NewIPAllowlist(env.List("ADMIN_ALLOWED_CIDRS", nil), env.List("TRUSTED_PROXY_CIDRS", nil))
+	env.Check(err == nil, "ADMIN_ALLOWED_CIDRS / TRUSTED_PROXY_CIDRS: %v", err)
+	cfg.AdminAllowlist = allowlist
+	cfg.GeoCountryHeader = env.String("GEO_COUNTRY_HEADER", "")
+	cfg.InternalAPIToken = env.String("INTERNAL_API_TOKEN", "")
+	env.Check(cfg.InternalAPIToken == "" || len(cfg.InternalAPIToken) >= 32, "INTERNAL_API_TOKEN must be at least 32 characters")
+
+	cfg.PasswordPepper = env.String("PASSWORD_PEPPER", "")
+	cfg.PasswordPepperID = env.String("PASSWORD_PEPPER_ID", "1")
+	cfg.PreviousPepper = env.String("PASSWORD_PEPPER_PREVIOUS", "")
+	cfg.PreviousPepperID = env.String("PASSWORD_PEPPER_PREVIOUS_ID", "")
+	env.Check(cfg.PasswordPepper == "" || utils.ValidPepperID(cfg.PasswordPepperID), "PASSWORD_PEPPER_ID must be non-empty and cannot contain '$'")
+	env.Check(cfg.PreviousPepper == "" || (utils.ValidPepperID(cfg.PreviousPepperID) && cfg.PreviousPepperID != cfg.PasswordPepperID),
+		"PASSWORD_PEPPER_PREVIOUS_ID must be set, without '$', and differ from PASSWORD_PEPPER_ID")
+	env.Check(cfg.PreviousPepper == "" || cfg.PasswordPepper != "", "PASSWORD_PEPPER_PREVIOUS requires PASSWORD_PEPPER")
+
+	cfg.GoogleClientIDs = env.List("GOOGLE_CLIENT_IDS", nil)
+	cfg.OIDCProviders = oidcProvidersFromEnv(env)
+	cfg.Storage = storage.ConfigFromEnv(env)
+
+	cfg.BootstrapAdmin = dto.CreateUserRequest{
+		Username:  env.String("ADMIN_USERNAME", ""),
+		Password:  env.String("ADMIN_PASSWORD", ""),
+		FirstName: "Admin",
+		LastName:  "Spotly",
+	}
+	cfg.BootstrapAdmin.Email = env.String("ADMIN_EMAIL", cfg.BootstrapAdmin.Username+"@spotly.local")
+	env.Check(cfg.BootstrapAdmin.Username == "" || len(cfg.BootstrapAdmin.Password) >= 8, "ADMIN_USERNAME requires ADMIN_PASSWORD with at least 8 characters")
+	return cfg
+}
+
+// oidcProviderName es el formato de los nombres de OIDC_PROVIDERS: va en la
+// ruta y en login_events.method como "oidc:<nombre>" (hasta 20 caracteres)
+var oidcProviderName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,14}$`)
+
+// OIDCSecretKeys son los client secrets de los proveedores OIDC de
+// OIDC_PROVIDERS, para pedírselos al gestor de secretos antes de ConfigFromEnv
+func OIDCSecretKeys(env *config.Env) []string {
+	var keys []string
+	for _, name := range env.List("OIDC_PROVIDERS", nil) {
+		keys = append(keys, oidcEnvKey(name, "CLIENT_SECRET"))
+	}
+	return keys
+}
+
+// oidcProvidersFromEnv lee cada proveedor de OIDC_PROVIDERS ("keycloak,auth0")
+// de OIDC_<NOMBRE>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _DISPLAY_NAME y _TRUST_EMAIL
+// El nombre queda en users.provider: cambiarlo desasocia las cuentas
+func oidcProvidersFromEnv(env *config.Env) []utils.OIDCProviderConfig {
+	var providers []utils.OIDCProviderConfig
+	seen := map[string]bool{}
+	for _, name := range env.List("OIDC_PROVIDERS", nil) {
+		env.Check(oidcProviderName.MatchString(name) && name != domain.ProviderGoogle && !seen[name],
+			"OIDC_PROVIDERS: %q must be unique, lowercase (letters, digits, '-'), up to 15 characters and not %q", name, domain.ProviderGoogle)
+		seen[name] = true
+
+		provider := utils.OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  env.String(oidcEnvKey(name, "DISPLAY_NAME"), name),
+			Issuer:       env.Required(oidcEnvKey(name, "ISSUER")),
+			ClientID:     env.Required(oidcEnvKey(name, "CLIENT_ID")),
+			ClientSecret: env.Required(oidcEnvKey(name, "CLIENT_SECRET")),
+			TrustEmail:   env.Bool(oidcEnvKey(name, "TRUST_EMAIL"), false),
+		}
+		issuer, err := url.Parse(provider.Issuer)
+		secure := err == nil && issuer.Host != "" && (issuer.Scheme == "https" || issuer.Hostname() == "localhost")
+		env.Check(provider.Issuer == "" || secure, "%s must be an https URL (http only for localhost)", oidcEnvKey(name, "ISSUER"))
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// oidcEnvKey arma la variable de un proveedor: ("azure-ad", "ISSUER") => OIDC_AZURE_AD_ISSUER
+func oidcEnvKey(name, suffix string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_" + suffix
+}
+
+// validOrigin indica si es "*" o un origen sin path (ej: http://localhost:3000)
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.User == nil
+}
+
+// Infra son las conexiones externas que usa el servicio
+// Open las abre de verdad; un test puede armarla a mano con fakes
+type Infra struct {
+	DB        *gorm.DB
+	Publisher queue.EventPublisher
+	Flags     *featureflags.Client
+	Locker    scheduler.Locker // nil = no se programan jobs
+	Health    *health.Checker  // nil = no se expone /readyz
+	Audit     audit.Emitter    // nil = los eventos de auditoría solo se loguean
+
+	// Bucket de los avatares; nil = no se aceptan
+	Avatars storage.ObjectStore
+
+	closers []func() error
+}
+
+// Open conecta a MySQL (y migra), RabbitMQ y carga los feature flags
+// También arma los chequeos de /readyz sobre esas conexiones
+func Open(cfg Config) (*Infra, error) {
+	infra := &Infra{Health: health.New(2 * time.Second)}
+
+	log.Println("📡 Conectando a MySQL...")
+	db, err := database.Open(cfg.Database, false)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	infra.DB = db
+	infra.Locker = scheduler.NewMySQLLocker(sqlDB, "users-api:")
+	infra.closers = append(infra.closers, sqlDB.Close)
+	infra.Health.Add("mysql", health.PingCheck(sqlDB))
+	log.Println("✅ Conexión a MySQL exitosa")
+
+	// GORM crea automáticamente las tablas si no existen
+	log.Println("🔄 Ejecutando migraciones...")
+	if err := db.AutoMigrate(
+		&domain.User{},
+		&domain.NotificationPreferences{},
+		&domain.SecurityEvent{},
+		&domain.KnownDevice{},
+		&domain.KnownNetwork{},
+		&domain.MagicLinkToken{},
+		&domain.Invitation{},
+		&domain.BulkJob{},
+		&domain.PhoneVerification{},
+		&domain.OutboxEvent{},
+		&domain.RevokedToken{},
+		&domain.TokenRevocation{},
+		&domain.AuditLog{},
+		&domain.LoginEvent{},
+		&domain.Webhook{},
+		&domain.WebhookDelivery{},
+	); err != nil {
+		infra.Close()
+		return nil, err
+	}
+	log.Println("✅ Tablas creadas/actualizadas")
+
+	if cfg.RabbitURL == "" {
+		log.Println("⚠️  RABBITMQ_URL no definido, los eventos no se publican")
+		infra.Publisher = queue.NewNoopPublisher()
+	} else {
+		log.Println("📡 Conectando a RabbitMQ...")
+		conn, err := rabbitmq.Dial(cfg.RabbitURL)
+		if err != nil {
+			infra.Close()
+			return nil, err
+		}
+		infra.closers = append(infra.closers, conn.Close)
+		infra.Publisher, err = queue.NewRabbitMQPublisher(conn)
+		if err != nil {
+			infra.Close()
+			return nil, err
+		}
+		infra.Audit, err = audit.NewRabbitMQEmitter(conn, "users-api")
+		if err != nil {
+			infra.Close()
+			return nil, err
+		}
+		// Opcional: sin broker se pierden eventos pero la API sigue atendiendo
+		infra.Health.AddOptional("rabbitmq", conn.Ping)
+		log.Println("✅ Conexión a RabbitMQ exitosa")
+	}
+
+	if cfg.Storage.Enabled() {
+		infra.Avatars = storage.NewS3Store(cfg.Storage)
+		log.Printf("🪣 Avatares en el bucket %s (%s)", cfg.Storage.Bucket, cfg.Storage.Endpoint)
+	} else {
+		log.Println("⚠️  S3_ENDPOINT no definido, no se aceptan avatares")
+	}
+
+	infra.Flags, err = featureflags.NewClient(featureflags.NewFileSource(cfg.FlagsFile), cfg.FlagsRefresh)
+	if err != nil {
+		infra.Close()
+		return nil, err
+	}
+
+	return infra, nil
+}
+
+// Close cierra las conexiones abiertas por Open (en orden inverso)
+func (i *Infra) Close() error {
+	var errs []error
+	for j := len(i.closers) - 1; j >= 0; j-- {
+		errs = append(errs, i.closers[j]())
+	}
+	return errors.Join(errs...)
+}
+
+// App es el grafo de dependencias ya armado
+// Los campos quedan exportados para que los comandos y los tests usen
+// directamente un service o un repository sin volver a cablear nada
+type App struct {
+	UserRepo        repositories.UserRepository
+	PreferencesRepo repositories.PreferencesRepository
+	SecurityRepo    repositories.SecurityRepository
+	MagicLinkRepo   repositories.MagicLinkRepository
+	BulkJobRepo     repositories.BulkJobRepository
+	StatsRepo       repositories.StatsRepository
+	PhoneRepo       repositories.PhoneVerificationRepository
+	MergeRepo       repositories.MergeRepository
+	OutboxRepo      repositories.OutboxRepository
+	TokenRepo       repositories.TokenRepository
+	AuditLogRepo    repositories.AuditLogRepository
+	InvitationRepo  repositories.InvitationRepository
+	WebhookRepo     repositories.WebhookRepository
+
+	UserService        services.UserService
+	PreferencesService services.PreferencesService
+	SecurityService    services.SecurityService
+	MagicLinkService   services.MagicLinkService
+	Impersonation      services.ImpersonationService
+	BulkService        services.BulkService
+	StatsService       services.StatsService
+	PhoneService       services.PhoneService
+	MergeService       services.MergeService
+	OutboxService      services.OutboxService
+	TokenService       services.TokenService
+	BanService         services.BanService
+	AuditLogService    services.AuditLogService
+	AvatarService      services.AvatarService
+	GoogleLogin        services.GoogleLoginService
+	OIDCLogin          services.OIDCLoginService
+	Invitations        services.InvitationService
+	Webhooks           services.WebhookService
+
+	Handler     http.Handler
+	closeServer func() error
+}
+
+// webhookTimeout es cuánto se espera a que responda cada webhook
+const webhookTimeout = 10 * time.Second
+
+// Build arma repositories, services y el servidor HTTP sobre la infra
+// No abre conexiones: con una Infra de prueba no necesita MySQL ni RabbitMQ
+func Build(cfg Config, infra *Infra) *App {
+	utils.ConfigureJWT(utils.JWTConfig{
+		Secret:    cfg.JWTSecret,
+		ClockSkew: cfg.JWTClockSkew,
+		Issuer:    cfg.JWTIssuer,
+		Audience:  cfg.JWTAudience,
+		TTL:       cfg.JWTTTL,
+	})
+	utils.ConfigureBcryptCost(cfg.BcryptCost)
+	utils.ConfigurePasswordHasher(cfg.PasswordHasher)
+	utils.ConfigureEmailNormalization(cfg.EmailStripPlus)
+	utils.ConfigurePepper(cfg.PasswordPepperID, cfg.PasswordPepper, map[string]string{cfg.PreviousPepperID: cfg.PreviousPepper})
+	i18n.Register("es", spanishMessages)
+
+	publisher := infra.Publisher
+	if publisher == nil {
+		publisher = queue.NewNoopPublisher()
+	}
+
+	auditor := infra.Audit
+	if auditor == nil {
+		auditor = audit.NewLogEmitter("users-api")
+	}
+
+	a := &App{}
+
+	// Repository: acceso a datos
+	// Los cambios de usuarios hechos por admins quedan en audit_logs y todos
+	// se avisan a los webhooks suscriptos
+	a.AuditLogRepo = repositories.NewAuditLogRepository(infra.DB)
+	a.WebhookRepo = repositories.NewWebhookRepository(infra.DB)
+	a.UserRepo = repositories.NewAuditedUserRepository(repositories.NewWebhookUserRepository(repositories.NewUserRepository(infra.DB), a.WebhookRepo), a.AuditLogRepo)
+	a.PreferencesRepo = repositories.NewPreferencesRepository(infra.DB)
+	a.SecurityRepo = repositories.NewSecurityRepository(infra.DB)
+	a.MagicLinkRepo = repositories.NewMagicLinkRepository(infra.DB)
+	a.BulkJobRepo = repositories.NewBulkJobRepository(infra.DB)
+	a.StatsRepo = repositories.NewStatsRepository(infra.DB)
+	a.PhoneRepo = repositories.NewPhoneVerificationRepository(infra.DB)
+	a.MergeRepo = repositories.NewMergeRepository(infra.DB)
+	a.OutboxRepo = repositories.NewOutboxRepository(infra.DB)
+	a.TokenRepo = repositories.NewTokenRepository(infra.DB)
+	a.InvitationRepo = repositories.NewInvitationRepository(infra.DB)
+
+	// Service: lógica de negocio
+	registration := services.RegistrationConfig{InviteOnly: cfg.RegistrationMode == services.RegistrationInviteOnly}
+	a.UserService = services.NewUserService(a.UserRepo, repositories.NewUnitOfWork(infra.DB), registration)
+	a.PreferencesService = services.NewPreferencesService(a.UserRepo, a.PreferencesRepo)
+	a.SecurityService = services.NewSecurityService(a.SecurityRepo, publisher, infra.Flags)
+	a.MagicLinkService = services.NewMagicLinkService(a.UserRepo, a.MagicLinkRepo, publisher, services.MagicLinkConfig{
+		URL: cfg.MagicLinkURL,
+		TTL: cfg.MagicLinkTTL,
+	})
+	a.Impersonation = services.NewImpersonationService(a.UserRepo, cfg.ImpersonationTTL)
+	a.BulkService = services.NewBulkService(a.UserService, a.UserRepo, a.BulkJobRepo, auditor)
+	a.StatsService = services.NewStatsService(a.StatsRepo)
+	a.PhoneService = services.NewPhoneService(a.UserRepo, a.PhoneRepo, publisher)
+	a.MergeService = services.NewMergeService(a.UserRepo, a.PreferencesService, a.MergeRepo)
+	a.OutboxService = services.NewOutboxService(a.OutboxRepo, publisher)
+	a.TokenService = services.NewTokenService(a.UserRepo, a.TokenRepo)
+	a.BanService = services.NewBanService(a.UserRepo, a.TokenRepo)
+	a.AuditLogService = services.NewAuditLogService(a.AuditLogRepo)
+	a.AvatarService = services.NewAvatarService(a.UserRepo, infra.Avatars)
+
+	var google services.GoogleVerifier
+	if len(cfg.GoogleClientIDs) > 0 {
+		google = utils.NewGoogleVerifier(cfg.GoogleClientIDs, cfg.JWTClockSkew)
+	}
+	a.GoogleLogin = services.NewGoogleLoginService(a.UserRepo, google, registration)
+
+	oidcProviders := make([]services.OIDCProvider, 0, len(cfg.OIDCProviders))
+	for _, provider := range cfg.OIDCProviders {
+		oidcProviders = append(oidcProviders, utils.NewOIDCProvider(provider, cfg.JWTClockSkew))
+	}
+	a.OIDCLogin = services.NewOIDCLoginService(a.UserRepo, oidcProviders, registration)
+	a.Invitations = services.NewInvitationService(a.UserRepo, a.InvitationRepo, publisher, services.InvitationConfig{
+		URL: cfg.InvitationURL,
+		TTL: cfg.InvitationTTL,
+	})
+
+	a.Webhooks = services.NewWebhookService(a.WebhookRepo, clients.NewWebhookClient(webhookTimeout))
+
+	// Servidor HTTP: controllers, rutas y jobs
+	a.Handler, a.closeServer = server.NewServer(server.Config{
+		UserService:             a.UserService,
+		PreferencesService:      a.PreferencesService,
+		SecurityService:         a.SecurityService,
+		MagicLinkService:        a.MagicLinkService,
+		Impersonation:           a.Impersonation,
+		BulkService:             a.BulkService,
+		StatsService:            a.StatsService,
+		PhoneService:            a.PhoneService,
+		MergeService:            a.MergeService,
+		OutboxService:           a.OutboxService,
+		TokenService:            a.TokenService,
+		BanService:              a.BanService,
+		AuditLogService:         a.AuditLogService,
+		AvatarService:           a.AvatarService,
+		GoogleLogin:             a.GoogleLogin,
+		OIDCLogin:               a.OIDCLogin,
+		Invitations:             a.Invitations,
+		Webhooks:                a.Webhooks,
+		Flags:                   infra.Flags,
+		Locker:                  infra.Locker,
+		SecurityEventsRetention: cfg.SecurityEventsRetention,
+		LoginRateLimit:          cfg.LoginRateLimit,
+		IdempotencyTTL:          cfg.IdempotencyTTL,
+		Health:                  infra.Health,
+		LegacySunset:            cfg.LegacySunset,
+		Audit:                   auditor,
+		HandlerTimeout:          cfg.HTTP.Handler,
+		QueriesPerRequestWarn:   cfg.Database.QueriesPerRequestWarn,
+		LogBodies:               cfg.LogHTTPBodies,
+		AdminAllowlist:          cfg.AdminAllowlist,
+		GeoCountryHeader:        cfg.GeoCountryHeader,
+		InternalAPIToken:        cfg.InternalAPIToken,
+		CORSOrigins:             cfg.CORSOrigins,
+	})
+
+	return a
+}
+
+// Close frena los jobs del servidor
+func (a *App) Close() error {
+	return a.closeServer()
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"users-api/utils"
+
+	"shared/config"
+	"shared/featureflags"
+	"shared/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test: Build arma el grafo completo con una Infra de prueba (sin MySQL ni RabbitMQ)
+func TestBuild_WithFakeInfra(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	flags, err := featureflags.NewClient(featureflags.NewStaticSource(nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := Build(Config{JWTSecret: "test-secret"}, &Infra{Flags: flags})
+	defer a.Close()
+
+	if a.UserService == nil || a.PreferencesService == nil || a.SecurityService == nil {
+		t.Fatal("Expected all services to be built")
+	}
+
+	rec := httptest.NewRecorder()
+	a.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
+
+// Test: Build registra los mensajes en español y cada traducción conserva
+// los verbos de formato de su mensaje (ver apperrors.Newf)
+func TestSpanishMessages(t *testing.T) {
+	flags, err := featureflags.NewClient(featureflags.NewStaticSource(nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Build(Config{JWTSecret: "test-secret"}, &Infra{Flags: flags})
+	defer a.Close()
+
+	if got := i18n.Translate("es", "user not found"); got != "usuario no encontrado" {
+		t.Errorf("Expected spanish message, got %q", got)
+	}
+
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for message, translation := range spanishMessages {
+		want := strings.Join(verbs.FindAllString(message, -1), " ")
+		if got := strings.Join(verbs.FindAllString(translation, -1), " "); got != want {
+			t.Errorf("%q: expected verbs %q in %q, got %q", message, want, translation, got)
+		}
+	}
+}
+
+// Test: sin un JWT_SECRET seguro o con valores inválidos no arranca
+func TestConfigFromEnv_FailsFast(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"sin JWT_SECRET", map[string]string{}, "JWT_SECRET"},
+		{"secret de desarrollo", map[string]string{"JWT_SECRET": utils.DefaultJWTSecret}, "JWT_SECRET"},
+		{"secret corto", map[string]string{"JWT_SECRET": "corto"}, "JWT_SECRET"},
+		{"bcrypt débil", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "BCRYPT_COST": "4"}, "BCRYPT_COST"},
+		{"origen con path", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "CORS_ALLOWED_ORIGINS": "https://spotly.com/app"}, "CORS_ALLOWED_ORIGINS"},
+		{"token de login muy largo", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "JWT_EXPIRATION": "2000h"}, "JWT_EXPIRATION"},
+		{"impersonación más larga que el login", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "JWT_EXPIRATION": "1h", "IMPERSONATION_TTL": "2h"}, "IMPERSONATION_TTL"},
+		{"proveedor OIDC sin issuer", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "OIDC_PROVIDERS": "keycloak", "OIDC_KEYCLOAK_CLIENT_ID": "spotly"}, "OIDC_KEYCLOAK_ISSUER"},
+		{"proveedor OIDC sin client secret", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "OIDC_PROVIDERS": "keycloak",
+			"OIDC_KEYCLOAK_ISSUER": "https://sso.example.com/realms/spotly", "OIDC_KEYCLOAK_CLIENT_ID": "spotly"}, "OIDC_KEYCLOAK_CLIENT_SECRET"},
+		{"proveedor OIDC con nombre inválido", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "OIDC_PROVIDERS": "Azure AD"}, "OIDC_PROVIDERS"},
+		{"token interno corto", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "INTERNAL_API_TOKEN": "corto"}, "INTERNAL_API_TOKEN"},
+		{"válida", map[string]string{"JWT_SECRET": strings.Repeat("x", 32), "CORS_ALLOWED_ORIGINS": "https://spotly.com, http://localhost:3000"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"JWT_SECRET", "BCRYPT_COST", "CORS_ALLOWED_ORIGINS", "JWT_EXPIRATION", "IMPERSONATION_TTL",
+				"OIDC_PROVIDERS", "OIDC_KEYCLOAK_ISSUER", "OIDC_KEYCLOAK_CLIENT_ID", "OIDC_KEYCLOAK_CLIENT_SECRET", "INTERNAL_API_TOKEN"} {
+				t.Setenv(key, tt.env[key])
+			}
+			env := config.New()
+			cfg := ConfigFromEnv(env)
+
+			err := env.Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(cfg.CORSOrigins) != 2 || cfg.BcryptCost != utils.MinBcryptCost {
+					t.Errorf("Unexpected config: %v, cost %d", cfg.CORSOrigins, cfg.BcryptCost)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error about %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
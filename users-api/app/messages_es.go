@@ -0,0 +1,110 @@
+package app
+
+// spanishMessages traduce los mensajes de error de users-api (ver shared/i18n)
+// Al agregar un error nuevo, sumar acá su traducción; si falta, sale en inglés
+var spanishMessages = map[string]string{
+	// Usuarios y login
+	"user not found":                           "usuario no encontrado",
+	"user type not found":                      "tipo de usuario no encontrado",
+	"username already exists":                  "el nombre de usuario ya existe",
+	"email already exists":                     "el email ya está registrado",
+	"user already exists":                      "el usuario ya existe",
+	"invalid credentials":                      "credenciales inválidas",
+	"account is deactivated":                   "la cuenta está desactivada",
+	"invalid or expired login link":            "el link de acceso es inválido o está vencido",
+	"Invalid user ID":                          "ID de usuario inválido",
+	"invalid JSON body":                        "el cuerpo no es un JSON válido",
+	"invalid user type: %s":                    "tipo de usuario inválido: %s",
+	"current password is incorrect":            "la contraseña actual es incorrecta",
+	"You can only update your own profile":     "solo podés modificar tu propio perfil",
+	"You can only update your own preferences": "solo podés modificar tus propias preferencias",
+	"preferences not found":                    "preferencias no encontradas",
+	"device not found":                         "dispositivo no encontrado",
+
+	// Logout
+	"this token cannot be revoked, it expires on its own": "este token no se puede revocar, vence solo",
+
+	// Login con Google
+	"Google login is not enabled":                     "el login con Google no está habilitado",
+	"invalid Google token":                            "token de Google inválido",
+	"the Google account email is not verified":        "el email de la cuenta de Google no está verificado",
+	"the account is linked to another external login": "la cuenta ya está asociada a otro login externo",
+	"error generating username":                       "error al generar el nombre de usuario",
+
+	// Login con proveedores OIDC
+	"OIDC provider not found":                           "proveedor OIDC no encontrado",
+	"either code or id_token is required":               "hay que mandar code o id_token",
+	"invalid OIDC authorization code":                   "código de autorización OIDC inválido",
+	"invalid OIDC token":                                "token OIDC inválido",
+	"the account email is not verified by the provider": "el proveedor no verificó el email de la cuenta",
+
+	// Perfil
+	"locale must be a BCP 47 language tag, e.g. es-AR":                   "el locale tiene que ser un tag BCP 47, ej: es-AR",
+	"timezone must be an IANA time zone, e.g. America/Argentina/Cordoba": "la zona horaria tiene que ser una zona IANA, ej: America/Argentina/Cordoba",
+
+	// Teléfono
+	"phone must be in international format, e.g. +5493511234567":                 "el teléfono tiene que estar en formato internacional, ej: +5493511234567",
+	"invalid verification code":                                                  "código de verificación inválido",
+	"verification code expired, request a new code":                              "el código de verificación venció, pedí uno nuevo",
+	"no pending phone verification":                                              "no hay una verificación de teléfono pendiente",
+	"no pending phone verification, request a new code":                          "no hay una verificación de teléfono pendiente, pedí un código nuevo",
+	"a verification code was just sent, wait a minute before requesting another": "ya se mandó un código, esperá un minuto antes de pedir otro",
+
+	// Admin
+	"admins cannot be impersonated":                             "no se puede impersonar a un administrador",
+	"cannot impersonate yourself":                               "no podés impersonarte a vos mismo",
+	"cannot apply a bulk operation to yourself":                 "no podés aplicarte una operación masiva a vos mismo",
+	"exactly one of ids or filter is required":                  "hay que mandar ids o filter (solo uno de los dos)",
+	"filter needs at least one criterion":                       "el filtro necesita al menos un criterio",
+	"filter matches more than %d users, narrow it down":         "el filtro coincide con más de %d usuarios, acotalo",
+	"unknown bulk action: %s":                                   "acción masiva desconocida: %s",
+	"bulk job not found":                                        "operación masiva no encontrada",
+	"Invalid job ID":                                            "ID de operación inválido",
+	"network not found":                                         "red no encontrada",
+	"%s must be between 1 and %d":                               "%s tiene que estar entre 1 y %d",
+	"cannot merge an account into itself":                       "no se puede fusionar una cuenta consigo misma",
+	"admin accounts cannot be merged away":                      "una cuenta de administrador no se puede fusionar en otra",
+	"the account that stays must be active":                     "la cuenta que queda tiene que estar activa",
+	"account was already merged":                                "la cuenta ya fue fusionada",
+	"account was merged by another request":                     "la cuenta fue fusionada por otra solicitud",
+	"account was merged into another and cannot be reactivated": "la cuenta se fusionó en otra y no se puede reactivar",
+
+	// Avatares
+	"avatar uploads are not enabled":                       "la subida de avatares no está habilitada",
+	"avatar must be at most 5 MB":                          "el avatar puede pesar hasta 5 MB",
+	"avatar must be a JPEG or PNG image":                   "el avatar tiene que ser una imagen JPEG o PNG",
+	"avatar must be at most 4096x4096 pixels":              "el avatar puede medir hasta 4096x4096 píxeles",
+	"avatar file is required (multipart field \"avatar\")": "falta el archivo del avatar (campo multipart \"avatar\")",
+	"error processing avatar":                              "error al procesar el avatar",
+	"error reading avatar":                                 "error al leer el avatar",
+	"error storing avatar":                                 "error al guardar el avatar",
+
+	// Suspensiones
+	"account is banned until %s":         "la cuenta está suspendida hasta %s",
+	"banned_until must be in the future": "banned_until tiene que ser una fecha futura",
+	"admins cannot be banned":            "los administradores no se pueden suspender",
+
+	// Invitaciones
+	"registration requires an invitation code": "el registro requiere un código de invitación",
+	"invalid or expired invitation code":       "código de invitación inválido o vencido",
+	"the invitation was sent to another email": "la invitación se mandó a otro email",
+	"invitation code was already used":         "el código de invitación ya se usó",
+	"invitation not found":                     "invitación no encontrada",
+	"error generating invitation code":         "error al generar el código de invitación",
+	"invalid INVITATION_URL":                   "INVITATION_URL inválida",
+
+	// Webhooks
+	"Invalid webhook ID":                        "ID de webhook inválido",
+	"webhook not found":                         "webhook no encontrado",
+	"url must be an absolute http or https URL": "url tiene que ser una URL http o https completa",
+	"error creating webhook":                    "error al crear el webhook",
+	"error generating webhook secret":           "error al generar el secret del webhook",
+
+	// Errores internos (el detalle queda en los logs)
+	"error hashing password":             "error al procesar la contraseña",
+	"error generating token":             "error al generar el token",
+	"error generating login link":        "error al generar el link de acceso",
+	"error generating verification code": "error al generar el código de verificación",
+	"error hashing verification code":    "error al procesar el código de verificación",
+	"invalid MAGIC_LINK_URL":             "MAGIC_LINK_URL inválida",
+}
@@ -0,0 +1,82 @@
+// Package clients tiene los clientes HTTP de servicios externos
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"users-api/domain"
+
+	"shared/requestid"
+)
+
+// Headers de cada envío de webhook
+const (
+	HeaderWebhookEvent     = "X-Spotly-Event"
+	HeaderWebhookDelivery  = "X-Spotly-Delivery" // ID del evento, igual en los reintentos
+	HeaderWebhookTimestamp = "X-Spotly-Timestamp"
+	HeaderWebhookSignature = "X-Spotly-Signature"
+)
+
+// WebhookClient manda los webhooks por HTTP
+type WebhookClient struct {
+	client *http.Client
+}
+
+// NewWebhookClient crea el cliente; timeout es cuánto se espera a cada receptor
+// No sigue redirecciones: un 3xx cuenta como fallo (la URL registrada es la
+// que recibe el secret firmado, no otra)
+func NewWebhookClient(timeout time.Duration) *WebhookClient {
+	return &WebhookClient{client: &http.Client{
+		Timeout:   timeout,
+		Transport: requestid.NewTransport(nil),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send hace POST del payload a url con la firma del secret
+// Devuelve el status de la respuesta (0 si no hubo) y un error si no fue 2xx
+func (c *WebhookClient) Send(ctx context.Context, url, secret string, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "spotly-webhooks/1")
+	req.Header.Set(HeaderWebhookEvent, delivery.Event)
+	req.Header.Set(HeaderWebhookDelivery, delivery.EventID)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(secret, timestamp, delivery.Payload))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook es la firma del header X-Spotly-Signature:
+// "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+// El receptor la recalcula con el mismo secret y descarta timestamps viejos
+// (ej: más de 5 minutos) para que no le reenvíen un mensaje capturado
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
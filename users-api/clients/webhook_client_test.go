@@ -0,0 +1,49 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"users-api/domain"
+)
+
+// Test: el envío lleva el evento, el ID y una firma que el receptor puede verificar
+func TestWebhookClient_Send(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := &domain.WebhookDelivery{EventID: "abc123", Event: "user.created", Payload: []byte(`{"id":"abc123"}`)}
+	status, err := NewWebhookClient(time.Second).Send(context.Background(), server.URL, "secreto", delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d, %v", status, err)
+	}
+	if got.Header.Get(HeaderWebhookEvent) != "user.created" || got.Header.Get(HeaderWebhookDelivery) != "abc123" || string(body) != `{"id":"abc123"}` {
+		t.Errorf("Unexpected request: %v %s", got.Header, body)
+	}
+	want := SignWebhook("secreto", got.Header.Get(HeaderWebhookTimestamp), body)
+	if got.Header.Get(HeaderWebhookSignature) != want {
+		t.Errorf("Expected signature %s, got %s", want, got.Header.Get(HeaderWebhookSignature))
+	}
+}
+
+// Test: un status que no es 2xx (ni siquiera una redirección) es un fallo
+func TestWebhookClient_SendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://other.example.com", http.StatusFound)
+	}))
+	defer server.Close()
+
+	status, err := NewWebhookClient(time.Second).Send(context.Background(), server.URL, "secreto", &domain.WebhookDelivery{Payload: []byte(`{}`)})
+	if err == nil || status != http.StatusFound {
+		t.Errorf("Expected a failed 302, got %d, %v", status, err)
+	}
+}
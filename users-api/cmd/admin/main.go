@@ -0,0 +1,222 @@
+// Comando admin: operaciones de soporte sobre usuarios sin pasar por la API
+//
+// Uso (desde users-api/, con las mismas variables DB_* que el servicio):
+//
+//	go run ./cmd/admin create --username root --email root@spotly.com --password secreto
+//	go run ./cmd/admin reset-password maria.gomez42         # genera una contraseña nueva
+//	go run ./cmd/admin promote maria@example.com
+//	go run ./cmd/admin demote maria.gomez42
+//	go run ./cmd/admin list --role admin
+//
+// Los usuarios se buscan por username o email, igual que en el login
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"users-api/database"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+	"users-api/services"
+
+	"shared/config"
+	"shared/secrets"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:           "admin",
+		Short:         "Operaciones de administración de usuarios de users-api",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(createCmd(), resetPasswordCmd(), roleCmd("promote", domain.UserTypeAdmin), roleCmd("demote", domain.UserTypeNormal), listCmd())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		os.Exit(1)
+	}
+}
+
+// userService conecta a MySQL con la configuración del servicio y arma el UserService
+func userService() (services.UserService, error) {
+	env, err := config.Load(".env")
+	if err != nil {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+	if _, err := secrets.Open(context.Background(), env, "DB_PASSWORD"); err != nil {
+		return nil, fmt.Errorf("load secrets: %w", err)
+	}
+	dbConfig := database.ConfigFromEnv(env)
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+
+	db, err := database.Open(dbConfig, true)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return services.NewUserService(repositories.NewUserRepository(db), repositories.NewUnitOfWork(db), services.RegistrationConfig{}), nil
+}
+
+// createCmd crea un usuario administrador
+func createCmd() *cobra.Command {
+	var req dto.CreateUserRequest
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Crear un usuario administrador",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			generated := req.Password == ""
+			if generated {
+				req.Password = randomPassword()
+			} else if len(req.Password) < 6 {
+				return fmt.Errorf("--password must have at least 6 characters")
+			}
+
+			svc, err := userService()
+			if err != nil {
+				return err
+			}
+			user, err := svc.CreateUser(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			if user, err = svc.SetUserType(cmd.Context(), user.ID, domain.UserTypeAdmin); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ Admin creado: #%d %s <%s>\n", user.ID, user.Username, user.Email)
+			if generated {
+				fmt.Printf("🔑 Contraseña: %s\n", req.Password)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&req.Username, "username", "", "username del admin")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email del admin")
+	cmd.Flags().StringVar(&req.Password, "password", "", "contraseña (si se omite se genera una)")
+	cmd.Flags().StringVar(&req.FirstName, "first-name", "Admin", "nombre")
+	cmd.Flags().StringVar(&req.LastName, "last-name", "Spotly", "apellido")
+	cmd.MarkFlagRequired("username")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+// resetPasswordCmd cambia la contraseña de un usuario
+func resetPasswordCmd() *cobra.Command {
+	var password string
+	cmd := &cobra.Command{
+		Use:   "reset-password <username|email>",
+		Short: "Cambiar la contraseña de un usuario",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			generated := password == ""
+			if generated {
+				password = randomPassword()
+			} else if len(password) < 6 {
+				return fmt.Errorf("--password must have at least 6 characters")
+			}
+
+			svc, err := userService()
+			if err != nil {
+				return err
+			}
+			user, err := svc.GetUserByLogin(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if _, err := svc.UpdateUser(cmd.Context(), user.ID, dto.UpdateUserRequest{Password: password}); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ Contraseña actualizada para %s\n", user.Username)
+			if generated {
+				fmt.Printf("🔑 Contraseña: %s\n", password)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&password, "password", "", "contraseña nueva (si se omite se genera una)")
+	return cmd
+}
+
+// roleCmd arma "promote" y "demote": cambian el rol de un usuario
+func roleCmd(name string, userType domain.UserType) *cobra.Command {
+	return &cobra.Command{
+		Use:   name + " <username|email>",
+		Short: fmt.Sprintf("Dejar a un usuario con rol %s", userType),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := userService()
+			if err != nil {
+				return err
+			}
+			user, err := svc.GetUserByLogin(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if user, err = svc.SetUserType(cmd.Context(), user.ID, userType); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ %s ahora es %s\n", user.Username, user.UserType)
+			return nil
+		},
+	}
+}
+
+// listCmd lista los usuarios, opcionalmente filtrando por rol
+func listCmd() *cobra.Command {
+	var role string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Listar usuarios",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if role != "" && role != string(domain.UserTypeNormal) && role != string(domain.UserTypeAdmin) {
+				return fmt.Errorf("--role must be %q or %q", domain.UserTypeNormal, domain.UserTypeAdmin)
+			}
+
+			svc, err := userService()
+			if err != nil {
+				return err
+			}
+			users, err := svc.GetAllUsers(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tROL")
+			for _, user := range users {
+				if role != "" && string(user.UserType) != role {
+					continue
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", user.ID, user.Username, user.Email, user.UserType)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&role, "role", "", "filtrar por rol (normal o admin)")
+	return cmd
+}
+
+// randomPassword genera una contraseña aleatoria de 16 caracteres
+func randomPassword() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,151 @@
+// Comando seed: carga usuarios de prueba en la base de users-api
+//
+// Uso (desde users-api/, con las mismas variables DB_* que el servicio):
+//
+//	go run ./cmd/seed -users 50 -admins 2
+//	go run ./cmd/seed -users 20 -events   # además publica user.created
+//
+// Todos los usuarios quedan con la misma contraseña (-password) para poder
+// loguearse en la demo. Si un username o email ya existe se saltea
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"users-api/database"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/config"
+	"shared/rabbitmq"
+	"shared/secrets"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+func main() {
+	users := flag.Int("users", 50, "cantidad de usuarios normales a crear")
+	admins := flag.Int("admins", 1, "cantidad de administradores a crear")
+	password := flag.String("password", "spotly123", "contraseña de todos los usuarios")
+	publish := flag.Bool("events", false, "publicar user.created por cada usuario (requiere RABBITMQ_URL)")
+	seed := flag.Int64("seed", 0, "semilla para generar siempre los mismos datos (0 = aleatorio)")
+	flag.Parse()
+
+	// ============================================
+	// 1. CONFIGURACIÓN
+	// ============================================
+	env, err := config.Load(".env")
+	if err != nil {
+		log.Fatal("❌ Failed to read .env:", err)
+	}
+	if _, err := secrets.Open(context.Background(), env, "DB_PASSWORD", "RABBITMQ_URL"); err != nil {
+		log.Fatal("❌ Failed to load secrets:", err)
+	}
+	dbConfig := database.ConfigFromEnv(env)
+	rabbitURL := env.String("RABBITMQ_URL", "")
+	env.Check(!*publish || rabbitURL != "", "RABBITMQ_URL is required with -events")
+	env.Check(len(*password) >= 6, "-password must have at least 6 characters")
+	if err := env.Err(); err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	if *seed != 0 {
+		gofakeit.Seed(*seed)
+	}
+
+	// ============================================
+	// 2. CONECTAR A MYSQL Y RABBITMQ
+	// ============================================
+	db, err := database.Open(dbConfig, true)
+	if err != nil {
+		log.Fatal("❌ Failed to connect to database:", err)
+	}
+	if err := db.AutoMigrate(&domain.User{}); err != nil {
+		log.Fatal("❌ Failed to migrate database:", err)
+	}
+
+	publisher := queue.NewNoopPublisher()
+	if *publish {
+		conn, err := rabbitmq.Dial(rabbitURL)
+		if err != nil {
+			log.Fatal("❌ Failed to connect to RabbitMQ:", err)
+		}
+		defer conn.Close()
+		publisher, err = queue.NewRabbitMQPublisher(conn)
+		if err != nil {
+			log.Fatal("❌ Failed to declare exchange:", err)
+		}
+	}
+
+	userService := services.NewUserService(repositories.NewUserRepository(db), repositories.NewUnitOfWork(db), services.RegistrationConfig{})
+
+	// ============================================
+	// 3. CREAR USUARIOS
+	// ============================================
+	log.Printf("🌱 Creando %d usuarios y %d admins (contraseña %q)...", *users, *admins, *password)
+	created, skipped := 0, 0
+	for i := 0; i < *users+*admins; i++ {
+		user, err := userService.CreateUser(context.Background(), fakeUser(*password))
+		if errors.Is(err, apperrors.ErrConflict) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			log.Fatal("❌ Failed to create user:", err)
+		}
+
+		if i < *admins {
+			if user, err = userService.SetUserType(context.Background(), user.ID, domain.UserTypeAdmin); err != nil {
+				log.Fatal("❌ Failed to promote admin:", err)
+			}
+		}
+
+		if err := publisher.Publish(context.Background(), "user.created", map[string]interface{}{
+			"user_id":    user.ID,
+			"email":      user.Email,
+			"first_name": user.FirstName,
+		}); err != nil {
+			log.Printf("⚠️  No se pudo publicar user.created de %s: %v", user.Username, err)
+		}
+
+		created++
+		log.Printf("   - %-6s %-24s %s", user.UserType, user.Username, user.Email)
+	}
+
+	log.Printf("✅ %d usuarios creados, %d salteados (ya existían)", created, skipped)
+}
+
+// fakeUser arma un registro con datos realistas
+// El username sale del nombre (ej: "maria.gomez42") para que sea fácil de recordar
+func fakeUser(password string) dto.CreateUserRequest {
+	first, last := gofakeit.FirstName(), gofakeit.LastName()
+	username := fmt.Sprintf("%s.%s%d", first, last, gofakeit.Number(1, 99))
+	username = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1 // saca espacios, apóstrofes, acentos
+	}, username)
+	if len(username) > 50 {
+		username = username[:50]
+	}
+
+	return dto.CreateUserRequest{
+		Username:  username,
+		Email:     username + "@" + gofakeit.DomainName(),
+		Password:  password,
+		FirstName: first,
+		LastName:  last,
+	}
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogController maneja el historial de cambios de admins
+type AuditLogController struct {
+	service services.AuditLogService
+}
+
+// NewAuditLogController crea una nueva instancia del controlador
+func NewAuditLogController(service services.AuditLogService) *AuditLogController {
+	return &AuditLogController{service: service}
+}
+
+// ListAuditLogs maneja GET /admin/audit-logs
+// Filtros opcionales: actor_id, target_id, action, from y to (RFC 3339),
+// page y limit (1-200, default 50)
+func (ctrl *AuditLogController) ListAuditLogs(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	logs, err := ctrl.service.List(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"users-api/dto"
+	"users-api/services"
+	"users-api/utils"
+
+	"shared/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AvatarController maneja la foto de perfil
+type AvatarController struct {
+	service services.AvatarService
+}
+
+// NewAvatarController crea una nueva instancia del controlador
+func NewAvatarController(service services.AvatarService) *AvatarController {
+	return &AvatarController{service: service}
+}
+
+// UploadAvatar maneja POST /users/me/avatar (multipart, campo "avatar")
+// El body se corta apenas pasa el límite (con margen para el resto del
+// formulario), antes de leer el archivo entero
+func (ctrl *AvatarController) UploadAvatar(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, utils.MaxAvatarBytes+64<<10)
+
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, apperrors.Validation("avatar must be at most 5 MB"))
+			return
+		}
+		respondError(c, apperrors.BadRequest("avatar file is required (multipart field \"avatar\")"))
+		return
+	}
+	if header.Size > utils.MaxAvatarBytes {
+		respondError(c, apperrors.Validation("avatar must be at most 5 MB"))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, apperrors.Wrap(apperrors.CodeInternal, "error reading avatar", err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, utils.MaxAvatarBytes+1))
+	if err != nil {
+		respondError(c, apperrors.Wrap(apperrors.CodeInternal, "error reading avatar", err))
+		return
+	}
+
+	user, err := ctrl.service.Upload(c.Request.Context(), c.GetUint("user_id"), data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Avatar updated successfully",
+		Data:    dto.NewUserResponse(user),
+	})
+}
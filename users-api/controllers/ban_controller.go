@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BanController maneja la suspensión de cuentas
+type BanController struct {
+	service services.BanService
+	audit   audit.Emitter
+}
+
+// NewBanController crea una nueva instancia del controlador
+func NewBanController(service services.BanService, auditor audit.Emitter) *BanController {
+	return &BanController{service: service, audit: auditor}
+}
+
+// BanUser maneja POST /admin/users/:id/ban
+// Suspende la cuenta hasta banned_until y corta sus sesiones abiertas
+func (ctrl *BanController) BanUser(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req dto.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	user, err := ctrl.service.Ban(c.Request.Context(), uint(id), req.BannedUntil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ctrl.audit.Emit(c.Request.Context(), adminEvent(c, audit.ActionUserBanned, idParam))
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "User banned",
+		Data:    dto.NewUserResponse(user),
+	})
+}
+
+// UnbanUser maneja POST /admin/users/:id/unban
+func (ctrl *BanController) UnbanUser(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	user, err := ctrl.service.Unban(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ctrl.audit.Emit(c.Request.Context(), adminEvent(c, audit.ActionUserUnbanned, idParam))
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "User unbanned",
+		Data:    dto.NewUserResponse(user),
+	})
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkController maneja las operaciones masivas sobre usuarios
+type BulkController struct {
+	service services.BulkService
+}
+
+// NewBulkController crea una nueva instancia del controlador
+func NewBulkController(service services.BulkService) *BulkController {
+	return &BulkController{service: service}
+}
+
+// StartBulk maneja POST /admin/users/bulk
+// Arranca el job y responde 202 con el job y su URL en Location
+func (ctrl *BulkController) StartBulk(c *gin.Context) {
+	var req dto.BulkUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	job, err := ctrl.service.Start(c.Request.Context(), c.GetUint("user_id"), c.GetString("username"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/%d", c.Request.URL.Path, job.ID))
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetBulkJob maneja GET /admin/users/bulk/:job_id
+// Devuelve el estado y el progreso del job
+func (ctrl *BulkController) GetBulkJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("job_id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid job ID"))
+		return
+	}
+
+	job, err := ctrl.service.GetJob(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+
+	"shared/featureflags"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureController expone los feature flags al frontend
+type FeatureController struct {
+	flags *featureflags.Client
+}
+
+// NewFeatureController crea una nueva instancia del controlador
+func NewFeatureController(flags *featureflags.Client) *FeatureController {
+	return &FeatureController{flags: flags}
+}
+
+// GetFeatures maneja GET /features
+// Devuelve los flags prendidos para quien llama (anónimo o logueado)
+// Ejemplo: {"features": ["login_security_alerts"]}
+func (ctrl *FeatureController) GetFeatures(c *gin.Context) {
+	userKey := featureflags.UserKey(c.GetUint("user_id"))
+
+	c.JSON(http.StatusOK, gin.H{
+		"features": ctrl.flags.EnabledFlags(userKey),
+	})
+}
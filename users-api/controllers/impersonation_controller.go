@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationController maneja la impersonación de usuarios por admins
+type ImpersonationController struct {
+	service services.ImpersonationService
+	audit   audit.Emitter
+}
+
+// NewImpersonationController crea una nueva instancia del controlador
+func NewImpersonationController(service services.ImpersonationService, auditor audit.Emitter) *ImpersonationController {
+	return &ImpersonationController{service: service, audit: auditor}
+}
+
+// Impersonate maneja POST /admin/users/:id/impersonate
+// Devuelve un token corto para actuar como el usuario (soporte)
+// El inicio queda auditado y cada request hecha con el token también,
+// con el admin en impersonator_id
+func (ctrl *ImpersonationController) Impersonate(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	response, err := ctrl.service.Impersonate(c.Request.Context(), c.GetUint("user_id"), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := adminEvent(c, audit.ActionAdminImpersonate, idParam)
+	event.Metadata = map[string]interface{}{"expires_at": response.ExpiresAt}
+	ctrl.audit.Emit(c.Request.Context(), event)
+
+	c.JSON(http.StatusOK, response)
+}
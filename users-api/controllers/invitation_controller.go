@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/audit"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationController maneja las invitaciones a registrarse
+type InvitationController struct {
+	service services.InvitationService
+	audit   audit.Emitter
+}
+
+// NewInvitationController crea una nueva instancia del controlador
+func NewInvitationController(service services.InvitationService, auditor audit.Emitter) *InvitationController {
+	return &InvitationController{service: service, audit: auditor}
+}
+
+// CreateInvitation maneja POST /admin/invitations
+// Manda el código por email y lo devuelve (es la única vez que se ve)
+func (ctrl *InvitationController) CreateInvitation(c *gin.Context) {
+	var req dto.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	invitation, err := ctrl.service.Create(c.Request.Context(), c.GetUint("user_id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := adminEvent(c, audit.ActionUserInvited, strconv.FormatUint(uint64(invitation.ID), 10))
+	event.TargetType = "invitation"
+	event.Metadata = map[string]interface{}{"user_type": invitation.UserType}
+	ctrl.audit.Emit(c.Request.Context(), event)
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Invitation created",
+		Data:    invitation,
+	})
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MergeController maneja la fusión de cuentas duplicadas
+type MergeController struct {
+	service services.MergeService
+	audit   audit.Emitter
+}
+
+// NewMergeController crea una nueva instancia del controlador
+func NewMergeController(service services.MergeService, auditor audit.Emitter) *MergeController {
+	return &MergeController{service: service, audit: auditor}
+}
+
+// MergeUsers maneja POST /admin/users/:id/merge
+// Fusiona duplicate_id en la cuenta de la URL, que es la que queda
+func (ctrl *MergeController) MergeUsers(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req dto.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	response, err := ctrl.service.Merge(c.Request.Context(), c.GetUint("user_id"), uint(id), req.DuplicateID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := adminEvent(c, audit.ActionAdminUsersMerged, idParam)
+	event.Metadata = map[string]interface{}{"duplicate_id": req.DuplicateID, "event_id": response.EventID}
+	ctrl.audit.Emit(c.Request.Context(), event)
+
+	c.JSON(http.StatusOK, response)
+}
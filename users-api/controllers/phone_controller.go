@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PhoneController maneja el teléfono del usuario logueado
+type PhoneController struct {
+	service services.PhoneService
+}
+
+// NewPhoneController crea una nueva instancia del controlador
+func NewPhoneController(service services.PhoneService) *PhoneController {
+	return &PhoneController{service: service}
+}
+
+// UpdatePhone maneja PUT /users/me/phone
+// Guarda el teléfono sin verificar y manda el código por SMS (202)
+func (ctrl *PhoneController) UpdatePhone(c *gin.Context) {
+	var req dto.UpdatePhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	user, err := ctrl.service.StartVerification(c.Request.Context(), c.GetUint("user_id"), req.Phone)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	status, message := http.StatusAccepted, "Verification code sent"
+	if user.PhoneVerified() {
+		status, message = http.StatusOK, "Phone already verified"
+	}
+	c.JSON(status, dto.SuccessResponse{Message: message, Data: dto.NewUserResponse(user)})
+}
+
+// VerifyPhone maneja POST /users/me/phone/verify
+// Confirma el código del SMS y devuelve el usuario con phone_verified_at
+func (ctrl *PhoneController) VerifyPhone(c *gin.Context) {
+	var req dto.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	user, err := ctrl.service.Verify(c.Request.Context(), c.GetUint("user_id"), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Phone verified", Data: dto.NewUserResponse(user)})
+}
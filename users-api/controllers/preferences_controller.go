@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreferencesController maneja los endpoints de preferencias de notificación
+type PreferencesController struct {
+	service services.PreferencesService
+}
+
+// NewPreferencesController crea una nueva instancia del controlador
+func NewPreferencesController(service services.PreferencesService) *PreferencesController {
+	return &PreferencesController{service: service}
+}
+
+// GetPreferences maneja GET /users/:id/preferences
+// Es interna (token de servicio): la usa notifications-api antes de enviar un email
+func (ctrl *PreferencesController) GetPreferences(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	prefs, err := ctrl.service.GetPreferences(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// GetMyPreferences maneja GET /users/me/preferences
+// El propio usuario lee sus preferencias (el ID sale del JWT)
+func (ctrl *PreferencesController) GetMyPreferences(c *gin.Context) {
+	prefs, err := ctrl.service.GetPreferences(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences maneja PUT /users/:id/preferences
+// Solo el propio usuario o un admin pueden cambiarlas
+func (ctrl *PreferencesController) UpdatePreferences(c *gin.Context) {
+	// 1. Obtener el ID de la URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	// 2. Verificar que sea el propio usuario o un admin
+	// (user_id y user_type los guarda AuthMiddleware)
+	if c.GetUint("user_id") != uint(id) && c.GetString("user_type") != "admin" {
+		respondError(c, apperrors.Forbidden("You can only update your own preferences"))
+		return
+	}
+
+	// 3. Leer el JSON del body
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	// 4. Llamar al servicio
+	prefs, err := ctrl.service.UpdatePreferences(c.Request.Context(), uint(id), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Preferences updated successfully",
+		Data:    prefs,
+	})
+}
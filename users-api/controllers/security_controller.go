@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityController maneja los endpoints de seguridad de la cuenta
+type SecurityController struct {
+	service services.SecurityService
+}
+
+// NewSecurityController crea una nueva instancia del controlador
+func NewSecurityController(service services.SecurityService) *SecurityController {
+	return &SecurityController{service: service}
+}
+
+// GetMySecurity maneja GET /users/me/security
+// Devuelve los logins sospechosos recientes y los dispositivos conocidos
+// del usuario logueado (user_id lo guarda AuthMiddleware)
+func (ctrl *SecurityController) GetMySecurity(c *gin.Context) {
+	overview, err := ctrl.service.GetOverview(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// GetMyLogins maneja GET /users/me/logins?page=1&limit=20
+// Historial de logins (exitosos y fallidos) del usuario logueado
+func (ctrl *SecurityController) GetMyLogins(c *gin.Context) {
+	ctrl.listLogins(c, c.GetUint("user_id"))
+}
+
+// GetUserLogins maneja GET /admin/users/:id/logins (solo admins)
+func (ctrl *SecurityController) GetUserLogins(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+	ctrl.listLogins(c, uint(id))
+}
+
+// listLogins responde una página del historial de logins del usuario
+func (ctrl *SecurityController) listLogins(c *gin.Context, userID uint) {
+	var query dto.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	logins, err := ctrl.service.ListLogins(c.Request.Context(), userID, query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, logins)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/services"
+
+	"shared/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsController maneja las estadísticas de usuarios del panel de admin
+type StatsController struct {
+	service services.StatsService
+}
+
+// NewStatsController crea una nueva instancia del controlador
+func NewStatsController(service services.StatsService) *StatsController {
+	return &StatsController{service: service}
+}
+
+// GetStats maneja GET /admin/users/stats?days=30&weeks=12
+// days (1-365, default 30) y weeks (1-52, default 12) son cuántos períodos
+// de registros devolver
+func (ctrl *StatsController) GetStats(c *gin.Context) {
+	days, err := queryRange(c, "days", 30, 365)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	weeks, err := queryRange(c, "weeks", 12, 52)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	stats, err := ctrl.service.GetStats(c.Request.Context(), days, weeks)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// queryRange lee un parámetro entero entre 1 y max (def si no viene)
+func queryRange(c *gin.Context, key string, def, max int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, apperrors.Newf(apperrors.CodeBadRequest, "%s must be between 1 and %d", key, max)
+	}
+	return n, nil
+}
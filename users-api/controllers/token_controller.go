@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenController maneja el logout y la revocación de tokens
+type TokenController struct {
+	service services.TokenService
+	audit   audit.Emitter
+}
+
+// NewTokenController crea una nueva instancia del controlador
+func NewTokenController(service services.TokenService, auditor audit.Emitter) *TokenController {
+	return &TokenController{service: service, audit: auditor}
+}
+
+// Logout maneja POST /users/logout
+// Revoca el token con el que se hizo la request (los claims los guarda AuthMiddleware)
+func (ctrl *TokenController) Logout(c *gin.Context) {
+	claims, ok := httpmw.ClaimsFromContext(c.Request.Context())
+	if !ok {
+		respondError(c, apperrors.Unauthorized("authorization header required"))
+		return
+	}
+
+	if err := ctrl.service.Logout(c.Request.Context(), claims); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Logged out"})
+}
+
+// RevokeTokens maneja POST /admin/users/:id/revoke-tokens
+// Invalida todos los tokens que el usuario tiene hasta ahora (ej: sesión robada)
+func (ctrl *TokenController) RevokeTokens(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	if err := ctrl.service.RevokeAll(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ctrl.audit.Emit(c.Request.Context(), adminEvent(c, audit.ActionTokensRevoked, idParam))
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Tokens revoked"})
+}
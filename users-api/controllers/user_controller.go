@@ -1,22 +1,39 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"users-api/domain"
 	"users-api/dto"
 	"users-api/services"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw/ginmw"
+	"shared/requestid"
 
 	"github.com/gin-gonic/gin"
 )
 
 // UserController maneja los endpoints HTTP de usuarios
 type UserController struct {
-	service services.UserService
+	service  services.UserService
+	security services.SecurityService
+	magic    services.MagicLinkService
+	google   services.GoogleLoginService
+	oidc     services.OIDCLoginService
+	audit    audit.Emitter // logins y cambios de admins (ver shared/audit)
+
+	// Header con el país de la IP que agrega el proxy o CDN (ej: CF-IPCountry)
+	// "" = los logins no llevan país
+	geoHeader string
 }
 
 // NewUserController crea una nueva instancia del controlador
-func NewUserController(service services.UserService) *UserController {
-	return &UserController{service: service}
+func NewUserController(service services.UserService, security services.SecurityService, magic services.MagicLinkService, google services.GoogleLoginService, oidc services.OIDCLoginService, auditor audit.Emitter, geoHeader string) *UserController {
+	return &UserController{service: service, security: security, magic: magic, google: google, oidc: oidc, audit: auditor, geoHeader: geoHeader}
 }
 
 // CreateUser maneja POST /users
@@ -26,21 +43,15 @@ func (ctrl *UserController) CreateUser(c *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// Si el JSON es inválido o faltan campos, devolver error 400
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		respondError(c, ginmw.BindingError(err))
 		return
 	}
 
 	// 2. Llamar al servicio para crear el usuario
-	user, err := ctrl.service.CreateUser(req)
+	user, err := ctrl.service.CreateUser(c.Request.Context(), req)
 	if err != nil {
-		// Si hay error (username duplicado, etc), devolver 400
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "create_user_error",
-			Message: err.Error(),
-		})
+		// Username/email duplicado => 409, error interno => 500
+		respondError(c, err)
 		return
 	}
 
@@ -48,12 +59,12 @@ func (ctrl *UserController) CreateUser(c *gin.Context) {
 	// Status 201 = Created
 	c.JSON(http.StatusCreated, dto.SuccessResponse{
 		Message: "User created successfully",
-		Data:    user,
+		Data:    dto.NewUserResponse(user),
 	})
 }
 
 // GetUserByID maneja GET /users/:id
-// Este endpoint obtiene un usuario por su ID
+// Este endpoint obtiene el perfil público de un usuario por su ID
 // Ejemplo: GET /users/5 -> obtiene el usuario con ID 5
 func (ctrl *UserController) GetUserByID(c *gin.Context) {
 	// 1. Obtener el parámetro "id" de la URL
@@ -62,26 +73,20 @@ func (ctrl *UserController) GetUserByID(c *gin.Context) {
 	// 2. Convertir el string a número
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID",
-		})
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
 		return
 	}
 
 	// 3. Llamar al servicio para obtener el usuario
-	user, err := ctrl.service.GetUserByID(uint(id))
+	user, err := ctrl.service.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
 		// Si no existe, devolver 404 (Not Found)
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{
-			Error:   "user_not_found",
-			Message: err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	// 4. Devolver el usuario encontrado
-	c.JSON(http.StatusOK, user)
+	// 4. Devolver el perfil público (la ruta no pide token)
+	c.JSON(http.StatusOK, dto.NewPublicUserResponse(user))
 }
 
 // Login maneja POST /users/login
@@ -90,30 +95,194 @@ func (ctrl *UserController) Login(c *gin.Context) {
 	// 1. Leer el JSON del body
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		respondError(c, ginmw.BindingError(err))
 		return
 	}
 
 	// 2. Llamar al servicio para hacer login
 	// El servicio valida contraseña y genera el JWT
-	response, err := ctrl.service.Login(req)
+	response, err := ctrl.service.Login(c.Request.Context(), req)
 	if err != nil {
 		// Si las credenciales son incorrectas, devolver 401 (Unauthorized)
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "login_error",
-			Message: err.Error(),
-		})
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			ctrl.audit.Emit(c.Request.Context(), audit.Event{
+				Action:    audit.ActionLoginFailed,
+				Outcome:   audit.OutcomeFailure,
+				ActorName: req.UsernameOrEmail,
+				IP:        c.ClientIP(),
+			})
+		}
+		ctrl.failedLogin(c, req.UsernameOrEmail, err)
+		respondError(c, err)
+		return
+	}
+
+	// 3. Auditoría, chequeo de seguridad y respuesta con el JWT
+	ctrl.loggedIn(c, response, "password")
+}
+
+// RequestMagicLink maneja POST /users/login/magic-link
+// Responde 202 siempre, exista o no el email (no revela qué emails tienen cuenta)
+func (ctrl *UserController) RequestMagicLink(c *gin.Context) {
+	var req dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	if err := ctrl.magic.Request(c.Request.Context(), req.Email); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// 3. Devolver el token JWT y los datos del usuario
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Message: "If the email is registered, a login link was sent",
+	})
+}
+
+// VerifyMagicLink maneja POST /users/login/magic-link/verify
+// Canjea el token del link (un solo uso) por el mismo JWT que da el login
+func (ctrl *UserController) VerifyMagicLink(c *gin.Context) {
+	var req dto.VerifyMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	response, err := ctrl.magic.Verify(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			ctrl.audit.Emit(c.Request.Context(), audit.Event{
+				Action:   audit.ActionLoginFailed,
+				Outcome:  audit.OutcomeFailure,
+				IP:       c.ClientIP(),
+				Metadata: map[string]interface{}{"method": "magic_link"},
+			})
+		}
+		respondError(c, err)
+		return
+	}
+
+	ctrl.loggedIn(c, response, "magic_link")
+}
+
+// GoogleLogin maneja POST /users/login/google
+// Canjea el ID token de Google Sign-In por el mismo JWT que da el login; la
+// primera vez crea el usuario (o lo asocia al que tiene el mismo email)
+func (ctrl *UserController) GoogleLogin(c *gin.Context) {
+	var req dto.GoogleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	response, err := ctrl.google.Login(c.Request.Context(), req.IDToken)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			ctrl.audit.Emit(c.Request.Context(), audit.Event{
+				Action:   audit.ActionLoginFailed,
+				Outcome:  audit.OutcomeFailure,
+				IP:       c.ClientIP(),
+				Metadata: map[string]interface{}{"method": "google"},
+			})
+		}
+		respondError(c, err)
+		return
+	}
+
+	ctrl.loggedIn(c, response, "google")
+}
+
+// OIDCProviders maneja GET /users/login/oidc
+// Lista los proveedores OIDC habilitados para que el frontend arme los botones
+func (ctrl *UserController) OIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "OIDC providers retrieved successfully",
+		Data:    ctrl.oidc.Providers(c.Request.Context()),
+	})
+}
+
+// OIDCLogin maneja POST /users/login/oidc/:provider
+// Canjea el code del proveedor por el mismo JWT que da el
+// login; como con Google, la primera vez crea o asocia el usuario
+func (ctrl *UserController) OIDCLogin(c *gin.Context) {
+	var req dto.OIDCLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	provider := c.Param("provider")
+	response, err := ctrl.oidc.Login(c.Request.Context(), provider, req)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			ctrl.audit.Emit(c.Request.Context(), audit.Event{
+				Action:   audit.ActionLoginFailed,
+				Outcome:  audit.OutcomeFailure,
+				IP:       c.ClientIP(),
+				Metadata: map[string]interface{}{"method": "oidc", "provider": provider},
+			})
+		}
+		respondError(c, err)
+		return
+	}
+
+	ctrl.loggedIn(c, response, "oidc:"+provider)
+}
+
+// loggedIn termina un login exitoso (con contraseña, magic link, Google u OIDC)
+//  1. Evento de auditoría login.succeeded
+//  2. Chequeo de dispositivo o red nuevos (si falla solo se loguea: no
+//     bloquea el login)
+//  3. Respuesta con el token JWT y los datos del usuario
+func (ctrl *UserController) loggedIn(c *gin.Context, response *dto.LoginResponse, method string) {
+	ctx := c.Request.Context()
+	ctrl.audit.Emit(ctx, audit.Event{
+		Action:    audit.ActionLoginSucceeded,
+		Outcome:   audit.OutcomeSuccess,
+		ActorID:   &response.User.ID,
+		ActorName: response.User.Username,
+		IP:        c.ClientIP(),
+		Metadata:  map[string]interface{}{"method": method},
+	})
+
+	client := ctrl.loginClient(c)
+	if err := ctrl.security.CheckLogin(ctx, response.User, client); err != nil {
+		requestid.Logf(ctx, "⚠️  Error chequeando login del usuario %d: %v", response.User.ID, err)
+	}
+	if err := ctrl.security.RecordLogin(ctx, response.User.ID, method, client, ""); err != nil {
+		requestid.Logf(ctx, "⚠️  Error guardando el login del usuario %d: %v", response.User.ID, err)
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
-// HealthCheck maneja GET /health
+// failedLogin guarda un login con contraseña rechazado en el historial del
+// usuario. Con un username o email que no existe no hay historial donde
+// guardarlo (queda solo en la auditoría)
+func (ctrl *UserController) failedLogin(c *gin.Context, login string, err error) {
+	failure := domain.LoginFailureInvalidCredentials
+	switch {
+	case errors.Is(err, apperrors.ErrForbidden):
+		failure = domain.LoginFailureDeactivated
+	case !errors.Is(err, apperrors.ErrUnauthorized):
+		return // error interno: no es un intento rechazado
+	}
+
+	ctx := c.Request.Context()
+	user, lookupErr := ctrl.service.GetUserByLogin(ctx, login)
+	if lookupErr != nil {
+		return
+	}
+	if failure == domain.LoginFailureDeactivated && user.Active() {
+		failure = domain.LoginFailureBanned
+	}
+	if err := ctrl.security.RecordLogin(ctx, user.ID, "password", ctrl.loginClient(c), failure); err != nil {
+		requestid.Logf(ctx, "⚠️  Error guardando el login fallido del usuario %d: %v", user.ID, err)
+	}
+}
+
+// HealthCheck maneja GET /health/live (y su alias GET /health)
 // Endpoint simple para verificar que el servicio está corriendo
 func (ctrl *UserController) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -130,37 +299,72 @@ func (ctrl *UserController) UpdateUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID",
-		})
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
 		return
 	}
 
-	// 2. Leer el JSON del body
+	// 2. Verificar que sea el propio usuario o un admin
+	// (la ruta está bajo /admin, pero el handler no debe depender de eso)
+	if c.GetUint("user_id") != uint(id) && c.GetString("user_type") != "admin" {
+		respondError(c, apperrors.Forbidden("You can only update your own profile"))
+		return
+	}
+
+	// 3. Leer el JSON del body
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		respondError(c, ginmw.BindingError(err))
 		return
 	}
 
-	// 3. Llamar al servicio para actualizar
-	user, err := ctrl.service.UpdateUser(uint(id), req)
+	// 4. Llamar al servicio para actualizar
+	user, err := ctrl.service.UpdateUser(c.Request.Context(), uint(id), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "update_user_error",
-			Message: err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	// 4. Devolver el usuario actualizado
+	// 5. Auditar el cambio y devolver el usuario actualizado
+	ctrl.audit.Emit(c.Request.Context(), adminEvent(c, audit.ActionAdminUserUpdated, idParam))
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "User updated successfully",
-		Data:    user,
+		Data:    dto.NewUserResponse(user),
+	})
+}
+
+// GetMe maneja GET /users/me
+// Devuelve el usuario del token (user_id lo guarda AuthMiddleware)
+func (ctrl *UserController) GetMe(c *gin.Context) {
+	user, err := ctrl.service.GetUserByID(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// UpdateMe maneja PUT /users/me
+// El propio usuario cambia su perfil sin pasar por las rutas de admin
+// (no puede tocar su rol ni su estado)
+func (ctrl *UserController) UpdateMe(c *gin.Context) {
+	// 1. Leer el JSON del body
+	var req dto.UpdateMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	// 2. Actualizar el usuario del token
+	user, err := ctrl.service.UpdateMe(c.Request.Context(), c.GetUint("user_id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Profile updated successfully",
+		Data:    dto.NewUserResponse(user),
 	})
 }
 
@@ -172,46 +376,62 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID",
-		})
+		respondError(c, apperrors.BadRequest("Invalid user ID"))
 		return
 	}
 
 	// 2. Llamar al servicio para eliminar
-	err = ctrl.service.DeleteUser(uint(id))
+	err = ctrl.service.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, dto.ErrorResponse{
-			Error:   "delete_user_error",
-			Message: err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	// 3. Devolver confirmación
+	// 3. Auditar y devolver confirmación
+	ctrl.audit.Emit(c.Request.Context(), adminEvent(c, audit.ActionAdminUserDeleted, idParam))
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "User deleted successfully",
 	})
 }
 
+// adminEvent arma el evento de auditoría de un cambio hecho por un admin
+// sobre el usuario targetID (el admin sale del JWT)
+func adminEvent(c *gin.Context, action, targetID string) audit.Event {
+	actorID := c.GetUint("user_id")
+	return audit.Event{
+		Action:     action,
+		Outcome:    audit.OutcomeSuccess,
+		ActorID:    &actorID,
+		ActorName:  c.GetString("username"),
+		TargetType: "user",
+		TargetID:   targetID,
+		IP:         c.ClientIP(),
+	}
+}
+
 // GetAllUsers maneja GET /users
 // Este endpoint lista todos los usuarios
 // Solo accesible por administradores
 func (ctrl *UserController) GetAllUsers(c *gin.Context) {
 	// 1. Llamar al servicio para obtener todos los usuarios
-	users, err := ctrl.service.GetAllUsers()
+	users, err := ctrl.service.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "get_users_error",
-			Message: err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	// 2. Devolver la lista de usuarios
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "Users retrieved successfully",
-		Data:    users,
+		Data:    dto.NewUserResponses(users),
 	})
 }
+
+// loginClient arma IP, user agent y país (si hay header de geo) del login
+func (ctrl *UserController) loginClient(c *gin.Context) domain.LoginClient {
+	client := domain.LoginClient{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	if ctrl.geoHeader != "" {
+		client.Country = utils.CountryCode(c.GetHeader(ctrl.geoHeader))
+	}
+	return client
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/dto"
+	"users-api/services"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController maneja los webhooks de eventos de usuarios
+type WebhookController struct {
+	service services.WebhookService
+	audit   audit.Emitter
+}
+
+// NewWebhookController crea una nueva instancia del controlador
+func NewWebhookController(service services.WebhookService, auditor audit.Emitter) *WebhookController {
+	return &WebhookController{service: service, audit: auditor}
+}
+
+// CreateWebhook maneja POST /admin/webhooks
+// Devuelve el secret con el que se firman los envíos (es la única vez que se ve)
+func (ctrl *WebhookController) CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	webhook, err := ctrl.service.Create(c.Request.Context(), c.GetUint("user_id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := adminEvent(c, audit.ActionWebhookCreated, strconv.FormatUint(uint64(webhook.ID), 10))
+	event.TargetType = "webhook"
+	event.Metadata = map[string]interface{}{"url": webhook.URL, "events": webhook.Events}
+	ctrl.audit.Emit(c.Request.Context(), event)
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Message: "Webhook created",
+		Data:    webhook,
+	})
+}
+
+// ListWebhooks maneja GET /admin/webhooks
+func (ctrl *WebhookController) ListWebhooks(c *gin.Context) {
+	webhooks, err := ctrl.service.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Webhooks retrieved successfully",
+		Data:    webhooks,
+	})
+}
+
+// DeleteWebhook maneja DELETE /admin/webhooks/:id
+func (ctrl *WebhookController) DeleteWebhook(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid webhook ID"))
+		return
+	}
+
+	if err := ctrl.service.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := adminEvent(c, audit.ActionWebhookDeleted, idParam)
+	event.TargetType = "webhook"
+	ctrl.audit.Emit(c.Request.Context(), event)
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Webhook deleted"})
+}
+
+// ListDeliveries maneja GET /admin/webhooks/:id/deliveries
+// Historial de envíos: estado, intentos, último status y error
+func (ctrl *WebhookController) ListDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("Invalid webhook ID"))
+		return
+	}
+
+	var query dto.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, ginmw.BindingError(err))
+		return
+	}
+
+	deliveries, err := ctrl.service.ListDeliveries(c.Request.Context(), uint(id), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
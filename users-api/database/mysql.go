@@ -0,0 +1,128 @@
+// Package database abre la conexión a MySQL de users-api
+// La usan el servidor (main.go) y los comandos de cmd/ (seed, admin)
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"shared/config"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Config son los datos de conexión a MySQL
+type Config struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+
+	// Queries más lentas que esto se loguean con ⚠️ (0 = ninguna)
+	SlowQueryThreshold time.Duration
+	// Requests con más queries que esto se loguean como posible N+1 (0 = no se avisa)
+	QueriesPerRequestWarn int
+
+	// Cuánto se reintenta la primera conexión si MySQL no responde (0 = no se reintenta)
+	ConnectMaxWait time.Duration
+
+	// Tope para cada query: pasado este tiempo se cancela (0 = sin límite)
+	QueryTimeout time.Duration
+}
+
+const (
+	initialConnectDelay = 1 * time.Second
+	maxConnectDelay     = 15 * time.Second
+)
+
+// ConfigFromEnv lee DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME,
+// DB_SLOW_QUERY_THRESHOLD (ej: "200ms"), DB_QUERIES_PER_REQUEST_WARN y
+// DB_CONNECT_MAX_WAIT (ej: "60s") y DB_QUERY_TIMEOUT (ej: "10s")
+func ConfigFromEnv(env *config.Env) Config {
+	return Config{
+		Host:                  env.String("DB_HOST", "localhost"),
+		Port:                  env.String("DB_PORT", "3306"),
+		User:                  env.String("DB_USER", "spotly_user"),
+		Password:              env.String("DB_PASSWORD", "spotly_password"),
+		Name:                  env.String("DB_NAME", "users_db"),
+		SlowQueryThreshold:    env.Duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		QueriesPerRequestWarn: env.Int("DB_QUERIES_PER_REQUEST_WARN", 20),
+		ConnectMaxWait:        env.Duration("DB_CONNECT_MAX_WAIT", 60*time.Second),
+		QueryTimeout:          env.Duration("DB_QUERY_TIMEOUT", 10*time.Second),
+	}
+}
+
+// DSN = Data Source Name (string de conexión)
+// Formato: usuario:password@tcp(host:puerto)/base_de_datos?opciones
+func (c Config) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		c.User, c.Password, c.Host, c.Port, c.Name)
+}
+
+// Open conecta a MySQL
+// Las queries se miden (/debug/vars "db_queries"), las lentas se loguean y
+// las hechas con el contexto de una request llevan su ID (ver querylog.go)
+// Cada query se cancela si pasa cfg.QueryTimeout (ver timeout.go)
+// quiet apaga el log de SQL de GORM (para los comandos de consola)
+// Si MySQL todavía no acepta conexiones (ej: el contenedor arranca más lento
+// que la API) se reintenta con backoff hasta cfg.ConnectMaxWait
+func Open(cfg Config, quiet bool) (*gorm.DB, error) {
+	queryLogger := NewQueryLogger(cfg.SlowQueryThreshold)
+	if quiet {
+		queryLogger = queryLogger.LogMode(logger.Silent)
+	}
+
+	var db *gorm.DB
+	err := retryConnect(cfg.ConnectMaxWait, time.Sleep, func() error {
+		var err error
+		db, err = gorm.Open(mysql.Open(cfg.DSN()), &gorm.Config{Logger: queryLogger})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := registerRequestIDTag(db); err != nil {
+		return nil, err
+	}
+	if err := registerQueryTimeout(db, cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// retryConnect llama a connect hasta que funcione, esperando 1s, 2s, 4s...
+// (hasta 15s entre intentos) mientras no se pase de maxWait
+// Un error de MySQL (ej: password incorrecto, base inexistente) no se
+// reintenta: el servidor respondió y esperar no lo arregla
+func retryConnect(maxWait time.Duration, sleep func(time.Duration), connect func() error) error {
+	delay := initialConnectDelay
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil {
+			return nil
+		}
+		var mysqlErr *mysqldriver.MySQLError
+		if errors.As(err, &mysqlErr) {
+			return err
+		}
+		if waited >= maxWait {
+			if waited > 0 {
+				return fmt.Errorf("mysql not reachable after %s: %w", waited, err)
+			}
+			return err
+		}
+
+		wait := min(delay, maxWait-waited)
+		log.Printf("⏳ MySQL no responde (intento %d): %v. Reintentando en %s", attempt, err, wait)
+		sleep(wait)
+		waited += wait
+		delay = min(delay*2, maxConnectDelay)
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// Test: se reintenta con backoff hasta que MySQL responde
+func TestRetryConnect(t *testing.T) {
+	var waits []time.Duration
+	sleep := func(d time.Duration) { waits = append(waits, d) }
+
+	attempts := 0
+	err := retryConnect(time.Minute, sleep, func() error {
+		attempts++
+		if attempts < 4 {
+			return errors.New("dial tcp: connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(waits) != len(want) {
+		t.Fatalf("Expected waits %v, got %v", want, waits)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("Expected waits %v, got %v", want, waits)
+		}
+	}
+}
+
+// Test: se deja de reintentar al llegar a la espera máxima o con un error de MySQL
+func TestRetryConnect_GivesUp(t *testing.T) {
+	var waited time.Duration
+	sleep := func(d time.Duration) { waited += d }
+	refused := errors.New("dial tcp: connection refused")
+
+	err := retryConnect(10*time.Second, sleep, func() error { return refused })
+	if !errors.Is(err, refused) || waited != 10*time.Second {
+		t.Errorf("Expected to give up after 10s, waited %s: %v", waited, err)
+	}
+
+	attempts := 0
+	denied := &mysqldriver.MySQLError{Number: 1045, Message: "Access denied"}
+	err = retryConnect(time.Minute, sleep, func() error { attempts++; return denied })
+	if !errors.Is(err, denied) || attempts != 1 {
+		t.Errorf("Expected a single attempt on a MySQL error, got %d: %v", attempts, err)
+	}
+
+	attempts = 0
+	if err := retryConnect(0, sleep, func() error { attempts++; return refused }); err != refused || attempts != 1 {
+		t.Errorf("Expected no retries with maxWait 0, got %d: %v", attempts, err)
+	}
+}
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"shared/logging"
+	"shared/requestid"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// queryMetrics son las métricas de las queries por operación (select,
+// insert, update, delete, raw), publicadas en /debug/vars como "db_queries":
+//
+//	{"select": {"count": 120, "total_ms": 340, "slow": 1, "errors": 0,
+//	            "le_10ms": 110, "le_100ms": 9, "le_1s": 1, "gt_1s": 0}}
+var queryMetrics = expvar.NewMap("db_queries")
+
+// durationBuckets agrupa las duraciones para ver la distribución sin un
+// sistema de métricas aparte
+var durationBuckets = []struct {
+	name string
+	max  time.Duration
+}{
+	{"le_10ms", 10 * time.Millisecond},
+	{"le_100ms", 100 * time.Millisecond},
+	{"le_1s", time.Second},
+}
+
+// recordQuery suma una query a las métricas de su operación
+func recordQuery(op string, elapsed time.Duration, slow, failed bool) {
+	metrics, ok := queryMetrics.Get(op).(*expvar.Map)
+	if !ok {
+		// Dos goroutines pueden llegar acá a la vez: gana el primer Set
+		// pero los dos Map son equivalentes, se pierde a lo sumo una muestra
+		metrics = new(expvar.Map).Init()
+		queryMetrics.Set(op, metrics)
+	}
+
+	metrics.Add("count", 1)
+	metrics.Add("total_ms", elapsed.Milliseconds())
+	if slow {
+		metrics.Add("slow", 1)
+	}
+	if failed {
+		metrics.Add("errors", 1)
+	}
+
+	bucket := "gt_1s"
+	for _, b := range durationBuckets {
+		if elapsed <= b.max {
+			bucket = b.name
+			break
+		}
+	}
+	metrics.Add(bucket, 1)
+}
+
+// operation saca el tipo de query de la primera palabra del SQL
+// (salteando el comentario con el request ID)
+func operation(sql string) string {
+	if strings.HasPrefix(sql, "/*") {
+		if end := strings.Index(sql, "*/"); end >= 0 {
+			sql = sql[end+2:]
+		}
+	}
+	word, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch word = strings.ToLower(word); word {
+	case "select", "insert", "update", "delete":
+		return word
+	}
+	return "raw"
+}
+
+// QueryStats cuenta las queries de una request (ver WithQueryStats)
+type QueryStats struct {
+	count atomic.Int64
+}
+
+// Count devuelve cuántas queries se hicieron hasta ahora
+func (s *QueryStats) Count() int64 {
+	return s.count.Load()
+}
+
+type queryStatsKey struct{}
+
+// WithQueryStats agrega un contador de queries al contexto
+// Las queries hechas con ese contexto (db.WithContext) se cuentan: una
+// request con muchas queries suele ser un N+1
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsKey{}, stats), stats
+}
+
+// queryLogger reemplaza al logger de GORM
+//   - queries que tardan más de slow: ⚠️ con el SQL, siempre
+//   - errores (salvo "record not found"): ❌
+//   - el resto: 🐛, solo se ven con LOG_LEVEL=debug
+//
+// Todas las líneas llevan el request ID cuando la query usó el contexto
+// de la request
+type queryLogger struct {
+	slow  time.Duration
+	level logger.LogLevel
+}
+
+// NewQueryLogger crea el logger de GORM con el umbral de query lenta
+// slow <= 0 no marca ninguna query como lenta
+func NewQueryLogger(slow time.Duration) logger.Interface {
+	return &queryLogger{slow: slow, level: logger.Info}
+}
+
+func (l *queryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		requestid.Logf(ctx, "🗄️  "+msg, args...)
+	}
+}
+
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		requestid.Logf(ctx, "⚠️  "+msg, args...)
+	}
+}
+
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		requestid.Logf(ctx, "❌ "+msg, args...)
+	}
+}
+
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := l.slow > 0 && elapsed >= l.slow
+	recordQuery(operation(sql), elapsed, slow, failed)
+	if stats, ok := ctx.Value(queryStatsKey{}).(*QueryStats); ok {
+		stats.count.Add(1)
+	}
+
+	switch {
+	case l.level <= logger.Silent:
+	case failed && l.level >= logger.Error:
+		requestid.Logf(ctx, "❌ SQL falló (%s): %v | %s", elapsed.Round(time.Microsecond), err, sql)
+	case slow && l.level >= logger.Warn:
+		requestid.Logf(ctx, "⚠️  SQL lento (%s, umbral %s, %d filas): %s", elapsed.Round(time.Microsecond), l.slow, rows, sql)
+	case l.level >= logger.Info && logging.Enabled(logging.LevelDebug):
+		requestid.Logf(ctx, "🐛 SQL (%s, %d filas): %s", elapsed.Round(time.Microsecond), rows, sql)
+	}
+}
+
+// tagRequestID antepone /* req=<id> */ a la cláusula name (SELECT, INSERT...)
+// de las queries hechas con el contexto de una request. MySQL conserva el
+// comentario en el slow query log y en SHOW PROCESSLIST, así una query lenta
+// se cruza con el log de acceso
+func tagRequestID(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		id := requestid.FromContext(db.Statement.Context)
+		// Valid solo acepta [A-Za-z0-9-_.]: el ID no puede cerrar el comentario
+		if !requestid.Valid(id) {
+			return
+		}
+		c := db.Statement.Clauses[name]
+		c.BeforeExpression = clause.Expr{SQL: fmt.Sprintf("/* req=%s */", id)}
+		db.Statement.Clauses[name] = c
+	}
+}
+
+// registerRequestIDTag registra tagRequestID antes de cada operación
+func registerRequestIDTag(db *gorm.DB) error {
+	return errors.Join(
+		db.Callback().Query().Before("gorm:query").Register("requestid:tag", tagRequestID("SELECT")),
+		db.Callback().Create().Before("gorm:create").Register("requestid:tag", tagRequestID("INSERT")),
+		db.Callback().Update().Before("gorm:update").Register("requestid:tag", tagRequestID("UPDATE")),
+		db.Callback().Delete().Before("gorm:delete").Register("requestid:tag", tagRequestID("DELETE")),
+	)
+}
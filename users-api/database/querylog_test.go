@@ -0,0 +1,81 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"users-api/domain"
+
+	"shared/requestid"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunDB arma SQL sin conectarse a MySQL
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(localhost:3306)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: NewQueryLogger(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registerRequestIDTag(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// Test: las queries con el contexto de una request llevan su ID
+func TestRequestIDTag(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := requestid.WithContext(context.Background(), "abc-123")
+
+	var user domain.User
+	stmt := db.WithContext(ctx).Where("username = ?", "maria").First(&user).Statement
+	if sql := stmt.SQL.String(); !strings.HasPrefix(sql, "/* req=abc-123 */ SELECT") {
+		t.Errorf("Expected tagged SELECT, got %q", sql)
+	}
+
+	stmt = db.WithContext(ctx).Delete(&domain.User{}, 1).Statement
+	if sql := stmt.SQL.String(); !strings.HasPrefix(sql, "/* req=abc-123 */ DELETE") {
+		t.Errorf("Expected tagged DELETE, got %q", sql)
+	}
+
+	// Sin request ID (o con uno que podría cerrar el comentario) no se toca
+	stmt = db.WithContext(requestid.WithContext(context.Background(), "*/ DROP")).First(&user).Statement
+	if sql := stmt.SQL.String(); !strings.HasPrefix(sql, "SELECT") {
+		t.Errorf("Expected untagged SELECT, got %q", sql)
+	}
+}
+
+// Test: las queries lentas se loguean con el request ID y cuentan en las métricas
+func TestQueryLogger_Slow(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, stats := WithQueryStats(requestid.WithContext(context.Background(), "abc-123"))
+	l := NewQueryLogger(100 * time.Millisecond)
+	sql := func() (string, int64) { return "/* req=abc-123 */ SELECT * FROM `users` WHERE email = 'x'", 1 }
+
+	l.Trace(ctx, time.Now(), sql, nil)
+	if out.Len() != 0 {
+		t.Errorf("Fast query logged outside debug: %s", out.String())
+	}
+	l.Trace(ctx, time.Now().Add(-time.Second), sql, nil)
+	if logged := out.String(); !strings.Contains(logged, "[req=abc-123] ⚠️  SQL lento") {
+		t.Errorf("Unexpected log: %s", logged)
+	}
+
+	if stats.Count() != 2 {
+		t.Errorf("Expected 2 queries counted, got %d", stats.Count())
+	}
+	if op := operation("/* req=abc-123 */ SELECT 1"); op != "select" {
+		t.Errorf("Expected select, got %s", op)
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryCancelKey = "querytimeout:cancel"
+
+// registerQueryTimeout corta cada operación que tarde más que timeout
+// (0 = sin límite). El contexto de la request ya llega a MySQL con
+// db.WithContext(ctx); esto agrega un tope por query para que una consulta
+// trabada no tenga la conexión ocupada hasta que el cliente se canse
+// No se aplica a Rows(): las filas se leen después del callback y cortar
+// el contexto ahí cerraría el cursor a mitad de camino
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	start, finish := withQueryTimeout(timeout), cancelQueryTimeout
+	return errors.Join(
+		db.Callback().Query().Before("gorm:query").Register("querytimeout:start", start),
+		db.Callback().Query().After("gorm:query").Register("querytimeout:finish", finish),
+		db.Callback().Create().Before("gorm:create").Register("querytimeout:start", start),
+		db.Callback().Create().After("gorm:create").Register("querytimeout:finish", finish),
+		db.Callback().Update().Before("gorm:update").Register("querytimeout:start", start),
+		db.Callback().Update().After("gorm:update").Register("querytimeout:finish", finish),
+		db.Callback().Delete().Before("gorm:delete").Register("querytimeout:start", start),
+		db.Callback().Delete().After("gorm:delete").Register("querytimeout:finish", finish),
+		db.Callback().Raw().Before("gorm:raw").Register("querytimeout:start", start),
+		db.Callback().Raw().After("gorm:raw").Register("querytimeout:finish", finish),
+	)
+}
+
+// withQueryTimeout reemplaza el contexto de la operación por uno con tope
+// Si el de la request vence antes, manda ese (WithTimeout se queda con el
+// plazo más corto)
+func withQueryTimeout(timeout time.Duration) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(queryCancelKey, cancel)
+	}
+}
+
+// cancelQueryTimeout libera el timer del contexto armado en withQueryTimeout
+func cancelQueryTimeout(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(queryCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+	"users-api/domain"
+
+	"gorm.io/gorm"
+)
+
+// Test: cada query corre con un contexto con tope, que se libera al terminar
+func TestQueryTimeout(t *testing.T) {
+	db := dryRunDB(t)
+	if err := registerQueryTimeout(db, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	var deadline time.Time
+	var hasDeadline bool
+	if err := db.Callback().Query().After("gorm:query").Before("querytimeout:finish").Register("test:deadline", func(tx *gorm.DB) {
+		deadline, hasDeadline = tx.Statement.Context.Deadline()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var user domain.User
+	stmt := db.WithContext(context.Background()).First(&user).Statement
+	if !hasDeadline || time.Until(deadline) > 50*time.Millisecond {
+		t.Errorf("Expected a 50ms deadline, got %v (%v)", deadline, hasDeadline)
+	}
+	if stmt.Context.Err() == nil {
+		t.Error("Expected the query context to be released after the query")
+	}
+
+	// Un plazo más corto de la request se respeta
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	db.WithContext(ctx).First(&user)
+	if !deadline.Equal(want) {
+		t.Errorf("Expected the request deadline %v, got %v", want, deadline)
+	}
+}
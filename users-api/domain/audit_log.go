@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Acciones que quedan en audit_logs
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditLog es un alta, cambio o baja de un usuario hecho por un admin
+// OldValues y NewValues tienen solo los campos que cambiaron (en un alta
+// OldValues va vacío, en una baja NewValues). Las filas no se modifican nunca
+type AuditLog struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	ActorID   uint            `gorm:"not null;index" json:"actor_id"`
+	ActorName string          `gorm:"size:255" json:"actor_name"`
+	Action    string          `gorm:"size:16;not null;index" json:"action"`
+	TargetID  uint            `gorm:"not null;index" json:"target_id"`
+	OldValues json.RawMessage `gorm:"type:json" json:"old_values,omitempty"`
+	NewValues json.RawMessage `gorm:"type:json" json:"new_values,omitempty"`
+	RequestID string          `gorm:"size:64" json:"request_id,omitempty"`
+	CreatedAt time.Time       `gorm:"index" json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
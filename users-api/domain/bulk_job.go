@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Acciones de POST /admin/users/bulk
+const (
+	BulkActionDeactivate = "deactivate"
+	BulkActionActivate   = "activate"
+	BulkActionDelete     = "delete"
+	BulkActionSetRole    = "set_role"
+)
+
+// BulkJobStatus es el estado de una operación masiva
+type BulkJobStatus string
+
+const (
+	BulkJobPending BulkJobStatus = "pending" // creada, todavía no arrancó
+	BulkJobRunning BulkJobStatus = "running"
+	BulkJobDone    BulkJobStatus = "done" // terminó; los usuarios que fallaron están en Errors
+)
+
+// BulkJobError es un usuario que no se pudo procesar
+type BulkJobError struct {
+	UserID uint   `json:"user_id"`
+	Error  string `json:"error"`
+}
+
+// BulkJob es una operación masiva sobre usuarios que corre en segundo plano
+// Se consulta con GET /admin/users/bulk/:id. UpdatedAt avanza con cada lote
+// procesado: un job "running" que no avanza quedó cortado por un reinicio
+type BulkJob struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Action     string         `gorm:"size:32;not null" json:"action"`
+	Role       UserType       `gorm:"type:varchar(20)" json:"role,omitempty"`
+	CreatedBy  uint           `gorm:"index;not null" json:"created_by"`
+	Status     BulkJobStatus  `gorm:"size:16;not null" json:"status"`
+	Total      int            `json:"total"`
+	Processed  int            `json:"processed"`
+	Failed     int            `json:"failed"`
+	Errors     []BulkJobError `gorm:"serializer:json;type:text" json:"errors,omitempty"` // los primeros MaxBulkJobErrors
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// MaxBulkJobErrors es cuántos errores se guardan por job (el resto solo cuenta en Failed)
+const MaxBulkJobErrors = 100
+
+// TableName especifica el nombre de la tabla en MySQL
+func (BulkJob) TableName() string {
+	return "user_bulk_jobs"
+}
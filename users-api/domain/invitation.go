@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Invitation es una invitación a registrarse (POST /admin/invitations)
+// El código va por email al invitado; en la base se guarda solo su hash
+// SHA-256, como los magic links. Se usa una sola vez y únicamente con el
+// email al que se mandó
+type Invitation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CodeHash  string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Email     string    `gorm:"size:255;not null;index" json:"email"`
+	UserType  UserType  `gorm:"type:varchar(20);default:'normal'" json:"user_type"` // rol con el que se crea el usuario
+	CreatedBy uint      `gorm:"not null" json:"created_by"`                         // admin que invitó
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// UsedAt y UsedBy se completan al registrarse con el código
+	UsedAt *time.Time `json:"used_at,omitempty"`
+	UsedBy *uint      `json:"used_by,omitempty"`
+}
+
+// Usable indica si el código todavía sirve para registrarse
+func (i *Invitation) Usable(now time.Time) bool {
+	return i.UsedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (Invitation) TableName() string {
+	return "invitations"
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// MagicLinkToken es un link de login por email (sin contraseña)
+// Se guarda solo el hash SHA-256 del token: con una copia de la base no se
+// puede usar ningún link pendiente
+type MagicLinkToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (MagicLinkToken) TableName() string {
+	return "magic_link_tokens"
+}
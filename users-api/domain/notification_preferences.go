@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// NotificationPreferences guarda qué emails quiere recibir cada usuario
+// Hay una fila por usuario; si no existe se usan los valores por defecto
+// (sin default en GORM: si no, un false no se insertaría)
+type NotificationPreferences struct {
+	UserID             uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	EmailTransactional bool      `gorm:"not null" json:"email_transactional"` // Reservas, cuenta, seguridad
+	EmailMarketing     bool      `gorm:"not null" json:"email_marketing"`     // Promociones y novedades
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreferences devuelve las preferencias de un usuario que nunca las configuró
+// Los transaccionales están activados y el marketing es opt-in
+func DefaultNotificationPreferences(userID uint) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:             userID,
+		EmailTransactional: true,
+		EmailMarketing:     false,
+	}
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (NotificationPreferences) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// OutboxEvent es un evento de dominio guardado en la misma transacción que
+// el cambio que lo produce (transactional outbox)
+// Un job lo publica en RabbitMQ después: si la transacción se revierte el
+// evento no existe, y si RabbitMQ no responde se reintenta sin perderlo
+type OutboxEvent struct {
+	ID          uint                   `gorm:"primaryKey" json:"id"`
+	EventID     string                 `gorm:"size:32;not null;uniqueIndex" json:"event_id"` // ID del sobre, estable entre reintentos
+	Type        string                 `gorm:"size:100;not null" json:"type"`
+	Data        map[string]interface{} `gorm:"serializer:json;type:text" json:"data"`
+	CreatedAt   time.Time              `json:"created_at"`
+	PublishedAt *time.Time             `gorm:"index" json:"published_at,omitempty"` // nil = pendiente
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
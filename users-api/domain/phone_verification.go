@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// PhoneVerification es el código de verificación por SMS pendiente de un usuario
+// Hay uno por usuario: pedir otro código reemplaza al anterior
+type PhoneVerification struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;uniqueIndex"`
+	Phone     string    `gorm:"size:16;not null"` // el teléfono al que se mandó el código
+	CodeHash  string    `gorm:"size:60;not null"` // bcrypt: el código nunca se guarda en claro
+	Attempts  int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (PhoneVerification) TableName() string {
+	return "phone_verifications"
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// RevokedToken es un JWT invalidado antes de vencer (logout)
+// Se identifica por su jti y se guarda hasta ExpiresAt: después el token ya
+// no vale igual y la fila se puede borrar
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:64"`
+	UserID    uint      `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// TokenRevocation invalida todos los tokens de un usuario emitidos hasta
+// RevokedAt (ej: un admin sospecha que le robaron la sesión)
+// Hay una fila por usuario; revocar de nuevo mueve RevokedAt
+type TokenRevocation struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	RevokedAt time.Time `gorm:"not null;index"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (TokenRevocation) TableName() string {
+	return "token_revocations"
+}
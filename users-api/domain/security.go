@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// SecurityEventType define los tipos de eventos de seguridad
+type SecurityEventType string
+
+const (
+	SecurityEventNewDevice   SecurityEventType = "new_device"   // Login desde un navegador/dispositivo nuevo
+	SecurityEventNewLocation SecurityEventType = "new_location" // Login desde una red (IP) nueva
+)
+
+// SecurityEvent es un evento de seguridad de la cuenta de un usuario
+// Se muestra en /users/me/security
+type SecurityEvent struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	UserID    uint              `gorm:"not null;index" json:"user_id"`
+	Type      SecurityEventType `gorm:"type:varchar(30);not null" json:"type"`
+	IP        string            `gorm:"size:45" json:"ip"`
+	UserAgent string            `gorm:"size:255" json:"user_agent"`
+	Device    string            `gorm:"size:100" json:"device"`          // ej: "Chrome (Windows)"
+	Country   string            `gorm:"size:2" json:"country,omitempty"` // ISO 3166-1 (si hay geo)
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+// KnownDevice es un dispositivo desde el que el usuario ya se logueó
+// Se identifica por navegador, sistema y plataforma (ver utils.DeviceFingerprint);
+// UserAgent, LastIP y LastCountry son los del último login
+type KnownDevice struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_user_device" json:"-"`
+	Fingerprint string    `gorm:"size:64;not null;uniqueIndex:idx_user_device" json:"-"`
+	Browser     string    `gorm:"size:40" json:"browser"`
+	OS          string    `gorm:"size:40" json:"os"`
+	Platform    string    `gorm:"size:20" json:"platform"` // desktop, mobile, tablet, bot o unknown
+	UserAgent   string    `gorm:"size:255" json:"user_agent"`
+	LastIP      string    `gorm:"size:45" json:"last_ip"`
+	LastCountry string    `gorm:"size:2" json:"last_country,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (KnownDevice) TableName() string {
+	return "known_devices"
+}
+
+// KnownNetwork es una red (prefijo de IP) desde la que el usuario ya se logueó
+// Usamos el prefijo (/24 en IPv4, /48 en IPv6) como aproximación a la ubicación
+type KnownNetwork struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_user_network"`
+	Prefix      string `gorm:"size:64;not null;uniqueIndex:idx_user_network"`
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (KnownNetwork) TableName() string {
+	return "known_networks"
+}
+
+// Motivos de un login fallido (LoginEvent.Failure)
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureDeactivated        = "account_deactivated"
+	LoginFailureBanned             = "account_banned"
+)
+
+// LoginEvent es un intento de login de un usuario (GET /users/me/logins)
+// Los intentos con un username o email que no existe no se guardan: no son
+// de ninguna cuenta (quedan en la auditoría como login.failed)
+type LoginEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index:idx_login_user_time" json:"user_id"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Method    string    `gorm:"size:20" json:"method"`            // password, magic_link, google u oidc:<proveedor>
+	Failure   string    `gorm:"size:30" json:"failure,omitempty"` // ver LoginFailure*
+	IP        string    `gorm:"size:45" json:"ip"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	Country   string    `gorm:"size:2" json:"country,omitempty"`
+	CreatedAt time.Time `gorm:"index;index:idx_login_user_time" json:"created_at"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// LoginClient es desde dónde se hizo un login
+type LoginClient struct {
+	IP        string
+	UserAgent string
+	Country   string // ISO 3166-1 alfa-2 del proxy o CDN; "" = sin geo
+}
@@ -15,12 +15,91 @@ type User struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Username  string    `gorm:"unique;not null" json:"username"`
 	Email     string    `gorm:"unique;not null" json:"email"`
-	Password  string    `gorm:"not null" json:"-"` // El "-" oculta el password en JSON
+	Password  string    `json:"-"` // El "-" oculta el password en JSON; vacío = sin contraseña (login social)
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	UserType  UserType  `gorm:"type:varchar(20);default:'normal'" json:"user_type"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeactivatedAt marca una cuenta desactivada por un admin: no puede
+	// iniciar sesión. nil = activa
+	DeactivatedAt *time.Time `gorm:"index" json:"deactivated_at,omitempty"`
+
+	// Phone es el teléfono en formato E.164 ("+5493511234567"), opcional
+	// PhoneVerifiedAt se completa al confirmar el código por SMS y se borra
+	// si el teléfono cambia
+	Phone           string     `gorm:"size:16" json:"phone,omitempty"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+
+	// MergedInto es la cuenta en la que un admin fusionó esta (duplicada)
+	// Una cuenta fusionada queda desactivada y no se puede reactivar
+	MergedInto *uint `gorm:"index" json:"merged_into,omitempty"`
+
+	// Provider y ProviderID identifican la cuenta externa con la que entra el
+	// usuario (ej: "google" y el "sub" de Google, o el nombre de un proveedor
+	// OIDC y su "sub"). "" = solo login propio
+	// Un usuario creado desde un login social no tiene contraseña
+	Provider   string  `gorm:"size:20;uniqueIndex:idx_users_provider" json:"provider,omitempty"`
+	ProviderID *string `gorm:"size:255;uniqueIndex:idx_users_provider" json:"-"`
+
+	// AvatarURL y AvatarThumbURL son la foto de perfil y su miniatura
+	// (POST /users/me/avatar). AvatarKey es la clave en el bucket, para borrar
+	// la foto anterior cuando se sube otra ("" = sin avatar)
+	AvatarURL      string `gorm:"size:512" json:"avatar_url,omitempty"`
+	AvatarThumbURL string `gorm:"size:512" json:"avatar_thumb_url,omitempty"`
+	AvatarKey      string `gorm:"size:255" json:"-"`
+
+	// LastLoginAt es el último login exitoso (con cualquier método)
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+	// EmailNormalized es el email en la forma con la que se compara
+	// (utils.NormalizeEmail). Con el índice único, Foo@Bar.com y foo@bar.com
+	// no pueden ser dos cuentas. Lo completa el repositorio al guardar; nil =
+	// usuario anterior a la columna (ver UserService.NormalizeEmails)
+	EmailNormalized *string `gorm:"size:255;uniqueIndex:idx_users_email_normalized" json:"-"`
+
+	// BannedUntil suspende la cuenta hasta esa fecha (POST /admin/users/:id/ban):
+	// no puede iniciar sesión y sus tokens se revocan. nil o una fecha
+	// pasada = no suspendida. Para una baja definitiva está DeactivatedAt
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
+
+	// Locale (BCP 47, ej: "es-AR") y Timezone (IANA, ej:
+	// "America/Argentina/Cordoba") del perfil; van en el JWT para que los
+	// otros servicios localicen sus respuestas. "" = sin definir
+	Locale   string `gorm:"size:35" json:"locale,omitempty"`
+	Timezone string `gorm:"size:64" json:"timezone,omitempty"`
+}
+
+// Proveedores de login social
+const ProviderGoogle = "google"
+
+// PhoneVerified indica si el teléfono actual está verificado
+func (u *User) PhoneVerified() bool {
+	return u.Phone != "" && u.PhoneVerifiedAt != nil
+}
+
+// SetPhone cambia el teléfono; si es otro, deja de estar verificado
+func (u *User) SetPhone(phone string) {
+	if phone != u.Phone {
+		u.Phone = phone
+		u.PhoneVerifiedAt = nil
+	}
+}
+
+// HasPassword indica si el usuario puede entrar con contraseña
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// Active indica si la cuenta puede iniciar sesión
+func (u *User) Active() bool {
+	return u.DeactivatedAt == nil
+}
+
+// Banned indica si la cuenta está suspendida en el momento now
+func (u *User) Banned(now time.Time) bool {
+	return u.BannedUntil != nil && now.Before(*u.BannedUntil)
 }
 
 // TableName especifica el nombre de la tabla en MySQL
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Eventos de usuarios que se pueden recibir por webhook
+const (
+	WebhookUserCreated = "user.created"
+	WebhookUserUpdated = "user.updated"
+	WebhookUserDeleted = "user.deleted"
+)
+
+// WebhookEvents son todos los eventos a los que se puede suscribir un webhook
+var WebhookEvents = []string{WebhookUserCreated, WebhookUserUpdated, WebhookUserDeleted}
+
+// Webhook es una URL de un sistema externo que recibe los eventos de
+// usuarios (POST /admin/webhooks)
+// Cada envío va firmado con Secret (HMAC-SHA256) para que el receptor
+// compruebe que viene de users-api; el secret solo se muestra al crearlo
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	URL       string    `gorm:"size:2048;not null" json:"url"`
+	Secret    string    `gorm:"size:128;not null" json:"-"`
+	Events    []string  `gorm:"serializer:json;type:text" json:"events"`
+	CreatedBy uint      `gorm:"not null" json:"created_by"` // admin que lo registró
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribed indica si el webhook recibe el evento
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Estados de una entrega de webhook
+const (
+	DeliveryPending   = "pending"   // esperando el primer intento o un reintento
+	DeliveryDelivered = "delivered" // el receptor respondió 2xx
+	DeliveryFailed    = "failed"    // se agotaron los reintentos
+)
+
+// WebhookDelivery es un evento a entregar a un webhook y el resultado de
+// los intentos (GET /admin/webhooks/:id/deliveries)
+// Se guarda en la misma transacción que el cambio del usuario, como el
+// outbox: si el cambio se revierte, no se avisa de nada
+type WebhookDelivery struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	WebhookID uint            `gorm:"not null;index" json:"webhook_id"`
+	Webhook   *Webhook        `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	EventID   string          `gorm:"size:32;not null;uniqueIndex" json:"event_id"` // estable entre reintentos, para deduplicar
+	Event     string          `gorm:"size:100;not null" json:"event"`
+	Payload   json.RawMessage `gorm:"type:text;not null" json:"payload"` // el body tal cual se firma y se manda
+
+	Status         string     `gorm:"size:20;not null;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	NextAttemptAt  time.Time  `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"` // status HTTP del último intento
+	LastError      string     `gorm:"size:500" json:"last_error,omitempty"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookPayload es el body JSON que recibe el webhook
+//
+//	{"id": "9f2c...", "event": "user.updated", "created_at": "2026-05-04T12:00:00Z",
+//	 "data": {"user": {"id": 42, "username": "ana", ...}, "changed_fields": ["email"]}}
+//
+// En user.deleted, user son los datos que tenía la cuenta antes de borrarse
+type WebhookPayload struct {
+	ID        string             `json:"id"`
+	Event     string             `json:"event"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      WebhookPayloadData `json:"data"`
+}
+
+// WebhookPayloadData es el usuario del evento y, en user.updated, qué cambió
+type WebhookPayloadData struct {
+	User          *WebhookUser `json:"user"`
+	ChangedFields []string     `json:"changed_fields,omitempty"`
+}
+
+// WebhookUser es el usuario que reciben los webhooks
+// Los campos se eligen uno por uno (no es el modelo de la tabla): el
+// teléfono, la suspensión, la fusión, la cuenta externa o una columna nueva
+// no salen hacia un sistema externo sin decidirlo
+type WebhookUser struct {
+	ID            uint       `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	UserType      UserType   `json:"user_type"`
+	Locale        string     `json:"locale,omitempty"`
+	Timezone      string     `json:"timezone,omitempty"`
+	AvatarURL     string     `json:"avatar_url,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+}
+
+// NewWebhookUser arma el usuario del payload a partir del de la base
+func NewWebhookUser(user *User) *WebhookUser {
+	return &WebhookUser{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		UserType:      user.UserType,
+		Locale:        user.Locale,
+		Timezone:      user.Timezone,
+		AvatarURL:     user.AvatarURL,
+		CreatedAt:     user.CreatedAt,
+		DeactivatedAt: user.DeactivatedAt,
+	}
+}
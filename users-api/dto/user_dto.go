@@ -1,6 +1,11 @@
 package dto
 
-import "users-api/domain"
+import (
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+)
 
 // CreateUserRequest representa el request para crear un usuario
 // Esto es lo que el frontend te envía cuando alguien se registra
@@ -10,6 +15,11 @@ type CreateUserRequest struct {
 	Password  string `json:"password" binding:"required,min=6"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
+	Phone     string `json:"phone,omitempty"` // opcional, internacional (se normaliza a E.164, sin verificar)
+
+	// InvitationCode es el código de POST /admin/invitations; obligatorio con
+	// REGISTRATION_MODE=invite_only. El email tiene que ser el invitado
+	InvitationCode string `json:"invitation_code,omitempty"`
 }
 
 // LoginRequest representa el request para login
@@ -19,6 +29,45 @@ type LoginRequest struct {
 	Password        string `json:"password" binding:"required"`
 }
 
+// MagicLinkRequest pide un link de login por email (POST /users/login/magic-link)
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyMagicLinkRequest canjea el token del link por un JWT
+// El frontend lo manda por POST: un GET lo consumirían los antivirus y
+// previews de links de los clientes de email antes que el usuario
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// GoogleLoginRequest canjea el ID token de Google Sign-In por un JWT
+// (POST /users/login/google)
+type GoogleLoginRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+}
+
+// OIDCLoginRequest entra con un proveedor OIDC (POST /users/login/oidc/:provider)
+// Va el code que devolvió el proveedor al redirect_uri y el backend lo canjea
+// con el client secret. No se acepta un id_token suelto: sin un nonce atado a
+// la sesión, cualquiera que capture uno podría reusarlo hasta que venza
+type OIDCLoginRequest struct {
+	Code         string `json:"code" binding:"required"`
+	RedirectURI  string `json:"redirect_uri" binding:"required,url"`
+	CodeVerifier string `json:"code_verifier"` // PKCE, si se usó
+	Nonce        string `json:"nonce"`         // el que mandó el frontend al autorizar; se compara con el del token
+}
+
+// OIDCProviderResponse es un proveedor OIDC habilitado (GET /users/login/oidc)
+// Con esto el frontend arma el botón y el redirect al proveedor
+type OIDCProviderResponse struct {
+	Name                  string `json:"name"`
+	DisplayName           string `json:"display_name"`
+	Issuer                string `json:"issuer"`
+	ClientID              string `json:"client_id"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+}
+
 // UpdateUserRequest representa el request para actualizar un usuario
 // Todos los campos son opcionales
 type UpdateUserRequest struct {
@@ -27,34 +76,310 @@ type UpdateUserRequest struct {
 	Password  string `json:"password,omitempty" binding:"omitempty,min=6"`
 	FirstName string `json:"first_name,omitempty"`
 	LastName  string `json:"last_name,omitempty"`
+	Phone     string `json:"phone,omitempty"` // si cambia, deja de estar verificado
+
+	// Locale BCP 47 y zona horaria IANA; se validan y van en el JWT desde el
+	// próximo login
+	Locale   string `json:"locale,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// UpdateMeRequest representa el request del propio usuario (PUT /users/me)
+// No incluye el teléfono (va por PUT /users/me/phone, con código por SMS) y
+// para cambiar la contraseña o el email pide la actual
+type UpdateMeRequest struct {
+	Username        string `json:"username,omitempty"`
+	Email           string `json:"email,omitempty" binding:"omitempty,email"`
+	FirstName       string `json:"first_name,omitempty"`
+	LastName        string `json:"last_name,omitempty"`
+	Password        string `json:"password,omitempty" binding:"omitempty,min=6"`
+	CurrentPassword string `json:"current_password,omitempty"` // obligatoria si cambia la contraseña o el email
+	Locale          string `json:"locale,omitempty"`           // BCP 47, ej: "es-AR"
+	Timezone        string `json:"timezone,omitempty"`         // IANA, ej: "America/Argentina/Cordoba"
+}
+
+// UpdatePhoneRequest cambia el teléfono propio y manda el código por SMS
+// (PUT /users/me/phone)
+type UpdatePhoneRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// VerifyPhoneRequest confirma el código recibido por SMS
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
 }
 
 // LoginResponse representa la respuesta del login
 // Devuelves el token JWT y los datos del usuario
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  domain.User `json:"user"`
+	Token string       `json:"token"`
+	User  UserResponse `json:"user"`
 }
 
-// UserResponse representa la respuesta con datos de usuario
-// (Opcional, si querés una respuesta más limpia sin algunos campos)
+// ImpersonationResponse es la respuesta de POST /admin/users/:id/impersonate
+// El token actúa como el usuario y vence en ExpiresAt (no se renueva)
+type ImpersonationResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      UserResponse `json:"user"`
+}
+
+// BulkUsersRequest es el body de POST /admin/users/bulk
+// Los usuarios se eligen por IDs o por filtro (uno de los dos)
+// Ejemplo: {"action": "set_role", "role": "normal", "filter": {"user_type": "admin"}}
+type BulkUsersRequest struct {
+	Action string          `json:"action" binding:"required,oneof=deactivate activate delete set_role"`
+	Role   string          `json:"role" binding:"required_if=Action set_role,omitempty,oneof=normal admin"`
+	IDs    []uint          `json:"ids" binding:"omitempty,max=1000"`
+	Filter *BulkUserFilter `json:"filter"`
+}
+
+// BulkUserFilter elige los usuarios de una operación masiva
+// Tiene que tener al menos un criterio: un filtro vacío no selecciona a todos
+type BulkUserFilter struct {
+	UserType      string     `json:"user_type" binding:"omitempty,oneof=normal admin"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	Deactivated   *bool      `json:"deactivated"`
+}
+
+// ExportUsersQuery son los filtros de GET /admin/users/export
+// Ejemplo: ?user_type=normal&created_after=2026-01-01T00:00:00Z&deactivated=false
+type ExportUsersQuery struct {
+	UserType      string    `form:"user_type" binding:"omitempty,oneof=normal admin"`
+	CreatedAfter  time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Deactivated   *bool     `form:"deactivated"`
+}
+
+// UserStatsResponse es la respuesta de GET /admin/users/stats
+type UserStatsResponse struct {
+	Total       int64            `json:"total"`
+	ByType      map[string]int64 `json:"by_type"`     // ej: {"normal": 120, "admin": 3}
+	Active      int64            `json:"active"`      // cuentas no desactivadas
+	Deactivated int64            `json:"deactivated"` // ver POST /admin/users/bulk
+	// Usuarios con el teléfono verificado por SMS y el resto (sin teléfono o
+	// sin confirmar el código)
+	PhoneVerified   int64 `json:"phone_verified"`
+	PhoneUnverified int64 `json:"phone_unverified"`
+	// Usuarios que iniciaron sesión en los últimos 30 días
+	ActiveLast30Days int64 `json:"active_last_30_days"`
+	// Registros por día (los últimos days, incluido hoy) y por semana ISO
+	// (las últimas weeks, incluida la actual), con ceros donde no hubo
+	SignupsPerDay  []PeriodCount `json:"signups_per_day"`
+	SignupsPerWeek []PeriodCount `json:"signups_per_week"`
+}
+
+// PeriodCount es la cantidad de un período: día ("2026-03-01") o semana ISO ("2026-W09")
+type PeriodCount struct {
+	Period string `json:"period"`
+	Count  int64  `json:"count"`
+}
+
+// UserResponse es el usuario que ven los clientes en todas las respuestas
+// Se arma con NewUserResponse: así el contrato no cambia si cambia la tabla,
+// y nunca salen el hash de la contraseña, la cuenta externa ni la clave del avatar
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	UserType  string `json:"user_type"`
+	ID              uint       `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	UserType        string     `json:"user_type"`
+	Phone           string     `json:"phone,omitempty"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+	Provider        string     `json:"provider,omitempty"` // login social con el que entra ("google")
+	HasPassword     bool       `json:"has_password"`       // false = PUT /users/me no pide current_password
+	AvatarURL       string     `json:"avatar_url,omitempty"`
+	AvatarThumbURL  string     `json:"avatar_thumb_url,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+	Timezone        string     `json:"timezone,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"`
+	BannedUntil     *time.Time `json:"banned_until,omitempty"`
+	MergedInto      *uint      `json:"merged_into,omitempty"`
 }
 
-// ErrorResponse representa una respuesta de error
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+// NewUserResponse arma la respuesta a partir del usuario de la base
+func NewUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		UserType:        string(user.UserType),
+		Phone:           user.Phone,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+		Provider:        user.Provider,
+		HasPassword:     user.HasPassword(),
+		AvatarURL:       user.AvatarURL,
+		AvatarThumbURL:  user.AvatarThumbURL,
+		Locale:          user.Locale,
+		Timezone:        user.Timezone,
+		CreatedAt:       user.CreatedAt,
+		LastLoginAt:     user.LastLoginAt,
+		DeactivatedAt:   user.DeactivatedAt,
+		BannedUntil:     user.BannedUntil,
+		MergedInto:      user.MergedInto,
+	}
+}
+
+// NewUserResponses arma la respuesta de un listado (nunca null: [] si no hay usuarios)
+func NewUserResponses(users []domain.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, NewUserResponse(&users[i]))
+	}
+	return responses
 }
 
+// PublicUserResponse es el perfil que ve cualquiera en GET /users/:id
+// Solo lleva lo que se muestra de otro usuario: el email, el teléfono y el
+// estado de la cuenta quedan para /users/me y las rutas de admin
+type PublicUserResponse struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
+}
+
+// NewPublicUserResponse arma el perfil público a partir del usuario de la base
+func NewPublicUserResponse(user *domain.User) PublicUserResponse {
+	return PublicUserResponse{
+		ID:             user.ID,
+		Username:       user.Username,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		AvatarURL:      user.AvatarURL,
+		AvatarThumbURL: user.AvatarThumbURL,
+	}
+}
+
+// ErrorResponse representa una respuesta de error
+// Es el sobre común de shared/apperrors: {"code": "<código>", "message": "...", "details": ..., "request_id": "..."}
+type ErrorResponse = apperrors.Response
+
 // SuccessResponse representa una respuesta exitosa
 type SuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+// UpdatePreferencesRequest representa el request para cambiar las preferencias de notificación
+// Son punteros para distinguir "no enviado" de "false"
+type UpdatePreferencesRequest struct {
+	EmailTransactional *bool `json:"email_transactional,omitempty"`
+	EmailMarketing     *bool `json:"email_marketing,omitempty"`
+}
+
+// SecurityOverviewResponse es la respuesta de GET /users/me/security
+type SecurityOverviewResponse struct {
+	Events  []domain.SecurityEvent `json:"events"`
+	Devices []domain.KnownDevice   `json:"devices"`
+}
+
+// PageQuery es la paginación de los listados (?page=2&limit=50)
+// Los ceros los reemplaza el servicio por sus valores por defecto
+type PageQuery struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// LoginHistoryResponse es la respuesta de GET /users/me/logins y
+// GET /admin/users/:id/logins
+type LoginHistoryResponse struct {
+	Logins []domain.LoginEvent `json:"logins"`
+	Total  int64               `json:"total"`
+	Page   int                 `json:"page"`
+	Limit  int                 `json:"limit"`
+}
+
+// MergeUsersRequest es el body de POST /admin/users/:id/merge
+// La cuenta de la URL es la que queda; DuplicateID se fusiona en ella
+type MergeUsersRequest struct {
+	DuplicateID uint `json:"duplicate_id" binding:"required"`
+}
+
+// MergeUsersResponse es la respuesta de POST /admin/users/:id/merge
+type MergeUsersResponse struct {
+	User        UserResponse                   `json:"user"` // cuenta que queda, ya con los datos movidos
+	Duplicate   UserResponse                   `json:"duplicate"`
+	Preferences domain.NotificationPreferences `json:"preferences"`
+	PhoneMoved  bool                           `json:"phone_moved"`
+	EventID     string                         `json:"event_id"` // user.merged (para seguir la reasignación en los otros servicios)
+}
+
+// BanUserRequest es el body de POST /admin/users/:id/ban
+// La suspensión siempre tiene fin; para una baja definitiva se desactiva la cuenta
+type BanUserRequest struct {
+	BannedUntil time.Time `json:"banned_until" binding:"required"`
+}
+
+// CreateInvitationRequest es el body de POST /admin/invitations
+// Sin user_type el invitado se registra como normal; sin expires_in_hours
+// vale INVITATION_TTL
+type CreateInvitationRequest struct {
+	Email          string `json:"email" binding:"required,email"`
+	UserType       string `json:"user_type" binding:"omitempty,oneof=normal admin"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// InvitationResponse es la respuesta de POST /admin/invitations
+// El código solo se ve acá (en la base queda su hash): el admin lo puede
+// pasar a mano si el email no llega
+type InvitationResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	UserType  string    `json:"user_type"`
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AuditLogQuery son los filtros de GET /admin/audit-logs
+// Ejemplo: ?target_id=42&action=update&from=2026-01-01T00:00:00Z&page=2
+type AuditLogQuery struct {
+	ActorID  uint      `form:"actor_id"`
+	TargetID uint      `form:"target_id"`
+	Action   string    `form:"action" binding:"omitempty,oneof=create update delete"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page     int       `form:"page" binding:"omitempty,min=1"`
+	Limit    int       `form:"limit" binding:"omitempty,min=1,max=200"` // default 50
+}
+
+// AuditLogsResponse es la respuesta de GET /admin/audit-logs
+type AuditLogsResponse struct {
+	Logs  []domain.AuditLog `json:"logs"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+}
+
+// CreateWebhookRequest es el body de POST /admin/webhooks
+// Sin secret se genera uno; en los dos casos solo se devuelve en la respuesta
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=user.created user.updated user.deleted"`
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=128"`
+}
+
+// WebhookResponse es la respuesta de POST /admin/webhooks
+// Secret es con lo que se firman los envíos (X-Spotly-Signature); después
+// no se vuelve a mostrar
+type WebhookResponse struct {
+	domain.Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveriesResponse es la respuesta de GET /admin/webhooks/:id/deliveries
+type WebhookDeliveriesResponse struct {
+	Deliveries []domain.WebhookDelivery `json:"deliveries"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"users-api/domain"
+)
+
+// Test: la respuesta no lleva el hash, la cuenta externa, la clave del avatar
+// ni updated_at, y un listado vacío sale como []
+func TestNewUserResponse(t *testing.T) {
+	subject := "google-123"
+	user := &domain.User{
+		ID: 7, Username: "ana", Email: "ana@example.com", Password: "$2a$10$hash",
+		UserType: domain.UserTypeAdmin, Provider: domain.ProviderGoogle, ProviderID: &subject,
+		AvatarKey: "avatars/7/ab12.jpg", AvatarURL: "https://cdn.example.com/avatars/7/ab12.jpg",
+	}
+
+	body, err := json.Marshal(NewUserResponse(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"$2a$10$hash", subject, `"avatars/7/ab12.jpg"`, "updated_at"} {
+		if strings.Contains(string(body), leaked) {
+			t.Errorf("Expected %s not to be in %s", leaked, body)
+		}
+	}
+	if !strings.Contains(string(body), `"user_type":"admin"`) || !strings.Contains(string(body), `"has_password":true`) {
+		t.Errorf("Unexpected response %s", body)
+	}
+
+	list, _ := json.Marshal(NewUserResponses(nil))
+	if string(list) != "[]" {
+		t.Errorf("Expected [], got %s", list)
+	}
+}
+
+// Test: el perfil público (GET /users/:id, sin token) no lleva email,
+// teléfono ni el estado de la cuenta
+func TestNewPublicUserResponse(t *testing.T) {
+	subject := "google-123"
+	user := &domain.User{
+		ID: 7, Username: "ana", Email: "ana@example.com", FirstName: "Ana", LastName: "García",
+		Phone: "+5493515550000", UserType: domain.UserTypeAdmin, Provider: domain.ProviderGoogle, ProviderID: &subject,
+		AvatarURL: "https://cdn.example.com/avatars/7/ab12.jpg",
+	}
+
+	body, err := json.Marshal(NewPublicUserResponse(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"ana@example.com", "+5493515550000", "user_type", "provider", "has_password", "last_login_at"} {
+		if strings.Contains(string(body), leaked) {
+			t.Errorf("Expected %s not to be in %s", leaked, body)
+		}
+	}
+	if !strings.Contains(string(body), `"username":"ana"`) || !strings.Contains(string(body), `"avatar_url":"https://cdn.example.com/avatars/7/ab12.jpg"`) {
+		t.Errorf("Unexpected response %s", body)
+	}
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"users-api/services"
+
+	"shared/scheduler"
+)
+
+// Register agrega al scheduler los jobs recurrentes de users-api
+// Cada job corre en una sola instancia gracias al lock en MySQL
+func Register(s *scheduler.Scheduler, securityService services.SecurityService, magicLinks services.MagicLinkService, outbox services.OutboxService, tokens services.TokenService, webhooks services.WebhookService, securityEventsRetention time.Duration) error {
+	// Todos los días a las 03:00: borrar eventos de seguridad e historial de logins viejos
+	err := s.Register("purge_security_events", "0 3 * * *", func(ctx context.Context) error {
+		deleted, err := securityService.PurgeOldEvents(ctx, securityEventsRetention)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d eventos de seguridad y logins borrados (retención %s)", deleted, securityEventsRetention)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Todos los días a las 03:30: borrar magic links vencidos
+	err = s.Register("purge_magic_links", "30 3 * * *", func(ctx context.Context) error {
+		deleted, err := magicLinks.PurgeExpired(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d magic links vencidos borrados", deleted)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Todos los días a las 03:45: borrar tokens revocados que ya vencieron
+	err = s.Register("purge_revoked_tokens", "45 3 * * *", func(ctx context.Context) error {
+		deleted, err := tokens.PurgeExpired(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d revocaciones de tokens vencidas borradas", deleted)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cada minuto: publicar los eventos pendientes del outbox
+	err = s.Register("relay_outbox", "* * * * *", func(ctx context.Context) error {
+		_, err := outbox.Relay(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Todos los días a las 04:00: borrar del outbox lo publicado hace más de una semana
+	err = s.Register("purge_outbox", "0 4 * * *", func(ctx context.Context) error {
+		deleted, err := outbox.PurgePublished(ctx, outboxRetention)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d eventos publicados borrados del outbox", deleted)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cada minuto: mandar las entregas de webhooks pendientes (y los reintentos que ya tocan)
+	err = s.Register("deliver_webhooks", "* * * * *", func(ctx context.Context) error {
+		_, err := webhooks.Deliver(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Todos los días a las 04:15: borrar las entregas de webhooks terminadas hace más de un mes
+	return s.Register("purge_webhook_deliveries", "15 4 * * *", func(ctx context.Context) error {
+		deleted, err := webhooks.PurgeDeliveries(ctx, webhookDeliveriesRetention)
+		if err != nil {
+			return err
+		}
+		log.Printf("🧹 %d entregas de webhooks borradas", deleted)
+		return nil
+	})
+}
+
+// outboxRetention es cuánto se guardan los eventos ya publicados del outbox
+const outboxRetention = 7 * 24 * time.Hour
+
+// webhookDeliveriesRetention es cuánto se guarda el historial de entregas de webhooks
+const webhookDeliveriesRetention = 30 * 24 * time.Hour
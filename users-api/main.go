@@ -1,145 +1,122 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"os"
-	"users-api/controllers"
-	"users-api/domain"
-	"users-api/middleware"
-	"users-api/repositories"
-	"users-api/services"
-
-	"github.com/gin-gonic/gin"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
+	"strings"
+	"users-api/app"
+	"users-api/utils"
+
+	"shared/config"
+	"shared/diagnostics"
+	"shared/graceful"
+	"shared/httpmw"
+	"shared/logging"
+	"shared/secrets"
 )
 
 func main() {
 	// ============================================
-	// 1. CONFIGURACIÓN - Leer variables de entorno
-	// ============================================
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "3306")
-	dbUser := getEnv("DB_USER", "spotly_user")
-	dbPassword := getEnv("DB_PASSWORD", "spotly_password")
-	dbName := getEnv("DB_NAME", "users_db")
-
-	log.Println("🔧 Configuración cargada:")
-	log.Printf("   - DB Host: %s:%s", dbHost, dbPort)
-	log.Printf("   - DB Name: %s", dbName)
-
-	// ============================================
-	// 2. CONECTAR A MYSQL
+	// 1. CONFIGURACIÓN - Leer variables de entorno (y .env si existe)
 	// ============================================
-	// DSN = Data Source Name (string de conexión)
-	// Formato: usuario:password@tcp(host:puerto)/base_de_datos?opciones
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
-
-	log.Println("📡 Conectando a MySQL...")
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	env, err := config.Load(".env")
 	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
+		log.Fatal("❌ Failed to read .env:", err)
 	}
-	log.Println("✅ Conexión a MySQL exitosa")
 
-	// ============================================
-	// 3. AUTO-MIGRAR LAS TABLAS
-	// ============================================
-	// GORM crea automáticamente la tabla "users" si no existe
-	log.Println("🔄 Ejecutando migraciones...")
-	err = db.AutoMigrate(&domain.User{})
+	// Credenciales desde el gestor de secretos (SECRETS_PROVIDER, ver shared/secrets)
+	// (también los client secrets de los proveedores OIDC de OIDC_PROVIDERS)
+	secretKeys := append([]string{"DB_PASSWORD", "JWT_SECRET", "RABBITMQ_URL",
+		"PASSWORD_PEPPER", "PASSWORD_PEPPER_PREVIOUS", "ADMIN_PASSWORD", "INTERNAL_API_TOKEN"}, app.OIDCSecretKeys(env)...)
+	secretStore, err := secrets.Open(context.Background(), env, secretKeys...)
 	if err != nil {
-		log.Fatal("❌ Failed to migrate database:", err)
+		log.Fatal("❌ Failed to load secrets:", err)
 	}
-	log.Println("✅ Tablas creadas/actualizadas")
-
-	// ============================================
-	// 4. INICIALIZAR CAPAS (Patrón MVC)
-	// ============================================
-	log.Println("🏗️  Inicializando capas...")
-
-	// Repository: acceso a datos
-	userRepo := repositories.NewUserRepository(db)
+	cfg := app.ConfigFromEnv(env)
 
-	// Service: lógica de negocio
-	userService := services.NewUserService(userRepo)
+	// Si algo está mal se muestran todos los errores juntos
+	if err := env.Err(); err != nil {
+		log.Fatal("❌ ", err)
+	}
 
-	// Controller: maneja HTTP
-	userController := controllers.NewUserController(userService)
+	// LOG_LEVEL; se cambia en caliente con PUT /admin/loglevel o SIGUSR1
+	logging.Install(cfg.LogLevel)
 
-	log.Println("✅ Capas inicializadas")
+	log.Println("🔧 Configuración cargada:")
+	log.Printf("   - DB Host: %s:%s", cfg.Database.Host, cfg.Database.Port)
+	log.Printf("   - DB Name: %s", cfg.Database.Name)
+	log.Printf("   - CORS: %s", strings.Join(cfg.CORSOrigins, ", "))
+	log.Printf("   - Contraseñas: %s (bcrypt cost %d)", cfg.PasswordHasher, cfg.BcryptCost)
+	for _, provider := range cfg.OIDCProviders {
+		log.Printf("   - Login OIDC: %s (%s)", provider.Name, provider.Issuer)
+	}
+	log.Printf("   - Feature flags: %s (recarga cada %s)", cfg.FlagsFile, cfg.FlagsRefresh)
 
 	// ============================================
-	// 5. CONFIGURAR GIN (Framework web)
+	// 2. CONECTAR A MYSQL, RABBITMQ Y FEATURE FLAGS
 	// ============================================
-	// Gin es como Express en Node.js
-	router := gin.Default()
-
-	// CORS - Permitir requests desde el frontend
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
+	infra, err := app.Open(cfg)
+	if err != nil {
+		log.Fatal("❌ Failed to initialize infrastructure:", err)
+	}
+	defer infra.Close()
 
 	// ============================================
-	// 6. DEFINIR RUTAS (Endpoints)
+	// 3. ARMAR LAS CAPAS (ver paquete app)
 	// ============================================
-	log.Println("🛣️  Configurando rutas...")
+	log.Println("🏗️  Inicializando capas...")
+	application := app.Build(cfg, infra)
+	defer application.Close()
+	log.Println("✅ Capas inicializadas y jobs programados")
 
-	// Rutas PÚBLICAS (sin autenticación)
-	router.GET("/health", userController.HealthCheck)
-	router.POST("/users", userController.CreateUser)     // Registro
-	router.POST("/users/login", userController.Login)    // Login
-	router.GET("/users/:id", userController.GetUserByID) // Obtener usuario
+	// Emails normalizados pendientes (usuarios anteriores a la columna o
+	// cambio de EMAIL_STRIP_PLUS_ADDRESSING); los que chocan quedan para fusionar
+	updated, conflicts, err := application.UserService.NormalizeEmails(context.Background())
+	if err != nil {
+		log.Println("❌ No se pudieron normalizar los emails:", err)
+	}
+	if updated > 0 {
+		log.Printf("📧 %d emails normalizados", updated)
+	}
+	if len(conflicts) > 0 {
+		log.Printf("⚠️  Usuarios con el mismo email normalizado que otra cuenta (fusionarlos): %v", conflicts)
+	}
 
-	// Rutas PROTEGIDAS (requieren JWT - solo admin)
-	// Importar middleware aquí si no está importado
-	admin := router.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
-	{
-		admin.GET("/users", userController.GetAllUsers)       // Listar todos
-		admin.PUT("/users/:id", userController.UpdateUser)    // Actualizar
-		admin.DELETE("/users/:id", userController.DeleteUser) // Eliminar
+	// Primer admin desde ADMIN_USERNAME / ADMIN_PASSWORD (solo si no hay ninguno)
+	// Si falla se avisa y el servicio arranca igual: el admin se puede crear
+	// después con go run ./cmd/admin create
+	if cfg.BootstrapAdmin.Username != "" {
+		admin, err := application.UserService.BootstrapAdmin(context.Background(), cfg.BootstrapAdmin)
+		switch {
+		case err != nil:
+			log.Println("❌ No se pudo crear el admin inicial:", err)
+		case admin != nil:
+			log.Printf("👑 Admin inicial creado: #%d %s <%s>", admin.ID, admin.Username, admin.Email)
+		default:
+			log.Println("ℹ️  Ya hay un admin: se ignora ADMIN_USERNAME")
+		}
 	}
 
-	log.Println("✅ Rutas configuradas:")
-	log.Println("   - GET  /health")
-	log.Println("   - POST /users (registro)")
-	log.Println("   - POST /users/login")
-	log.Println("   - GET  /users/:id")
-	log.Println("   - GET  /admin/users (admin)")
-	log.Println("   - PUT  /admin/users/:id (admin)")
-	log.Println("   - DELETE /admin/users/:id (admin)")
+	// El JWT_SECRET rota sin reiniciar; la base y RabbitMQ toman
+	// las credenciales nuevas en el próximo reinicio
+	secretStore.OnChange("JWT_SECRET", utils.SetJWTSecret)
+	go secretStore.Run(context.Background())
 
 	// ============================================
-	// 7. ARRANCAR EL SERVIDOR
+	// 4. ARRANCAR EL SERVIDOR
 	// ============================================
-	port := getEnv("SERVER_PORT", "8080")
-
 	log.Println("🚀 =======================================")
-	log.Printf("🚀 Users API corriendo en puerto %s", port)
+	log.Printf("🚀 Users API corriendo en puerto %s", cfg.Port)
 	log.Println("🚀 =======================================")
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("❌ Failed to start server:", err)
+	// pprof/expvar en un puerto interno (además de /debug/* con auth de admin)
+	if cfg.DebugAddr != "" {
+		diagnostics.Serve(cfg.DebugAddr)
 	}
-}
 
-// getEnv obtiene una variable de entorno o retorna un valor por defecto
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+	// SIGTERM/SIGUSR2 drenan las requests en curso antes de salir (ver shared/graceful)
+	srv := httpmw.NewServer(":"+cfg.Port, application.Handler, cfg.HTTP)
+	if err := graceful.Run(srv, cfg.DrainTimeout); err != nil {
+		log.Println("❌ Server stopped:", err)
 	}
-	return value
 }
@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"shared/rabbitmq"
+	"shared/requestid"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventsExchange es el exchange topic compartido por todos los servicios
+const EventsExchange = "spotly.events"
+
+// EventPublisher define la interfaz para publicar eventos de dominio
+// La routing key es el tipo de evento (ej: "user.security_alert")
+// Si el contexto trae un request ID viaja en el header X-Request-ID del mensaje
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType string, data map[string]interface{}) error
+}
+
+// event es el sobre JSON que esperan los consumidores (ej: notifications-api)
+type event struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+type eventIDKey struct{}
+
+// WithEventID fija el ID del próximo evento publicado con el contexto
+// Lo usa el outbox: un evento que se reintenta sale siempre con el mismo ID
+// y los consumidores lo deduplican
+func WithEventID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, eventIDKey{}, id)
+}
+
+// eventID devuelve el ID fijado con WithEventID o uno nuevo
+func eventID(ctx context.Context) string {
+	if id, ok := ctx.Value(eventIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return newEventID()
+}
+
+// rabbitMQPublisher publica en el exchange spotly.events
+// La reconexión y la confirmación del broker las resuelve shared/rabbitmq
+type rabbitMQPublisher struct {
+	publisher *rabbitmq.Publisher
+}
+
+// NewRabbitMQPublisher declara el exchange sobre la conexión
+// La conexión la abre y la cierra quien llama (ver app.Open)
+func NewRabbitMQPublisher(conn *rabbitmq.Connection) (EventPublisher, error) {
+	publisher, err := rabbitmq.NewPublisher(conn, EventsExchange)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rabbitMQPublisher{publisher: publisher}, nil
+}
+
+// Publish arma el sobre del evento y lo publica como mensaje persistente
+func (p *rabbitMQPublisher) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	body, err := json.Marshal(event{
+		ID:         eventID(ctx),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.publisher.Publish(ctx, eventType, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+	})
+}
+
+// newEventID genera un ID aleatorio para que los consumidores puedan deduplicar
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// noopPublisher descarta los eventos (cuando no hay RabbitMQ configurado)
+type noopPublisher struct{}
+
+// NewNoopPublisher crea un publisher que solo loguea
+// Se usa en desarrollo local cuando RABBITMQ_URL no está definido
+func NewNoopPublisher() EventPublisher {
+	return &noopPublisher{}
+}
+
+// Publish solo loguea el evento
+func (p *noopPublisher) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	requestid.Logf(ctx, "ℹ️  RabbitMQ no configurado, evento %s descartado", eventType)
+	return nil
+}
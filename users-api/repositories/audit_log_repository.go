@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository define el acceso al historial de cambios hechos por admins
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domain.AuditLog) error
+	List(ctx context.Context, filter AuditLogFilter, page, limit int) ([]domain.AuditLog, int64, error)
+}
+
+// AuditLogFilter son los criterios de GET /admin/audit-logs
+// Los campos vacíos no filtran
+type AuditLogFilter struct {
+	ActorID  uint
+	TargetID uint
+	Action   string
+	From     time.Time
+	To       time.Time
+}
+
+// auditLogRepository es la implementación con GORM
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository crea una nueva instancia del repositorio
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create guarda una entrada
+func (r *auditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// List devuelve una página de entradas (las más nuevas primero) y el total
+func (r *auditLogRepository) List(ctx context.Context, filter AuditLogFilter, page, limit int) ([]domain.AuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []domain.AuditLog{}
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	return entries, total, err
+}
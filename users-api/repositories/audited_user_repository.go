@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"users-api/domain"
+
+	"shared/auth"
+	"shared/httpmw"
+	"shared/requestid"
+)
+
+// auditedUserRepository guarda en audit_logs las altas, cambios y bajas de
+// usuarios hechas por un admin (el actor sale del JWT de la request, ver
+// httpmw.WithClaims). Así quedan registradas todas las rutas que tocan
+// usuarios, incluidas las operaciones masivas, sin que cada servicio se acuerde
+// El resto de las operaciones pasan directo al repositorio envuelto
+type auditedUserRepository struct {
+	UserRepository
+	logs AuditLogRepository
+}
+
+// NewAuditedUserRepository envuelve repo para registrar los cambios de admins
+func NewAuditedUserRepository(repo UserRepository, logs AuditLogRepository) UserRepository {
+	return &auditedUserRepository{UserRepository: repo, logs: logs}
+}
+
+// Create registra el alta con todos los campos del usuario nuevo
+func (r *auditedUserRepository) Create(ctx context.Context, user *domain.User) error {
+	if err := r.UserRepository.Create(ctx, user); err != nil {
+		return err
+	}
+	if claims, ok := adminActor(ctx); ok {
+		_, after := userChanges(nil, user)
+		r.record(ctx, claims, domain.AuditCreate, user.ID, nil, after)
+	}
+	return nil
+}
+
+// Update registra solo los campos que cambiaron
+// Los valores anteriores se leen de la base antes de guardar (una query más,
+// solo en requests de admins)
+func (r *auditedUserRepository) Update(ctx context.Context, user *domain.User) error {
+	claims, ok := adminActor(ctx)
+	if !ok {
+		return r.UserRepository.Update(ctx, user)
+	}
+
+	// Si no se pueden leer, igual se registra el cambio (con todos los campos)
+	old, _ := r.UserRepository.GetByID(ctx, user.ID)
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+
+	before, after := userChanges(old, user)
+	if len(after) > 0 {
+		r.record(ctx, claims, domain.AuditUpdate, user.ID, before, after)
+	}
+	return nil
+}
+
+// Delete registra la baja con los datos que tenía el usuario
+func (r *auditedUserRepository) Delete(ctx context.Context, id uint) error {
+	claims, ok := adminActor(ctx)
+	if !ok {
+		return r.UserRepository.Delete(ctx, id)
+	}
+
+	old, _ := r.UserRepository.GetByID(ctx, id)
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	before, _ := userChanges(old, nil)
+	r.record(ctx, claims, domain.AuditDelete, id, before, nil)
+	return nil
+}
+
+// record guarda la entrada; si falla queda en el log pero no rompe la
+// request (el cambio ya se hizo)
+func (r *auditedUserRepository) record(ctx context.Context, claims *auth.Claims, action string, targetID uint, before, after map[string]interface{}) {
+	entry := &domain.AuditLog{
+		ActorID:   claims.UserID,
+		ActorName: claims.Username,
+		Action:    action,
+		TargetID:  targetID,
+		RequestID: requestid.FromContext(ctx),
+	}
+	if before != nil {
+		entry.OldValues, _ = json.Marshal(before)
+	}
+	if after != nil {
+		entry.NewValues, _ = json.Marshal(after)
+	}
+
+	if err := r.logs.Create(ctx, entry); err != nil {
+		requestid.Logf(ctx, "❌ No se pudo guardar el audit log (%s del usuario %d por %d): %v", action, targetID, claims.UserID, err)
+	}
+}
+
+// adminActor devuelve los claims si la request la hizo un admin
+func adminActor(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := httpmw.ClaimsFromContext(ctx)
+	if !ok || claims.UserType != string(domain.UserTypeAdmin) {
+		return nil, false
+	}
+	return claims, true
+}
+
+// ignoredFields no se registran: cambian solos en cada guardado
+var ignoredFields = map[string]bool{"updated_at": true}
+
+// userChanges compara los dos usuarios por su forma JSON y devuelve los
+// campos distintos (antes y después). Con old o user en nil devuelve todos
+// los campos del otro. La contraseña nunca se guarda: si cambió figura como
+// "[redacted]" de los dos lados
+func userChanges(old, user *domain.User) (before, after map[string]interface{}) {
+	oldFields, newFields := userFields(old), userFields(user)
+	if old == nil || user == nil {
+		return oldFields, newFields
+	}
+
+	before, after = map[string]interface{}{}, map[string]interface{}{}
+	for key, value := range newFields {
+		if ignoredFields[key] || reflect.DeepEqual(oldFields[key], value) {
+			continue
+		}
+		before[key], after[key] = oldFields[key], value
+	}
+	for key, value := range oldFields {
+		if _, ok := newFields[key]; !ok && !ignoredFields[key] {
+			before[key], after[key] = value, nil
+		}
+	}
+	if old.Password != user.Password {
+		before["password"], after["password"] = "[redacted]", "[redacted]"
+	}
+	return before, after
+}
+
+// userFields pasa el usuario a un mapa con los nombres de su JSON
+func userFields(user *domain.User) map[string]interface{} {
+	if user == nil {
+		return nil
+	}
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	json.Unmarshal(body, &fields)
+	return fields
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"users-api/domain"
+
+	"shared/auth"
+	"shared/httpmw"
+)
+
+// memoryUserRepository guarda copias, como la base: lo que devuelve GetByID
+// no cambia hasta el próximo Update
+type memoryUserRepository struct {
+	UserRepository
+	users map[uint]domain.User
+}
+
+func (m *memoryUserRepository) Create(ctx context.Context, user *domain.User) error {
+	user.ID = uint(len(m.users) + 1)
+	m.users[user.ID] = *user
+	return nil
+}
+
+func (m *memoryUserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (m *memoryUserRepository) Update(ctx context.Context, user *domain.User) error {
+	m.users[user.ID] = *user
+	return nil
+}
+
+func (m *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	delete(m.users, id)
+	return nil
+}
+
+type memoryAuditLogRepository struct {
+	AuditLogRepository
+	entries []domain.AuditLog
+}
+
+func (m *memoryAuditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+// Test: solo los cambios de admins quedan registrados, con los campos que cambiaron
+func TestAuditedUserRepository(t *testing.T) {
+	logs := &memoryAuditLogRepository{}
+	repo := NewAuditedUserRepository(&memoryUserRepository{users: map[uint]domain.User{}}, logs)
+	admin := httpmw.WithClaims(context.Background(), &auth.Claims{UserID: 7, Username: "root", UserType: "admin"})
+	self := httpmw.WithClaims(context.Background(), &auth.Claims{UserID: 1, Username: "ana", UserType: "normal"})
+
+	user := &domain.User{Username: "ana", Email: "ana@example.com", Password: "hash1", UserType: domain.UserTypeNormal}
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// El propio usuario cambia su nombre: no se registra
+	user.FirstName = "Ana"
+	if err := repo.Update(self, user); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(logs.entries) != 0 {
+		t.Fatalf("Expected no entries for non-admin changes, got %d", len(logs.entries))
+	}
+
+	// Un admin la promueve y le cambia la contraseña
+	user.UserType = domain.UserTypeAdmin
+	user.Password = "hash2"
+	if err := repo.Update(admin, user); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := repo.Delete(admin, user.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(logs.entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(logs.entries))
+	}
+	update := logs.entries[0]
+	if update.Action != domain.AuditUpdate || update.ActorID != 7 || update.TargetID != user.ID {
+		t.Errorf("Unexpected update entry: %+v", update)
+	}
+	var before, after map[string]interface{}
+	json.Unmarshal(update.OldValues, &before)
+	json.Unmarshal(update.NewValues, &after)
+	if len(after) != 2 || before["user_type"] != "normal" || after["user_type"] != "admin" || after["password"] != "[redacted]" {
+		t.Errorf("Expected only user_type and password, got %v -> %v", before, after)
+	}
+
+	deleted := logs.entries[1]
+	if deleted.Action != domain.AuditDelete || deleted.NewValues != nil {
+		t.Errorf("Unexpected delete entry: %+v", deleted)
+	}
+	json.Unmarshal(deleted.OldValues, &before)
+	if before["email"] != "ana@example.com" {
+		t.Errorf("Expected the deleted user's data, got %v", before)
+	}
+}
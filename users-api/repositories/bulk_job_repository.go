@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// BulkJobRepository guarda el estado de las operaciones masivas sobre usuarios
+type BulkJobRepository interface {
+	Create(ctx context.Context, job *domain.BulkJob) error
+	Update(ctx context.Context, job *domain.BulkJob) error
+	GetByID(ctx context.Context, id uint) (*domain.BulkJob, error)
+}
+
+// bulkJobRepository es la implementación con GORM
+type bulkJobRepository struct {
+	db *gorm.DB
+}
+
+// NewBulkJobRepository crea una nueva instancia del repositorio
+func NewBulkJobRepository(db *gorm.DB) BulkJobRepository {
+	return &bulkJobRepository{db: db}
+}
+
+// Create guarda un job nuevo
+func (r *bulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Update guarda el progreso del job
+func (r *bulkJobRepository) Update(ctx context.Context, job *domain.BulkJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// GetByID busca un job por su ID
+func (r *bulkJobRepository) GetByID(ctx context.Context, id uint) (*domain.BulkJob, error) {
+	var job domain.BulkJob
+	err := r.db.WithContext(ctx).First(&job, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("bulk job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// InvitationRepository define el acceso a las invitaciones de registro
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	GetByCodeHash(ctx context.Context, codeHash string) (*domain.Invitation, error)
+	MarkUsed(ctx context.Context, id, userID uint, now time.Time) error
+}
+
+// invitationRepository es la implementación con GORM
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository crea una nueva instancia del repositorio
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+// Create guarda una invitación nueva
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+// GetByCodeHash busca la invitación de un código (usada o no)
+func (r *invitationRepository) GetByCodeHash(ctx context.Context, codeHash string) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.db.WithContext(ctx).First(&invitation, "code_hash = ?", codeHash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NotFound("invitation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// MarkUsed marca la invitación como usada por userID
+// El UPDATE con "used_at IS NULL" es atómico: si dos registros usan el mismo
+// código a la vez, solo uno lo consume y el otro recibe un Conflict
+func (r *invitationRepository) MarkUsed(ctx context.Context, id, userID uint, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&domain.Invitation{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Updates(map[string]interface{}{"used_at": now, "used_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.Conflict("invitation code was already used")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// MagicLinkRepository define el acceso a los links de login por email
+type MagicLinkRepository interface {
+	Create(ctx context.Context, token *domain.MagicLinkToken) error
+	Consume(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error)
+	DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// magicLinkRepository es la implementación con GORM
+type magicLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewMagicLinkRepository crea una nueva instancia del repositorio
+func NewMagicLinkRepository(db *gorm.DB) MagicLinkRepository {
+	return &magicLinkRepository{db: db}
+}
+
+// Create guarda un link nuevo
+func (r *magicLinkRepository) Create(ctx context.Context, token *domain.MagicLinkToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// Consume marca el link como usado y lo devuelve
+// El UPDATE con "used_at IS NULL" es atómico: si dos requests usan el mismo
+// link a la vez, solo una lo consume
+func (r *magicLinkRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MagicLinkToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, apperrors.Unauthorized("invalid or expired login link")
+	}
+
+	var token domain.MagicLinkToken
+	if err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteExpiredBefore borra los links vencidos antes de la fecha dada
+// Devuelve cuántas filas se borraron
+func (r *magicLinkRepository) DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&domain.MagicLinkToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// MergePlan es el resultado de fusionar dos cuentas, listo para guardar
+// Lo arma MergeService; el repositorio solo lo escribe
+type MergePlan struct {
+	Survivor    *domain.User                    // cuenta que queda (con los datos movidos)
+	Duplicate   *domain.User                    // cuenta fusionada (desactivada, con MergedInto)
+	Preferences *domain.NotificationPreferences // preferencias combinadas de Survivor
+	Event       *domain.OutboxEvent             // user.merged para el resto de los servicios
+}
+
+// MergeRepository guarda una fusión de cuentas
+type MergeRepository interface {
+	Merge(ctx context.Context, plan MergePlan) error
+}
+
+// mergeRepository es la implementación con GORM
+type mergeRepository struct {
+	db *gorm.DB
+}
+
+// NewMergeRepository crea una nueva instancia del repositorio
+func NewMergeRepository(db *gorm.DB) MergeRepository {
+	return &mergeRepository{db: db}
+}
+
+// Merge escribe todo en una transacción: las dos cuentas, las preferencias
+// y el evento del outbox. Si algo falla no queda nada a medias ni se publica
+// el evento. Las cuentas se actualizan solo si siguen sin fusionar: dos
+// fusiones simultáneas de la misma cuenta no pueden pasar las dos
+func (r *mergeRepository) Merge(ctx context.Context, plan MergePlan) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, user := range []*domain.User{plan.Duplicate, plan.Survivor} {
+			result := tx.Model(user).Where("merged_into IS NULL").Select("*").Updates(user)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return apperrors.Conflict("account was merged by another request")
+			}
+		}
+		if err := tx.Save(plan.Preferences).Error; err != nil {
+			return err
+		}
+		return tx.Create(plan.Event).Error
+	})
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+	"users-api/domain"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository define el acceso a los eventos pendientes del outbox
+// Los eventos se insertan dentro de la transacción de cada cambio (ver
+// MergeRepository); acá solo se leen y se marcan como publicados
+type OutboxRepository interface {
+	ListPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id uint, at time.Time) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// outboxRepository es la implementación con GORM
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository crea una nueva instancia del repositorio
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// NewOutboxEvent arma un evento para insertar en el outbox con un ID nuevo
+func NewOutboxEvent(eventType string, data map[string]interface{}) *domain.OutboxEvent {
+	return &domain.OutboxEvent{EventID: newEventID(), Type: eventType, Data: data}
+}
+
+// newEventID genera el ID de un evento (32 caracteres hex)
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ListPending lista los eventos sin publicar, los más viejos primero
+func (r *outboxRepository) ListPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := r.db.WithContext(ctx).Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkPublished marca un evento como publicado
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.OutboxEvent{}).Where("id = ?", id).Update("published_at", at).Error
+}
+
+// DeletePublishedBefore borra los eventos publicados antes de la fecha dada
+// Devuelve cuántas filas se borraron
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("published_at < ?", before).Delete(&domain.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PhoneVerificationRepository guarda los códigos de verificación por SMS
+type PhoneVerificationRepository interface {
+	Replace(ctx context.Context, verification *domain.PhoneVerification) error
+	GetByUser(ctx context.Context, userID uint) (*domain.PhoneVerification, error)
+	IncrementAttempts(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// phoneVerificationRepository es la implementación con GORM
+type phoneVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPhoneVerificationRepository crea una nueva instancia del repositorio
+func NewPhoneVerificationRepository(db *gorm.DB) PhoneVerificationRepository {
+	return &phoneVerificationRepository{db: db}
+}
+
+// Replace guarda el código nuevo del usuario, pisando el anterior si había
+// (INSERT ... ON DUPLICATE KEY UPDATE sobre user_id)
+func (r *phoneVerificationRepository) Replace(ctx context.Context, verification *domain.PhoneVerification) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"phone", "code_hash", "attempts", "expires_at", "created_at"}),
+	}).Create(verification).Error
+}
+
+// GetByUser devuelve el código pendiente del usuario
+func (r *phoneVerificationRepository) GetByUser(ctx context.Context, userID uint) (*domain.PhoneVerification, error) {
+	var verification domain.PhoneVerification
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&verification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("no pending phone verification")
+		}
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// IncrementAttempts suma un intento fallido (en la base, para que dos
+// requests a la vez no se pisen el contador)
+func (r *phoneVerificationRepository) IncrementAttempts(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&domain.PhoneVerification{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// Delete borra el código (ya usado)
+func (r *phoneVerificationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.PhoneVerification{}, id).Error
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// PreferencesRepository define el acceso a las preferencias de notificación
+type PreferencesRepository interface {
+	GetByUserID(ctx context.Context, userID uint) (*domain.NotificationPreferences, error)
+	Save(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+// preferencesRepository es la implementación con GORM
+type preferencesRepository struct {
+	db *gorm.DB
+}
+
+// NewPreferencesRepository crea una nueva instancia del repositorio
+func NewPreferencesRepository(db *gorm.DB) PreferencesRepository {
+	return &preferencesRepository{db: db}
+}
+
+// GetByUserID busca las preferencias de un usuario
+// Si el usuario nunca las guardó devuelve "preferences not found"
+func (r *preferencesRepository) GetByUserID(ctx context.Context, userID uint) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	err := r.db.WithContext(ctx).First(&prefs, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("preferences not found")
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Save inserta o actualiza las preferencias (upsert por user_id)
+func (r *preferencesRepository) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	return r.db.WithContext(ctx).Save(prefs).Error
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// SecurityRepository define el acceso a dispositivos, redes, eventos de
+// seguridad e historial de logins
+type SecurityRepository interface {
+	GetDevice(ctx context.Context, userID uint, fingerprint string) (*domain.KnownDevice, error)
+	SaveDevice(ctx context.Context, device *domain.KnownDevice) error
+	ListDevices(ctx context.Context, userID uint) ([]domain.KnownDevice, error)
+	GetNetwork(ctx context.Context, userID uint, prefix string) (*domain.KnownNetwork, error)
+	SaveNetwork(ctx context.Context, network *domain.KnownNetwork) error
+	CountNetworks(ctx context.Context, userID uint) (int64, error)
+	CreateEvent(ctx context.Context, event *domain.SecurityEvent) error
+	ListEvents(ctx context.Context, userID uint, limit int) ([]domain.SecurityEvent, error)
+	DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error)
+	CreateLoginEvent(ctx context.Context, event *domain.LoginEvent) error
+	ListLoginEvents(ctx context.Context, userID uint, page, limit int) ([]domain.LoginEvent, int64, error)
+	DeleteLoginEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// securityRepository es la implementación con GORM
+type securityRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityRepository crea una nueva instancia del repositorio
+func NewSecurityRepository(db *gorm.DB) SecurityRepository {
+	return &securityRepository{db: db}
+}
+
+// GetDevice busca un dispositivo conocido del usuario por su fingerprint
+func (r *securityRepository) GetDevice(ctx context.Context, userID uint, fingerprint string) (*domain.KnownDevice, error) {
+	var device domain.KnownDevice
+	err := r.db.WithContext(ctx).Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("device not found")
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// SaveDevice inserta o actualiza un dispositivo
+func (r *securityRepository) SaveDevice(ctx context.Context, device *domain.KnownDevice) error {
+	return r.db.WithContext(ctx).Save(device).Error
+}
+
+// ListDevices lista los dispositivos del usuario, el último usado primero
+func (r *securityRepository) ListDevices(ctx context.Context, userID uint) ([]domain.KnownDevice, error) {
+	var devices []domain.KnownDevice
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+// GetNetwork busca una red conocida del usuario por su prefijo
+func (r *securityRepository) GetNetwork(ctx context.Context, userID uint, prefix string) (*domain.KnownNetwork, error) {
+	var network domain.KnownNetwork
+	err := r.db.WithContext(ctx).Where("user_id = ? AND prefix = ?", userID, prefix).First(&network).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("network not found")
+		}
+		return nil, err
+	}
+	return &network, nil
+}
+
+// SaveNetwork inserta o actualiza una red
+func (r *securityRepository) SaveNetwork(ctx context.Context, network *domain.KnownNetwork) error {
+	return r.db.WithContext(ctx).Save(network).Error
+}
+
+// CountNetworks cuenta cuántas redes conocidas tiene el usuario
+func (r *securityRepository) CountNetworks(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.KnownNetwork{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CreateEvent guarda un evento de seguridad
+func (r *securityRepository) CreateEvent(ctx context.Context, event *domain.SecurityEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// ListEvents lista los últimos eventos de seguridad del usuario
+func (r *securityRepository) ListEvents(ctx context.Context, userID uint, limit int) ([]domain.SecurityEvent, error) {
+	var events []domain.SecurityEvent
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// DeleteEventsBefore borra los eventos más viejos que la fecha dada
+// Devuelve cuántas filas se borraron
+func (r *securityRepository) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&domain.SecurityEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateLoginEvent guarda un intento de login; si fue exitoso actualiza
+// además users.last_login_at, en la misma transacción
+// (UpdateColumn no toca updated_at: loguearse no es editar el perfil)
+func (r *securityRepository) CreateLoginEvent(ctx context.Context, event *domain.LoginEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		if !event.Success {
+			return nil
+		}
+		return tx.Model(&domain.User{}).Where("id = ?", event.UserID).UpdateColumn("last_login_at", event.CreatedAt).Error
+	})
+}
+
+// ListLoginEvents devuelve una página del historial de logins del usuario
+// (lo más nuevo primero) y el total
+func (r *securityRepository) ListLoginEvents(ctx context.Context, userID uint, page, limit int) ([]domain.LoginEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.LoginEvent{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	events := []domain.LoginEvent{}
+	err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&events).Error
+	return events, total, err
+}
+
+// DeleteLoginEventsBefore borra el historial de logins más viejo que la fecha dada
+func (r *securityRepository) DeleteLoginEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&domain.LoginEvent{})
+	return result.RowsAffected, result.Error
+}
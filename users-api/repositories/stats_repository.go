@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+
+	"gorm.io/gorm"
+)
+
+// DayCount es la cantidad de algo en un día (YYYY-MM-DD)
+type DayCount struct {
+	Day   string
+	Count int64
+}
+
+// StatsRepository calcula las estadísticas de usuarios con queries de
+// agregación: nunca trae los usuarios a memoria
+type StatsRepository interface {
+	CountByType(ctx context.Context) (map[domain.UserType]int64, error)
+	CountDeactivated(ctx context.Context) (int64, error)
+	CountPhoneVerified(ctx context.Context) (int64, error)
+	SignupsPerDay(ctx context.Context, since time.Time) ([]DayCount, error)
+	CountActiveSince(ctx context.Context, since time.Time) (int64, error)
+}
+
+// statsRepository es la implementación con GORM
+type statsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository crea una nueva instancia del repositorio
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// CountByType cuenta los usuarios de cada tipo
+// SELECT user_type, COUNT(*) FROM users GROUP BY user_type
+func (r *statsRepository) CountByType(ctx context.Context) (map[domain.UserType]int64, error) {
+	var rows []struct {
+		UserType domain.UserType
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.User{}).
+		Select("user_type, COUNT(*) AS count").
+		Group("user_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.UserType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.UserType] = row.Count
+	}
+	return counts, nil
+}
+
+// CountDeactivated cuenta las cuentas desactivadas
+func (r *statsRepository) CountDeactivated(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("deactivated_at IS NOT NULL").Count(&count).Error
+	return count, err
+}
+
+// CountPhoneVerified cuenta los usuarios con el teléfono actual verificado por SMS
+// Es la única verificación que guarda users-api (ver User.PhoneVerified)
+func (r *statsRepository) CountPhoneVerified(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("phone <> '' AND phone_verified_at IS NOT NULL").
+		Count(&count).Error
+	return count, err
+}
+
+// SignupsPerDay cuenta los registros por día desde since (solo los días con registros)
+// SELECT DATE(created_at) AS day, COUNT(*) FROM users WHERE created_at >= ? GROUP BY day
+func (r *statsRepository) SignupsPerDay(ctx context.Context, since time.Time) ([]DayCount, error) {
+	var rows []struct {
+		Day   time.Time
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.User{}).
+		Select("DATE(created_at) AS day, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("day").
+		Order("day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]DayCount, len(rows))
+	for i, row := range rows {
+		days[i] = DayCount{Day: row.Day.Format(time.DateOnly), Count: row.Count}
+	}
+	return days, nil
+}
+
+// CountActiveSince cuenta los usuarios que iniciaron sesión desde since
+// Cada login actualiza last_seen_at del dispositivo (ver SecurityService.CheckLogin)
+func (r *statsRepository) CountActiveSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.KnownDevice{}).
+		Joins("JOIN users ON users.id = known_devices.user_id").
+		Where("known_devices.last_seen_at >= ?", since).
+		Distinct("known_devices.user_id").
+		Count(&count).Error
+	return count, err
+}
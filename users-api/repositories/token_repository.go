@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRepository define el acceso a los JWT revocados
+type TokenRepository interface {
+	Revoke(ctx context.Context, token *domain.RevokedToken) error
+	RevokeAll(ctx context.Context, userID uint, at time.Time) error
+	IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, tokensBefore, revocationsBefore time.Time) (int64, error)
+}
+
+// tokenRepository es la implementación con GORM
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository crea una nueva instancia del repositorio
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// Revoke guarda un token revocado; revocar dos veces el mismo no es error
+func (r *tokenRepository) Revoke(ctx context.Context, token *domain.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// RevokeAll invalida los tokens del usuario emitidos hasta at
+func (r *tokenRepository) RevokeAll(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at"}),
+	}).Create(&domain.TokenRevocation{UserID: userID, RevokedAt: at}).Error
+}
+
+// IsRevoked indica si el token fue revocado por su jti o por una revocación
+// de todos los tokens del usuario posterior a su emisión
+// jti vacío (tokens de antes del jti) solo se chequea por usuario
+func (r *tokenRepository) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	var count int64
+	if jti != "" {
+		if err := r.db.WithContext(ctx).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	// iat tiene precisión de segundos: un token del mismo segundo que la
+	// revocación también queda afuera (mejor de más que de menos)
+	err := r.db.WithContext(ctx).Model(&domain.TokenRevocation{}).
+		Where("user_id = ? AND revoked_at >= ?", userID, issuedAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired borra los tokens revocados que vencieron antes de tokensBefore
+// y las revocaciones por usuario anteriores a revocationsBefore
+// Devuelve cuántas filas se borraron en total
+func (r *tokenRepository) DeleteExpired(ctx context.Context, tokensBefore, revocationsBefore time.Time) (int64, error) {
+	tokens := r.db.WithContext(ctx).Where("expires_at < ?", tokensBefore).Delete(&domain.RevokedToken{})
+	if tokens.Error != nil {
+		return 0, tokens.Error
+	}
+	revocations := r.db.WithContext(ctx).Where("revoked_at < ?", revocationsBefore).Delete(&domain.TokenRevocation{})
+	return tokens.RowsAffected + revocations.RowsAffected, revocations.Error
+}
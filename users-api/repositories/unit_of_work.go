@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Tx son los repositorios de una transacción: todo lo que se escribe con
+// ellos se confirma junto o no se escribe
+type Tx struct {
+	Users       UserRepository
+	Preferences PreferencesRepository
+	Invitations InvitationRepository
+}
+
+// UnitOfWork corre escrituras en varias tablas en una sola transacción
+// Si fn devuelve un error (o hace panic) se deshace todo
+// Ejemplo: uow.Do(ctx, func(tx Tx) error { ...tx.Users.Create...; return tx.Preferences.Save(...) })
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// unitOfWork es la implementación con GORM
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork crea una nueva instancia
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Do abre la transacción y le pasa a fn repositorios que escriben en ella
+// Los cambios de usuarios hechos por admins se auditan y las entregas de
+// webhooks se encolan dentro de la misma transacción (ver
+// NewAuditedUserRepository y NewWebhookUserRepository)
+func (u *unitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	return u.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		return fn(Tx{
+			Users:       NewAuditedUserRepository(NewWebhookUserRepository(NewUserRepository(db), NewWebhookRepository(db)), NewAuditLogRepository(db)),
+			Preferences: NewPreferencesRepository(db),
+			Invitations: NewInvitationRepository(db),
+		})
+	})
+}
@@ -1,22 +1,55 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
 	"users-api/domain"
+	"users-api/utils"
 
+	"shared/apperrors"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
+// ErrDuplicate es la causa de los Conflict que devuelven Create y Update
+// cuando MySQL rechaza una clave única repetida (error 1062). Pasa si dos
+// requests con el mismo username o email pasan a la vez el chequeo del service
+// Ejemplo: errors.Is(err, repositories.ErrDuplicate)
+var ErrDuplicate = errors.New("duplicate entry")
+
+// mysqlDuplicateEntry es el número de error de MySQL para una clave única repetida
+const mysqlDuplicateEntry = 1062
+
 // UserRepository define la interfaz del repositorio
 // Es como un "contrato" que dice qué operaciones debe tener
 type UserRepository interface {
-	Create(user *domain.User) error
-	GetByID(id uint) (*domain.User, error)
-	GetByUsername(username string) (*domain.User, error)
-	GetByEmail(email string) (*domain.User, error)
-	Update(user *domain.User) error
-	Delete(id uint) error
-	GetAll() ([]domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id uint) (*domain.User, error)
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetByProvider(ctx context.Context, provider, providerID string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id uint) error
+	GetAll(ctx context.Context) ([]domain.User, error)
+	FindIDs(ctx context.Context, filter UserFilter, limit int) ([]uint, error)
+	EachBatch(ctx context.Context, filter UserFilter, size int, fn func([]domain.User) error) error
+}
+
+// UserFilter son los criterios de búsqueda de usuarios (operaciones masivas)
+// Los campos vacíos no filtran
+type UserFilter struct {
+	UserType      domain.UserType
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Deactivated   *bool // true = solo desactivados, false = solo activos
+}
+
+// Empty indica si el filtro no tiene ningún criterio (seleccionaría a todos)
+func (f UserFilter) Empty() bool {
+	return f.UserType == "" && f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero() && f.Deactivated == nil
 }
 
 // userRepository es la implementación real del repositorio
@@ -33,18 +66,19 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 
 // Create inserta un nuevo usuario en la base de datos
 // GORM automáticamente hace el INSERT
-func (r *userRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	setNormalizedEmail(user)
+	return duplicateError(r.db.WithContext(ctx).Create(user).Error)
 }
 
 // GetByID busca un usuario por su ID
 // Ejemplo: GetByID(1) -> SELECT * FROM users WHERE id = 1
-func (r *userRepository) GetByID(id uint) (*domain.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperrors.NotFound("user not found")
 		}
 		return nil, err
 	}
@@ -53,12 +87,12 @@ func (r *userRepository) GetByID(id uint) (*domain.User, error) {
 
 // GetByUsername busca un usuario por su username
 // Se usa en el login cuando el usuario pone su username
-func (r *userRepository) GetByUsername(username string) (*domain.User, error) {
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperrors.NotFound("user not found")
 		}
 		return nil, err
 	}
@@ -67,12 +101,26 @@ func (r *userRepository) GetByUsername(username string) (*domain.User, error) {
 
 // GetByEmail busca un usuario por su email
 // Se usa en el login cuando el usuario pone su email
-func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
+// Compara la forma normalizada: "Ana@Gmail.com " encuentra a ana@gmail.com
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := r.db.WithContext(ctx).Where("email_normalized = ?", utils.NormalizeEmail(email)).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByProvider busca un usuario por su cuenta externa (ej: Google)
+func (r *userRepository) GetByProvider(ctx context.Context, provider, providerID string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("provider = ? AND provider_id = ?", provider, providerID).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperrors.NotFound("user not found")
 		}
 		return nil, err
 	}
@@ -81,20 +129,103 @@ func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
 
 // Update actualiza un usuario existente
 // GORM hace UPDATE de todos los campos
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	setNormalizedEmail(user)
+	return duplicateError(r.db.WithContext(ctx).Save(user).Error)
 }
 
 // Delete elimina un usuario por su ID
 // GORM hace DELETE FROM users WHERE id = ?
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.User{}, id).Error
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
 }
 
 // GetAll obtiene todos los usuarios
 // GORM hace SELECT * FROM users
-func (r *userRepository) GetAll() ([]domain.User, error) {
+func (r *userRepository) GetAll(ctx context.Context) ([]domain.User, error) {
 	var users []domain.User
-	err := r.db.Find(&users).Error
+	err := r.db.WithContext(ctx).Find(&users).Error
 	return users, err
 }
+
+// FindIDs devuelve los IDs de los usuarios que cumplen el filtro, hasta limit
+// Ejemplo: {UserType: "normal"} -> SELECT id FROM users WHERE user_type = 'normal' ORDER BY id LIMIT ...
+func (r *userRepository) FindIDs(ctx context.Context, filter UserFilter, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.filtered(ctx, filter).Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// EachBatch recorre los usuarios que cumplen el filtro de a size, en orden de ID
+// Pagina por cursor (WHERE id > último) y no por OFFSET: cada lote cuesta lo
+// mismo aunque la tabla tenga cientos de miles de filas, y nunca hay más de
+// un lote en memoria. Si fn devuelve error se corta el recorrido
+func (r *userRepository) EachBatch(ctx context.Context, filter UserFilter, size int, fn func([]domain.User) error) error {
+	var lastID uint
+	for {
+		var users []domain.User
+		err := r.filtered(ctx, filter).Where("id > ?", lastID).Order("id").Limit(size).Find(&users).Error
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		if err := fn(users); err != nil {
+			return err
+		}
+		if len(users) < size {
+			return nil
+		}
+		lastID = users[len(users)-1].ID
+	}
+}
+
+// filtered arma la query de usuarios con los criterios del filtro
+func (r *userRepository) filtered(ctx context.Context, filter UserFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.User{})
+	if filter.UserType != "" {
+		query = query.Where("user_type = ?", filter.UserType)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	if filter.Deactivated != nil {
+		if *filter.Deactivated {
+			query = query.Where("deactivated_at IS NOT NULL")
+		} else {
+			query = query.Where("deactivated_at IS NULL")
+		}
+	}
+	return query
+}
+
+// setNormalizedEmail completa la columna con la que se buscan y comparan los emails
+func setNormalizedEmail(user *domain.User) {
+	normalized := utils.NormalizeEmail(user.Email)
+	user.EmailNormalized = &normalized
+}
+
+// duplicateError traduce una clave única repetida a un Conflict con el
+// mensaje del campo que chocó; cualquier otro error vuelve igual
+// MySQL 8 nombra la clave "users.email" y 5.7 solo "email"
+func duplicateError(err error) error {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return err
+	}
+
+	message := "user already exists"
+	switch {
+	case strings.HasSuffix(mysqlErr.Message, "email'"), strings.Contains(mysqlErr.Message, "idx_users_email_normalized"):
+		message = "email already exists"
+	case strings.HasSuffix(mysqlErr.Message, "username'"):
+		message = "username already exists"
+	case strings.Contains(mysqlErr.Message, "idx_users_provider"):
+		message = "the account is linked to another external login"
+	}
+	return apperrors.Wrap(apperrors.CodeConflict, message, ErrDuplicate)
+}
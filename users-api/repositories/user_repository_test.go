@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"shared/apperrors"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// Test: una clave única repetida de MySQL se traduce a un Conflict con el campo
+func TestDuplicateError(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"email MySQL 8", "Duplicate entry 'ana@spotly.com' for key 'users.email'", "email already exists"},
+		{"email normalizado", "Duplicate entry 'ana@spotly.com' for key 'users.idx_users_email_normalized'", "email already exists"},
+		{"username MySQL 5.7", "Duplicate entry 'ana' for key 'username'", "username already exists"},
+		{"login externo", "Duplicate entry 'google-123' for key 'users.idx_users_provider'", "the account is linked to another external login"},
+		{"otra clave", "Duplicate entry '1' for key 'PRIMARY'", "user already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := duplicateError(&mysqldriver.MySQLError{Number: mysqlDuplicateEntry, Message: tt.message})
+			if !errors.Is(err, ErrDuplicate) || !errors.Is(err, apperrors.ErrConflict) || err.Error() != tt.want {
+				t.Errorf("Expected a conflict %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	other := &mysqldriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}
+	if err := duplicateError(other); err != other {
+		t.Errorf("Expected other errors unchanged, got %v", err)
+	}
+	if err := duplicateError(nil); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepository define el acceso a los webhooks y a sus entregas
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetByID(ctx context.Context, id uint) (*domain.Webhook, error)
+	List(ctx context.Context) ([]domain.Webhook, error)
+	Delete(ctx context.Context, id uint) error
+
+	Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID uint, page, limit int) ([]domain.WebhookDelivery, int64, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// webhookRepository es la implementación con GORM
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository crea una nueva instancia del repositorio
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create guarda un webhook nuevo
+func (r *webhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+// GetByID busca un webhook por ID
+func (r *webhookRepository) GetByID(ctx context.Context, id uint) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.db.WithContext(ctx).First(&webhook, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NotFound("webhook not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// List devuelve todos los webhooks (son pocos: los registra un admin a mano)
+func (r *webhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+	err := r.db.WithContext(ctx).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// Delete borra el webhook junto con sus entregas (pendientes o no)
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.NotFound("webhook not found")
+		}
+		return nil
+	})
+}
+
+// Enqueue guarda entregas nuevas (pendientes)
+func (r *webhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Omit("Webhook").Create(&deliveries).Error
+}
+
+// ListDeliveries devuelve una página de las entregas de un webhook (lo más
+// nuevo primero) y el total
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID uint, page, limit int) ([]domain.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := []domain.WebhookDelivery{}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// ListDue lista las entregas pendientes cuyo próximo intento ya llegó, las
+// más viejas primero, con su webhook (URL y secret) cargado
+func (r *webhookRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// SaveAttempt guarda el resultado de un intento (estado, contadores y error)
+func (r *webhookRepository) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error
+}
+
+// DeleteFinishedBefore borra las entregas terminadas (entregadas o fallidas)
+// creadas antes de la fecha dada. Devuelve cuántas filas se borraron
+func (r *webhookRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status <> ? AND created_at < ?", domain.DeliveryPending, before).
+		Delete(&domain.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"users-api/domain"
+
+	"shared/requestid"
+)
+
+// webhookUserRepository encola una entrega por cada webhook suscripto cuando
+// un usuario se crea, cambia o se borra (user.created/updated/deleted)
+// Como el auditado, está en el repositorio para que ninguna ruta que toque
+// usuarios se olvide de avisar. Dentro de un UnitOfWork las entregas se
+// guardan en la misma transacción que el cambio
+// El envío lo hace después el job "deliver_webhooks" (ver WebhookService)
+type webhookUserRepository struct {
+	UserRepository
+	webhooks WebhookRepository
+}
+
+// NewWebhookUserRepository envuelve repo para avisar los cambios a los webhooks
+func NewWebhookUserRepository(repo UserRepository, webhooks WebhookRepository) UserRepository {
+	return &webhookUserRepository{UserRepository: repo, webhooks: webhooks}
+}
+
+// webhookFields son los campos de domain.WebhookUser: solo un cambio en
+// alguno de ellos es un user.updated (la contraseña, el login o el teléfono
+// no se mandan, así que tampoco se avisa que cambiaron)
+var webhookFields = jsonFields(reflect.TypeOf(domain.WebhookUser{}))
+
+// jsonFields devuelve los nombres JSON de los campos de un struct
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// Create avisa el alta
+func (r *webhookUserRepository) Create(ctx context.Context, user *domain.User) error {
+	if err := r.UserRepository.Create(ctx, user); err != nil {
+		return err
+	}
+	if subscribers := r.subscribers(ctx, domain.WebhookUserCreated); len(subscribers) > 0 {
+		r.enqueue(ctx, subscribers, domain.WebhookUserCreated, user, nil)
+	}
+	return nil
+}
+
+// Update avisa los campos que cambiaron
+// Los valores anteriores se leen de la base antes de guardar (una query más,
+// solo si algún webhook recibe user.updated)
+func (r *webhookUserRepository) Update(ctx context.Context, user *domain.User) error {
+	subscribers := r.subscribers(ctx, domain.WebhookUserUpdated)
+	if len(subscribers) == 0 {
+		return r.UserRepository.Update(ctx, user)
+	}
+
+	old, _ := r.UserRepository.GetByID(ctx, user.ID)
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+
+	_, after := userChanges(old, user)
+	changed := make([]string, 0, len(after))
+	for field := range after {
+		if webhookFields[field] {
+			changed = append(changed, field)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		r.enqueue(ctx, subscribers, domain.WebhookUserUpdated, user, changed)
+	}
+	return nil
+}
+
+// Delete avisa la baja con los datos que tenía el usuario
+func (r *webhookUserRepository) Delete(ctx context.Context, id uint) error {
+	subscribers := r.subscribers(ctx, domain.WebhookUserDeleted)
+	if len(subscribers) == 0 {
+		return r.UserRepository.Delete(ctx, id)
+	}
+
+	old, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil || old == nil {
+		old = &domain.User{ID: id}
+	}
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.enqueue(ctx, subscribers, domain.WebhookUserDeleted, old, nil)
+	return nil
+}
+
+// subscribers devuelve los webhooks que reciben el evento
+// Si no se pueden leer queda en el log: el cambio del usuario sigue igual
+func (r *webhookUserRepository) subscribers(ctx context.Context, event string) []domain.Webhook {
+	webhooks, err := r.webhooks.List(ctx)
+	if err != nil {
+		requestid.Logf(ctx, "❌ No se pudieron leer los webhooks para %s: %v", event, err)
+		return nil
+	}
+	var subscribed []domain.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribed(event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed
+}
+
+// enqueue guarda una entrega pendiente por webhook, cada una con su ID
+// Si falla queda en el log pero no rompe la request (el cambio ya se hizo)
+func (r *webhookUserRepository) enqueue(ctx context.Context, webhooks []domain.Webhook, event string, user *domain.User, changed []string) {
+	now := time.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		payload := domain.WebhookPayload{
+			ID:        newEventID(),
+			Event:     event,
+			CreatedAt: now.UTC(),
+			Data:      domain.WebhookPayloadData{User: domain.NewWebhookUser(user), ChangedFields: changed},
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			requestid.Logf(ctx, "❌ No se pudo armar el webhook %s del usuario %d: %v", event, user.ID, err)
+			return
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       payload.ID,
+			Event:         event,
+			Payload:       body,
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	if err := r.webhooks.Enqueue(ctx, deliveries); err != nil {
+		requestid.Logf(ctx, "❌ No se pudieron encolar los webhooks %s del usuario %d: %v", event, user.ID, err)
+	}
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"users-api/domain"
+)
+
+// memoryWebhookRepository guarda los webhooks y las entregas encoladas
+type memoryWebhookRepository struct {
+	WebhookRepository
+	webhooks   []domain.Webhook
+	deliveries []domain.WebhookDelivery
+}
+
+func (m *memoryWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *memoryWebhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	m.deliveries = append(m.deliveries, deliveries...)
+	return nil
+}
+
+// Test: altas, cambios y bajas se encolan solo para los webhooks suscriptos
+func TestWebhookUserRepository(t *testing.T) {
+	webhooks := &memoryWebhookRepository{webhooks: []domain.Webhook{
+		{ID: 1, Events: []string{domain.WebhookUserCreated, domain.WebhookUserUpdated, domain.WebhookUserDeleted}},
+		{ID: 2, Events: []string{domain.WebhookUserDeleted}},
+	}}
+	repo := NewWebhookUserRepository(&memoryUserRepository{users: map[uint]domain.User{}}, webhooks)
+	ctx := context.Background()
+
+	user := &domain.User{Username: "ana", Email: "ana@example.com", Password: "hash"}
+	repo.Create(ctx, user)
+	if len(webhooks.deliveries) != 1 || webhooks.deliveries[0].WebhookID != 1 || webhooks.deliveries[0].Event != domain.WebhookUserCreated {
+		t.Fatalf("Expected one user.created delivery for webhook 1, got %+v", webhooks.deliveries)
+	}
+
+	// Solo cambió la contraseña (ej: rehash del login): no se avisa
+	user.Password = "otro-hash"
+	repo.Update(ctx, user)
+	if len(webhooks.deliveries) != 1 {
+		t.Fatalf("Expected a password-only change not to be delivered, got %d deliveries", len(webhooks.deliveries))
+	}
+
+	user.Email, user.FirstName = "ana@new.com", "Ana"
+	repo.Update(ctx, user)
+	var payload domain.WebhookPayload
+	if err := json.Unmarshal(webhooks.deliveries[1].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Event != domain.WebhookUserUpdated || payload.ID != webhooks.deliveries[1].EventID || payload.Data.User.Email != "ana@new.com" {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if len(payload.Data.ChangedFields) != 2 || payload.Data.ChangedFields[0] != "email" || payload.Data.ChangedFields[1] != "first_name" {
+		t.Errorf("Expected email and first_name as changed fields, got %v", payload.Data.ChangedFields)
+	}
+
+	// La baja va a los dos, con los datos que tenía el usuario y un ID distinto cada una
+	repo.Delete(ctx, user.ID)
+	deleted := webhooks.deliveries[2:]
+	if len(deleted) != 2 || deleted[0].EventID == deleted[1].EventID {
+		t.Fatalf("Expected two user.deleted deliveries with their own IDs, got %+v", deleted)
+	}
+	json.Unmarshal(deleted[1].Payload, &payload)
+	if payload.Event != domain.WebhookUserDeleted || payload.Data.User.Username != "ana" {
+		t.Errorf("Unexpected delete payload: %+v", payload)
+	}
+}
+
+// Test: el payload lleva exactamente los campos de domain.WebhookUser (ni
+// teléfono, ni suspensión, ni la cuenta externa) y un cambio de teléfono no
+// se avisa
+func TestWebhookUserRepository_PayloadFields(t *testing.T) {
+	webhooks := &memoryWebhookRepository{webhooks: []domain.Webhook{
+		{ID: 1, Events: []string{domain.WebhookUserCreated, domain.WebhookUserUpdated}},
+	}}
+	repo := NewWebhookUserRepository(&memoryUserRepository{users: map[uint]domain.User{}}, webhooks)
+	ctx := context.Background()
+
+	subject, normalized, bannedUntil, mergedInto := "google-123", "ana@example.com", time.Now().Add(time.Hour), uint(9)
+	user := &domain.User{
+		Username: "ana", Email: "ana@example.com", Password: "hash", FirstName: "Ana", LastName: "García",
+		UserType: domain.UserTypeNormal, Phone: "+5493515550000", Provider: domain.ProviderGoogle, ProviderID: &subject,
+		EmailNormalized: &normalized, AvatarKey: "avatars/1/ab12.jpg", AvatarURL: "https://cdn.example.com/avatars/1/ab12.jpg",
+		BannedUntil: &bannedUntil, MergedInto: &mergedInto, Locale: "es-AR", Timezone: "America/Argentina/Cordoba",
+	}
+	repo.Create(ctx, user)
+	if len(webhooks.deliveries) != 1 {
+		t.Fatalf("Expected one delivery, got %d", len(webhooks.deliveries))
+	}
+
+	var payload struct {
+		Data struct {
+			User map[string]interface{} `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(webhooks.deliveries[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(payload.Data.User))
+	for key := range payload.Data.User {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	want := []string{"avatar_url", "created_at", "email", "first_name", "id", "last_name", "locale", "timezone", "user_type", "username"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected user keys %v, got %v", want, keys)
+	}
+
+	user.Phone = "+5493515550001"
+	repo.Update(ctx, user)
+	if len(webhooks.deliveries) != 1 {
+		t.Errorf("Expected a phone-only change not to be delivered, got %d deliveries", len(webhooks.deliveries))
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net/http"
+	"users-api/domain"
+	"users-api/dto"
+
+	"shared/diagnostics"
+	"shared/health"
+	"shared/logging"
+	"shared/openapi"
+)
+
+// featuresResponse es la forma de la respuesta de GET /features
+type featuresResponse struct {
+	Features []string `json:"features"`
+}
+
+// apiSpec documenta las rutas de users-api (GET /openapi.json)
+// Los schemas salen de los DTOs; server_test.go verifica que cada ruta del
+// router esté acá, así el documento no queda desactualizado
+func apiSpec() *openapi.Spec {
+	spec := openapi.New("users-api", "1.0.0")
+
+	// Salud
+	spec.Add(openapi.Operation{Method: "GET", Path: "/health/live", Summary: "El proceso responde (liveness)", Tags: []string{"health"},
+		Reply: map[string]string{}})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/health", Summary: "Alias de /health/live", Tags: []string{"health"},
+		Reply: map[string]string{}})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/health/ready", Summary: "Chequeo de MySQL y RabbitMQ con estado y latencia de cada uno (readiness)", Tags: []string{"health"},
+		Reply: health.Report{}, Extra: map[int]interface{}{http.StatusServiceUnavailable: health.Report{}}})
+	spec.Add(openapi.Operation{Method: "GET", Path: "/readyz", Summary: "Alias de /health/ready", Tags: []string{"health"},
+		Reply: health.Report{}, Extra: map[int]interface{}{http.StatusServiceUnavailable: health.Report{}}})
+	diagnostics.AddOperations(spec) // /debug/* (admins)
+	logging.AddOperations(spec)     // /admin/loglevel (admins)
+
+	// Rutas de la API: cada una en /v1 y sin prefijo (deprecada)
+	add := func(op openapi.Operation) {
+		legacy := op
+		legacy.Deprecated = true
+		spec.Add(legacy)
+
+		op.Path = "/v1" + op.Path
+		spec.Add(op)
+	}
+
+	add(openapi.Operation{Method: "GET", Path: "/features", Summary: "Feature flags prendidos para quien llama", Tags: []string{"features"},
+		Reply: featuresResponse{}})
+
+	// Usuarios
+	add(openapi.Operation{Method: "POST", Path: "/users", Summary: "Registrar un usuario (acepta Idempotency-Key; invitation_code obligatorio en modo invite_only)", Tags: []string{"users"},
+		Request: dto.CreateUserRequest{}, Status: http.StatusCreated, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "POST", Path: "/users/login", Summary: "Login con username o email", Tags: []string{"users"},
+		Request: dto.LoginRequest{}, Reply: dto.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "POST", Path: "/users/login/magic-link", Summary: "Pedir un link de login por email (202 exista o no el email)", Tags: []string{"users"},
+		Request: dto.MagicLinkRequest{}, Status: http.StatusAccepted, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "POST", Path: "/users/login/magic-link/verify", Summary: "Canjear un magic link (un solo uso) por un JWT", Tags: []string{"users"},
+		Request: dto.VerifyMagicLinkRequest{}, Reply: dto.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "POST", Path: "/users/login/google", Summary: "Login con un ID token de Google (crea el usuario la primera vez)", Tags: []string{"users"},
+		Request: dto.GoogleLoginRequest{}, Reply: dto.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "GET", Path: "/users/login/oidc", Summary: "Proveedores OIDC habilitados (para armar el redirect al proveedor)", Tags: []string{"users"},
+		Reply: dto.SuccessResponse{}})
+	add(openapi.Operation{Method: "POST", Path: "/users/login/oidc/:provider", Summary: "Login con un proveedor OIDC: canjea el code del redirect (crea el usuario la primera vez)", Tags: []string{"users"},
+		Request: dto.OIDCLoginRequest{}, Reply: dto.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "GET", Path: "/users/:id", Summary: "Perfil público de un usuario", Tags: []string{"users"},
+		Reply: dto.PublicUserResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}})
+
+	// Preferencias y seguridad
+	add(openapi.Operation{Method: "GET", Path: "/users/:id/preferences", Summary: "Preferencias de notificación (interna: header X-Service-Token)", Tags: []string{"preferences"},
+		Reply: domain.NotificationPreferences{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound}})
+	add(openapi.Operation{Method: "GET", Path: "/users/me/preferences", Summary: "Preferencias de notificación propias", Tags: []string{"preferences"},
+		Auth: true, Reply: domain.NotificationPreferences{}, Errors: []int{http.StatusUnauthorized, http.StatusNotFound}})
+	add(openapi.Operation{Method: "PUT", Path: "/users/:id/preferences", Summary: "Cambiar preferencias (propio usuario o admin)", Tags: []string{"preferences"},
+		Auth: true, Request: dto.UpdatePreferencesRequest{}, Reply: domain.NotificationPreferences{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "POST", Path: "/users/logout", Summary: "Revocar el token actual (logout)", Tags: []string{"users"},
+		Auth: true, Reply: dto.SuccessResponse{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized}})
+	add(openapi.Operation{Method: "GET", Path: "/users/me", Summary: "Perfil del usuario del token", Tags: []string{"users"},
+		Auth: true, Reply: dto.UserResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusNotFound}})
+	add(openapi.Operation{Method: "PUT", Path: "/users/me", Summary: "Editar el perfil propio (contraseña o email nuevos piden la actual)", Tags: []string{"users"},
+		Auth: true, Request: dto.UpdateMeRequest{}, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict}})
+	add(openapi.Operation{Method: "GET", Path: "/users/me/security", Summary: "Logins sospechosos y dispositivos conocidos", Tags: []string{"security"},
+		Auth: true, Reply: dto.SecurityOverviewResponse{}, Errors: []int{http.StatusUnauthorized}})
+
+	add(openapi.Operation{Method: "GET", Path: "/users/me/logins", Summary: "Historial de logins propios, exitosos y fallidos", Tags: []string{"security"},
+		Auth: true, Query: []string{"page", "limit"}, Reply: dto.LoginHistoryResponse{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized}})
+
+	add(openapi.Operation{Method: "PUT", Path: "/users/me/phone", Summary: "Cambiar el teléfono propio y mandar el código por SMS", Tags: []string{"phone"},
+		Auth: true, Request: dto.UpdatePhoneRequest{}, Status: http.StatusAccepted, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests}})
+	add(openapi.Operation{Method: "POST", Path: "/users/me/phone/verify", Summary: "Confirmar el código recibido por SMS", Tags: []string{"phone"},
+		Auth: true, Request: dto.VerifyPhoneRequest{}, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests}})
+
+	add(openapi.Operation{Method: "POST", Path: "/users/me/avatar", Summary: "Subir la foto de perfil (multipart, campo avatar; JPEG o PNG hasta 5 MB)", Tags: []string{"users"},
+		Auth: true, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound}})
+
+	// Admin
+	add(openapi.Operation{Method: "GET", Path: "/admin/users", Summary: "Listar usuarios", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/users/stats", Summary: "Totales por tipo, activos, teléfonos verificados y registros por día/semana", Tags: []string{"admin"},
+		Auth: true, Query: []string{"days", "weeks"}, Reply: dto.UserStatsResponse{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/users/export", Summary: "CSV de los usuarios filtrados (sin contraseñas), en streaming", Tags: []string{"admin"},
+		Auth: true, Query: []string{"user_type", "created_after", "created_before", "deactivated"},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "PUT", Path: "/admin/users/:id", Summary: "Actualizar un usuario", Tags: []string{"admin"},
+		Auth: true, Request: dto.UpdateUserRequest{}, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict}})
+	add(openapi.Operation{Method: "DELETE", Path: "/admin/users/:id", Summary: "Eliminar un usuario", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/bulk", Summary: "Desactivar, activar, borrar o cambiar el rol de muchos usuarios (async)", Tags: []string{"admin"},
+		Auth: true, Request: dto.BulkUsersRequest{}, Status: http.StatusAccepted, Reply: domain.BulkJob{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/users/bulk/:job_id", Summary: "Estado y progreso de una operación masiva", Tags: []string{"admin"},
+		Auth: true, Reply: domain.BulkJob{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/:id/impersonate", Summary: "Token corto para actuar como el usuario (no admins)", Tags: []string{"admin"},
+		Auth: true, Reply: dto.ImpersonationResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/:id/merge", Summary: "Fusionar una cuenta duplicada en esta (preferencias, teléfono; el resto vía user.merged)", Tags: []string{"admin"},
+		Auth: true, Request: dto.MergeUsersRequest{}, Reply: dto.MergeUsersResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/:id/revoke-tokens", Summary: "Invalidar todos los tokens emitidos hasta ahora para el usuario", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/:id/ban", Summary: "Suspender la cuenta hasta banned_until y revocar sus tokens", Tags: []string{"admin"},
+		Auth: true, Request: dto.BanUserRequest{}, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/users/:id/unban", Summary: "Levantar la suspensión de la cuenta", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/users/:id/logins", Summary: "Historial de logins de un usuario", Tags: []string{"admin"},
+		Auth: true, Query: []string{"page", "limit"}, Reply: dto.LoginHistoryResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/invitations", Summary: "Invitar a registrarse: manda un código por email (con rol opcional) y lo devuelve", Tags: []string{"admin"},
+		Auth: true, Request: dto.CreateInvitationRequest{}, Status: http.StatusCreated, Reply: dto.InvitationResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict}})
+	add(openapi.Operation{Method: "POST", Path: "/admin/webhooks", Summary: "Registrar un webhook de eventos de usuarios (devuelve el secret de la firma)", Tags: []string{"admin"},
+		Auth: true, Request: dto.CreateWebhookRequest{}, Status: http.StatusCreated, Reply: dto.WebhookResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/webhooks", Summary: "Listar los webhooks registrados", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusForbidden}})
+	add(openapi.Operation{Method: "DELETE", Path: "/admin/webhooks/:id", Summary: "Borrar un webhook y sus entregas pendientes", Tags: []string{"admin"},
+		Auth: true, Reply: dto.SuccessResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/webhooks/:id/deliveries", Summary: "Historial de entregas de un webhook (estado, intentos, último error)", Tags: []string{"admin"},
+		Auth: true, Query: []string{"page", "limit"}, Reply: dto.WebhookDeliveriesResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}})
+	add(openapi.Operation{Method: "GET", Path: "/admin/audit-logs", Summary: "Altas, cambios y bajas de usuarios hechos por admins (valores anteriores y nuevos)", Tags: []string{"admin"},
+		Auth: true, Query: []string{"actor_id", "target_id", "action", "from", "to", "page", "limit"}, Reply: dto.AuditLogsResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}})
+
+	return spec
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"users-api/controllers"
+
+	"shared/httpmw/ginmw"
+
+	"github.com/gin-gonic/gin"
+)
+
+// api reúne los controllers y middlewares que usan las rutas versionadas
+type api struct {
+	users         *controllers.UserController
+	security      *controllers.SecurityController
+	features      *controllers.FeatureController
+	prefs         *controllers.PreferencesController
+	impersonation *controllers.ImpersonationController
+	bulk          *controllers.BulkController
+	stats         *controllers.StatsController
+	export        *controllers.ExportController
+	phone         *controllers.PhoneController
+	merge         *controllers.MergeController
+	tokens        *controllers.TokenController
+	bans          *controllers.BanController
+	invitations   *controllers.InvitationController
+	webhooks      *controllers.WebhookController
+	auditLogs     *controllers.AuditLogController
+	avatars       *controllers.AvatarController
+
+	authRequired gin.HandlerFunc
+	authOptional gin.HandlerFunc
+	loginLimiter gin.HandlerFunc
+	idempotent   gin.HandlerFunc
+	adminIPs     gin.HandlerFunc
+	internal     gin.HandlerFunc
+}
+
+// registerV1 define las rutas de la versión 1 de la API
+// Se monta en /v1 y, por compatibilidad, sin prefijo (deprecado)
+// Una v2 se agrega con su propio registerV2 montado en /v2: define solo lo
+// que cambia y reutiliza los handlers de v1 para el resto
+func (a *api) registerV1(r gin.IRouter) {
+	// Rutas PÚBLICAS (sin autenticación)
+	r.GET("/features", a.authOptional, a.features.GetFeatures)
+	r.POST("/users", a.loginLimiter, a.idempotent, a.users.CreateUser) // Registro
+	r.POST("/users/login", a.loginLimiter, a.users.Login)              // Login
+	r.GET("/users/:id", a.users.GetUserByID)                           // Perfil público
+
+	// Rutas INTERNAS (token de servicio): notifications-api lee las
+	// preferencias antes de mandar un email
+	r.GET("/users/:id/preferences", a.internal, a.prefs.GetPreferences)
+
+	// Login sin contraseña: el link llega por email y el frontend lo canjea
+	r.POST("/users/login/magic-link", a.loginLimiter, a.idempotent, a.users.RequestMagicLink)
+	r.POST("/users/login/magic-link/verify", a.loginLimiter, a.users.VerifyMagicLink)
+
+	// Login con Google: el frontend manda el ID token del botón de Google
+	r.POST("/users/login/google", a.loginLimiter, a.users.GoogleLogin)
+
+	// Login con proveedores OIDC (OIDC_PROVIDERS): el frontend lista los
+	// proveedores, manda al usuario a autorizar y canjea acá el code
+	r.GET("/users/login/oidc", a.users.OIDCProviders)
+	r.POST("/users/login/oidc/:provider", a.loginLimiter, a.users.OIDCLogin)
+
+	// Rutas PROTEGIDAS (requieren JWT - el propio usuario o admin)
+	r.GET("/users/me/preferences", a.authRequired, a.prefs.GetMyPreferences)
+	r.PUT("/users/:id/preferences", a.authRequired, a.prefs.UpdatePreferences)
+	r.GET("/users/me", a.authRequired, a.users.GetMe)    // Perfil propio
+	r.PUT("/users/me", a.authRequired, a.users.UpdateMe) // Editar perfil propio
+	r.GET("/users/me/security", a.authRequired, a.security.GetMySecurity)
+	r.GET("/users/me/logins", a.authRequired, a.security.GetMyLogins)
+	r.POST("/users/logout", a.authRequired, a.tokens.Logout) // Revoca el token actual
+
+	// Teléfono propio: cada cambio manda un código por SMS (el límite cuida el costo)
+	r.PUT("/users/me/phone", a.authRequired, a.loginLimiter, a.idempotent, a.phone.UpdatePhone)
+	r.POST("/users/me/phone/verify", a.authRequired, a.loginLimiter, a.phone.VerifyPhone)
+
+	// Foto de perfil (multipart, hasta 5 MB): se guarda en el bucket con su miniatura
+	r.POST("/users/me/avatar", a.authRequired, a.avatars.UploadAvatar)
+
+	// Rutas PROTEGIDAS (requieren JWT - solo admin)
+	admin := r.Group("/admin")
+	admin.Use(a.adminIPs, a.authRequired, ginmw.Admin())
+	{
+		admin.GET("/users", a.users.GetAllUsers)       // Listar todos
+		admin.PUT("/users/:id", a.users.UpdateUser)    // Actualizar
+		admin.DELETE("/users/:id", a.users.DeleteUser) // Eliminar
+
+		// Panel de admin: estadísticas y exportación en CSV (streaming)
+		admin.GET("/users/stats", a.stats.GetStats)
+		admin.GET("/users/export", a.export.ExportUsers)
+
+		// Operaciones masivas: corren en segundo plano, el estado se consulta aparte
+		admin.POST("/users/bulk", a.idempotent, a.bulk.StartBulk)
+		admin.GET("/users/bulk/:job_id", a.bulk.GetBulkJob)
+
+		// Token corto para actuar como el usuario (soporte)
+		admin.POST("/users/:id/impersonate", a.impersonation.Impersonate)
+
+		// Fusión de cuentas duplicadas (la de la URL es la que queda)
+		admin.POST("/users/:id/merge", a.idempotent, a.merge.MergeUsers)
+
+		// Invalidar todos los tokens del usuario (ej: sesión robada)
+		admin.POST("/users/:id/revoke-tokens", a.tokens.RevokeTokens)
+
+		// Suspender una cuenta hasta una fecha (también corta sus sesiones)
+		admin.POST("/users/:id/ban", a.bans.BanUser)
+		admin.POST("/users/:id/unban", a.bans.UnbanUser)
+
+		// Historial de logins de un usuario (soporte: "no puedo entrar")
+		admin.GET("/users/:id/logins", a.security.GetUserLogins)
+
+		// Invitaciones a registrarse (obligatorias con REGISTRATION_MODE=invite_only)
+		admin.POST("/invitations", a.idempotent, a.invitations.CreateInvitation)
+
+		// Webhooks: URLs externas que reciben user.created/updated/deleted firmados
+		admin.POST("/webhooks", a.idempotent, a.webhooks.CreateWebhook)
+		admin.GET("/webhooks", a.webhooks.ListWebhooks)
+		admin.DELETE("/webhooks/:id", a.webhooks.DeleteWebhook)
+		admin.GET("/webhooks/:id/deliveries", a.webhooks.ListDeliveries)
+
+		// Historial de altas, cambios y bajas de usuarios hechos por admins
+		admin.GET("/audit-logs", a.auditLogs.ListAuditLogs)
+	}
+}
@@ -0,0 +1,281 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+	"users-api/controllers"
+	"users-api/database"
+	"users-api/jobs"
+	"users-api/services"
+	"users-api/utils"
+
+	"shared/audit"
+	"shared/diagnostics"
+	"shared/featureflags"
+	"shared/health"
+	"shared/httpmw"
+	"shared/httpmw/ginmw"
+	"shared/idempotency"
+	"shared/logging"
+	"shared/openapi"
+	"shared/requestid"
+	"shared/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config reúne las dependencias y parámetros del servidor HTTP de users-api
+// Los servicios los arma quien llama (ver el paquete app), así el handler se
+// puede montar igual en main, en tests con httptest o dentro de otro binario
+type Config struct {
+	UserService        services.UserService
+	PreferencesService services.PreferencesService
+	SecurityService    services.SecurityService
+	MagicLinkService   services.MagicLinkService
+	Impersonation      services.ImpersonationService
+	BulkService        services.BulkService
+	StatsService       services.StatsService
+	PhoneService       services.PhoneService
+	MergeService       services.MergeService
+	OutboxService      services.OutboxService
+	TokenService       services.TokenService
+	BanService         services.BanService
+	AuditLogService    services.AuditLogService
+	AvatarService      services.AvatarService
+	GoogleLogin        services.GoogleLoginService
+	OIDCLogin          services.OIDCLoginService // nil = sin proveedores OIDC
+	Invitations        services.InvitationService
+	Webhooks           services.WebhookService
+	Flags              *featureflags.Client
+
+	// Locker de los jobs programados; nil = no se programan jobs (tests)
+	Locker                  scheduler.Locker
+	SecurityEventsRetention time.Duration
+
+	LoginRateLimit int           // requests por minuto e IP para login y registro
+	IdempotencyTTL time.Duration // cuánto se recuerda un Idempotency-Key
+
+	Health *health.Checker // chequeos de GET /health/ready; nil = no se expone
+
+	// Eventos de auditoría (spotly.audit); nil = solo se loguean
+	Audit audit.Emitter
+
+	// Fecha de baja de las rutas sin /v1 (header Sunset); cero = sin fecha
+	LegacySunset time.Time
+
+	// Loguear headers y bodies de cada request (LOG_HTTP_BODIES, solo se
+	// ven con nivel debug); contraseñas, tokens y Authorization van tapados
+	LogBodies bool
+
+	// Tope para que responda cada ruta de la API; 0 = sin tope
+	HandlerTimeout time.Duration
+
+	// Requests con más queries que esto se loguean como posible N+1; 0 = no se avisa
+	QueriesPerRequestWarn int
+
+	// Redes desde las que se aceptan las rutas de admin (/admin, /debug)
+	// nil = cualquiera; el rol del JWT se chequea igual
+	AdminAllowlist *httpmw.IPAllowlist
+
+	// Header del proxy o CDN con el país de la IP (ej: CF-IPCountry); "" = sin geo
+	GeoCountryHeader string
+
+	// Token de las rutas que solo llaman otros servicios (X-Service-Token)
+	// "" = esas rutas rechazan todo
+	InternalAPIToken string
+
+	// Orígenes que acepta CORS; nil = cualquiera
+	CORSOrigins []string
+}
+
+// NewServer arma los controllers y el router sobre los servicios recibidos
+// Devuelve el handler y una función para liberar lo que el servidor arrancó
+// (por ahora el scheduler de jobs)
+func NewServer(cfg Config) (http.Handler, func() error) {
+	if cfg.LoginRateLimit <= 0 {
+		cfg.LoginRateLimit = 10
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		cfg.IdempotencyTTL = 24 * time.Hour
+	}
+	if cfg.Audit == nil {
+		cfg.Audit = audit.NewLogEmitter("users-api")
+	}
+	if cfg.OIDCLogin == nil {
+		cfg.OIDCLogin = services.NewOIDCLoginService(nil, nil, services.RegistrationConfig{})
+	}
+
+	// ============================================
+	// 1. CONTROLLERS (manejan HTTP)
+	// ============================================
+	userController := controllers.NewUserController(cfg.UserService, cfg.SecurityService, cfg.MagicLinkService, cfg.GoogleLogin, cfg.OIDCLogin, cfg.Audit, cfg.GeoCountryHeader)
+	securityController := controllers.NewSecurityController(cfg.SecurityService)
+	featureController := controllers.NewFeatureController(cfg.Flags)
+	prefsController := controllers.NewPreferencesController(cfg.PreferencesService)
+	impersonationController := controllers.NewImpersonationController(cfg.Impersonation, cfg.Audit)
+	bulkController := controllers.NewBulkController(cfg.BulkService)
+	statsController := controllers.NewStatsController(cfg.StatsService)
+	exportController := controllers.NewExportController(cfg.UserService)
+	phoneController := controllers.NewPhoneController(cfg.PhoneService)
+	mergeController := controllers.NewMergeController(cfg.MergeService, cfg.Audit)
+	tokenController := controllers.NewTokenController(cfg.TokenService, cfg.Audit)
+	banController := controllers.NewBanController(cfg.BanService, cfg.Audit)
+	invitationController := controllers.NewInvitationController(cfg.Invitations, cfg.Audit)
+	webhookController := controllers.NewWebhookController(cfg.Webhooks, cfg.Audit)
+	auditLogController := controllers.NewAuditLogController(cfg.AuditLogService)
+	avatarController := controllers.NewAvatarController(cfg.AvatarService)
+
+	// ============================================
+	// 2. JOBS PROGRAMADOS
+	// ============================================
+	closeFn := func() error { return nil }
+	if cfg.Locker != nil {
+		jobScheduler := scheduler.New(cfg.Locker)
+		if err := jobs.Register(jobScheduler, cfg.SecurityService, cfg.MagicLinkService, cfg.OutboxService, cfg.TokenService, cfg.Webhooks, cfg.SecurityEventsRetention); err != nil {
+			// Las expresiones cron son constantes: si fallan es un bug
+			panic("users-api: invalid job spec: " + err.Error())
+		}
+		jobScheduler.Start()
+		closeFn = func() error {
+			jobScheduler.Stop()
+			return nil
+		}
+	}
+
+	// ============================================
+	// 3. CONFIGURAR GIN (Framework web)
+	// ============================================
+	router := gin.New()
+
+	// Middlewares comunes (shared/httpmw): request ID primero para que
+	// el log de acceso y las respuestas de error lo incluyan
+	router.Use(ginmw.RequestID(), ginmw.Logger(), ginmw.Recovery())
+	if cfg.QueriesPerRequestWarn > 0 {
+		router.Use(countQueries(cfg.QueriesPerRequestWarn))
+	}
+	if cfg.LogBodies {
+		router.Use(ginmw.BodyLog(httpmw.DefaultBodyLogMax))
+	}
+
+	// Auditoría: cada 401/403 queda como permission.denied
+	router.Use(ginmw.AuditDenied(cfg.Audit))
+
+	// CORS - Permitir requests desde el frontend
+	cors := httpmw.DefaultCORS("GET", "POST", "PUT", "DELETE")
+	if len(cfg.CORSOrigins) > 0 {
+		cors.AllowedOrigins = cfg.CORSOrigins
+	}
+	router.Use(ginmw.CORS(cors))
+
+	// Auth: valida el JWT con el mismo validador que firma los tokens y
+	// rechaza los revocados (logout)
+	validator := utils.Validator()
+	if cfg.TokenService != nil {
+		validator = validator.WithRevocation(cfg.TokenService)
+	}
+	authRequired := ginmw.Auth(validator)
+	authOptional := ginmw.OptionalAuth(validator)
+
+	// Rate limit por IP para login y registro (contra fuerza bruta)
+	loginLimiter := ginmw.RateLimit(httpmw.NewRateLimiter(cfg.LoginRateLimit, cfg.LoginRateLimit))
+
+	// Allowlist de IPs para las rutas de admin: va antes de Auth, así desde
+	// fuera de la red permitida ni siquiera se valida el token
+	adminIPs := ginmw.AllowIPs(cfg.AdminAllowlist, cfg.Audit)
+
+	// Rutas internas (servicio a servicio): piden INTERNAL_API_TOKEN
+	internal := ginmw.ServiceToken(cfg.InternalAPIToken)
+
+	// Idempotency-Key: una request reintentada (registro, magic link, alta de
+	// admin...) devuelve la misma respuesta sin repetir el efecto
+	idempotent := ginmw.Idempotency(idempotency.New(idempotency.NewMemoryStore(), cfg.IdempotencyTTL))
+
+	// ============================================
+	// 4. DEFINIR RUTAS (Endpoints)
+	// ============================================
+	// Rutas operativas: sin versión
+	// live solo dice que el proceso responde (si falla, reiniciarlo); ready
+	// chequea MySQL y RabbitMQ (si falla, no mandarle tráfico)
+	// /health y /readyz quedan como alias (status-api consulta /readyz)
+	router.GET("/health/live", userController.HealthCheck)
+	router.GET("/health", userController.HealthCheck)
+	if cfg.Health != nil {
+		ready := gin.WrapH(cfg.Health.Handler())
+		router.GET("/health/ready", ready)
+		router.GET("/readyz", ready)
+	}
+
+	// Diagnóstico (pprof, expvar): solo admins. También puede ir en un
+	// puerto interno sin auth con DEBUG_ADDR (ver shared/diagnostics)
+	debug := router.Group("/debug", adminIPs, authRequired, ginmw.Admin())
+	{
+		diag := gin.WrapH(diagnostics.Handler())
+		debug.GET("/pprof/*profile", diag)
+		debug.POST("/pprof/symbol", diag)
+		debug.GET("/vars", diag)
+	}
+
+	// Nivel de log en caliente: solo admins (también con SIGUSR1)
+	logLevel := gin.WrapH(logging.Handler())
+	router.GET("/admin/loglevel", adminIPs, authRequired, ginmw.Admin(), logLevel)
+	router.PUT("/admin/loglevel", adminIPs, authRequired, ginmw.Admin(), logLevel)
+
+	// Documentación: OpenAPI 3 y Swagger UI
+	router.GET("/openapi.json", gin.WrapH(apiSpec().Handler()))
+	router.GET("/docs", gin.WrapH(openapi.SwaggerUI("users-api", "/openapi.json")))
+
+	// Rutas de la API versionadas (ver routes.go)
+	api := &api{
+		users:         userController,
+		security:      securityController,
+		features:      featureController,
+		prefs:         prefsController,
+		impersonation: impersonationController,
+		bulk:          bulkController,
+		stats:         statsController,
+		export:        exportController,
+		phone:         phoneController,
+		merge:         mergeController,
+		tokens:        tokenController,
+		bans:          banController,
+		invitations:   invitationController,
+		webhooks:      webhookController,
+		auditLogs:     auditLogController,
+		avatars:       avatarController,
+		authRequired:  authRequired,
+		authOptional:  authOptional,
+		loginLimiter:  loginLimiter,
+		idempotent:    idempotent,
+		adminIPs:      adminIPs,
+		internal:      internal,
+	}
+	// El timeout va en los grupos y no global: /readyz ya tiene el suyo
+	api.registerV1(router.Group("/v1", ginmw.Timeout(cfg.HandlerTimeout)))
+
+	// Las rutas sin prefijo son las mismas de v1 y quedan deprecadas:
+	// responden con Deprecation, Link a /v1 y Sunset (LEGACY_ROUTES_SUNSET)
+	api.registerV1(router.Group("", ginmw.Timeout(cfg.HandlerTimeout), ginmw.Deprecated("/v1", cfg.LegacySunset)))
+
+	log.Println("✅ Rutas configuradas:")
+	for _, route := range router.Routes() {
+		log.Printf("   - %-6s %s", route.Method, route.Path)
+	}
+
+	return router, closeFn
+}
+
+// countQueries cuenta las queries de cada request y avisa cuando pasan de
+// limit: casi siempre es un N+1 (un query por cada elemento de una lista)
+func countQueries(limit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, stats := database.WithQueryStats(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if n := stats.Count(); n > int64(limit) {
+			requestid.Logf(ctx, "⚠️  %s %s hizo %d queries (límite %d): posible N+1", c.Request.Method, c.FullPath(), n, limit)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"users-api/dto"
+	"users-api/services"
+	"users-api/utils"
+
+	"shared/featureflags"
+	"shared/health"
+	"shared/httpmw"
+	"shared/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestServer monta el servidor sin base ni RabbitMQ: alcanza para las
+// rutas que no tocan la base (health, features, auth)
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	flags, err := featureflags.NewClient(featureflags.NewStaticSource(map[string]featureflags.Flag{
+		"new_search": {Enabled: true, Percentage: 100},
+	}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, closeFn := NewServer(Config{Flags: flags})
+	srv := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		srv.Close()
+		closeFn()
+	})
+	return srv
+}
+
+// Test: /health responde y propaga el request ID
+func TestNewServer_Health(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get(requestid.Header) == "" {
+		t.Error("Expected X-Request-ID header")
+	}
+}
+
+// Test: con MySQL caído /health/live sigue en 200 y /health/ready da 503 con
+// el estado y la latencia de cada dependencia
+func TestNewServer_LiveAndReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checker := health.New(time.Second)
+	checker.Add("mysql", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Add("rabbitmq", func(ctx context.Context) error { return nil })
+
+	handler, closeFn := NewServer(Config{Health: checker})
+	defer closeFn()
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/health/live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected live 200, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/health/ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var report health.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || report.Checks["mysql"].Status != health.StatusDown ||
+		report.Checks["rabbitmq"].Status != health.StatusUp {
+		t.Errorf("Expected 503 with mysql down, got %d %+v", resp.StatusCode, report)
+	}
+}
+
+// Test: los feature flags se sirven sin login
+func TestNewServer_Features(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/features")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Features []string `json:"features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Features) != 1 || body.Features[0] != "new_search" {
+		t.Errorf("Expected [new_search], got %v", body.Features)
+	}
+}
+
+// countingMagicLinks cuenta los pedidos de magic link
+type countingMagicLinks struct {
+	services.MagicLinkService
+	requests int
+}
+
+func (m *countingMagicLinks) Request(ctx context.Context, email string) error {
+	m.requests++
+	return nil
+}
+
+// Test: un pedido de magic link reintentado con la misma Idempotency-Key no
+// manda otro email; con otro body la clave se rechaza
+func TestNewServer_Idempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	magic := &countingMagicLinks{}
+	handler, closeFn := NewServer(Config{MagicLinkService: magic})
+	srv := httptest.NewServer(handler)
+	defer closeFn()
+	defer srv.Close()
+
+	post := func(body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/users/login/magic-link", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "retry-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	first, retry := post(`{"email":"ana@example.com"}`), post(`{"email":"ana@example.com"}`)
+	if first.StatusCode != http.StatusAccepted || retry.StatusCode != http.StatusAccepted || retry.Header.Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Expected the retry to replay the 202, got %d and %d %v", first.StatusCode, retry.StatusCode, retry.Header)
+	}
+	if magic.requests != 1 {
+		t.Errorf("Expected one magic link request, got %d", magic.requests)
+	}
+	if other := post(`{"email":"otra@example.com"}`); other.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for a reused key, got %d", other.StatusCode)
+	}
+}
+
+// stubSecurity responde el resumen de seguridad de cualquier usuario
+type stubSecurity struct {
+	services.SecurityService
+}
+
+func (s *stubSecurity) GetOverview(ctx context.Context, userID uint) (*dto.SecurityOverviewResponse, error) {
+	return &dto.SecurityOverviewResponse{}, nil
+}
+
+// Test: con un token de impersonación el admin ve lo que ve el usuario, pero
+// no puede cambiar el perfil, el teléfono, el avatar ni cerrar la sesión
+func TestNewServer_ImpersonationIsReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, closeFn := NewServer(Config{SecurityService: &stubSecurity{}})
+	srv := httptest.NewServer(handler)
+	defer closeFn()
+	defer srv.Close()
+
+	token, _, err := utils.GenerateImpersonationToken(utils.TokenUser{ID: 42, Username: "ana", UserType: "normal"}, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, route := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/v1/users/me/security", http.StatusOK},
+		{http.MethodPut, "/v1/users/me", http.StatusForbidden},
+		{http.MethodPut, "/v1/users/me/phone", http.StatusForbidden},
+		{http.MethodPost, "/v1/users/me/avatar", http.StatusForbidden},
+		{http.MethodPut, "/v1/users/42/preferences", http.StatusForbidden},
+		{http.MethodPost, "/v1/users/logout", http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest(route.method, srv.URL+route.path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != route.want {
+			t.Errorf("%s %s: expected %d, got %d", route.method, route.path, route.want, resp.StatusCode)
+		}
+	}
+}
+
+// Test: las rutas protegidas rechazan requests sin JWT (o sin token de servicio)
+func TestNewServer_RequiresAuth(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, path := range []string{"/users/me/security", "/users/me/preferences", "/users/1/preferences", "/admin/users", "/debug/vars", "/debug/pprof/heap", "/admin/loglevel"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+// Test: con allowlist, las rutas de admin responden 403 a otras IPs (el
+// servidor de prueba escucha en 127.0.0.1) y el resto de la API no cambia
+func TestNewServer_AdminAllowlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	allowlist, err := httpmw.NewIPAllowlist([]string{"10.0.0.0/8"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, closeFn := NewServer(Config{AdminAllowlist: allowlist})
+	srv := httptest.NewServer(handler)
+	defer closeFn()
+	defer srv.Close()
+
+	for path, want := range map[string]int{
+		"/admin/users":       http.StatusForbidden,
+		"/v1/admin/users":    http.StatusForbidden,
+		"/admin/loglevel":    http.StatusForbidden,
+		"/debug/vars":        http.StatusForbidden,
+		"/users/me/security": http.StatusUnauthorized,
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected %d, got %d", path, want, resp.StatusCode)
+		}
+	}
+}
+
+// Test: todas las rutas del router están en /openapi.json
+func TestNewServer_OpenAPICoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, closeFn := NewServer(Config{Health: health.New(time.Second)})
+	defer closeFn()
+
+	spec := apiSpec()
+	for _, route := range handler.(*gin.Engine).Routes() {
+		if route.Path == "/openapi.json" || route.Path == "/docs" {
+			continue
+		}
+		if !spec.Has(route.Method, route.Path) {
+			t.Errorf("%s %s is not documented in apiSpec()", route.Method, route.Path)
+		}
+	}
+}
+
+// Test: /v1 es la ruta actual y la ruta sin prefijo avisa que está deprecada
+func TestNewServer_VersionedRoutes(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/v1/features")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Deprecation") != "" {
+		t.Errorf("Expected 200 without Deprecation, got %d %q", resp.StatusCode, resp.Header.Get("Deprecation"))
+	}
+
+	resp, err = http.Get(srv.URL + "/features")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Deprecation") != "true" || resp.Header.Get("Link") != `</v1/features>; rel="successor-version"` {
+		t.Errorf("Expected deprecation headers, got %q %q", resp.Header.Get("Deprecation"), resp.Header.Get("Link"))
+	}
+}
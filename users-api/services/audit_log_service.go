@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"users-api/dto"
+	"users-api/repositories"
+)
+
+// defaultAuditLogLimit es el tamaño de página si no se pide otro
+const defaultAuditLogLimit = 50
+
+// AuditLogService consulta el historial de cambios hechos por admins
+// (lo escribe el repositorio de usuarios, ver NewAuditedUserRepository)
+type AuditLogService interface {
+	List(ctx context.Context, query dto.AuditLogQuery) (*dto.AuditLogsResponse, error)
+}
+
+// auditLogService es la implementación real del servicio
+type auditLogService struct {
+	repo repositories.AuditLogRepository
+}
+
+// NewAuditLogService crea una nueva instancia del servicio
+func NewAuditLogService(repo repositories.AuditLogRepository) AuditLogService {
+	return &auditLogService{repo: repo}
+}
+
+// List devuelve una página del historial (lo más nuevo primero)
+func (s *auditLogService) List(ctx context.Context, query dto.AuditLogQuery) (*dto.AuditLogsResponse, error) {
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultAuditLogLimit
+	}
+
+	logs, total, err := s.repo.List(ctx, repositories.AuditLogFilter{
+		ActorID:  query.ActorID,
+		TargetID: query.TargetID,
+		Action:   query.Action,
+		From:     query.From,
+		To:       query.To,
+	}, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuditLogsResponse{Logs: logs, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
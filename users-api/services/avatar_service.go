@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"users-api/domain"
+	"users-api/repositories"
+	"users-api/storage"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// AvatarService maneja la foto de perfil del propio usuario
+type AvatarService interface {
+	Upload(ctx context.Context, userID uint, data []byte) (*domain.User, error)
+}
+
+// avatarService es la implementación real del servicio
+type avatarService struct {
+	repo  repositories.UserRepository
+	store storage.ObjectStore
+}
+
+// NewAvatarService crea una nueva instancia del servicio
+// store nil = no hay bucket configurado y Upload responde 404
+func NewAvatarService(repo repositories.UserRepository, store storage.ObjectStore) AvatarService {
+	return &avatarService{repo: repo, store: store}
+}
+
+// Upload valida la imagen, sube la foto y su miniatura, guarda las URLs en
+// el usuario y borra la foto anterior
+// Cada foto va con una clave nueva (avatars/<id>/<random>.jpg): así un CDN
+// nunca sirve la vieja con la URL nueva
+func (s *avatarService) Upload(ctx context.Context, userID uint, data []byte) (*domain.User, error) {
+	if s.store == nil {
+		return nil, apperrors.NotFound("avatar uploads are not enabled")
+	}
+	if len(data) > utils.MaxAvatarBytes {
+		return nil, apperrors.Validation("avatar must be at most 5 MB")
+	}
+
+	avatar, err := utils.ProcessAvatar(data)
+	if errors.Is(err, utils.ErrAvatarType) || errors.Is(err, utils.ErrAvatarDimensions) {
+		return nil, apperrors.Validation(err.Error())
+	}
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error processing avatar", err)
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Subir la foto y la miniatura
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error storing avatar", err)
+	}
+	key := fmt.Sprintf("avatars/%d/%s.%s", user.ID, hex.EncodeToString(suffix), avatar.Ext)
+	if err := s.store.Put(ctx, key, avatar.ContentType, avatar.Image); err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error storing avatar", err)
+	}
+	if err := s.store.Put(ctx, avatarThumbKey(key), avatar.ContentType, avatar.Thumbnail); err != nil {
+		s.deleteObjects(ctx, key)
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error storing avatar", err)
+	}
+
+	// 2. Guardar las URLs; si falla, los archivos nuevos sobran
+	previous := user.AvatarKey
+	user.AvatarKey = key
+	user.AvatarURL = s.store.URL(key)
+	user.AvatarThumbURL = s.store.URL(avatarThumbKey(key))
+	if err := s.repo.Update(ctx, user); err != nil {
+		s.deleteObjects(ctx, key)
+		return nil, err
+	}
+
+	// 3. Borrar la foto anterior (si queda, solo ocupa lugar)
+	if previous != "" {
+		s.deleteObjects(ctx, previous)
+	}
+	return user, nil
+}
+
+// deleteObjects borra una foto y su miniatura; los errores quedan en el log
+func (s *avatarService) deleteObjects(ctx context.Context, key string) {
+	for _, k := range []string{key, avatarThumbKey(key)} {
+		if err := s.store.Delete(ctx, k); err != nil {
+			requestid.Logf(ctx, "⚠️  No se pudo borrar el avatar %s: %v", k, err)
+		}
+	}
+}
+
+// avatarThumbKey es la clave de la miniatura: "avatars/7/ab12.jpg" -> "avatars/7/ab12_thumb.jpg"
+func avatarThumbKey(key string) string {
+	dot := strings.LastIndex(key, ".")
+	return key[:dot] + "_thumb" + key[dot:]
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"users-api/domain"
+
+	"shared/apperrors"
+)
+
+// memoryStore es un bucket en memoria
+type memoryStore struct {
+	objects map[string][]byte
+}
+
+func (m *memoryStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryStore) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func pngAvatar(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 300, 300))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Test: subir un avatar guarda foto y miniatura, y el siguiente borra los anteriores
+func TestAvatarUpload(t *testing.T) {
+	repo := newMockUserRepository()
+	repo.users[1] = &domain.User{ID: 1, Username: "ana"}
+	store := &memoryStore{objects: map[string][]byte{}}
+	service := NewAvatarService(repo, store)
+
+	first, err := service.Upload(context.Background(), 1, pngAvatar(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	firstKey := first.AvatarKey
+	if first.AvatarURL != "https://cdn.example.com/"+firstKey || first.AvatarThumbURL != "https://cdn.example.com/"+avatarThumbKey(firstKey) {
+		t.Errorf("Unexpected URLs %s %s", first.AvatarURL, first.AvatarThumbURL)
+	}
+	if len(store.objects) != 2 {
+		t.Fatalf("Expected image and thumbnail, got %d objects", len(store.objects))
+	}
+
+	second, err := service.Upload(context.Background(), 1, pngAvatar(t))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second.AvatarKey == firstKey {
+		t.Error("Expected a new key for the new avatar")
+	}
+	if _, ok := store.objects[firstKey]; ok || len(store.objects) != 2 {
+		t.Errorf("Expected the previous avatar to be deleted, got %d objects", len(store.objects))
+	}
+}
+
+// Test: archivos que no son imágenes y servicio sin bucket
+func TestAvatarUpload_Rejected(t *testing.T) {
+	repo := newMockUserRepository()
+	repo.users[1] = &domain.User{ID: 1, Username: "ana"}
+
+	service := NewAvatarService(repo, &memoryStore{objects: map[string][]byte{}})
+	if _, err := service.Upload(context.Background(), 1, []byte("GIF89a")); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	disabled := NewAvatarService(repo, nil)
+	if _, err := disabled.Upload(context.Background(), 1, pngAvatar(t)); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found without a bucket, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+	"users-api/repositories"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// BanService suspende cuentas por un tiempo (abuso, spam)
+// A diferencia de la desactivación, la suspensión vence sola y corta también
+// las sesiones abiertas
+type BanService interface {
+	Ban(ctx context.Context, id uint, until time.Time) (*domain.User, error)
+	Unban(ctx context.Context, id uint) (*domain.User, error)
+}
+
+// banService es la implementación real del servicio
+type banService struct {
+	users  repositories.UserRepository
+	tokens repositories.TokenRepository
+}
+
+// NewBanService crea una nueva instancia del servicio
+func NewBanService(users repositories.UserRepository, tokens repositories.TokenRepository) BanService {
+	return &banService{users: users, tokens: tokens}
+}
+
+// Ban suspende la cuenta hasta until y revoca todos sus tokens: el
+// middleware de auth los rechaza desde ya, aunque no hayan vencido, y
+// mientras dure la suspensión no se pueden pedir nuevos (ver loginAllowed)
+func (s *banService) Ban(ctx context.Context, id uint, until time.Time) (*domain.User, error) {
+	now := time.Now()
+	if !until.After(now) {
+		return nil, apperrors.Validation("banned_until must be in the future")
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserType == domain.UserTypeAdmin {
+		return nil, apperrors.Forbidden("admins cannot be banned")
+	}
+
+	user.BannedUntil = &until
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RevokeAll(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+
+	requestid.Logf(ctx, "🚫 Usuario %d suspendido hasta %s", user.ID, until.UTC().Format(time.RFC3339))
+	return user, nil
+}
+
+// Unban levanta la suspensión antes de que venza
+// Los tokens revocados por el ban siguen revocados: el usuario vuelve a
+// iniciar sesión
+func (s *banService) Unban(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.BannedUntil == nil {
+		return user, nil
+	}
+
+	user.BannedUntil = nil
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	requestid.Logf(ctx, "✅ Suspensión del usuario %d levantada", user.ID)
+	return user, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// Test: el ban revoca los tokens abiertos y bloquea el login hasta que se levanta
+func TestBan_RevokesTokensAndBlocksLogin(t *testing.T) {
+	ctx := context.Background()
+	users := newMockUserRepository()
+	userService := newTestUserService(users)
+	userService.CreateUser(ctx, dto.CreateUserRequest{
+		Username: "ana", Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Pérez",
+	})
+	tokens := newMockTokenRepository()
+	tokenService := NewTokenService(users, tokens)
+	service := NewBanService(users, tokens)
+
+	token, err := utils.GenerateToken(utils.TokenUser{ID: 1, Username: "ana", UserType: "normal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := service.Ban(ctx, 1, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !user.Banned(time.Now()) {
+		t.Error("Expected the user to be banned")
+	}
+	if revoked, _ := tokenService.TokenRevoked(ctx, claims); !revoked {
+		t.Error("Expected the open token to be revoked")
+	}
+
+	login := dto.LoginRequest{UsernameOrEmail: "ana", Password: "password123"}
+	if _, err := userService.Login(ctx, login); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden while banned, got %v", err)
+	}
+
+	if _, err := service.Unban(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := userService.Login(ctx, login); err != nil {
+		t.Errorf("Expected login after the unban, got %v", err)
+	}
+}
+
+// Test: la fecha tiene que ser futura, los admins no se suspenden y un ban vencido ya no cuenta
+func TestBan_Rejected(t *testing.T) {
+	ctx := context.Background()
+	users := newMockUserRepository()
+	users.users[1] = &domain.User{ID: 1, Username: "ana"}
+	users.users[2] = &domain.User{ID: 2, Username: "root", UserType: domain.UserTypeAdmin}
+	service := NewBanService(users, newMockTokenRepository())
+
+	if _, err := service.Ban(ctx, 1, time.Now().Add(-time.Minute)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for a past date, got %v", err)
+	}
+	if _, err := service.Ban(ctx, 2, time.Now().Add(time.Hour)); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden for an admin, got %v", err)
+	}
+
+	expired := time.Now().Add(-time.Hour)
+	users.users[1].BannedUntil = &expired
+	if users.users[1].Banned(time.Now()) {
+		t.Error("Expected an expired ban not to count")
+	}
+}
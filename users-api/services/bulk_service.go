@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+
+	"shared/apperrors"
+	"shared/audit"
+	"shared/requestid"
+)
+
+// MaxBulkUsers es el máximo de usuarios por operación masiva
+const MaxBulkUsers = 1000
+
+// bulkProgressEvery es cada cuántos usuarios se guarda el progreso del job
+const bulkProgressEvery = 50
+
+// BulkService ejecuta operaciones masivas sobre usuarios en segundo plano
+type BulkService interface {
+	Start(ctx context.Context, adminID uint, adminName string, req dto.BulkUsersRequest) (*domain.BulkJob, error)
+	GetJob(ctx context.Context, id uint) (*domain.BulkJob, error)
+}
+
+// bulkService es la implementación real del servicio
+type bulkService struct {
+	users   UserService
+	repo    repositories.UserRepository
+	jobs    repositories.BulkJobRepository
+	auditor audit.Emitter
+
+	wg sync.WaitGroup // jobs corriendo (los tests esperan a que terminen)
+}
+
+// NewBulkService crea una nueva instancia del servicio
+// Cada usuario se procesa con UserService, con las mismas validaciones que
+// las rutas de a uno, y queda auditado como un cambio de admin
+func NewBulkService(users UserService, repo repositories.UserRepository, jobs repositories.BulkJobRepository, auditor audit.Emitter) BulkService {
+	return &bulkService{users: users, repo: repo, jobs: jobs, auditor: auditor}
+}
+
+// Start valida el pedido, resuelve los usuarios y arranca el job
+// La lista de usuarios se fija acá: los que cumplan el filtro después no entran
+// Devuelve el job en "pending"; el progreso se consulta con GetJob
+func (s *bulkService) Start(ctx context.Context, adminID uint, adminName string, req dto.BulkUsersRequest) (*domain.BulkJob, error) {
+	ids, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.BulkJob{
+		Action:    req.Action,
+		Role:      domain.UserType(req.Role),
+		CreatedBy: adminID,
+		Status:    domain.BulkJobPending,
+		Total:     len(ids),
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	started := *job
+
+	// El job sigue aunque la request termine, con su request ID para los logs
+	runCtx := context.WithoutCancel(ctx)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(runCtx, job, ids, adminID, adminName)
+	}()
+
+	return &started, nil
+}
+
+// GetJob devuelve el estado de un job
+func (s *bulkService) GetJob(ctx context.Context, id uint) (*domain.BulkJob, error) {
+	return s.jobs.GetByID(ctx, id)
+}
+
+// resolve devuelve los IDs a procesar (sin repetidos)
+func (s *bulkService) resolve(ctx context.Context, req dto.BulkUsersRequest) ([]uint, error) {
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return nil, apperrors.BadRequest("exactly one of ids or filter is required")
+	}
+
+	if req.Filter == nil {
+		seen := make(map[uint]bool, len(req.IDs))
+		ids := make([]uint, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	filter := repositories.UserFilter{
+		UserType:    domain.UserType(req.Filter.UserType),
+		Deactivated: req.Filter.Deactivated,
+	}
+	if req.Filter.CreatedAfter != nil {
+		filter.CreatedAfter = *req.Filter.CreatedAfter
+	}
+	if req.Filter.CreatedBefore != nil {
+		filter.CreatedBefore = *req.Filter.CreatedBefore
+	}
+	if filter.Empty() {
+		return nil, apperrors.BadRequest("filter needs at least one criterion")
+	}
+
+	// Se pide uno más que el máximo para saber si se pasa
+	ids, err := s.repo.FindIDs(ctx, filter, MaxBulkUsers+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > MaxBulkUsers {
+		return nil, apperrors.Newf(apperrors.CodeBadRequest, "filter matches more than %d users, narrow it down", MaxBulkUsers)
+	}
+	return ids, nil
+}
+
+// run procesa los usuarios de a uno y guarda el progreso cada bulkProgressEvery
+// Un usuario que falla (no existe, es el propio admin...) no corta el job:
+// queda en Errors y se sigue con el resto
+func (s *bulkService) run(ctx context.Context, job *domain.BulkJob, ids []uint, adminID uint, adminName string) {
+	job.Status = domain.BulkJobRunning
+	s.save(ctx, job)
+	requestid.Logf(ctx, "📦 Job masivo %d: %s sobre %d usuarios", job.ID, job.Action, job.Total)
+
+	for i, id := range ids {
+		if err := s.apply(ctx, job, id, adminID); err != nil {
+			job.Failed++
+			if len(job.Errors) < domain.MaxBulkJobErrors {
+				job.Errors = append(job.Errors, domain.BulkJobError{UserID: id, Error: err.Error()})
+			}
+		} else {
+			s.audit(ctx, job, id, adminID, adminName)
+		}
+		job.Processed++
+
+		if (i+1)%bulkProgressEvery == 0 {
+			s.save(ctx, job)
+		}
+	}
+
+	now := time.Now()
+	job.Status = domain.BulkJobDone
+	job.FinishedAt = &now
+	s.save(ctx, job)
+	requestid.Logf(ctx, "✅ Job masivo %d terminado: %d procesados, %d fallaron", job.ID, job.Processed, job.Failed)
+}
+
+// apply aplica la acción del job a un usuario
+// El admin no puede desactivarse, borrarse ni cambiarse el rol a sí mismo
+func (s *bulkService) apply(ctx context.Context, job *domain.BulkJob, id, adminID uint) error {
+	if id == adminID {
+		return apperrors.BadRequest("cannot apply a bulk operation to yourself")
+	}
+
+	var err error
+	switch job.Action {
+	case domain.BulkActionDeactivate:
+		_, err = s.users.SetActive(ctx, id, false)
+	case domain.BulkActionActivate:
+		_, err = s.users.SetActive(ctx, id, true)
+	case domain.BulkActionDelete:
+		err = s.users.DeleteUser(ctx, id)
+	case domain.BulkActionSetRole:
+		_, err = s.users.SetUserType(ctx, id, job.Role)
+	default:
+		err = apperrors.Newf(apperrors.CodeBadRequest, "unknown bulk action: %s", job.Action)
+	}
+	return err
+}
+
+// audit emite el mismo evento que la ruta de a uno, con el job en metadata
+func (s *bulkService) audit(ctx context.Context, job *domain.BulkJob, id, adminID uint, adminName string) {
+	action := audit.ActionAdminUserUpdated
+	if job.Action == domain.BulkActionDelete {
+		action = audit.ActionAdminUserDeleted
+	}
+	s.auditor.Emit(ctx, audit.Event{
+		Action:     action,
+		Outcome:    audit.OutcomeSuccess,
+		ActorID:    &adminID,
+		ActorName:  adminName,
+		TargetType: "user",
+		TargetID:   fmt.Sprint(id),
+		Metadata:   map[string]interface{}{"bulk_job_id": job.ID, "bulk_action": job.Action},
+	})
+}
+
+// save guarda el progreso; si falla solo se loguea (el job sigue)
+func (s *bulkService) save(ctx context.Context, job *domain.BulkJob) {
+	if err := s.jobs.Update(ctx, job); err != nil {
+		requestid.Logf(ctx, "⚠️  No se pudo guardar el progreso del job masivo %d: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"users-api/domain"
+	"users-api/dto"
+
+	"shared/apperrors"
+	"shared/audit"
+)
+
+// ============================================
+// MOCK del repositorio de jobs masivos
+// ============================================
+type mockBulkJobRepository struct {
+	jobs map[uint]domain.BulkJob
+}
+
+func (m *mockBulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	job.ID = uint(len(m.jobs) + 1)
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *mockBulkJobRepository) Update(ctx context.Context, job *domain.BulkJob) error {
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *mockBulkJobRepository) GetByID(ctx context.Context, id uint) (*domain.BulkJob, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, apperrors.NotFound("bulk job not found")
+	}
+	return &job, nil
+}
+
+// mockEmitter guarda los eventos de auditoría
+type mockEmitter struct {
+	events []audit.Event
+}
+
+func (m *mockEmitter) Emit(ctx context.Context, event audit.Event) {
+	m.events = append(m.events, event)
+}
+
+// newTestBulkService arma el servicio con el admin 1 y los usuarios 2 a 4
+func newTestBulkService() (*bulkService, *mockUserRepository, *mockEmitter) {
+	repo := newMockUserRepository()
+	repo.users[1] = &domain.User{ID: 1, Username: "root", UserType: domain.UserTypeAdmin}
+	for id := uint(2); id <= 4; id++ {
+		repo.users[id] = &domain.User{ID: id, UserType: domain.UserTypeNormal}
+	}
+	emitter := &mockEmitter{}
+	service := NewBulkService(newTestUserService(repo), repo, &mockBulkJobRepository{jobs: map[uint]domain.BulkJob{}}, emitter)
+	return service.(*bulkService), repo, emitter
+}
+
+// Test: desactivar por IDs; los que fallan quedan en el job y no cortan el resto
+func TestBulk_DeactivateByIDs(t *testing.T) {
+	service, repo, emitter := newTestBulkService()
+
+	job, err := service.Start(context.Background(), 1, "root", dto.BulkUsersRequest{
+		Action: domain.BulkActionDeactivate,
+		IDs:    []uint{2, 3, 3, 1, 99},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if job.Status != domain.BulkJobPending || job.Total != 4 {
+		t.Errorf("Expected a pending job over 4 users, got %+v", job)
+	}
+	service.wg.Wait()
+
+	done, _ := service.GetJob(context.Background(), job.ID)
+	if done.Status != domain.BulkJobDone || done.Processed != 4 || done.Failed != 2 || done.FinishedAt == nil {
+		t.Errorf("Unexpected final job: %+v", done)
+	}
+	if len(done.Errors) != 2 || done.Errors[0].UserID != 1 || done.Errors[1].UserID != 99 {
+		t.Errorf("Expected errors for the admin and the missing user, got %+v", done.Errors)
+	}
+	if repo.users[2].Active() || repo.users[3].Active() || !repo.users[4].Active() || !repo.users[1].Active() {
+		t.Error("Expected only users 2 and 3 deactivated")
+	}
+	if len(emitter.events) != 2 || emitter.events[0].Metadata["bulk_job_id"] != job.ID {
+		t.Errorf("Expected one audit event per changed user, got %+v", emitter.events)
+	}
+}
+
+// Test: cambiar el rol por filtro
+func TestBulk_SetRoleByFilter(t *testing.T) {
+	service, repo, _ := newTestBulkService()
+
+	job, err := service.Start(context.Background(), 1, "root", dto.BulkUsersRequest{
+		Action: domain.BulkActionSetRole,
+		Role:   "admin",
+		Filter: &dto.BulkUserFilter{UserType: "normal"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	service.wg.Wait()
+
+	if job.Total != 3 {
+		t.Errorf("Expected 3 users matched, got %d", job.Total)
+	}
+	for id := uint(2); id <= 4; id++ {
+		if repo.users[id].UserType != domain.UserTypeAdmin {
+			t.Errorf("Expected user %d to be admin", id)
+		}
+	}
+}
+
+// Test: pedidos inválidos se rechazan antes de crear el job
+func TestBulk_InvalidRequests(t *testing.T) {
+	service, _, _ := newTestBulkService()
+
+	for name, req := range map[string]dto.BulkUsersRequest{
+		"no target":    {Action: domain.BulkActionDelete},
+		"both targets": {Action: domain.BulkActionDelete, IDs: []uint{2}, Filter: &dto.BulkUserFilter{UserType: "normal"}},
+		"empty filter": {Action: domain.BulkActionDelete, Filter: &dto.BulkUserFilter{}},
+	} {
+		if _, err := service.Start(context.Background(), 1, "root", req); !errors.Is(err, apperrors.ErrBadRequest) {
+			t.Errorf("%s: expected bad request, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// externalIdentity es la cuenta con la que se entra desde un login externo
+// (Google o un proveedor OIDC), ya validada por su verificador
+type externalIdentity struct {
+	Provider      string // users.provider (ej: "google", "keycloak")
+	Subject       string // users.provider_id
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// externalLogin da el mismo JWT que el login a una cuenta externa
+//  1. Si la cuenta externa ya está asociada a un usuario, entra con ese
+//  2. Si no, y el proveedor verificó el email, la asocia al usuario con ese
+//     email o crea uno nuevo (sin contraseña; no en modo invite_only)
+//
+// Un email sin verificar no se usa para buscar ni crear cuentas: cualquiera
+// podría crear una cuenta externa con el email de otro. unverified es el
+// mensaje de ese error (cada proveedor tiene el suyo)
+func externalLogin(ctx context.Context, users repositories.UserRepository, registration RegistrationConfig, identity externalIdentity, unverified string) (*dto.LoginResponse, error) {
+	user, err := users.GetByProvider(ctx, identity.Provider, identity.Subject)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		if !identity.EmailVerified || identity.Email == "" {
+			return nil, apperrors.Unauthorized(unverified)
+		}
+		user, err = provisionExternal(ctx, users, registration, identity)
+	}
+	if err == nil && user.MergedInto != nil {
+		// La cuenta externa quedó en una cuenta fusionada: se entra a la que quedó
+		user, err = users.GetByID(ctx, *user.MergedInto)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loginAllowed(user); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateToken(tokenUser(user))
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating token", err)
+	}
+	return &dto.LoginResponse{Token: token, User: dto.NewUserResponse(user)}, nil
+}
+
+// provisionExternal asocia la cuenta externa al usuario con el mismo email o crea uno nuevo
+func provisionExternal(ctx context.Context, users repositories.UserRepository, registration RegistrationConfig, identity externalIdentity) (*domain.User, error) {
+	subject := identity.Subject
+
+	user, err := users.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		// Solo se asocia con el mismo email exacto: con plus addressing,
+		// ana+x@dominio.com no prueba ser dueña de ana@dominio.com
+		if utils.CleanEmail(user.Email) != utils.CleanEmail(identity.Email) {
+			return nil, apperrors.Conflict("email already exists")
+		}
+		if user.Provider != "" {
+			// Ya tiene otra cuenta externa: no se reemplaza sin que la pida el usuario
+			return nil, apperrors.Conflict("the account is linked to another external login")
+		}
+		user.Provider = identity.Provider
+		user.ProviderID = &subject
+		if err := users.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		requestid.Logf(ctx, "🔗 Cuenta de %s asociada al usuario %d", identity.Provider, user.ID)
+		return user, nil
+	case !errors.Is(err, apperrors.ErrNotFound):
+		return nil, err
+	}
+
+	// Con registro por invitación, los logins externos no crean cuentas: el
+	// invitado se registra con el código y después puede asociar la cuenta
+	// externa con el mismo email
+	if registration.InviteOnly {
+		return nil, apperrors.Forbidden("registration requires an invitation code")
+	}
+
+	username, err := freeUsername(ctx, users, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	user = &domain.User{
+		Username:   username,
+		Email:      utils.CleanEmail(identity.Email),
+		FirstName:  identity.FirstName,
+		LastName:   identity.LastName,
+		UserType:   domain.UserTypeNormal,
+		Provider:   identity.Provider,
+		ProviderID: &subject,
+	}
+	if err := users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	requestid.Logf(ctx, "✅ Usuario %d creado desde %s", user.ID, identity.Provider)
+	return user, nil
+}
+
+// freeUsername arma un username a partir del email ("ana.perez@gmail.com" =>
+// "ana.perez") y, si ya existe, le agrega un sufijo al azar
+func freeUsername(ctx context.Context, users repositories.UserRepository, email string) (string, error) {
+	base := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-') {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, strings.SplitN(email, "@", 2)[0])
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		_, err := users.GetByUsername(ctx, candidate)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", apperrors.Wrap(apperrors.CodeInternal, "error generating username", err)
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", apperrors.Conflict("username already exists")
+}
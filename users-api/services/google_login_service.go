@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// GoogleVerifier valida el ID token que el frontend recibe de Google Sign-In
+// (lo implementa utils.GoogleVerifier; los tests usan uno falso)
+type GoogleVerifier interface {
+	Verify(idToken string) (*utils.GoogleIdentity, error)
+}
+
+// GoogleLoginService maneja el login con una cuenta de Google
+type GoogleLoginService interface {
+	Login(ctx context.Context, idToken string) (*dto.LoginResponse, error)
+}
+
+// googleLoginService es la implementación real del servicio
+type googleLoginService struct {
+	users        repositories.UserRepository
+	verifier     GoogleVerifier // nil = login con Google apagado
+	registration RegistrationConfig
+}
+
+// NewGoogleLoginService crea una nueva instancia del servicio
+// Con verifier nil (sin GOOGLE_CLIENT_IDS) el login con Google responde 404
+func NewGoogleLoginService(users repositories.UserRepository, verifier GoogleVerifier, registration RegistrationConfig) GoogleLoginService {
+	return &googleLoginService{users: users, verifier: verifier, registration: registration}
+}
+
+// Login canjea un ID token de Google por el mismo JWT que da el login
+// (asocia o crea la cuenta como cualquier login externo, ver externalLogin)
+func (s *googleLoginService) Login(ctx context.Context, idToken string) (*dto.LoginResponse, error) {
+	if s.verifier == nil {
+		return nil, apperrors.NotFound("Google login is not enabled")
+	}
+
+	identity, err := s.verifier.Verify(idToken)
+	if err != nil {
+		requestid.Logf(ctx, "⚠️  ID token de Google rechazado: %v", err)
+		return nil, apperrors.Unauthorized("invalid Google token")
+	}
+
+	return externalLogin(ctx, s.users, s.registration, externalIdentity{
+		Provider:      domain.ProviderGoogle,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		FirstName:     identity.FirstName,
+		LastName:      identity.LastName,
+	}, "the Google account email is not verified")
+}
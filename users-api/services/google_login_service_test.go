@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// fakeGoogleVerifier acepta como ID token la clave de identities
+type fakeGoogleVerifier struct {
+	identities map[string]*utils.GoogleIdentity
+}
+
+func (f *fakeGoogleVerifier) Verify(idToken string) (*utils.GoogleIdentity, error) {
+	identity, ok := f.identities[idToken]
+	if !ok {
+		return nil, utils.ErrInvalidGoogleToken
+	}
+	return identity, nil
+}
+
+func newGoogleTestService(users *mockUserRepository) GoogleLoginService {
+	return NewGoogleLoginService(users, &fakeGoogleVerifier{identities: map[string]*utils.GoogleIdentity{
+		"ana":        {Subject: "g-1", Email: "ana.perez@gmail.com", EmailVerified: true, FirstName: "Ana", LastName: "Pérez"},
+		"unverified": {Subject: "g-2", Email: "test@example.com"},
+	}}, RegistrationConfig{})
+}
+
+// Test: la primera vez se crea el usuario sin contraseña; la segunda entra con el mismo
+func TestGoogleLogin_ProvisionsUser(t *testing.T) {
+	users := newMockUserRepository()
+	service := newGoogleTestService(users)
+
+	response, err := service.Login(context.Background(), "ana")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	user := users.users[response.User.ID]
+	if response.Token == "" || user.Username != "ana.perez" || user.FirstName != "Ana" || user.HasPassword() || response.User.HasPassword {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if user.Provider != domain.ProviderGoogle || user.ProviderID == nil || *user.ProviderID != "g-1" {
+		t.Errorf("Expected the Google account to be linked, got %+v", user)
+	}
+
+	again, err := service.Login(context.Background(), "ana")
+	if err != nil || again.User.ID != user.ID || len(users.users) != 1 {
+		t.Errorf("Expected the same user on the second login, got %+v, %v", again, err)
+	}
+
+	// Sin contraseña no se puede entrar con el login común
+	if _, err := newTestUserService(users).Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "ana.perez", Password: ""}); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected password login to fail, got %v", err)
+	}
+}
+
+// Test: con el email verificado se asocia al usuario que ya existe
+func TestGoogleLogin_LinksExistingUser(t *testing.T) {
+	users := newMockUserRepository()
+	newTestUserService(users).CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "ana.perez", Email: "otra@example.com", Password: "password123", FirstName: "Otra", LastName: "Ana",
+	})
+	newTestUserService(users).CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "anap", Email: "ana.perez@gmail.com", Password: "password123", FirstName: "Ana", LastName: "Pérez",
+	})
+	service := newGoogleTestService(users)
+
+	response, err := service.Login(context.Background(), "ana")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.User.ID != 2 || response.User.Provider != domain.ProviderGoogle || !response.User.HasPassword {
+		t.Errorf("Expected the existing user linked and keeping its password, got %+v", response.User)
+	}
+	if len(users.users) != 2 {
+		t.Errorf("Expected no new user, got %d users", len(users.users))
+	}
+}
+
+// Test: un email sin verificar no crea cuentas, y sin configuración el login no existe
+func TestGoogleLogin_Rejections(t *testing.T) {
+	service := newGoogleTestService(newMockUserRepository())
+
+	if _, err := service.Login(context.Background(), "unverified"); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected unauthorized for an unverified email, got %v", err)
+	}
+	if _, err := service.Login(context.Background(), "forged"); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected unauthorized for an invalid token, got %v", err)
+	}
+	if _, err := NewGoogleLoginService(newMockUserRepository(), nil, RegistrationConfig{}).Login(context.Background(), "ana"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found when Google login is off, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// ImpersonationService genera los tokens con los que un admin actúa como
+// otro usuario para reproducir problemas de soporte
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, adminID, targetID uint) (*dto.ImpersonationResponse, error)
+}
+
+// impersonationService es la implementación real del servicio
+type impersonationService struct {
+	users repositories.UserRepository
+	ttl   time.Duration
+}
+
+// NewImpersonationService crea una nueva instancia del servicio
+// ttl es cuánto vale cada token (corto: no se puede renovar)
+func NewImpersonationService(users repositories.UserRepository, ttl time.Duration) ImpersonationService {
+	return &impersonationService{users: users, ttl: ttl}
+}
+
+// Impersonate genera un token que actúa como targetID
+// El token tiene los permisos del usuario (nunca de admin: los admins no se
+// pueden impersonar) y lleva adminID en impersonator_id, así todo lo que se
+// haga con él queda auditado a nombre del admin
+func (s *impersonationService) Impersonate(ctx context.Context, adminID, targetID uint) (*dto.ImpersonationResponse, error) {
+	if adminID == targetID {
+		return nil, apperrors.BadRequest("cannot impersonate yourself")
+	}
+
+	user, err := s.users.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserType == domain.UserTypeAdmin {
+		return nil, apperrors.Forbidden("admins cannot be impersonated")
+	}
+
+	token, expiresAt, err := utils.GenerateImpersonationToken(tokenUser(user), adminID, s.ttl)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating token", err)
+	}
+
+	requestid.Logf(ctx, "🎭 Admin %d impersona al usuario %d hasta %s", adminID, user.ID, expiresAt.Format(time.RFC3339))
+	return &dto.ImpersonationResponse{Token: token, ExpiresAt: expiresAt, User: dto.NewUserResponse(user)}, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// Test: el token actúa como el usuario, lleva al admin y vence pronto
+func TestImpersonate_Success(t *testing.T) {
+	repo := newMockUserRepository()
+	repo.users[2] = &domain.User{ID: 2, Username: "jdoe", UserType: domain.UserTypeNormal}
+	service := NewImpersonationService(repo, 15*time.Minute)
+
+	response, err := service.Impersonate(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	claims, err := utils.ValidateToken(response.Token)
+	if err != nil {
+		t.Fatalf("Expected a valid token, got %v", err)
+	}
+	if claims.UserID != 2 || claims.Username != "jdoe" || claims.IsAdmin() {
+		t.Errorf("Expected a token for jdoe, got %+v", claims)
+	}
+	if !claims.Impersonated() || *claims.ImpersonatorID != 1 {
+		t.Errorf("Expected impersonator 1, got %v", claims.ImpersonatorID)
+	}
+	if remaining := time.Until(claims.ExpiresAt.Time); remaining > 15*time.Minute || remaining < 14*time.Minute {
+		t.Errorf("Expected the token to expire in 15m, got %s", remaining)
+	}
+}
+
+// Test: no se puede impersonar a un admin ni a uno mismo
+func TestImpersonate_Rejected(t *testing.T) {
+	repo := newMockUserRepository()
+	repo.users[1] = &domain.User{ID: 1, Username: "root", UserType: domain.UserTypeAdmin}
+	repo.users[3] = &domain.User{ID: 3, Username: "other-admin", UserType: domain.UserTypeAdmin}
+	service := NewImpersonationService(repo, 15*time.Minute)
+
+	if _, err := service.Impersonate(context.Background(), 1, 1); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("Expected bad request impersonating yourself, got %v", err)
+	}
+	if _, err := service.Impersonate(context.Background(), 1, 3); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden impersonating an admin, got %v", err)
+	}
+	if _, err := service.Impersonate(context.Background(), 1, 99); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
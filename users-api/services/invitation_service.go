@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// Modos de registro (REGISTRATION_MODE)
+const (
+	RegistrationOpen       = "open"        // cualquiera se registra
+	RegistrationInviteOnly = "invite_only" // hace falta un código de invitación
+)
+
+// RegistrationConfig es quién puede crear una cuenta nueva
+// Con InviteOnly, POST /users pide invitation_code y el login con Google
+// solo entra a cuentas que ya existen
+type RegistrationConfig struct {
+	InviteOnly bool
+}
+
+// InvitationService genera las invitaciones a registrarse
+// El código se canjea en el alta de usuario (ver UserService.CreateUser)
+type InvitationService interface {
+	Create(ctx context.Context, adminID uint, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error)
+}
+
+// InvitationConfig son los parámetros de las invitaciones
+type InvitationConfig struct {
+	URL string        // página de registro del frontend que recibe ?code= (ej: http://localhost:3000/register)
+	TTL time.Duration // cuánto vale una invitación si el admin no dice otra cosa
+}
+
+// invitationService es la implementación real del servicio
+type invitationService struct {
+	users       repositories.UserRepository
+	invitations repositories.InvitationRepository
+	publisher   queue.EventPublisher
+	cfg         InvitationConfig
+}
+
+// NewInvitationService crea una nueva instancia del servicio
+func NewInvitationService(users repositories.UserRepository, invitations repositories.InvitationRepository, publisher queue.EventPublisher, cfg InvitationConfig) InvitationService {
+	return &invitationService{users: users, invitations: invitations, publisher: publisher, cfg: cfg}
+}
+
+// Create guarda la invitación y publica "user.invitation" para que
+// notifications-api mande el email con el código
+// Si el evento no se puede publicar la invitación vale igual: el código
+// vuelve en la respuesta para que el admin lo pase a mano
+func (s *invitationService) Create(ctx context.Context, adminID uint, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error) {
+	email := utils.CleanEmail(req.Email)
+	_, err := s.users.GetByEmail(ctx, email)
+	if err == nil {
+		return nil, apperrors.Conflict("email already exists")
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
+	userType := domain.UserTypeNormal
+	if req.UserType != "" {
+		userType = domain.UserType(req.UserType)
+	}
+	ttl := s.cfg.TTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	// 1. Código aleatorio: va en el email, en la base solo su hash
+	code, err := newInvitationCode()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating invitation code", err)
+	}
+	invitation := &domain.Invitation{
+		CodeHash:  hashInvitationCode(code),
+		Email:     email,
+		UserType:  userType,
+		CreatedBy: adminID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.invitations.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	// 2. El email lo manda notifications-api
+	link, err := frontendURL(s.cfg.URL, "code", code)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "invalid INVITATION_URL", err)
+	}
+	err = s.publisher.Publish(ctx, "user.invitation", map[string]interface{}{
+		"email":            invitation.Email,
+		"code":             code,
+		"url":              link,
+		"user_type":        string(invitation.UserType),
+		"expires_in_hours": int(ttl.Hours()),
+		"expires_at":       invitation.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		requestid.Logf(ctx, "⚠️  No se pudo publicar la invitación %d: %v", invitation.ID, err)
+	}
+
+	requestid.Logf(ctx, "✉️  Invitación %d creada por el admin %d", invitation.ID, adminID)
+	return &dto.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		UserType:  string(invitation.UserType),
+		Code:      code,
+		URL:       link,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// redeemInvitation valida el código de un alta dentro de la transacción
+// Devuelve la invitación para marcarla usada cuando el usuario ya existe
+func redeemInvitation(ctx context.Context, tx repositories.Tx, code, email string) (*domain.Invitation, error) {
+	invitation, err := tx.Invitations.GetByCodeHash(ctx, hashInvitationCode(code))
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, apperrors.Validation("invalid or expired invitation code")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !invitation.Usable(time.Now()) {
+		return nil, apperrors.Validation("invalid or expired invitation code")
+	}
+	if utils.NormalizeEmail(invitation.Email) != utils.NormalizeEmail(email) {
+		return nil, apperrors.Validation("the invitation was sent to another email")
+	}
+	return invitation, nil
+}
+
+// newInvitationCode genera 10 bytes aleatorios en base32 (16 caracteres
+// en mayúsculas, fáciles de copiar desde un email)
+func newInvitationCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+// hashInvitationCode es lo que se guarda y se busca en la base
+// El código se compara sin espacios y en mayúsculas
+func hashInvitationCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// ============================================
+// MOCK del repositorio de invitaciones
+// ============================================
+type mockInvitationRepository struct {
+	invitations map[uint]*domain.Invitation
+}
+
+func newMockInvitationRepository() *mockInvitationRepository {
+	return &mockInvitationRepository{invitations: make(map[uint]*domain.Invitation)}
+}
+
+func (m *mockInvitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	invitation.ID = uint(len(m.invitations) + 1)
+	m.invitations[invitation.ID] = invitation
+	return nil
+}
+
+func (m *mockInvitationRepository) GetByCodeHash(ctx context.Context, codeHash string) (*domain.Invitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.CodeHash == codeHash {
+			return invitation, nil
+		}
+	}
+	return nil, apperrors.NotFound("invitation not found")
+}
+
+func (m *mockInvitationRepository) MarkUsed(ctx context.Context, id, userID uint, now time.Time) error {
+	invitation, ok := m.invitations[id]
+	if !ok || !invitation.Usable(now) {
+		return apperrors.Conflict("invitation code was already used")
+	}
+	invitation.UsedAt = &now
+	invitation.UsedBy = &userID
+	return nil
+}
+
+// invitationTest son los servicios de invitación y de alta (en modo
+// invite_only) sobre los mismos repositorios en memoria
+type invitationTest struct {
+	service     InvitationService
+	users       UserService
+	userRepo    *mockUserRepository
+	invitations *mockInvitationRepository
+	publisher   *mockPublisher
+}
+
+func newInvitationTest() *invitationTest {
+	it := &invitationTest{
+		userRepo:    newMockUserRepository(),
+		invitations: newMockInvitationRepository(),
+		publisher:   &mockPublisher{},
+	}
+	it.service = NewInvitationService(it.userRepo, it.invitations, it.publisher, InvitationConfig{
+		URL: "http://localhost:3000/register",
+		TTL: 7 * 24 * time.Hour,
+	})
+	uow := &memoryUnitOfWork{users: it.userRepo, prefs: newMockPreferencesRepository(), invitations: it.invitations}
+	it.users = NewUserService(it.userRepo, uow, RegistrationConfig{InviteOnly: true})
+	return it
+}
+
+func invitedUser(email, code string) dto.CreateUserRequest {
+	return dto.CreateUserRequest{
+		Username: "ana", Email: email, Password: "password123", FirstName: "Ana", LastName: "Pérez", InvitationCode: code,
+	}
+}
+
+// Test: el código va por email y registra al invitado con el rol de la invitación
+func TestInvitation_Register(t *testing.T) {
+	it := newInvitationTest()
+	ctx := context.Background()
+
+	invitation, err := it.service.Create(ctx, 1, dto.CreateInvitationRequest{Email: "Ana@Example.com", UserType: "admin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(it.publisher.published) != 1 || it.publisher.published[0] != "user.invitation" || it.publisher.payloads[0]["code"] != invitation.Code {
+		t.Errorf("Expected a user.invitation event with the code, got %v", it.publisher.published)
+	}
+	if invitation.URL != "http://localhost:3000/register?code="+invitation.Code {
+		t.Errorf("Unexpected URL %s", invitation.URL)
+	}
+
+	if _, err := it.users.CreateUser(ctx, invitedUser("ana@example.com", "")); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden without a code, got %v", err)
+	}
+	if _, err := it.users.CreateUser(ctx, invitedUser("otra@example.com", invitation.Code)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for another email, got %v", err)
+	}
+
+	user, err := it.users.CreateUser(ctx, invitedUser("ana@example.com", invitation.Code))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.UserType != domain.UserTypeAdmin {
+		t.Errorf("Expected the invitation role, got %s", user.UserType)
+	}
+
+	if used := it.invitations.invitations[invitation.ID]; used.UsedBy == nil || *used.UsedBy != user.ID {
+		t.Errorf("Expected the invitation used by user %d, got %v", user.ID, used.UsedBy)
+	}
+}
+
+// Test: un código desconocido o vencido no sirve y no se invita a un email registrado
+func TestInvitation_Rejected(t *testing.T) {
+	it := newInvitationTest()
+	ctx := context.Background()
+
+	if _, err := it.users.CreateUser(ctx, invitedUser("ana@example.com", "NOPE")); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an unknown code, got %v", err)
+	}
+
+	invitation, err := it.service.Create(ctx, 1, dto.CreateInvitationRequest{Email: "ana@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	it.invitations.invitations[invitation.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := it.users.CreateUser(ctx, invitedUser("ana@example.com", invitation.Code)); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for an expired code, got %v", err)
+	}
+
+	it.userRepo.Create(ctx, &domain.User{Username: "beto", Email: "beto@example.com"})
+	if _, err := it.service.Create(ctx, 1, dto.CreateInvitationRequest{Email: "BETO@example.com"}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected conflict for a registered email, got %v", err)
+	}
+}
+
+// Test: en modo invite_only el login con Google no crea cuentas
+func TestGoogleLogin_InviteOnly(t *testing.T) {
+	users := newMockUserRepository()
+	service := NewGoogleLoginService(users, &fakeGoogleVerifier{identities: map[string]*utils.GoogleIdentity{
+		"ana": {Subject: "g-1", Email: "ana.perez@gmail.com", EmailVerified: true},
+	}}, RegistrationConfig{InviteOnly: true})
+
+	if _, err := service.Login(context.Background(), "ana"); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden, got %v", err)
+	}
+	if len(users.users) != 0 {
+		t.Errorf("Expected no users created, got %d", len(users.users))
+	}
+}
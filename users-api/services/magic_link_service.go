@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// MagicLinkService maneja el login sin contraseña por email
+type MagicLinkService interface {
+	Request(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) (*dto.LoginResponse, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// MagicLinkConfig son los parámetros de los links
+type MagicLinkConfig struct {
+	URL string        // página del frontend que recibe ?token= (ej: http://localhost:3000/login/magic)
+	TTL time.Duration // cuánto vale un link
+}
+
+// magicLinkService es la implementación real del servicio
+type magicLinkService struct {
+	users     repositories.UserRepository
+	tokens    repositories.MagicLinkRepository
+	publisher queue.EventPublisher
+	cfg       MagicLinkConfig
+}
+
+// NewMagicLinkService crea una nueva instancia del servicio
+func NewMagicLinkService(users repositories.UserRepository, tokens repositories.MagicLinkRepository, publisher queue.EventPublisher, cfg MagicLinkConfig) MagicLinkService {
+	return &magicLinkService{users: users, tokens: tokens, publisher: publisher, cfg: cfg}
+}
+
+// Request genera un link de un solo uso y publica "user.magic_link" para
+// que notifications-api mande el email
+// Si el email no está registrado no hace nada y no devuelve error: la
+// respuesta no puede revelar qué emails tienen cuenta. Por lo mismo, si falla
+// el envío a una cuenta que existe solo se loguea (el usuario lo pide de nuevo)
+func (s *magicLinkService) Request(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		requestid.Logf(ctx, "ℹ️  Magic link pedido para un email no registrado, se ignora")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active() {
+		requestid.Logf(ctx, "ℹ️  Magic link pedido para la cuenta desactivada %d, se ignora", user.ID)
+		return nil
+	}
+
+	if err := s.send(ctx, user); err != nil {
+		requestid.Logf(ctx, "⚠️  No se pudo mandar el magic link del usuario %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// send guarda el token de un link nuevo y publica el evento del email
+func (s *magicLinkService) send(ctx context.Context, user *domain.User) error {
+	// 1. Token aleatorio: va en el link, en la base solo su hash
+	token, err := newMagicLinkToken()
+	if err != nil {
+		return apperrors.Wrap(apperrors.CodeInternal, "error generating login link", err)
+	}
+	expiresAt := time.Now().Add(s.cfg.TTL)
+	if err := s.tokens.Create(ctx, &domain.MagicLinkToken{
+		UserID:    user.ID,
+		TokenHash: hashMagicLinkToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return err
+	}
+
+	// 2. El email lo manda notifications-api
+	link, err := frontendURL(s.cfg.URL, "token", token)
+	if err != nil {
+		return apperrors.Wrap(apperrors.CodeInternal, "invalid MAGIC_LINK_URL", err)
+	}
+	return s.publisher.Publish(ctx, "user.magic_link", map[string]interface{}{
+		"user_id":            user.ID,
+		"email":              user.Email,
+		"first_name":         user.FirstName,
+		"locale":             user.Locale,
+		"url":                link,
+		"expires_in_minutes": int(s.cfg.TTL.Minutes()),
+		"expires_at":         expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Verify consume el link y devuelve un JWT igual al del login con contraseña
+// Un link usado, vencido o inexistente da 401
+func (s *magicLinkService) Verify(ctx context.Context, token string) (*dto.LoginResponse, error) {
+	consumed, err := s.tokens.Consume(ctx, hashMagicLinkToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(ctx, consumed.UserID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// El usuario se borró después de pedir el link
+		return nil, apperrors.Unauthorized("invalid or expired login link")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loginAllowed(user); err != nil {
+		return nil, err
+	}
+
+	jwtToken, err := utils.GenerateToken(tokenUser(user))
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating token", err)
+	}
+	return &dto.LoginResponse{Token: jwtToken, User: dto.NewUserResponse(user)}, nil
+}
+
+// PurgeExpired borra los links vencidos hace más de un día
+// Lo ejecuta el job programado "purge_magic_links"
+func (s *magicLinkService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.tokens.DeleteExpiredBefore(ctx, time.Now().Add(-24*time.Hour))
+}
+
+// frontendURL arma una URL del frontend con un parámetro más en la query
+// (ej: el token de un magic link o el código de una invitación)
+func frontendURL(base, param, value string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(param, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// newMagicLinkToken genera 32 bytes aleatorios en base64 apto para URLs
+func newMagicLinkToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashMagicLinkToken es lo que se guarda y se busca en la base
+func hashMagicLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+)
+
+// ============================================
+// MOCK del repositorio de magic links
+// ============================================
+type mockMagicLinkRepository struct {
+	tokens map[string]*domain.MagicLinkToken // por hash
+}
+
+func newMockMagicLinkRepository() *mockMagicLinkRepository {
+	return &mockMagicLinkRepository{tokens: make(map[string]*domain.MagicLinkToken)}
+}
+
+func (m *mockMagicLinkRepository) Create(ctx context.Context, token *domain.MagicLinkToken) error {
+	m.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (m *mockMagicLinkRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error) {
+	token, ok := m.tokens[tokenHash]
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, apperrors.Unauthorized("invalid or expired login link")
+	}
+	token.UsedAt = &now
+	return token, nil
+}
+
+func (m *mockMagicLinkRepository) DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for hash, token := range m.tokens {
+		if token.ExpiresAt.Before(before) {
+			delete(m.tokens, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// newMagicLinkTest arma el servicio con un usuario registrado
+func newMagicLinkTest() (MagicLinkService, *mockMagicLinkRepository, *mockPublisher) {
+	users := newMockUserRepository()
+	users.Create(context.Background(), &domain.User{Username: "maria", Email: "maria@example.com", FirstName: "María"})
+	tokens := newMockMagicLinkRepository()
+	publisher := &mockPublisher{}
+	service := NewMagicLinkService(users, tokens, publisher, MagicLinkConfig{
+		URL: "http://localhost:3000/login/magic",
+		TTL: 15 * time.Minute,
+	})
+	return service, tokens, publisher
+}
+
+// tokenFromLink saca el token de la URL publicada en el evento
+func tokenFromLink(t *testing.T, publisher *mockPublisher) string {
+	t.Helper()
+	if len(publisher.payloads) != 1 {
+		t.Fatalf("Expected 1 event, got %v", publisher.published)
+	}
+	link, err := url.Parse(publisher.payloads[0]["url"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link.Query().Get("token")
+}
+
+// ============================================
+// TESTS
+// ============================================
+
+// Test: el link sirve una sola vez y da un JWT del usuario
+func TestMagicLink_SingleUse(t *testing.T) {
+	service, tokens, publisher := newMagicLinkTest()
+
+	if err := service.Request(context.Background(), "maria@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if publisher.published[0] != "user.magic_link" {
+		t.Errorf("Expected user.magic_link, got %s", publisher.published[0])
+	}
+	token := tokenFromLink(t, publisher)
+
+	// En la base solo queda el hash
+	if _, stored := tokens.tokens[token]; stored {
+		t.Error("Expected only the token hash to be stored")
+	}
+
+	response, err := service.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Token == "" || response.User.Username != "maria" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	if _, err := service.Verify(context.Background(), token); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected unauthorized on reuse, got %v", err)
+	}
+}
+
+// Test: un email no registrado no da error ni manda nada
+func TestMagicLink_UnknownEmail(t *testing.T) {
+	service, _, publisher := newMagicLinkTest()
+
+	if err := service.Request(context.Background(), "nadie@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no events, got %v", publisher.published)
+	}
+}
+
+// Test: si falla la publicación a una cuenta que existe la respuesta es la
+// misma que para un email no registrado (no revela qué emails tienen cuenta)
+func TestMagicLink_PublishFailureLooksLikeUnknownEmail(t *testing.T) {
+	users := newMockUserRepository()
+	users.Create(context.Background(), &domain.User{Username: "maria", Email: "maria@example.com"})
+	publisher := &failingPublisher{failAt: 0}
+	service := NewMagicLinkService(users, newMockMagicLinkRepository(), publisher, MagicLinkConfig{
+		URL: "http://localhost:3000/login/magic",
+		TTL: 15 * time.Minute,
+	})
+
+	for _, email := range []string{"maria@example.com", "nadie@example.com"} {
+		if err := service.Request(context.Background(), email); err != nil {
+			t.Errorf("%s: expected no error, got %v", email, err)
+		}
+	}
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no events, got %v", publisher.published)
+	}
+}
+
+// Test: un link vencido no sirve y el job lo borra
+func TestMagicLink_Expired(t *testing.T) {
+	service, tokens, publisher := newMagicLinkTest()
+	service.Request(context.Background(), "maria@example.com")
+	token := tokenFromLink(t, publisher)
+
+	for _, stored := range tokens.tokens {
+		stored.ExpiresAt = time.Now().Add(-48 * time.Hour)
+	}
+	if _, err := service.Verify(context.Background(), token); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Expected unauthorized, got %v", err)
+	}
+
+	deleted, err := service.PurgeExpired(context.Background())
+	if err != nil || deleted != 1 {
+		t.Errorf("Expected 1 link purged, got %d (%v)", deleted, err)
+	}
+}
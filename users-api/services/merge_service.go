@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// MergeService fusiona cuentas duplicadas de la misma persona (ej: una
+// creada con login social y otra con contraseña)
+type MergeService interface {
+	Merge(ctx context.Context, adminID, survivorID, duplicateID uint) (*dto.MergeUsersResponse, error)
+}
+
+// mergeService es la implementación real del servicio
+type mergeService struct {
+	users repositories.UserRepository
+	prefs PreferencesService
+	repo  repositories.MergeRepository
+	now   func() time.Time
+}
+
+// NewMergeService crea una nueva instancia del servicio
+func NewMergeService(users repositories.UserRepository, prefs PreferencesService, repo repositories.MergeRepository) MergeService {
+	return &mergeService{users: users, prefs: prefs, repo: repo, now: time.Now}
+}
+
+// Merge fusiona duplicateID en survivorID, todo en una transacción:
+//  1. Preferencias: gana la opción más restrictiva de cada categoría (si
+//     alguna de las dos cuentas se dio de baja de un tipo de email, sigue así)
+//  2. Teléfono: si la que queda no tiene, se mueve el de la duplicada (con
+//     su verificación)
+//  3. La duplicada queda desactivada y con MergedInto
+//  4. user.merged va al outbox: los servicios dueños de propiedades,
+//     reservas y reseñas reasignan las de duplicate_id a survivor_id
+//
+// Los dispositivos y eventos de seguridad quedan en la duplicada (son su
+// historial de accesos)
+func (s *mergeService) Merge(ctx context.Context, adminID, survivorID, duplicateID uint) (*dto.MergeUsersResponse, error) {
+	if survivorID == duplicateID {
+		return nil, apperrors.BadRequest("cannot merge an account into itself")
+	}
+
+	survivor, err := s.users.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.users.GetByID(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	if survivor.MergedInto != nil || duplicate.MergedInto != nil {
+		return nil, apperrors.Conflict("account was already merged")
+	}
+	if !survivor.Active() {
+		return nil, apperrors.BadRequest("the account that stays must be active")
+	}
+	if duplicate.UserType == domain.UserTypeAdmin || duplicate.ID == adminID {
+		return nil, apperrors.BadRequest("admin accounts cannot be merged away")
+	}
+
+	// 1. Preferencias
+	survivorPrefs, err := s.prefs.GetPreferences(ctx, survivor.ID)
+	if err != nil {
+		return nil, err
+	}
+	duplicatePrefs, err := s.prefs.GetPreferences(ctx, duplicate.ID)
+	if err != nil {
+		return nil, err
+	}
+	merged := *survivorPrefs
+	merged.EmailTransactional = survivorPrefs.EmailTransactional && duplicatePrefs.EmailTransactional
+	merged.EmailMarketing = survivorPrefs.EmailMarketing && duplicatePrefs.EmailMarketing
+
+	// 2. Teléfono
+	phoneMoved := survivor.Phone == "" && duplicate.Phone != ""
+	if phoneMoved {
+		survivor.Phone, survivor.PhoneVerifiedAt = duplicate.Phone, duplicate.PhoneVerifiedAt
+		duplicate.Phone, duplicate.PhoneVerifiedAt = "", nil
+	}
+
+	// 3. Duplicada desactivada
+	now := s.now()
+	if duplicate.DeactivatedAt == nil {
+		duplicate.DeactivatedAt = &now
+	}
+	duplicate.MergedInto = &survivor.ID
+
+	// 4. Evento para el resto de los servicios
+	event := repositories.NewOutboxEvent("user.merged", map[string]interface{}{
+		"survivor_id":  survivor.ID,
+		"duplicate_id": duplicate.ID,
+		"merged_by":    adminID,
+		"merged_at":    now.UTC().Format(time.RFC3339),
+	})
+
+	err = s.repo.Merge(ctx, repositories.MergePlan{
+		Survivor:    survivor,
+		Duplicate:   duplicate,
+		Preferences: &merged,
+		Event:       event,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requestid.Logf(ctx, "🔗 Admin %d fusionó la cuenta %d en la %d", adminID, duplicate.ID, survivor.ID)
+	return &dto.MergeUsersResponse{
+		User:        dto.NewUserResponse(survivor),
+		Duplicate:   dto.NewUserResponse(duplicate),
+		Preferences: merged,
+		PhoneMoved:  phoneMoved,
+		EventID:     event.EventID,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/repositories"
+)
+
+// ============================================
+// MOCKS de la fusión y del outbox
+// ============================================
+type mockMergeRepository struct {
+	users *mockUserRepository
+	prefs *mockPreferencesRepository
+	plans []repositories.MergePlan
+}
+
+func (m *mockMergeRepository) Merge(ctx context.Context, plan repositories.MergePlan) error {
+	m.users.users[plan.Survivor.ID] = plan.Survivor
+	m.users.users[plan.Duplicate.ID] = plan.Duplicate
+	m.prefs.prefs[plan.Preferences.UserID] = plan.Preferences
+	m.plans = append(m.plans, plan)
+	return nil
+}
+
+type mockOutboxRepository struct {
+	events []domain.OutboxEvent
+}
+
+func (m *mockOutboxRepository) ListPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	var pending []domain.OutboxEvent
+	for _, event := range m.events {
+		if event.PublishedAt == nil && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (m *mockOutboxRepository) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	for i := range m.events {
+		if m.events[i].ID == id {
+			m.events[i].PublishedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// failingPublisher falla a partir de la publicación número failAt
+type failingPublisher struct {
+	mockPublisher
+	failAt int
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	if len(p.published) == p.failAt {
+		return errors.New("broker unavailable")
+	}
+	return p.mockPublisher.Publish(ctx, eventType, data)
+}
+
+func newTestMergeService() (MergeService, *mockUserRepository, *mockPreferencesRepository, *mockMergeRepository) {
+	users := newMockUserRepository()
+	prefs := newMockPreferencesRepository()
+	repo := &mockMergeRepository{users: users, prefs: prefs}
+	return NewMergeService(users, NewPreferencesService(users, prefs), repo), users, prefs, repo
+}
+
+// ============================================
+// TESTS
+// ============================================
+
+// Test: la fusión mueve el teléfono, combina preferencias y desactiva la duplicada
+func TestMerge(t *testing.T) {
+	service, users, prefs, repo := newTestMergeService()
+	verified := time.Now()
+	users.users[1] = &domain.User{ID: 1, Username: "ana", UserType: domain.UserTypeNormal}
+	users.users[2] = &domain.User{ID: 2, Username: "ana.google", UserType: domain.UserTypeNormal, Phone: "+5493511234567", PhoneVerifiedAt: &verified}
+	prefs.prefs[1] = &domain.NotificationPreferences{UserID: 1, EmailTransactional: true, EmailMarketing: true}
+	prefs.prefs[2] = &domain.NotificationPreferences{UserID: 2, EmailTransactional: true, EmailMarketing: false}
+
+	response, err := service.Merge(context.Background(), 9, 1, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !response.PhoneMoved || !users.users[1].PhoneVerified() || users.users[2].Phone != "" {
+		t.Errorf("Expected the verified phone moved, got %+v / %+v", users.users[1], users.users[2])
+	}
+	if merged := prefs.prefs[1]; !merged.EmailTransactional || merged.EmailMarketing {
+		t.Errorf("Expected the most restrictive preferences, got %+v", merged)
+	}
+	duplicate := users.users[2]
+	if duplicate.Active() || duplicate.MergedInto == nil || *duplicate.MergedInto != 1 {
+		t.Errorf("Expected the duplicate deactivated and merged into 1, got %+v", duplicate)
+	}
+	event := repo.plans[0].Event
+	if event.Type != "user.merged" || event.Data["survivor_id"] != uint(1) || event.Data["duplicate_id"] != uint(2) || response.EventID != event.EventID {
+		t.Errorf("Unexpected outbox event: %+v", event)
+	}
+
+	// Una cuenta fusionada no se puede reactivar
+	if _, err := newTestUserService(users).SetActive(context.Background(), 2, true); err == nil {
+		t.Error("Expected an error reactivating a merged account")
+	}
+}
+
+// Test: casos que no se pueden fusionar
+func TestMerge_Rejects(t *testing.T) {
+	service, users, _, repo := newTestMergeService()
+	other := uint(3)
+	users.users[1] = &domain.User{ID: 1, UserType: domain.UserTypeNormal}
+	users.users[2] = &domain.User{ID: 2, UserType: domain.UserTypeAdmin}
+	users.users[3] = &domain.User{ID: 3, UserType: domain.UserTypeNormal}
+	users.users[4] = &domain.User{ID: 4, UserType: domain.UserTypeNormal, MergedInto: &other}
+
+	cases := map[string][2]uint{
+		"itself":         {1, 1},
+		"admin":          {1, 2},
+		"already merged": {1, 4},
+		"into merged":    {4, 1},
+		"unknown":        {1, 99},
+	}
+	for name, ids := range cases {
+		if _, err := service.Merge(context.Background(), 9, ids[0], ids[1]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if len(repo.plans) != 0 {
+		t.Errorf("Expected nothing merged, got %d", len(repo.plans))
+	}
+}
+
+// Test: el relay publica en orden y frena en el primer error sin marcar el resto
+func TestOutboxRelay(t *testing.T) {
+	repo := &mockOutboxRepository{events: []domain.OutboxEvent{
+		{ID: 1, EventID: "a", Type: "user.merged"},
+		{ID: 2, EventID: "b", Type: "user.merged"},
+		{ID: 3, EventID: "c", Type: "user.merged"},
+	}}
+	publisher := &failingPublisher{failAt: 2}
+	service := NewOutboxService(repo, publisher)
+
+	published, err := service.Relay(context.Background())
+	if err == nil || published != 2 {
+		t.Fatalf("Expected 2 published and an error, got %d, %v", published, err)
+	}
+	if repo.events[1].PublishedAt == nil || repo.events[2].PublishedAt != nil {
+		t.Errorf("Expected only the first two marked, got %+v", repo.events)
+	}
+
+	// La próxima corrida sigue desde el que falló
+	publisher.failAt = -1
+	if published, err := service.Relay(context.Background()); err != nil || published != 1 {
+		t.Errorf("Expected the pending event published, got %d, %v", published, err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"users-api/dto"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// OIDCProvider es un proveedor OpenID Connect configurado (lo implementa
+// utils.OIDCProvider; los tests usan uno falso)
+type OIDCProvider interface {
+	Config() utils.OIDCProviderConfig
+	Discover(ctx context.Context) (*utils.OIDCDiscovery, error)
+	Exchange(ctx context.Context, code, redirectURI, codeVerifier string) (string, error)
+	Verify(ctx context.Context, idToken, nonce string) (*utils.OIDCIdentity, error)
+}
+
+// OIDCLoginService maneja el login con los proveedores OIDC configurados
+// (Keycloak, Auth0, Azure AD...)
+type OIDCLoginService interface {
+	Providers(ctx context.Context) []dto.OIDCProviderResponse
+	Login(ctx context.Context, provider string, req dto.OIDCLoginRequest) (*dto.LoginResponse, error)
+}
+
+// oidcLoginService es la implementación real del servicio
+type oidcLoginService struct {
+	users        repositories.UserRepository
+	providers    map[string]OIDCProvider
+	order        []string // orden de OIDC_PROVIDERS, para listarlos
+	registration RegistrationConfig
+}
+
+// NewOIDCLoginService crea el servicio con el registro de proveedores
+// Sin proveedores (OIDC_PROVIDERS vacío) la lista sale vacía y el login responde 404
+func NewOIDCLoginService(users repositories.UserRepository, providers []OIDCProvider, registration RegistrationConfig) OIDCLoginService {
+	s := &oidcLoginService{users: users, providers: map[string]OIDCProvider{}, registration: registration}
+	for _, provider := range providers {
+		name := provider.Config().Name
+		s.providers[name] = provider
+		s.order = append(s.order, name)
+	}
+	return s
+}
+
+// Providers lista los proveedores habilitados con lo que necesita el
+// frontend para mandar al usuario a autorizar
+// Un proveedor cuyo discovery no responde no se lista (queda en el log)
+func (s *oidcLoginService) Providers(ctx context.Context) []dto.OIDCProviderResponse {
+	list := []dto.OIDCProviderResponse{}
+	for _, name := range s.order {
+		provider := s.providers[name]
+		doc, err := provider.Discover(ctx)
+		if err != nil {
+			requestid.Logf(ctx, "⚠️  Proveedor OIDC %s sin discovery: %v", name, err)
+			continue
+		}
+		cfg := provider.Config()
+		list = append(list, dto.OIDCProviderResponse{
+			Name:                  cfg.Name,
+			DisplayName:           cfg.DisplayName,
+			Issuer:                doc.Issuer,
+			ClientID:              cfg.ClientID,
+			AuthorizationEndpoint: doc.AuthorizationEndpoint,
+		})
+	}
+	return list
+}
+
+// Login canjea el code del proveedor por su ID token y da el mismo
+// JWT que el login; asocia o crea la cuenta como cualquier login externo
+// (ver externalLogin). users.provider es el nombre del proveedor: cambiarlo
+// en la configuración deja sin asociar las cuentas que ya entraron
+func (s *oidcLoginService) Login(ctx context.Context, name string, req dto.OIDCLoginRequest) (*dto.LoginResponse, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, apperrors.NotFound("OIDC provider not found")
+	}
+	if req.Code == "" {
+		return nil, apperrors.Validation("code is required")
+	}
+
+	// El ID token llega directo del token endpoint (con el client secret y el
+	// code de un solo uso): no hay un token capturado que se pueda reusar
+	idToken, err := provider.Exchange(ctx, req.Code, req.RedirectURI, req.CodeVerifier)
+	if err != nil {
+		requestid.Logf(ctx, "⚠️  Code de %s rechazado: %v", name, err)
+		return nil, apperrors.Unauthorized("invalid OIDC authorization code")
+	}
+
+	identity, err := provider.Verify(ctx, idToken, req.Nonce)
+	if err != nil {
+		requestid.Logf(ctx, "⚠️  ID token de %s rechazado: %v", name, err)
+		return nil, apperrors.Unauthorized("invalid OIDC token")
+	}
+
+	return externalLogin(ctx, s.users, s.registration, externalIdentity{
+		Provider:      name,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		FirstName:     identity.FirstName,
+		LastName:      identity.LastName,
+	}, "the account email is not verified by the provider")
+}
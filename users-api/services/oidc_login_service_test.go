@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// fakeOIDCProvider canjea codes por ID tokens y acepta como ID token la
+// clave de identities
+type fakeOIDCProvider struct {
+	name       string
+	codes      map[string]string
+	identities map[string]*utils.OIDCIdentity
+	down       bool // el discovery no responde
+}
+
+func (f *fakeOIDCProvider) Config() utils.OIDCProviderConfig {
+	return utils.OIDCProviderConfig{Name: f.name, DisplayName: f.name, ClientID: "spotly"}
+}
+
+func (f *fakeOIDCProvider) Discover(context.Context) (*utils.OIDCDiscovery, error) {
+	if f.down {
+		return nil, errors.New("connection refused")
+	}
+	return &utils.OIDCDiscovery{Issuer: "https://" + f.name + ".example.com", AuthorizationEndpoint: "https://" + f.name + ".example.com/authorize"}, nil
+}
+
+func (f *fakeOIDCProvider) Exchange(_ context.Context, code, _, _ string) (string, error) {
+	idToken, ok := f.codes[code]
+	if !ok {
+		return "", errors.New("invalid_grant")
+	}
+	return idToken, nil
+}
+
+func (f *fakeOIDCProvider) Verify(_ context.Context, idToken, _ string) (*utils.OIDCIdentity, error) {
+	identity, ok := f.identities[idToken]
+	if !ok {
+		return nil, utils.ErrInvalidOIDCToken
+	}
+	return identity, nil
+}
+
+func newOIDCTestService(users *mockUserRepository) OIDCLoginService {
+	keycloak := &fakeOIDCProvider{
+		name:  "keycloak",
+		codes: map[string]string{"code-ana": "ana", "code-forged": "forged", "code-unverified": "unverified"},
+		identities: map[string]*utils.OIDCIdentity{
+			"ana":        {Subject: "kc-1", Email: "ana.perez@example.com", EmailVerified: true, FirstName: "Ana", LastName: "Pérez"},
+			"unverified": {Subject: "kc-2", Email: "test@example.com"},
+		},
+	}
+	return NewOIDCLoginService(users, []OIDCProvider{keycloak, &fakeOIDCProvider{name: "auth0", down: true}}, RegistrationConfig{})
+}
+
+// Test: el code se canjea y la primera vez se crea el usuario con el proveedor
+func TestOIDCLogin_CodeProvisionsUser(t *testing.T) {
+	users := newMockUserRepository()
+	service := newOIDCTestService(users)
+
+	response, err := service.Login(context.Background(), "keycloak", dto.OIDCLoginRequest{Code: "code-ana", RedirectURI: "https://spotly.com/login/oidc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	user := users.users[response.User.ID]
+	if response.Token == "" || user.Username != "ana.perez" || user.Provider != "keycloak" || user.ProviderID == nil || *user.ProviderID != "kc-1" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	again, err := service.Login(context.Background(), "keycloak", dto.OIDCLoginRequest{Code: "code-ana", RedirectURI: "https://spotly.com/login/oidc"})
+	if err != nil || again.User.ID != user.ID || len(users.users) != 1 {
+		t.Errorf("Expected the same user on the second login, got %+v, %v", again, err)
+	}
+}
+
+// Test: una cuenta con Google no se asocia además a un proveedor OIDC
+func TestOIDCLogin_DoesNotReplaceGoogle(t *testing.T) {
+	users := newMockUserRepository()
+	if _, err := newGoogleTestService(users).Login(context.Background(), "ana"); err != nil {
+		t.Fatal(err)
+	}
+	users.users[1].Email = "ana.perez@example.com"
+
+	_, err := newOIDCTestService(users).Login(context.Background(), "keycloak", dto.OIDCLoginRequest{Code: "code-ana"})
+	if !errors.Is(err, apperrors.ErrConflict) || users.users[1].Provider != domain.ProviderGoogle {
+		t.Errorf("Expected a conflict keeping the Google link, got %v", err)
+	}
+}
+
+// Test: proveedor desconocido, sin code, code o token inválidos y email sin verificar
+func TestOIDCLogin_Rejections(t *testing.T) {
+	service := newOIDCTestService(newMockUserRepository())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		provider string
+		req      dto.OIDCLoginRequest
+		want     error
+	}{
+		{"unknown provider", "okta", dto.OIDCLoginRequest{Code: "code-ana"}, apperrors.ErrNotFound},
+		{"no code", "keycloak", dto.OIDCLoginRequest{}, apperrors.ErrValidation},
+		{"invalid code", "keycloak", dto.OIDCLoginRequest{Code: "used"}, apperrors.ErrUnauthorized},
+		{"invalid token", "keycloak", dto.OIDCLoginRequest{Code: "code-forged"}, apperrors.ErrUnauthorized},
+		{"unverified email", "keycloak", dto.OIDCLoginRequest{Code: "code-unverified"}, apperrors.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		if _, err := service.Login(ctx, tt.provider, tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+// Test: se listan los proveedores en orden, salvo los que no responden
+func TestOIDCLogin_Providers(t *testing.T) {
+	providers := newOIDCTestService(newMockUserRepository()).Providers(context.Background())
+	if len(providers) != 1 || providers[0].Name != "keycloak" || providers[0].AuthorizationEndpoint != "https://keycloak.example.com/authorize" {
+		t.Errorf("Unexpected providers: %+v", providers)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/queue"
+	"users-api/repositories"
+
+	"shared/requestid"
+)
+
+// outboxBatchSize es cuántos eventos se publican por corrida del relay
+const outboxBatchSize = 100
+
+// OutboxService publica en RabbitMQ los eventos guardados en el outbox
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+	PurgePublished(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// outboxService es la implementación real del servicio
+type outboxService struct {
+	repo      repositories.OutboxRepository
+	publisher queue.EventPublisher
+}
+
+// NewOutboxService crea una nueva instancia del servicio
+func NewOutboxService(repo repositories.OutboxRepository, publisher queue.EventPublisher) OutboxService {
+	return &outboxService{repo: repo, publisher: publisher}
+}
+
+// Relay publica los eventos pendientes en orden y los marca como publicados
+// Frena en el primer error (el resto sale en la próxima corrida, sin
+// desordenarse). Si se publica pero no se llega a marcar, el evento sale de
+// nuevo con el mismo ID y los consumidores lo deduplican
+// Lo ejecuta el job programado "relay_outbox"; devuelve cuántos publicó
+func (s *outboxService) Relay(ctx context.Context) (int, error) {
+	events, err := s.repo.ListPending(ctx, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if err := s.publisher.Publish(queue.WithEventID(ctx, event.EventID), event.Type, event.Data); err != nil {
+			return i, err
+		}
+		if err := s.repo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+			return i, err
+		}
+		requestid.Logf(ctx, "📤 Evento %s (%s) publicado desde el outbox", event.Type, event.EventID)
+	}
+	return len(events), nil
+}
+
+// PurgePublished borra los eventos publicados hace más de retention
+func (s *outboxService) PurgePublished(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeletePublishedBefore(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+	"users-api/domain"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+)
+
+// Parámetros de los códigos de verificación por SMS
+const (
+	phoneCodeTTL         = 10 * time.Minute
+	phoneCodeMaxAttempts = 5           // intentos fallidos antes de tener que pedir otro código
+	phoneCodeResendAfter = time.Minute // espera mínima entre dos códigos (cada SMS cuesta)
+)
+
+// PhoneService maneja el teléfono del usuario y su verificación por SMS
+type PhoneService interface {
+	StartVerification(ctx context.Context, userID uint, phone string) (*domain.User, error)
+	Verify(ctx context.Context, userID uint, code string) (*domain.User, error)
+}
+
+// phoneService es la implementación real del servicio
+type phoneService struct {
+	users         repositories.UserRepository
+	verifications repositories.PhoneVerificationRepository
+	publisher     queue.EventPublisher
+	now           func() time.Time
+}
+
+// NewPhoneService crea una nueva instancia del servicio
+func NewPhoneService(users repositories.UserRepository, verifications repositories.PhoneVerificationRepository, publisher queue.EventPublisher) PhoneService {
+	return &phoneService{users: users, verifications: verifications, publisher: publisher, now: time.Now}
+}
+
+// StartVerification guarda el teléfono (normalizado a E.164, sin verificar)
+// y publica "user.phone_verification" para que notifications-api mande el
+// código por SMS. Si el teléfono ya está verificado no manda nada
+func (s *phoneService) StartVerification(ctx context.Context, userID uint, phone string) (*domain.User, error) {
+	normalized, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return nil, apperrors.Validation(err.Error())
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Phone == normalized && user.PhoneVerified() {
+		return user, nil
+	}
+
+	// 1. Un código por minuto como máximo
+	now := s.now()
+	pending, err := s.verifications.GetByUser(ctx, userID)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+	if pending != nil && now.Sub(pending.CreatedAt) < phoneCodeResendAfter {
+		return nil, apperrors.New(apperrors.CodeRateLimited, "a verification code was just sent, wait a minute before requesting another")
+	}
+
+	// 2. Teléfono nuevo: queda sin verificar hasta confirmar el código
+	if user.Phone != normalized {
+		user.SetPhone(normalized)
+		if err := s.users.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Código de 6 dígitos; en la base solo el hash
+	code, err := newPhoneCode()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating verification code", err)
+	}
+	hash, err := utils.HashPassword(code)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error hashing verification code", err)
+	}
+	if err := s.verifications.Replace(ctx, &domain.PhoneVerification{
+		UserID:    user.ID,
+		Phone:     normalized,
+		CodeHash:  hash,
+		ExpiresAt: now.Add(phoneCodeTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	// 4. El SMS lo manda notifications-api
+	err = s.publisher.Publish(ctx, "user.phone_verification", map[string]interface{}{
+		"user_id":            user.ID,
+		"phone":              normalized,
+		"first_name":         user.FirstName,
+		"locale":             user.Locale,
+		"code":               code,
+		"expires_in_minutes": int(phoneCodeTTL.Minutes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Verify confirma el código y marca el teléfono como verificado
+// Código vencido, de otro teléfono o con demasiados intentos: hay que pedir otro
+func (s *phoneService) Verify(ctx context.Context, userID uint, code string) (*domain.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.verifications.GetByUser(ctx, userID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, apperrors.BadRequest("no pending phone verification, request a new code")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pending.Phone != user.Phone || !s.now().Before(pending.ExpiresAt) || pending.Attempts >= phoneCodeMaxAttempts {
+		return nil, apperrors.BadRequest("verification code expired, request a new code")
+	}
+
+	if !utils.CheckPasswordHash(code, pending.CodeHash) {
+		if err := s.verifications.IncrementAttempts(ctx, pending.ID); err != nil {
+			return nil, err
+		}
+		return nil, apperrors.BadRequest("invalid verification code")
+	}
+
+	now := s.now()
+	user.PhoneVerifiedAt = &now
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.verifications.Delete(ctx, pending.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// newPhoneCode genera un código de 6 dígitos con crypto/rand
+func newPhoneCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+
+	"shared/apperrors"
+)
+
+// ============================================
+// MOCK del repositorio de códigos por SMS
+// ============================================
+type mockPhoneVerificationRepository struct {
+	pending *domain.PhoneVerification
+}
+
+func (m *mockPhoneVerificationRepository) Replace(ctx context.Context, verification *domain.PhoneVerification) error {
+	verification.ID = 1
+	copied := *verification
+	m.pending = &copied
+	return nil
+}
+
+func (m *mockPhoneVerificationRepository) GetByUser(ctx context.Context, userID uint) (*domain.PhoneVerification, error) {
+	if m.pending == nil || m.pending.UserID != userID {
+		return nil, apperrors.NotFound("no pending phone verification")
+	}
+	copied := *m.pending
+	return &copied, nil
+}
+
+func (m *mockPhoneVerificationRepository) IncrementAttempts(ctx context.Context, id uint) error {
+	m.pending.Attempts++
+	return nil
+}
+
+func (m *mockPhoneVerificationRepository) Delete(ctx context.Context, id uint) error {
+	m.pending = nil
+	return nil
+}
+
+// newTestPhoneService arma el servicio con el usuario 1 y un reloj controlable
+func newTestPhoneService() (*phoneService, *mockUserRepository, *mockPublisher, *time.Time) {
+	repo := newMockUserRepository()
+	repo.users[1] = &domain.User{ID: 1, FirstName: "Ana"}
+	publisher := &mockPublisher{}
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	service := NewPhoneService(repo, &mockPhoneVerificationRepository{}, publisher).(*phoneService)
+	service.now = func() time.Time { return now }
+	return service, repo, publisher, &now
+}
+
+// wrongCode devuelve un código distinto de code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+// Test: el teléfono se normaliza, se manda el código y al confirmarlo queda verificado
+func TestPhone_VerifyFlow(t *testing.T) {
+	service, repo, publisher, _ := newTestPhoneService()
+
+	if _, err := service.StartVerification(context.Background(), 1, "+54 9 351 123-4567"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.users[1].Phone != "+5493511234567" || repo.users[1].PhoneVerified() {
+		t.Errorf("Expected the normalized phone unverified, got %+v", repo.users[1])
+	}
+	if len(publisher.published) != 1 || publisher.published[0] != "user.phone_verification" {
+		t.Fatalf("Expected user.phone_verification published, got %v", publisher.published)
+	}
+	code := publisher.payloads[0]["code"].(string)
+
+	if _, err := service.Verify(context.Background(), 1, wrongCode(code)); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("Expected a wrong code to be rejected, got %v", err)
+	}
+	user, err := service.Verify(context.Background(), 1, code)
+	if err != nil {
+		t.Fatalf("Expected the code to be accepted, got %v", err)
+	}
+	if !user.PhoneVerified() {
+		t.Error("Expected the phone to be verified")
+	}
+
+	// Cambiar el teléfono lo deja sin verificar otra vez
+	repo.users[1].SetPhone("+14155552671")
+	if repo.users[1].PhoneVerified() {
+		t.Error("Expected a new phone to be unverified")
+	}
+}
+
+// Test: reenvíos muy seguidos, código vencido y demasiados intentos
+func TestPhone_Limits(t *testing.T) {
+	service, _, publisher, now := newTestPhoneService()
+
+	if _, err := service.StartVerification(context.Background(), 1, "3511234567"); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected a phone without country code to be rejected, got %v", err)
+	}
+
+	service.StartVerification(context.Background(), 1, "+5493511234567")
+	if _, err := service.StartVerification(context.Background(), 1, "+5493511234567"); !errors.Is(err, apperrors.ErrRateLimited) {
+		t.Errorf("Expected a resend within a minute to be rate limited, got %v", err)
+	}
+
+	*now = now.Add(11 * time.Minute)
+	code := publisher.payloads[0]["code"].(string)
+	if _, err := service.Verify(context.Background(), 1, code); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("Expected an expired code to be rejected, got %v", err)
+	}
+
+	service.StartVerification(context.Background(), 1, "+5493511234567")
+	code = publisher.payloads[1]["code"].(string)
+	for i := 0; i < phoneCodeMaxAttempts; i++ {
+		service.Verify(context.Background(), 1, wrongCode(code))
+	}
+	if _, err := service.Verify(context.Background(), 1, code); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("Expected the code to be locked after %d attempts, got %v", phoneCodeMaxAttempts, err)
+	}
+}
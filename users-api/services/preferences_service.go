@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+
+	"shared/apperrors"
+)
+
+// PreferencesService define la interfaz del servicio de preferencias
+type PreferencesService interface {
+	GetPreferences(ctx context.Context, userID uint) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uint, req dto.UpdatePreferencesRequest) (*domain.NotificationPreferences, error)
+}
+
+// preferencesService es la implementación real del servicio
+type preferencesService struct {
+	userRepo  repositories.UserRepository
+	prefsRepo repositories.PreferencesRepository
+}
+
+// NewPreferencesService crea una nueva instancia del servicio
+func NewPreferencesService(userRepo repositories.UserRepository, prefsRepo repositories.PreferencesRepository) PreferencesService {
+	return &preferencesService{userRepo: userRepo, prefsRepo: prefsRepo}
+}
+
+// GetPreferences obtiene las preferencias de un usuario
+// Si nunca las configuró, devuelve los valores por defecto
+func (s *preferencesService) GetPreferences(ctx context.Context, userID uint) (*domain.NotificationPreferences, error) {
+	// 1. Verificar que el usuario existe
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 2. Buscar las preferencias guardadas
+	prefs, err := s.prefsRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences cambia solo los campos enviados en el request
+func (s *preferencesService) UpdatePreferences(ctx context.Context, userID uint, req dto.UpdatePreferencesRequest) (*domain.NotificationPreferences, error) {
+	// 1. Partir de las preferencias actuales (o las por defecto)
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Aplicar los cambios
+	if req.EmailTransactional != nil {
+		prefs.EmailTransactional = *req.EmailTransactional
+	}
+	if req.EmailMarketing != nil {
+		prefs.EmailMarketing = *req.EmailMarketing
+	}
+
+	// 3. Guardar
+	if err := s.prefsRepo.Save(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"users-api/domain"
+	"users-api/dto"
+
+	"shared/apperrors"
+)
+
+// ============================================
+// MOCK del repositorio de preferencias
+// ============================================
+type mockPreferencesRepository struct {
+	prefs map[uint]*domain.NotificationPreferences
+}
+
+func newMockPreferencesRepository() *mockPreferencesRepository {
+	return &mockPreferencesRepository{
+		prefs: make(map[uint]*domain.NotificationPreferences),
+	}
+}
+
+func (m *mockPreferencesRepository) GetByUserID(ctx context.Context, userID uint) (*domain.NotificationPreferences, error) {
+	prefs, exists := m.prefs[userID]
+	if !exists {
+		return nil, apperrors.NotFound("preferences not found")
+	}
+	return prefs, nil
+}
+
+func (m *mockPreferencesRepository) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	m.prefs[prefs.UserID] = prefs
+	return nil
+}
+
+// ============================================
+// TESTS
+// ============================================
+
+// Test: un usuario sin preferencias guardadas recibe los valores por defecto
+func TestGetPreferences_Defaults(t *testing.T) {
+	userRepo := newMockUserRepository()
+	userRepo.Create(context.Background(), &domain.User{Username: "testuser", Email: "test@example.com"})
+	service := NewPreferencesService(userRepo, newMockPreferencesRepository())
+
+	prefs, err := service.GetPreferences(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !prefs.EmailTransactional {
+		t.Error("Expected transactional emails enabled by default")
+	}
+	if prefs.EmailMarketing {
+		t.Error("Expected marketing emails disabled by default")
+	}
+}
+
+// Test: solo se cambian los campos enviados
+func TestUpdatePreferences_PartialUpdate(t *testing.T) {
+	userRepo := newMockUserRepository()
+	userRepo.Create(context.Background(), &domain.User{Username: "testuser", Email: "test@example.com"})
+	prefsRepo := newMockPreferencesRepository()
+	service := NewPreferencesService(userRepo, prefsRepo)
+
+	enabled := true
+	prefs, err := service.UpdatePreferences(context.Background(), 1, dto.UpdatePreferencesRequest{EmailMarketing: &enabled})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !prefs.EmailMarketing {
+		t.Error("Expected marketing emails enabled")
+	}
+	if !prefs.EmailTransactional {
+		t.Error("Expected transactional emails to stay enabled")
+	}
+	if _, saved := prefsRepo.prefs[1]; !saved {
+		t.Error("Expected preferences to be saved")
+	}
+}
+
+// Test: error si el usuario no existe
+func TestGetPreferences_UserNotFound(t *testing.T) {
+	service := NewPreferencesService(newMockUserRepository(), newMockPreferencesRepository())
+
+	_, err := service.GetPreferences(context.Background(), 999)
+
+	if err == nil {
+		t.Error("Expected error for non-existent user, got nil")
+	}
+}
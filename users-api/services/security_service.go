@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/queue"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/featureflags"
+)
+
+const (
+	// recentSecurityEvents es cuántos eventos se muestran en /users/me/security
+	recentSecurityEvents = 20
+
+	// defaultLoginsPerPage es el tamaño de página del historial de logins
+	defaultLoginsPerPage = 20
+
+	// FlagLoginSecurityAlerts prende el email de alerta por login sospechoso
+	FlagLoginSecurityAlerts = "login_security_alerts"
+)
+
+// SecurityService detecta logins sospechosos y expone la actividad de seguridad
+type SecurityService interface {
+	CheckLogin(ctx context.Context, user dto.UserResponse, client domain.LoginClient) error
+	GetOverview(ctx context.Context, userID uint) (*dto.SecurityOverviewResponse, error)
+	PurgeOldEvents(ctx context.Context, retention time.Duration) (int64, error)
+	RecordLogin(ctx context.Context, userID uint, method string, client domain.LoginClient, failure string) error
+	ListLogins(ctx context.Context, userID uint, query dto.PageQuery) (*dto.LoginHistoryResponse, error)
+}
+
+// securityService es la implementación real del servicio
+type securityService struct {
+	repo      repositories.SecurityRepository
+	publisher queue.EventPublisher
+	flags     *featureflags.Client
+}
+
+// NewSecurityService crea una nueva instancia del servicio
+func NewSecurityService(repo repositories.SecurityRepository, publisher queue.EventPublisher, flags *featureflags.Client) SecurityService {
+	return &securityService{repo: repo, publisher: publisher, flags: flags}
+}
+
+// CheckLogin se llama después de cada login exitoso
+//  1. Identifica el dispositivo (navegador, sistema y plataforma, sin
+//     versiones) y la red (prefijo de IP)
+//  2. Si alguno es nuevo, registra un evento de seguridad
+//  3. Publica "user.security_alert" para que notifications-api mande el email
+//     (solo si el flag login_security_alerts está prendido para el usuario)
+//
+// El primer login de la cuenta solo registra dispositivo y red, sin alertar
+func (s *securityService) CheckLogin(ctx context.Context, user dto.UserResponse, client domain.LoginClient) error {
+	now := time.Now()
+	ip, userAgent := client.IP, client.UserAgent
+	info := utils.ParseUserAgent(userAgent)
+	prefix := utils.IPPrefix(ip)
+
+	knownNetworks, err := s.repo.CountNetworks(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Dispositivo
+	device, err := s.findDevice(ctx, user.ID, userAgent)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return err
+	}
+	newDevice := err != nil
+	if newDevice {
+		device = &domain.KnownDevice{UserID: user.ID, FirstSeenAt: now}
+	}
+	device.Fingerprint = utils.DeviceFingerprint(userAgent)
+	device.Browser = info.Browser
+	device.OS = info.OS
+	device.Platform = info.Platform
+	device.UserAgent = truncate(userAgent, 255)
+	device.LastIP = ip
+	device.LastCountry = client.Country
+	device.LastSeenAt = now
+	if err := s.repo.SaveDevice(ctx, device); err != nil {
+		return err
+	}
+
+	// 2. Red (si la IP no se puede parsear no la tenemos en cuenta)
+	newLocation := false
+	if prefix != "" {
+		network, err := s.repo.GetNetwork(ctx, user.ID, prefix)
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return err
+		}
+		if err != nil {
+			newLocation = true
+			network = &domain.KnownNetwork{UserID: user.ID, Prefix: prefix, FirstSeenAt: now}
+		}
+		network.LastSeenAt = now
+		if err := s.repo.SaveNetwork(ctx, network); err != nil {
+			return err
+		}
+	}
+
+	// 3. Primer login de la cuenta: no hay con qué comparar
+	firstLogin := newDevice && knownNetworks == 0
+	if firstLogin || (!newDevice && !newLocation) {
+		return nil
+	}
+
+	var reasons []string
+	if newDevice {
+		reasons = append(reasons, string(domain.SecurityEventNewDevice))
+		s.recordEvent(ctx, user.ID, domain.SecurityEventNewDevice, client, info)
+	}
+	if newLocation {
+		reasons = append(reasons, string(domain.SecurityEventNewLocation))
+		s.recordEvent(ctx, user.ID, domain.SecurityEventNewLocation, client, info)
+	}
+
+	if !s.flags.IsEnabled(FlagLoginSecurityAlerts, featureflags.UserKey(user.ID)) {
+		return nil
+	}
+
+	return s.publisher.Publish(ctx, "user.security_alert", map[string]interface{}{
+		"user_id":    user.ID,
+		"email":      user.Email,
+		"first_name": user.FirstName,
+		"locale":     user.Locale,
+		"ip":         ip,
+		"user_agent": userAgent,
+		"device":     info.String(),
+		"country":    client.Country,
+		"reasons":    reasons,
+		"login_at":   now.UTC().Format(time.RFC3339),
+	})
+}
+
+// findDevice busca el dispositivo por su fingerprint y, si no está, por el
+// fingerprint anterior (hash del user agent completo): así los dispositivos
+// guardados antes del cambio no se toman como nuevos (CheckLogin los migra)
+func (s *securityService) findDevice(ctx context.Context, userID uint, userAgent string) (*domain.KnownDevice, error) {
+	fingerprint := utils.DeviceFingerprint(userAgent)
+	device, err := s.repo.GetDevice(ctx, userID, fingerprint)
+	if legacy := utils.LegacyDeviceFingerprint(userAgent); errors.Is(err, apperrors.ErrNotFound) && legacy != fingerprint {
+		return s.repo.GetDevice(ctx, userID, legacy)
+	}
+	return device, err
+}
+
+// recordEvent guarda un evento de seguridad (un error acá no corta el login)
+func (s *securityService) recordEvent(ctx context.Context, userID uint, eventType domain.SecurityEventType, client domain.LoginClient, info utils.Device) {
+	err := s.repo.CreateEvent(ctx, &domain.SecurityEvent{
+		UserID:    userID,
+		Type:      eventType,
+		IP:        client.IP,
+		UserAgent: truncate(client.UserAgent, 255),
+		Device:    info.String(),
+		Country:   client.Country,
+	})
+	if err != nil {
+		log.Printf("⚠️  No se pudo guardar el evento de seguridad %s del usuario %d: %v", eventType, userID, err)
+	}
+}
+
+// GetOverview devuelve los últimos eventos de seguridad y los dispositivos conocidos
+func (s *securityService) GetOverview(ctx context.Context, userID uint) (*dto.SecurityOverviewResponse, error) {
+	events, err := s.repo.ListEvents(ctx, userID, recentSecurityEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.repo.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SecurityOverviewResponse{
+		Events:  events,
+		Devices: devices,
+	}, nil
+}
+
+// PurgeOldEvents borra los eventos de seguridad y el historial de logins
+// más viejos que retention; devuelve cuántas filas se borraron en total
+// Lo ejecuta el job programado "purge_security_events"
+func (s *securityService) PurgeOldEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	before := time.Now().Add(-retention)
+	events, err := s.repo.DeleteEventsBefore(ctx, before)
+	if err != nil {
+		return events, err
+	}
+	logins, err := s.repo.DeleteLoginEventsBefore(ctx, before)
+	return events + logins, err
+}
+
+// RecordLogin agrega un intento de login al historial del usuario
+// failure es el motivo si falló (ver domain.LoginFailure*); "" = exitoso,
+// y entonces también se actualiza el último login del usuario
+func (s *securityService) RecordLogin(ctx context.Context, userID uint, method string, client domain.LoginClient, failure string) error {
+	return s.repo.CreateLoginEvent(ctx, &domain.LoginEvent{
+		UserID:    userID,
+		Success:   failure == "",
+		Method:    method,
+		Failure:   failure,
+		IP:        client.IP,
+		UserAgent: truncate(client.UserAgent, 255),
+		Country:   client.Country,
+		CreatedAt: time.Now(),
+	})
+}
+
+// ListLogins devuelve una página del historial de logins (lo más nuevo primero)
+func (s *securityService) ListLogins(ctx context.Context, userID uint, query dto.PageQuery) (*dto.LoginHistoryResponse, error) {
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultLoginsPerPage
+	}
+
+	logins, total, err := s.repo.ListLoginEvents(ctx, userID, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginHistoryResponse{Logins: logins, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
+
+// truncate corta un string al largo máximo de la columna
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
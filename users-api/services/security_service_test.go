@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/featureflags"
+)
+
+// ============================================
+// MOCKS del repositorio de seguridad y del publisher
+// ============================================
+type mockSecurityRepository struct {
+	devices  map[string]*domain.KnownDevice
+	networks map[string]*domain.KnownNetwork
+	events   []domain.SecurityEvent
+	logins   []domain.LoginEvent
+}
+
+func newMockSecurityRepository() *mockSecurityRepository {
+	return &mockSecurityRepository{
+		devices:  make(map[string]*domain.KnownDevice),
+		networks: make(map[string]*domain.KnownNetwork),
+	}
+}
+
+func (m *mockSecurityRepository) GetDevice(ctx context.Context, userID uint, fingerprint string) (*domain.KnownDevice, error) {
+	device, exists := m.devices[fingerprint]
+	if !exists || device.UserID != userID {
+		return nil, apperrors.NotFound("device not found")
+	}
+	return device, nil
+}
+
+func (m *mockSecurityRepository) SaveDevice(ctx context.Context, device *domain.KnownDevice) error {
+	m.devices[device.Fingerprint] = device
+	return nil
+}
+
+func (m *mockSecurityRepository) ListDevices(ctx context.Context, userID uint) ([]domain.KnownDevice, error) {
+	var devices []domain.KnownDevice
+	for _, device := range m.devices {
+		if device.UserID == userID {
+			devices = append(devices, *device)
+		}
+	}
+	return devices, nil
+}
+
+func (m *mockSecurityRepository) GetNetwork(ctx context.Context, userID uint, prefix string) (*domain.KnownNetwork, error) {
+	network, exists := m.networks[prefix]
+	if !exists || network.UserID != userID {
+		return nil, apperrors.NotFound("network not found")
+	}
+	return network, nil
+}
+
+func (m *mockSecurityRepository) SaveNetwork(ctx context.Context, network *domain.KnownNetwork) error {
+	m.networks[network.Prefix] = network
+	return nil
+}
+
+func (m *mockSecurityRepository) CountNetworks(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	for _, network := range m.networks {
+		if network.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockSecurityRepository) CreateEvent(ctx context.Context, event *domain.SecurityEvent) error {
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *mockSecurityRepository) ListEvents(ctx context.Context, userID uint, limit int) ([]domain.SecurityEvent, error) {
+	return m.events, nil
+}
+
+func (m *mockSecurityRepository) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	var kept []domain.SecurityEvent
+	for _, event := range m.events {
+		if !event.CreatedAt.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+	deleted := int64(len(m.events) - len(kept))
+	m.events = kept
+	return deleted, nil
+}
+
+func (m *mockSecurityRepository) CreateLoginEvent(ctx context.Context, event *domain.LoginEvent) error {
+	m.logins = append(m.logins, *event)
+	return nil
+}
+
+func (m *mockSecurityRepository) ListLoginEvents(ctx context.Context, userID uint, page, limit int) ([]domain.LoginEvent, int64, error) {
+	var logins []domain.LoginEvent
+	for i := len(m.logins) - 1; i >= 0; i-- {
+		if m.logins[i].UserID == userID {
+			logins = append(logins, m.logins[i])
+		}
+	}
+	total := int64(len(logins))
+	start := min((page-1)*limit, len(logins))
+	return logins[start:min(start+limit, len(logins))], total, nil
+}
+
+func (m *mockSecurityRepository) DeleteLoginEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	var kept []domain.LoginEvent
+	for _, login := range m.logins {
+		if !login.CreatedAt.Before(before) {
+			kept = append(kept, login)
+		}
+	}
+	deleted := int64(len(m.logins) - len(kept))
+	m.logins = kept
+	return deleted, nil
+}
+
+type mockPublisher struct {
+	published []string
+	payloads  []map[string]interface{}
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	m.published = append(m.published, eventType)
+	m.payloads = append(m.payloads, data)
+	return nil
+}
+
+func newTestFlags(t *testing.T, alertsEnabled bool) *featureflags.Client {
+	flags, err := featureflags.NewClient(featureflags.NewStaticSource(map[string]featureflags.Flag{
+		FlagLoginSecurityAlerts: {Enabled: alertsEnabled, Percentage: 100},
+	}), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create flags: %v", err)
+	}
+	return flags
+}
+
+// ============================================
+// TESTS
+// ============================================
+
+const (
+	testBrowser = "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0"
+	testPhone   = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0) Safari/604.1"
+)
+
+// Test: el primer login solo registra el dispositivo, sin alertar
+func TestCheckLogin_FirstLoginNoAlert(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, true))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	if err := service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no alerts, got %v", publisher.published)
+	}
+	if len(repo.devices) != 1 || len(repo.networks) != 1 {
+		t.Errorf("Expected device and network recorded, got %d/%d", len(repo.devices), len(repo.networks))
+	}
+}
+
+// Test: mismo dispositivo y misma red (/24) no generan alerta
+func TestCheckLogin_KnownDeviceNoAlert(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, true))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser})
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.99", UserAgent: testBrowser})
+
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no alerts, got %v", publisher.published)
+	}
+}
+
+// Test: una actualización del navegador no es un dispositivo nuevo
+func TestCheckLogin_BrowserUpdateNoAlert(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, true))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser})
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: "Mozilla/5.0 (Windows NT 10.0) Chrome/121.0", Country: "AR"})
+
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no alerts, got %v", publisher.published)
+	}
+	devices, _ := repo.ListDevices(context.Background(), 1)
+	if len(devices) != 1 || devices[0].Browser != "Chrome" || devices[0].OS != "Windows" || devices[0].Platform != "desktop" || devices[0].LastCountry != "AR" {
+		t.Errorf("Expected one Chrome/Windows device, got %+v", devices)
+	}
+}
+
+// Test: un dispositivo guardado con el fingerprint anterior se reconoce y se migra
+func TestCheckLogin_MigratesLegacyFingerprint(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, true))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	legacy := utils.LegacyDeviceFingerprint(testBrowser)
+	repo.devices[legacy] = &domain.KnownDevice{ID: 1, UserID: 1, Fingerprint: legacy, UserAgent: testBrowser}
+	repo.networks["181.46.12.0/24"] = &domain.KnownNetwork{UserID: 1, Prefix: "181.46.12.0/24"}
+
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser})
+
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no alerts, got %v", publisher.published)
+	}
+	if fingerprint := repo.devices[legacy].Fingerprint; fingerprint != utils.DeviceFingerprint(testBrowser) {
+		t.Errorf("Expected the device migrated to the new fingerprint, got %s", fingerprint)
+	}
+}
+
+// Test: dispositivo nuevo desde otra red => alerta con ambos eventos
+func TestCheckLogin_NewDeviceAndLocationAlert(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, true))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser})
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "200.1.2.3", UserAgent: testPhone})
+
+	if len(publisher.published) != 1 || publisher.published[0] != "user.security_alert" {
+		t.Fatalf("Expected one user.security_alert, got %v", publisher.published)
+	}
+	if len(repo.events) != 2 || repo.events[0].Device != "Safari (iOS)" {
+		t.Errorf("Expected 2 security events from Safari (iOS), got %+v", repo.events)
+	}
+	if device := publisher.payloads[0]["device"]; device != "Safari (iOS)" {
+		t.Errorf("Expected the device in the alert, got %v", device)
+	}
+}
+
+// Test: con el flag apagado se registran los eventos pero no se envía la alerta
+func TestCheckLogin_AlertsFlagOff(t *testing.T) {
+	repo := newMockSecurityRepository()
+	publisher := &mockPublisher{}
+	service := NewSecurityService(repo, publisher, newTestFlags(t, false))
+	user := dto.UserResponse{ID: 1, Email: "test@example.com"}
+
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "181.46.12.10", UserAgent: testBrowser})
+	service.CheckLogin(context.Background(), user, domain.LoginClient{IP: "200.1.2.3", UserAgent: testPhone})
+
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no alerts, got %v", publisher.published)
+	}
+	if len(repo.events) != 2 {
+		t.Errorf("Expected 2 security events, got %d", len(repo.events))
+	}
+}
+
+// Test: el purge solo borra los eventos más viejos que la retención
+func TestPurgeOldEvents(t *testing.T) {
+	repo := newMockSecurityRepository()
+	repo.events = []domain.SecurityEvent{
+		{ID: 1, CreatedAt: time.Now().Add(-100 * 24 * time.Hour)},
+		{ID: 2, CreatedAt: time.Now().Add(-time.Hour)},
+	}
+	repo.logins = []domain.LoginEvent{{ID: 1, CreatedAt: time.Now().Add(-100 * 24 * time.Hour)}}
+	service := NewSecurityService(repo, &mockPublisher{}, newTestFlags(t, true))
+
+	deleted, err := service.PurgeOldEvents(context.Background(), 90*24*time.Hour)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if deleted != 2 || len(repo.events) != 1 || repo.events[0].ID != 2 || len(repo.logins) != 0 {
+		t.Errorf("Expected only the old event deleted, got deleted=%d events=%v", deleted, repo.events)
+	}
+}
+
+// Test: el historial guarda exitosos y fallidos, y se lista de a páginas
+func TestRecordAndListLogins(t *testing.T) {
+	repo := newMockSecurityRepository()
+	service := NewSecurityService(repo, &mockPublisher{}, newTestFlags(t, true))
+	client := domain.LoginClient{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+
+	service.RecordLogin(context.Background(), 1, "password", client, domain.LoginFailureInvalidCredentials)
+	service.RecordLogin(context.Background(), 1, "password", client, "")
+	service.RecordLogin(context.Background(), 2, "google", client, "")
+
+	history, err := service.ListLogins(context.Background(), 1, dto.PageQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if history.Total != 2 || history.Page != 1 || len(history.Logins) != 1 {
+		t.Fatalf("Expected page 1 of 2 logins, got %+v", history)
+	}
+	if latest := history.Logins[0]; !latest.Success || latest.Failure != "" || latest.IP != client.IP {
+		t.Errorf("Expected the successful login first, got %+v", latest)
+	}
+	if repo.logins[0].Success || repo.logins[0].Failure != domain.LoginFailureInvalidCredentials {
+		t.Errorf("Expected the failed attempt with its reason, got %+v", repo.logins[0])
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"users-api/dto"
+	"users-api/repositories"
+)
+
+// activeWindow es la ventana de "usuarios activos"
+const activeWindow = 30 * 24 * time.Hour
+
+// StatsService calcula las estadísticas de usuarios del panel de admin
+type StatsService interface {
+	GetStats(ctx context.Context, days, weeks int) (*dto.UserStatsResponse, error)
+}
+
+// statsService es la implementación real del servicio
+type statsService struct {
+	repo repositories.StatsRepository
+	now  func() time.Time
+}
+
+// NewStatsService crea una nueva instancia del servicio
+func NewStatsService(repo repositories.StatsRepository) StatsService {
+	return &statsService{repo: repo, now: time.Now}
+}
+
+// GetStats junta los totales y los registros de los últimos days días y weeks semanas
+// Los registros salen de una sola query por día; las semanas se suman acá
+func (s *statsService) GetStats(ctx context.Context, days, weeks int) (*dto.UserStatsResponse, error) {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	firstDay := today.AddDate(0, 0, -(days - 1))
+	firstWeek := startOfISOWeek(today).AddDate(0, 0, -7*(weeks-1))
+
+	byType, err := s.repo.CountByType(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deactivated, err := s.repo.CountDeactivated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	phoneVerified, err := s.repo.CountPhoneVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activeRecently, err := s.repo.CountActiveSince(ctx, now.Add(-activeWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	since := firstDay
+	if firstWeek.Before(since) {
+		since = firstWeek
+	}
+	signups, err := s.repo.SignupsPerDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dto.UserStatsResponse{
+		ByType:           make(map[string]int64, len(byType)),
+		Deactivated:      deactivated,
+		PhoneVerified:    phoneVerified,
+		ActiveLast30Days: activeRecently,
+	}
+	for userType, count := range byType {
+		stats.ByType[string(userType)] = count
+		stats.Total += count
+	}
+	stats.Active = stats.Total - deactivated
+	stats.PhoneUnverified = stats.Total - phoneVerified
+
+	perDay := make(map[string]int64, len(signups))
+	for _, day := range signups {
+		perDay[day.Day] = day.Count
+	}
+	for day := firstDay; !day.After(today); day = day.AddDate(0, 0, 1) {
+		key := day.Format(time.DateOnly)
+		stats.SignupsPerDay = append(stats.SignupsPerDay, dto.PeriodCount{Period: key, Count: perDay[key]})
+	}
+	for week := firstWeek; !week.After(today); week = week.AddDate(0, 0, 7) {
+		var count int64
+		for day := week; day.Before(week.AddDate(0, 0, 7)); day = day.AddDate(0, 0, 1) {
+			count += perDay[day.Format(time.DateOnly)]
+		}
+		year, number := week.ISOWeek()
+		stats.SignupsPerWeek = append(stats.SignupsPerWeek, dto.PeriodCount{Period: fmt.Sprintf("%d-W%02d", year, number), Count: count})
+	}
+
+	return stats, nil
+}
+
+// startOfISOWeek devuelve el lunes de la semana de day
+func startOfISOWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7 // lunes = 0
+	return day.AddDate(0, 0, -offset)
+}
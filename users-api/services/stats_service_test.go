@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/repositories"
+)
+
+// ============================================
+// MOCK del repositorio de estadísticas
+// ============================================
+type mockStatsRepository struct {
+	since time.Time
+}
+
+func (m *mockStatsRepository) CountByType(ctx context.Context) (map[domain.UserType]int64, error) {
+	return map[domain.UserType]int64{domain.UserTypeNormal: 10, domain.UserTypeAdmin: 2}, nil
+}
+
+func (m *mockStatsRepository) CountDeactivated(ctx context.Context) (int64, error) {
+	return 3, nil
+}
+
+func (m *mockStatsRepository) CountPhoneVerified(ctx context.Context) (int64, error) {
+	return 4, nil
+}
+
+func (m *mockStatsRepository) SignupsPerDay(ctx context.Context, since time.Time) ([]repositories.DayCount, error) {
+	m.since = since
+	return []repositories.DayCount{
+		{Day: "2026-03-02", Count: 1}, // lunes de la semana anterior
+		{Day: "2026-03-10", Count: 2}, // martes
+		{Day: "2026-03-12", Count: 4}, // hoy (jueves)
+	}, nil
+}
+
+func (m *mockStatsRepository) CountActiveSince(ctx context.Context, since time.Time) (int64, error) {
+	return 5, nil
+}
+
+// Test: totales y registros con los días sin registros en cero
+func TestGetStats(t *testing.T) {
+	repo := &mockStatsRepository{}
+	service := &statsService{repo: repo, now: func() time.Time {
+		return time.Date(2026, time.March, 12, 15, 0, 0, 0, time.UTC)
+	}}
+
+	stats, err := service.GetStats(context.Background(), 3, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats.Total != 12 || stats.Active != 9 || stats.Deactivated != 3 || stats.ActiveLast30Days != 5 || stats.ByType["admin"] != 2 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if stats.PhoneVerified != 4 || stats.PhoneUnverified != 8 {
+		t.Errorf("Expected 4 verified and 8 unverified phones, got %d and %d", stats.PhoneVerified, stats.PhoneUnverified)
+	}
+
+	// 3 días: 10, 11 y 12 de marzo
+	days := stats.SignupsPerDay
+	if len(days) != 3 || days[0].Period != "2026-03-10" || days[0].Count != 2 || days[1].Count != 0 || days[2].Count != 4 {
+		t.Errorf("Unexpected signups per day: %+v", days)
+	}
+
+	// 2 semanas: la del 2 de marzo y la actual (arranca el lunes 9)
+	weeks := stats.SignupsPerWeek
+	if len(weeks) != 2 || weeks[0].Period != "2026-W10" || weeks[0].Count != 1 || weeks[1].Period != "2026-W11" || weeks[1].Count != 6 {
+		t.Errorf("Unexpected signups per week: %+v", weeks)
+	}
+
+	// Una sola query desde el lunes de la primera semana
+	if !repo.since.Equal(time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected signups since 2026-03-02, got %s", repo.since)
+	}
+}
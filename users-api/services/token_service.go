@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/domain"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/auth"
+	"shared/requestid"
+)
+
+// TokenService revoca JWT antes de que venzan (logout, sesiones robadas)
+// También es el auth.RevocationChecker que consulta el middleware de auth
+type TokenService interface {
+	Logout(ctx context.Context, claims *auth.Claims) error
+	RevokeAll(ctx context.Context, userID uint) error
+	TokenRevoked(ctx context.Context, claims *auth.Claims) (bool, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// tokenService es la implementación real del servicio
+type tokenService struct {
+	users  repositories.UserRepository
+	tokens repositories.TokenRepository
+}
+
+// NewTokenService crea una nueva instancia del servicio
+func NewTokenService(users repositories.UserRepository, tokens repositories.TokenRepository) TokenService {
+	return &tokenService{users: users, tokens: tokens}
+}
+
+// Logout revoca el token con el que se hizo la request hasta que venza
+func (s *tokenService) Logout(ctx context.Context, claims *auth.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		// Tokens emitidos antes de que existiera el jti: vencen solos en 24h
+		return apperrors.BadRequest("this token cannot be revoked, it expires on its own")
+	}
+
+	err := s.tokens.Revoke(ctx, &domain.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	requestid.Logf(ctx, "👋 Logout del usuario %d", claims.UserID)
+	return nil
+}
+
+// RevokeAll invalida todos los tokens que el usuario tiene hasta ahora
+// (también los de impersonación); los logins posteriores valen
+func (s *tokenService) RevokeAll(ctx context.Context, userID uint) error {
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeAll(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	requestid.Logf(ctx, "🔒 Tokens del usuario %d revocados", userID)
+	return nil
+}
+
+// TokenRevoked indica si el token fue revocado (logout o RevokeAll)
+func (s *tokenService) TokenRevoked(ctx context.Context, claims *auth.Claims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	return s.tokens.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt)
+}
+
+// PurgeExpired borra las revocaciones que ya no hacen falta: los tokens
+// revocados vencidos y las revocaciones por usuario más viejas que el token
+// más largo que emitimos. Deja una hora de margen por la tolerancia de reloj
+// del validador (un token vencido hace segundos todavía puede pasar)
+// Lo ejecuta el job programado "purge_revoked_tokens"
+func (s *tokenService) PurgeExpired(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-time.Hour)
+	return s.tokens.DeleteExpired(ctx, before, before.Add(-utils.TokenTTL()))
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ============================================
+// MOCK del repositorio de tokens revocados
+// ============================================
+type mockTokenRepository struct {
+	revoked     map[string]*domain.RevokedToken // por jti
+	revocations map[uint]time.Time              // por usuario
+}
+
+func newMockTokenRepository() *mockTokenRepository {
+	return &mockTokenRepository{revoked: make(map[string]*domain.RevokedToken), revocations: make(map[uint]time.Time)}
+}
+
+func (m *mockTokenRepository) Revoke(ctx context.Context, token *domain.RevokedToken) error {
+	m.revoked[token.JTI] = token
+	return nil
+}
+
+func (m *mockTokenRepository) RevokeAll(ctx context.Context, userID uint, at time.Time) error {
+	m.revocations[userID] = at
+	return nil
+}
+
+func (m *mockTokenRepository) IsRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	if _, ok := m.revoked[jti]; ok && jti != "" {
+		return true, nil
+	}
+	at, ok := m.revocations[userID]
+	return ok && !at.Before(issuedAt), nil
+}
+
+func (m *mockTokenRepository) DeleteExpired(ctx context.Context, tokensBefore, revocationsBefore time.Time) (int64, error) {
+	var deleted int64
+	for jti, token := range m.revoked {
+		if token.ExpiresAt.Before(tokensBefore) {
+			delete(m.revoked, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// newTokenTestService crea el servicio con un usuario y devuelve los claims de un login suyo
+func newTokenTestService(t *testing.T) (TokenService, *auth.Claims) {
+	t.Helper()
+	users := newMockUserRepository()
+	users.Create(context.Background(), &domain.User{Username: "ana", Email: "ana@example.com"})
+
+	token, err := utils.GenerateToken(utils.TokenUser{ID: 1, Username: "ana", UserType: "normal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTokenService(users, newMockTokenRepository()), claims
+}
+
+// Test: después del logout el token queda revocado (y solo ese)
+func TestLogout_RevokesToken(t *testing.T) {
+	service, claims := newTokenTestService(t)
+	ctx := context.Background()
+
+	if claims.ID == "" {
+		t.Fatal("Expected the token to have a jti")
+	}
+	if err := service.Logout(ctx, claims); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revoked, _ := service.TokenRevoked(ctx, claims); !revoked {
+		t.Error("Expected the token to be revoked")
+	}
+
+	other := *claims
+	other.ID = "otro-token"
+	if revoked, _ := service.TokenRevoked(ctx, &other); revoked {
+		t.Error("Expected other tokens of the user to stay valid")
+	}
+}
+
+// Test: un token sin jti (emitido antes de que existiera) no se puede revocar solo
+func TestLogout_TokenWithoutID(t *testing.T) {
+	service, claims := newTokenTestService(t)
+	claims.ID = ""
+
+	if err := service.Logout(context.Background(), claims); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("Expected bad request, got %v", err)
+	}
+}
+
+// Test: RevokeAll invalida los tokens emitidos hasta ahora, no los posteriores
+func TestRevokeAll(t *testing.T) {
+	service, claims := newTokenTestService(t)
+	ctx := context.Background()
+
+	if err := service.RevokeAll(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revoked, _ := service.TokenRevoked(ctx, claims); !revoked {
+		t.Error("Expected the existing token to be revoked")
+	}
+
+	later := *claims
+	later.IssuedAt = jwt.NewNumericDate(time.Now().Add(2 * time.Second))
+	if revoked, _ := service.TokenRevoked(ctx, &later); revoked {
+		t.Error("Expected a token issued after the revocation to be valid")
+	}
+
+	if err := service.RevokeAll(ctx, 99); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown user, got %v", err)
+	}
+}
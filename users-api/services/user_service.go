@@ -1,66 +1,148 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 	"users-api/domain"
 	"users-api/dto"
 	"users-api/repositories"
 	"users-api/utils"
+
+	"shared/apperrors"
+	"shared/requestid"
 )
 
 // UserService define la interfaz del servicio
 type UserService interface {
-	CreateUser(req dto.CreateUserRequest) (*domain.User, error)
-	GetUserByID(id uint) (*domain.User, error)
-	Login(req dto.LoginRequest) (*dto.LoginResponse, error)
+	CreateUser(ctx context.Context, req dto.CreateUserRequest) (*domain.User, error)
+	BootstrapAdmin(ctx context.Context, req dto.CreateUserRequest) (*domain.User, error)
+	GetUserByID(ctx context.Context, id uint) (*domain.User, error)
+	Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error)
+	UpdateUser(ctx context.Context, id uint, req dto.UpdateUserRequest) (*domain.User, error)
+	UpdateMe(ctx context.Context, id uint, req dto.UpdateMeRequest) (*domain.User, error)
+	DeleteUser(ctx context.Context, id uint) error
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	GetUserByLogin(ctx context.Context, usernameOrEmail string) (*domain.User, error)
+	SetUserType(ctx context.Context, id uint, userType domain.UserType) (*domain.User, error)
+	SetActive(ctx context.Context, id uint, active bool) (*domain.User, error)
+	ExportUsers(ctx context.Context, query dto.ExportUsersQuery, fn func([]domain.User) error) error
+	NormalizeEmails(ctx context.Context) (updated int, conflicts []uint, err error)
 }
 
+// exportBatchSize es cuántos usuarios se leen por query al exportar
+const exportBatchSize = 1000
+
 // userService es la implementación real del servicio
 // Tiene un repositorio para acceder a la base de datos
 type userService struct {
-	repo repositories.UserRepository
+	repo         repositories.UserRepository
+	uow          repositories.UnitOfWork // escrituras en varias tablas (alta de usuario)
+	registration RegistrationConfig
 }
 
 // NewUserService crea una nueva instancia del servicio
-func NewUserService(repo repositories.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repositories.UserRepository, uow repositories.UnitOfWork, registration RegistrationConfig) UserService {
+	return &userService{repo: repo, uow: uow, registration: registration}
+}
+
+// CreateUser crea un nuevo usuario con rol normal, o con el de la invitación
+// si viene invitation_code (obligatorio en modo invite_only)
+func (s *userService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*domain.User, error) {
+	if s.registration.InviteOnly && strings.TrimSpace(req.InvitationCode) == "" {
+		return nil, apperrors.Forbidden("registration requires an invitation code")
+	}
+	return s.createUser(ctx, req, domain.UserTypeNormal)
 }
 
-// CreateUser crea un nuevo usuario
+// BootstrapAdmin crea el primer administrador si todavía no hay ninguno
+// (sin un admin no hay forma de llegar a las rutas de /admin)
+// Si ya hay uno no hace nada y devuelve nil: la configuración puede quedar
+// puesta sin que cada arranque cree otro admin. Si el username o el email ya
+// son de otro usuario no lo promueve (lo pudo haber registrado cualquiera
+// antes del arranque) y devuelve el Conflict
+func (s *userService) BootstrapAdmin(ctx context.Context, req dto.CreateUserRequest) (*domain.User, error) {
+	admins, err := s.repo.FindIDs(ctx, repositories.UserFilter{UserType: domain.UserTypeAdmin}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(admins) > 0 {
+		return nil, nil
+	}
+	return s.createUser(ctx, req, domain.UserTypeAdmin)
+}
+
+// createUser crea un usuario con el rol indicado
 // Aquí va toda la lógica: validaciones, hashear password, etc.
-func (s *userService) CreateUser(req dto.CreateUserRequest) (*domain.User, error) {
+func (s *userService) createUser(ctx context.Context, req dto.CreateUserRequest, userType domain.UserType) (*domain.User, error) {
+	req.Email = utils.CleanEmail(req.Email)
+
 	// 1. Verificar si el username ya existe
-	existingUser, _ := s.repo.GetByUsername(req.Username)
+	// Si otra request lo registra entre el chequeo y el INSERT, Create
+	// devuelve el mismo Conflict (ver repositories.ErrDuplicate)
+	existingUser, _ := s.repo.GetByUsername(ctx, req.Username)
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, apperrors.Conflict("username already exists")
 	}
 
 	// 2. Verificar si el email ya existe
-	existingUser, _ = s.repo.GetByEmail(req.Email)
+	existingUser, _ = s.repo.GetByEmail(ctx, req.Email)
 	if existingUser != nil {
-		return nil, errors.New("email already exists")
+		return nil, apperrors.Conflict("email already exists")
 	}
 
 	// 3. Hashear la contraseña
 	// NUNCA guardamos contraseñas en texto plano
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
-		return nil, errors.New("error hashing password")
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error hashing password", err)
 	}
 
-	// 4. Crear el objeto User
+	// 4. Teléfono opcional, normalizado a E.164 (se verifica después por SMS)
+	phone := ""
+	if req.Phone != "" {
+		phone, err = utils.NormalizePhone(req.Phone)
+		if err != nil {
+			return nil, apperrors.Validation(err.Error())
+		}
+	}
+
+	// 5. Crear el objeto User
 	user := &domain.User{
 		Username:  req.Username,
 		Email:     req.Email,
 		Password:  hashedPassword, // Guardamos el hash, no la contraseña
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
-		UserType:  domain.UserTypeNormal, // Por defecto es usuario normal
+		UserType:  userType, // Normal, salvo el admin inicial o una invitación
+		Phone:     phone,
 	}
 
-	// 5. Guardar en la base de datos
-	err = s.repo.Create(user)
+	// 6. Guardar el usuario y sus preferencias por defecto en una transacción:
+	// si falla cualquiera de los dos no queda nada a medias. Con invitación,
+	// el usuario toma su rol y el código se consume en la misma transacción
+	err = s.uow.Do(ctx, func(tx repositories.Tx) error {
+		var invitation *domain.Invitation
+		if strings.TrimSpace(req.InvitationCode) != "" {
+			redeemed, err := redeemInvitation(ctx, tx, req.InvitationCode, user.Email)
+			if err != nil {
+				return err
+			}
+			invitation = redeemed
+			user.UserType = invitation.UserType
+		}
+
+		if err := tx.Users.Create(ctx, user); err != nil {
+			return err
+		}
+		if invitation != nil {
+			if err := tx.Invitations.MarkUsed(ctx, invitation.ID, user.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+		return tx.Preferences.Save(ctx, domain.DefaultNotificationPreferences(user.ID))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -70,116 +152,300 @@ func (s *userService) CreateUser(req dto.CreateUserRequest) (*domain.User, error
 
 // GetUserByID obtiene un usuario por su ID
 // Esta función es simple, solo delega al repositorio
-func (s *userService) GetUserByID(id uint) (*domain.User, error) {
-	return s.repo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
 // Login autentica un usuario y genera un token JWT
 // Esta es la función más importante del servicio
-func (s *userService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
-	var user *domain.User
-	var err error
-
-	// 1. Determinar si el usuario está intentando loguearse con username o email
-	// Si contiene "@" asumimos que es email
-	if strings.Contains(req.UsernameOrEmail, "@") {
-		user, err = s.repo.GetByEmail(req.UsernameOrEmail)
-	} else {
-		user, err = s.repo.GetByUsername(req.UsernameOrEmail)
-	}
+func (s *userService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+	// 1. Buscar el usuario por username o email
+	user, err := s.GetUserByLogin(ctx, req.UsernameOrEmail)
 
 	// 2. Si no encontramos el usuario, devolvemos error genérico
 	// (Por seguridad, no decimos si el username existe o no)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, apperrors.Unauthorized("invalid credentials")
 	}
 
 	// 3. Verificar que la contraseña sea correcta
 	// Comparamos el hash guardado con la contraseña que envió
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid credentials")
+		return nil, apperrors.Unauthorized("invalid credentials")
 	}
+	if err := loginAllowed(user); err != nil {
+		return nil, err
+	}
+	s.rehashPassword(ctx, user, req.Password)
 
 	// 4. Generar el token JWT
 	// Este token contiene: user_id, username, user_type
-	token, err := utils.GenerateToken(user.ID, user.Username, string(user.UserType))
+	token, err := utils.GenerateToken(tokenUser(user))
 	if err != nil {
-		return nil, errors.New("error generating token")
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating token", err)
 	}
 
 	// 5. Devolver el token y los datos del usuario
 	return &dto.LoginResponse{
 		Token: token,
-		User:  *user,
+		User:  dto.NewUserResponse(user),
 	}, nil
+}
 
-	// UpdateUser actualiza los datos de un usuario existente
-	func (s *userService) UpdateUser(id uint, req dto.UpdateUserRequest) (*domain.User, error) {
-		// 1. Verificar que el usuario existe
-		user, err := s.repo.GetByID(id)
-		if err != nil {
-			return nil, errors.New("user not found")
+// UpdateUser actualiza los datos de un usuario existente
+func (s *userService) UpdateUser(ctx context.Context, id uint, req dto.UpdateUserRequest) (*domain.User, error) {
+	// 1. Verificar que el usuario existe
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Si se proporciona un nuevo username, verificar que no esté en uso
+	if req.Username != "" && req.Username != user.Username {
+		existingUser, _ := s.repo.GetByUsername(ctx, req.Username)
+		if existingUser != nil {
+			return nil, apperrors.Conflict("username already exists")
 		}
+		user.Username = req.Username
+	}
 
-		// 2. Si se proporciona un nuevo username, verificar que no esté en uso
-		if req.Username != "" && req.Username != user.Username {
-			existingUser, _ := s.repo.GetByUsername(req.Username)
-			if existingUser != nil {
-				return nil, errors.New("username already exists")
-			}
-			user.Username = req.Username
+	// 3. Si se proporciona un nuevo email, verificar que no esté en uso
+	// (por otro usuario: cambiar solo las mayúsculas del propio se permite)
+	if email := utils.CleanEmail(req.Email); email != "" && email != user.Email {
+		existingUser, _ := s.repo.GetByEmail(ctx, email)
+		if existingUser != nil && existingUser.ID != user.ID {
+			return nil, apperrors.Conflict("email already exists")
 		}
+		user.Email = email
+	}
 
-		// 3. Si se proporciona un nuevo email, verificar que no esté en uso
-		if req.Email != "" && req.Email != user.Email {
-			existingUser, _ := s.repo.GetByEmail(req.Email)
-			if existingUser != nil {
-				return nil, errors.New("email already exists")
-			}
-			user.Email = req.Email
+	// 4. Actualizar otros campos si se proporcionan
+	if req.FirstName != "" {
+		user.FirstName = req.FirstName
+	}
+
+	if req.LastName != "" {
+		user.LastName = req.LastName
+	}
+
+	if req.Phone != "" {
+		phone, err := utils.NormalizePhone(req.Phone)
+		if err != nil {
+			return nil, apperrors.Validation(err.Error())
 		}
+		user.SetPhone(phone)
+	}
 
-		// 4. Actualizar otros campos si se proporcionan
-		if req.FirstName != "" {
-			user.FirstName = req.FirstName
+	if req.Locale != "" {
+		locale, err := utils.NormalizeLocale(req.Locale)
+		if err != nil {
+			return nil, apperrors.Validation(err.Error())
 		}
+		user.Locale = locale
+	}
 
-		if req.LastName != "" {
-			user.LastName = req.LastName
+	if req.Timezone != "" {
+		timezone, err := utils.NormalizeTimezone(req.Timezone)
+		if err != nil {
+			return nil, apperrors.Validation(err.Error())
 		}
+		user.Timezone = timezone
+	}
 
-		// 5. Si se proporciona una nueva contraseña, hashearla
-		if req.Password != "" {
-			hashedPassword, err := utils.HashPassword(req.Password)
-			if err != nil {
-				return nil, errors.New("error hashing password")
-			}
-			user.Password = hashedPassword
+	// 5. Si se proporciona una nueva contraseña, hashearla
+	if req.Password != "" {
+		hashedPassword, err := utils.HashPassword(req.Password)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.CodeInternal, "error hashing password", err)
 		}
+		user.Password = hashedPassword
+	}
+
+	// 6. Guardar los cambios en la base de datos
+	err = s.repo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
 
-		// 6. Guardar los cambios en la base de datos
-		err = s.repo.Update(user)
+// UpdateMe actualiza el perfil del propio usuario
+// Si cambia la contraseña o el email exige la actual (salvo que la cuenta no
+// tenga, ej: creada con Google), así un token robado no alcanza para quedarse
+// con la cuenta: con el email cambiado entraría por magic link
+func (s *userService) UpdateMe(ctx context.Context, id uint, req dto.UpdateMeRequest) (*domain.User, error) {
+	if req.Password != "" || req.Email != "" {
+		user, err := s.repo.GetByID(ctx, id)
 		if err != nil {
 			return nil, err
 		}
+		changesEmail := req.Email != "" && utils.NormalizeEmail(req.Email) != utils.NormalizeEmail(user.Email)
+		if (req.Password != "" || changesEmail) && user.HasPassword() &&
+			!utils.CheckPasswordHash(req.CurrentPassword, user.Password) {
+			return nil, apperrors.Forbidden("current password is incorrect")
+		}
+	}
+
+	return s.UpdateUser(ctx, id, dto.UpdateUserRequest{
+		Username:  req.Username,
+		Email:     req.Email,
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Locale:    req.Locale,
+		Timezone:  req.Timezone,
+	})
+}
+
+// DeleteUser elimina un usuario por su ID
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	// 1. Verificar que el usuario existe
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 2. Eliminar el usuario
+	return s.repo.Delete(ctx, id)
+}
+
+// GetAllUsers obtiene todos los usuarios del sistema
+// Solo accesible por administradores
+func (s *userService) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetUserByLogin busca un usuario por username o email
+// Si contiene "@" asumimos que es email
+func (s *userService) GetUserByLogin(ctx context.Context, usernameOrEmail string) (*domain.User, error) {
+	if strings.Contains(usernameOrEmail, "@") {
+		return s.repo.GetByEmail(ctx, usernameOrEmail)
+	}
+	return s.repo.GetByUsername(ctx, usernameOrEmail)
+}
+
+// NormalizeEmails completa el email normalizado de los usuarios que no lo
+// tienen al día: los anteriores a la columna, o todos si cambió
+// EMAIL_STRIP_PLUS_ADDRESSING. main lo corre al arrancar
+// Los que chocan con otra cuenta (ej: Ana@x.com y ana@x.com registrados antes)
+// quedan en conflicts, sin tocar: un admin tiene que fusionarlos
+// (POST /admin/users/:id/merge)
+func (s *userService) NormalizeEmails(ctx context.Context) (updated int, conflicts []uint, err error) {
+	err = s.repo.EachBatch(ctx, repositories.UserFilter{}, exportBatchSize, func(users []domain.User) error {
+		for i := range users {
+			user := &users[i]
+			if user.EmailNormalized != nil && *user.EmailNormalized == utils.NormalizeEmail(user.Email) {
+				continue
+			}
+			err := s.repo.Update(ctx, user)
+			if errors.Is(err, repositories.ErrDuplicate) {
+				conflicts = append(conflicts, user.ID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	return updated, conflicts, err
+}
+
+// SetUserType cambia el rol de un usuario (normal o admin)
+func (s *userService) SetUserType(ctx context.Context, id uint, userType domain.UserType) (*domain.User, error) {
+	if userType != domain.UserTypeNormal && userType != domain.UserTypeAdmin {
+		return nil, apperrors.Newf(apperrors.CodeValidation, "invalid user type: %s", userType)
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.UserType = userType
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
 
+// SetActive desactiva o reactiva la cuenta de un usuario
+// Una cuenta desactivada no puede iniciar sesión; los tokens ya emitidos
+// siguen valiendo hasta vencer
+func (s *userService) SetActive(ctx context.Context, id uint, active bool) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Active() == active {
 		return user, nil
 	}
+	if active && user.MergedInto != nil {
+		return nil, apperrors.Conflict("account was merged into another and cannot be reactivated")
+	}
 
-	// DeleteUser elimina un usuario por su ID
-	func (s *userService) DeleteUser(id uint) error {
-		// 1. Verificar que el usuario existe
-		_, err := s.repo.GetByID(id)
-		if err != nil {
-		return errors.New("user not found")
+	if active {
+		user.DeactivatedAt = nil
+	} else {
+		now := time.Now()
+		user.DeactivatedAt = &now
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
 	}
+	return user, nil
+}
+
+// tokenUser son los datos del usuario que van en su JWT
+func tokenUser(user *domain.User) utils.TokenUser {
+	return utils.TokenUser{
+		ID:       user.ID,
+		Username: user.Username,
+		UserType: string(user.UserType),
+		Locale:   user.Locale,
+		Timezone: user.Timezone,
+	}
+}
+
+// loginAllowed rechaza el login (con cualquier método) de una cuenta
+// desactivada o suspendida
+func loginAllowed(user *domain.User) error {
+	if !user.Active() {
+		return apperrors.Forbidden("account is deactivated")
+	}
+	if user.Banned(time.Now()) {
+		return apperrors.Newf(apperrors.CodeForbidden, "account is banned until %s", user.BannedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
 
-		// 2. Eliminar el usuario
-		return s.repo.Delete(id)
+// rehashPassword migra el hash al formato actual (pepper nuevo o primero)
+// Solo se puede en el login, que es cuando se tiene la contraseña en claro
+// Si falla se loguea y queda para el próximo login
+func (s *userService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !utils.PasswordNeedsRehash(user.Password) {
+		return
 	}
+	hash, err := utils.HashPassword(password)
+	if err == nil {
+		user.Password = hash
+		err = s.repo.Update(ctx, user)
+	}
+	if err != nil {
+		requestid.Logf(ctx, "⚠️  No se pudo migrar el hash de la contraseña del usuario %d: %v", user.ID, err)
+	}
+}
 
-	// GetAllUsers obtiene todos los usuarios del sistema
-	// Solo accesible por administradores
-	func (s *userService) GetAllUsers() ([]domain.User, error) {
-		return s.repo.GetAll()
+// ExportUsers recorre los usuarios filtrados de a lotes (ver
+// UserRepository.EachBatch) y se los pasa a fn, que los escribe
+func (s *userService) ExportUsers(ctx context.Context, query dto.ExportUsersQuery, fn func([]domain.User) error) error {
+	filter := repositories.UserFilter{
+		UserType:      domain.UserType(query.UserType),
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		Deactivated:   query.Deactivated,
 	}
+	return s.repo.EachBatch(ctx, filter, exportBatchSize, fn)
+}
@@ -1,10 +1,17 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"strings"
 	"testing"
 	"users-api/domain"
 	"users-api/dto"
+	"users-api/repositories"
+	"users-api/utils"
+
+	"shared/apperrors"
 )
 
 // ============================================
@@ -20,55 +27,117 @@ func newMockUserRepository() *mockUserRepository {
 	}
 }
 
-func (m *mockUserRepository) Create(user *domain.User) error {
+func (m *mockUserRepository) Create(ctx context.Context, user *domain.User) error {
 	// Simular auto-increment del ID
 	user.ID = uint(len(m.users) + 1)
 	m.users[user.ID] = user
 	return nil
 }
 
-func (m *mockUserRepository) GetByID(id uint) (*domain.User, error) {
+func (m *mockUserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	user, exists := m.users[id]
 	if !exists {
-		return nil, errors.New("user not found")
+		return nil, apperrors.NotFound("user not found")
 	}
 	return user, nil
 }
 
-func (m *mockUserRepository) GetByUsername(username string) (*domain.User, error) {
+func (m *mockUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	for _, user := range m.users {
 		if user.Username == username {
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, apperrors.NotFound("user not found")
 }
 
-func (m *mockUserRepository) GetByEmail(email string) (*domain.User, error) {
+func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	for _, user := range m.users {
-		if user.Email == email {
+		if utils.NormalizeEmail(user.Email) == utils.NormalizeEmail(email) {
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, apperrors.NotFound("user not found")
 }
 
-func (m *mockUserRepository) Update(user *domain.User) error {
+func (m *mockUserRepository) GetByProvider(ctx context.Context, provider, providerID string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.Provider == provider && user.ProviderID != nil && *user.ProviderID == providerID {
+			return user, nil
+		}
+	}
+	return nil, apperrors.NotFound("user not found")
+}
+
+func (m *mockUserRepository) Update(ctx context.Context, user *domain.User) error {
 	if _, exists := m.users[user.ID]; !exists {
-		return errors.New("user not found")
+		return apperrors.NotFound("user not found")
 	}
 	m.users[user.ID] = user
 	return nil
 }
 
-func (m *mockUserRepository) Delete(id uint) error {
+func (m *mockUserRepository) Delete(ctx context.Context, id uint) error {
 	if _, exists := m.users[id]; !exists {
-		return errors.New("user not found")
+		return apperrors.NotFound("user not found")
 	}
 	delete(m.users, id)
 	return nil
 }
 
+func (m *mockUserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(m.users))
+	for _, user := range m.users {
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (m *mockUserRepository) FindIDs(ctx context.Context, filter repositories.UserFilter, limit int) ([]uint, error) {
+	ids := []uint{}
+	for id, user := range m.users {
+		if filter.UserType != "" && user.UserType != filter.UserType {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (m *mockUserRepository) EachBatch(ctx context.Context, filter repositories.UserFilter, size int, fn func([]domain.User) error) error {
+	ids, _ := m.FindIDs(ctx, filter, len(m.users))
+	for start := 0; start < len(ids); start += size {
+		batch := []domain.User{}
+		for _, id := range ids[start:min(start+size, len(ids))] {
+			batch = append(batch, *m.users[id])
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memoryUnitOfWork corre fn sobre los mocks (sin rollback)
+type memoryUnitOfWork struct {
+	users       repositories.UserRepository
+	prefs       repositories.PreferencesRepository
+	invitations repositories.InvitationRepository
+}
+
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(tx repositories.Tx) error) error {
+	return fn(repositories.Tx{Users: u.users, Preferences: u.prefs, Invitations: u.invitations})
+}
+
+// newTestUserService arma el servicio con preferencias en memoria
+func newTestUserService(repo repositories.UserRepository) UserService {
+	return NewUserService(repo, &memoryUnitOfWork{users: repo, prefs: newMockPreferencesRepository(), invitations: newMockInvitationRepository()}, RegistrationConfig{})
+}
+
 // ============================================
 // TESTS
 // ============================================
@@ -76,7 +145,7 @@ func (m *mockUserRepository) Delete(id uint) error {
 // Test: Crear usuario exitosamente
 func TestCreateUser_Success(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	req := dto.CreateUserRequest{
 		Username:  "testuser",
@@ -86,7 +155,7 @@ func TestCreateUser_Success(t *testing.T) {
 		LastName:  "User",
 	}
 
-	user, err := service.CreateUser(req)
+	user, err := service.CreateUser(context.Background(), req)
 
 	// Verificaciones
 	if err != nil {
@@ -115,10 +184,41 @@ func TestCreateUser_Success(t *testing.T) {
 	}
 }
 
+// failingPreferencesRepository falla al guardar
+type failingPreferencesRepository struct {
+	mockPreferencesRepository
+}
+
+func (m *failingPreferencesRepository) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	return errors.New("connection lost")
+}
+
+// Test: el alta guarda las preferencias por defecto en la misma unidad de
+// trabajo y falla si no se pueden guardar
+func TestCreateUser_Preferences(t *testing.T) {
+	req := dto.CreateUserRequest{Username: "ana", Email: "ana@example.com", Password: "password123", FirstName: "Ana", LastName: "Perez"}
+
+	repo := newMockUserRepository()
+	prefs := newMockPreferencesRepository()
+	user, err := NewUserService(repo, &memoryUnitOfWork{users: repo, prefs: prefs}, RegistrationConfig{}).CreateUser(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved := prefs.prefs[user.ID]; saved == nil || !saved.EmailTransactional || saved.EmailMarketing {
+		t.Errorf("Expected default preferences, got %+v", saved)
+	}
+
+	repo = newMockUserRepository()
+	failing := &memoryUnitOfWork{users: repo, prefs: &failingPreferencesRepository{}}
+	if _, err := NewUserService(repo, failing, RegistrationConfig{}).CreateUser(context.Background(), req); err == nil {
+		t.Error("Expected the preferences error to fail the creation")
+	}
+}
+
 // Test: Error al crear usuario con username duplicado
 func TestCreateUser_DuplicateUsername(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear primer usuario
 	req1 := dto.CreateUserRequest{
@@ -128,7 +228,7 @@ func TestCreateUser_DuplicateUsername(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	service.CreateUser(req1)
+	service.CreateUser(context.Background(), req1)
 
 	// Intentar crear segundo usuario con mismo username
 	req2 := dto.CreateUserRequest{
@@ -139,7 +239,7 @@ func TestCreateUser_DuplicateUsername(t *testing.T) {
 		LastName:  "User",
 	}
 
-	user, err := service.CreateUser(req2)
+	user, err := service.CreateUser(context.Background(), req2)
 
 	// Verificaciones
 	if err == nil {
@@ -158,7 +258,7 @@ func TestCreateUser_DuplicateUsername(t *testing.T) {
 // Test: Error al crear usuario con email duplicado
 func TestCreateUser_DuplicateEmail(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear primer usuario
 	req1 := dto.CreateUserRequest{
@@ -168,7 +268,7 @@ func TestCreateUser_DuplicateEmail(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	service.CreateUser(req1)
+	service.CreateUser(context.Background(), req1)
 
 	// Intentar crear segundo usuario con mismo email
 	req2 := dto.CreateUserRequest{
@@ -179,7 +279,7 @@ func TestCreateUser_DuplicateEmail(t *testing.T) {
 		LastName:  "User",
 	}
 
-	user, err := service.CreateUser(req2)
+	user, err := service.CreateUser(context.Background(), req2)
 
 	// Verificaciones
 	if err == nil {
@@ -198,7 +298,7 @@ func TestCreateUser_DuplicateEmail(t *testing.T) {
 // Test: Login exitoso con username
 func TestLogin_SuccessWithUsername(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear usuario
 	createReq := dto.CreateUserRequest{
@@ -208,7 +308,7 @@ func TestLogin_SuccessWithUsername(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	service.CreateUser(createReq)
+	service.CreateUser(context.Background(), createReq)
 
 	// Intentar login
 	loginReq := dto.LoginRequest{
@@ -216,7 +316,7 @@ func TestLogin_SuccessWithUsername(t *testing.T) {
 		Password:        "password123",
 	}
 
-	response, err := service.Login(loginReq)
+	response, err := service.Login(context.Background(), loginReq)
 
 	// Verificaciones
 	if err != nil {
@@ -237,9 +337,54 @@ func TestLogin_SuccessWithUsername(t *testing.T) {
 }
 
 // Test: Login exitoso con email
+// Test: con pepper configurado, un hash sin pepper se migra en el login
+func TestLogin_RehashesWithPepper(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+	service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "testuser", Email: "test@example.com", Password: "password123", FirstName: "Test", LastName: "User",
+	})
+
+	utils.ConfigurePepper("1", "pepper", nil)
+	t.Cleanup(func() { utils.ConfigurePepper("", "", nil) })
+
+	if _, err := service.Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	hash := repo.users[1].Password
+	if utils.PasswordNeedsRehash(hash) || !utils.CheckPasswordHash("password123", hash) {
+		t.Errorf("Expected the hash migrated to the peppered format, got %q", hash)
+	}
+
+	// El login sigue funcionando con el hash nuevo
+	if _, err := service.Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"}); err != nil {
+		t.Errorf("Expected login with the new hash, got %v", err)
+	}
+}
+
+// Test: con PASSWORD_HASHER=argon2id, un hash bcrypt se migra en el login
+func TestLogin_RehashesToArgon2id(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+	service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "testuser", Email: "test@example.com", Password: "password123", FirstName: "Test", LastName: "User",
+	})
+
+	utils.ConfigurePasswordHasher(utils.HasherArgon2id)
+	t.Cleanup(func() { utils.ConfigurePasswordHasher("") })
+
+	if _, err := service.Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	hash := repo.users[1].Password
+	if !strings.HasPrefix(hash, "$argon2id$") || !utils.CheckPasswordHash("password123", hash) {
+		t.Errorf("Expected the hash migrated to Argon2id, got %q", hash)
+	}
+}
+
 func TestLogin_SuccessWithEmail(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear usuario
 	createReq := dto.CreateUserRequest{
@@ -249,7 +394,7 @@ func TestLogin_SuccessWithEmail(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	service.CreateUser(createReq)
+	service.CreateUser(context.Background(), createReq)
 
 	// Intentar login con email
 	loginReq := dto.LoginRequest{
@@ -257,7 +402,7 @@ func TestLogin_SuccessWithEmail(t *testing.T) {
 		Password:        "password123",
 	}
 
-	response, err := service.Login(loginReq)
+	response, err := service.Login(context.Background(), loginReq)
 
 	// Verificaciones
 	if err != nil {
@@ -276,14 +421,14 @@ func TestLogin_SuccessWithEmail(t *testing.T) {
 // Test: Login fallido - usuario no existe
 func TestLogin_UserNotFound(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	loginReq := dto.LoginRequest{
 		UsernameOrEmail: "nonexistent",
 		Password:        "password123",
 	}
 
-	response, err := service.Login(loginReq)
+	response, err := service.Login(context.Background(), loginReq)
 
 	// Verificaciones
 	if err == nil {
@@ -302,7 +447,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 // Test: Login fallido - contraseña incorrecta
 func TestLogin_WrongPassword(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear usuario
 	createReq := dto.CreateUserRequest{
@@ -312,7 +457,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	service.CreateUser(createReq)
+	service.CreateUser(context.Background(), createReq)
 
 	// Intentar login con contraseña incorrecta
 	loginReq := dto.LoginRequest{
@@ -320,7 +465,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 		Password:        "wrongpassword",
 	}
 
-	response, err := service.Login(loginReq)
+	response, err := service.Login(context.Background(), loginReq)
 
 	// Verificaciones
 	if err == nil {
@@ -336,10 +481,37 @@ func TestLogin_WrongPassword(t *testing.T) {
 	}
 }
 
+// Test: Login de una cuenta desactivada (y reactivada)
+func TestLogin_Deactivated(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+
+	user, _ := service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	loginReq := dto.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"}
+
+	if _, err := service.SetActive(context.Background(), user.ID, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Login(context.Background(), loginReq); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden for a deactivated account, got %v", err)
+	}
+
+	if _, err := service.SetActive(context.Background(), user.ID, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Login(context.Background(), loginReq); err != nil {
+		t.Errorf("Expected login after reactivation, got %v", err)
+	}
+}
+
 // Test: Obtener usuario por ID exitosamente
 func TestGetUserByID_Success(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Crear usuario
 	createReq := dto.CreateUserRequest{
@@ -349,10 +521,10 @@ func TestGetUserByID_Success(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	createdUser, _ := service.CreateUser(createReq)
+	createdUser, _ := service.CreateUser(context.Background(), createReq)
 
 	// Obtener usuario por ID
-	user, err := service.GetUserByID(createdUser.ID)
+	user, err := service.GetUserByID(context.Background(), createdUser.ID)
 
 	// Verificaciones
 	if err != nil {
@@ -371,10 +543,10 @@ func TestGetUserByID_Success(t *testing.T) {
 // Test: Error al obtener usuario que no existe
 func TestGetUserByID_NotFound(t *testing.T) {
 	repo := newMockUserRepository()
-	service := NewUserService(repo)
+	service := newTestUserService(repo)
 
 	// Intentar obtener usuario con ID inexistente
-	user, err := service.GetUserByID(999)
+	user, err := service.GetUserByID(context.Background(), 999)
 
 	// Verificaciones
 	if err == nil {
@@ -385,3 +557,247 @@ func TestGetUserByID_NotFound(t *testing.T) {
 		t.Error("Expected nil user, got user")
 	}
 }
+
+// Test: el propio usuario edita su perfil; la contraseña nueva pide la actual
+func TestUpdateMe(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+
+	createdUser, _ := service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	user, err := service.UpdateMe(context.Background(), createdUser.ID, dto.UpdateMeRequest{FirstName: "Nuevo"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.FirstName != "Nuevo" || user.UserType != domain.UserTypeNormal {
+		t.Errorf("Expected only the name to change, got %+v", user)
+	}
+
+	_, err = service.UpdateMe(context.Background(), createdUser.ID, dto.UpdateMeRequest{
+		Password:        "newpassword",
+		CurrentPassword: "wrongpassword",
+	})
+	if !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden with a wrong current password, got %v", err)
+	}
+
+	_, err = service.UpdateMe(context.Background(), createdUser.ID, dto.UpdateMeRequest{
+		Password:        "newpassword",
+		CurrentPassword: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "testuser", Password: "newpassword"}); err != nil {
+		t.Errorf("Expected login with the new password, got %v", err)
+	}
+}
+
+// Test: cambiar solo el email también pide la contraseña actual; con el email
+// cambiado un token robado alcanzaría para entrar por magic link
+func TestUpdateMe_EmailRequiresCurrentPassword(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+	ctx := context.Background()
+	service.CreateUser(ctx, dto.CreateUserRequest{Username: "testuser", Email: "test@example.com", Password: "password123"})
+
+	if _, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Email: "attacker@example.com"}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("Expected forbidden without the current password, got %v", err)
+	}
+	if user, _ := repo.GetByID(ctx, 1); user.Email != "test@example.com" {
+		t.Errorf("Expected the email not to change, got %s", user.Email)
+	}
+
+	// Mandar el mismo email (ej: el formulario completo) no lo pide
+	if _, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Email: "test@example.com", FirstName: "Nuevo"}); err != nil {
+		t.Errorf("Expected no error with the same email, got %v", err)
+	}
+
+	user, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Email: "new@example.com", CurrentPassword: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Email != "new@example.com" {
+		t.Errorf("Expected the new email, got %s", user.Email)
+	}
+}
+
+// Test: locale y zona horaria se validan, se guardan canónicos y van en el JWT
+func TestUpdateMe_LocaleAndTimezone(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+	ctx := context.Background()
+	service.CreateUser(ctx, dto.CreateUserRequest{Username: "testuser", Email: "test@example.com", Password: "password123"})
+
+	if _, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Locale: "español"}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for the locale, got %v", err)
+	}
+	if _, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Timezone: "GMT-3"}); !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error for the timezone, got %v", err)
+	}
+
+	user, err := service.UpdateMe(ctx, 1, dto.UpdateMeRequest{Locale: "es_ar", Timezone: "America/Argentina/Cordoba"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Locale != "es-AR" || user.Timezone != "America/Argentina/Cordoba" {
+		t.Errorf("Unexpected locale %q and timezone %q", user.Locale, user.Timezone)
+	}
+
+	response, err := service.Login(ctx, dto.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	claims, err := utils.ValidateToken(response.Token)
+	if err != nil || claims.Locale != "es-AR" || claims.Timezone != "America/Argentina/Cordoba" {
+		t.Errorf("Expected locale and timezone in the token, got %+v (%v)", claims, err)
+	}
+}
+
+// Test: el admin inicial se crea solo si no hay ninguno, y nunca promueve a un usuario existente
+func TestBootstrapAdmin(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+	req := dto.CreateUserRequest{Username: "root", Email: "root@spotly.com", Password: "password123"}
+
+	// Alguien registró "root" antes del primer arranque
+	service.CreateUser(context.Background(), req)
+	if _, err := service.BootstrapAdmin(context.Background(), req); !errors.Is(err, apperrors.ErrConflict) {
+		t.Fatalf("Expected conflict for an existing username, got %v", err)
+	}
+	if repo.users[1].UserType != domain.UserTypeNormal {
+		t.Fatal("Expected the existing user to stay normal")
+	}
+
+	req.Username, req.Email = "admin", "admin@spotly.com"
+	admin, err := service.BootstrapAdmin(context.Background(), req)
+	if err != nil || admin == nil || admin.UserType != domain.UserTypeAdmin {
+		t.Fatalf("Expected a new admin, got %+v (%v)", admin, err)
+	}
+
+	req.Username, req.Email = "admin2", "admin2@spotly.com"
+	if again, err := service.BootstrapAdmin(context.Background(), req); again != nil || err != nil {
+		t.Errorf("Expected nothing once an admin exists, got %+v (%v)", again, err)
+	}
+}
+
+// Test: Promover un usuario a admin
+func TestSetUserType_Promote(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+
+	createdUser, _ := service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username:  "testuser",
+		Email:     "test@example.com",
+		Password:  "password123",
+		FirstName: "Test",
+		LastName:  "User",
+	})
+
+	user, err := service.SetUserType(context.Background(), createdUser.ID, domain.UserTypeAdmin)
+
+	// Verificaciones
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if user.UserType != domain.UserTypeAdmin || repo.users[createdUser.ID].UserType != domain.UserTypeAdmin {
+		t.Errorf("Expected user to be admin, got %s", user.UserType)
+	}
+}
+
+// Test: Rol inválido
+func TestSetUserType_InvalidType(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+
+	_, err := service.SetUserType(context.Background(), 1, domain.UserType("superuser"))
+
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+// Test: la exportación recorre los usuarios filtrados de a lotes
+func TestExportUsers(t *testing.T) {
+	repo := newMockUserRepository()
+	for id := uint(1); id <= exportBatchSize+5; id++ {
+		repo.users[id] = &domain.User{ID: id, UserType: domain.UserTypeNormal}
+	}
+	repo.users[3].UserType = domain.UserTypeAdmin
+	service := newTestUserService(repo)
+
+	var batches, rows int
+	err := service.ExportUsers(context.Background(), dto.ExportUsersQuery{UserType: "normal"}, func(users []domain.User) error {
+		batches++
+		rows += len(users)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if batches != 2 || rows != exportBatchSize+4 {
+		t.Errorf("Expected %d rows in 2 batches, got %d in %d", exportBatchSize+4, rows, batches)
+	}
+}
+
+// Test: el email se guarda limpio y otro con distintas mayúsculas no se puede registrar
+func TestCreateUser_EmailCaseInsensitive(t *testing.T) {
+	repo := newMockUserRepository()
+	service := newTestUserService(repo)
+
+	user, err := service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "foo", Email: " Foo@Bar.com", Password: "password123", FirstName: "Foo", LastName: "Bar",
+	})
+	if err != nil || user.Email != "foo@bar.com" {
+		t.Fatalf("Expected the email stored as foo@bar.com, got %+v, %v", user, err)
+	}
+
+	_, err = service.CreateUser(context.Background(), dto.CreateUserRequest{
+		Username: "otro", Email: "foo@bar.com", Password: "password123", FirstName: "Otro", LastName: "Bar",
+	})
+	if !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Expected conflict, got %v", err)
+	}
+
+	if _, err := service.Login(context.Background(), dto.LoginRequest{UsernameOrEmail: "FOO@bar.com", Password: "password123"}); err != nil {
+		t.Errorf("Expected login with another case to work, got %v", err)
+	}
+}
+
+// duplicateOnUpdate simula el índice único: Update falla para los IDs indicados
+type duplicateOnUpdate struct {
+	*mockUserRepository
+	duplicated map[uint]bool
+}
+
+func (m *duplicateOnUpdate) Update(ctx context.Context, user *domain.User) error {
+	if m.duplicated[user.ID] {
+		return apperrors.Wrap(apperrors.CodeConflict, "email already exists", repositories.ErrDuplicate)
+	}
+	normalized := utils.NormalizeEmail(user.Email)
+	user.EmailNormalized = &normalized
+	return m.mockUserRepository.Update(ctx, user)
+}
+
+// Test: se completan los emails pendientes y los que chocan se informan sin frenar
+func TestNormalizeEmails(t *testing.T) {
+	repo := &duplicateOnUpdate{mockUserRepository: newMockUserRepository(), duplicated: map[uint]bool{2: true}}
+	done := "otra@x.com"
+	repo.users[1] = &domain.User{ID: 1, Email: "Ana@X.com"}
+	repo.users[2] = &domain.User{ID: 2, Email: "ana@x.com"}
+	repo.users[3] = &domain.User{ID: 3, Email: "otra@x.com", EmailNormalized: &done}
+
+	updated, conflicts, err := newTestUserService(repo).NormalizeEmails(context.Background())
+	if err != nil || updated != 1 || len(conflicts) != 1 || conflicts[0] != 2 {
+		t.Errorf("Expected 1 updated and user 2 in conflict, got %d %v %v", updated, conflicts, err)
+	}
+	if repo.users[1].EmailNormalized == nil || *repo.users[1].EmailNormalized != "ana@x.com" {
+		t.Errorf("Expected user 1 normalized, got %v", repo.users[1].EmailNormalized)
+	}
+}
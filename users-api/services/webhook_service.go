@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+
+	"shared/apperrors"
+	"shared/requestid"
+)
+
+// Reintentos de los webhooks: el primero al minuto y después el doble cada
+// vez (1m, 2m, 4m... unas 2 horas en total) hasta webhookMaxAttempts
+const (
+	webhookBatchSize      = 50
+	webhookMaxAttempts    = 8
+	webhookFirstRetry     = time.Minute
+	defaultDeliveriesPage = 20
+)
+
+// WebhookSender manda una entrega a la URL del webhook, firmada con secret
+// Devuelve el status HTTP (0 si no hubo respuesta) y un error si no fue 2xx
+type WebhookSender interface {
+	Send(ctx context.Context, url, secret string, delivery *domain.WebhookDelivery) (int, error)
+}
+
+// WebhookService maneja los webhooks que reciben los eventos de usuarios
+// Las entregas se encolan al crear, cambiar o borrar un usuario (ver
+// repositories.NewWebhookUserRepository) y las manda Deliver
+type WebhookService interface {
+	Create(ctx context.Context, adminID uint, req dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	List(ctx context.Context) ([]domain.Webhook, error)
+	Delete(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, webhookID uint, query dto.PageQuery) (*dto.WebhookDeliveriesResponse, error)
+	Deliver(ctx context.Context) (int, error)
+	PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// webhookService es la implementación real del servicio
+type webhookService struct {
+	repo   repositories.WebhookRepository
+	sender WebhookSender
+}
+
+// NewWebhookService crea una nueva instancia del servicio
+func NewWebhookService(repo repositories.WebhookRepository, sender WebhookSender) WebhookService {
+	return &webhookService{repo: repo, sender: sender}
+}
+
+// Create registra un webhook y devuelve su secret (la única vez que se ve)
+func (s *webhookService) Create(ctx context.Context, adminID uint, req dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, apperrors.Validation("url must be an absolute http or https URL")
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, apperrors.Wrap(apperrors.CodeInternal, "error generating webhook secret", err)
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	webhook := &domain.Webhook{URL: req.URL, Secret: secret, Events: uniqueEvents(req.Events), CreatedBy: adminID}
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternal, "error creating webhook", err)
+	}
+	return &dto.WebhookResponse{Webhook: *webhook, Secret: secret}, nil
+}
+
+// uniqueEvents saca los eventos repetidos conservando el orden
+func uniqueEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+// List devuelve los webhooks registrados (sin sus secrets)
+func (s *webhookService) List(ctx context.Context) ([]domain.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+// Delete borra el webhook; sus entregas pendientes ya no se mandan
+func (s *webhookService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ListDeliveries devuelve una página del historial de entregas de un webhook
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID uint, query dto.PageQuery) (*dto.WebhookDeliveriesResponse, error) {
+	if _, err := s.repo.GetByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultDeliveriesPage
+	}
+
+	deliveries, total, err := s.repo.ListDeliveries(ctx, webhookID, query.Page, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WebhookDeliveriesResponse{Deliveries: deliveries, Total: total, Page: query.Page, Limit: query.Limit}, nil
+}
+
+// Deliver manda las entregas pendientes que ya tocan y guarda el resultado
+// Un receptor caído no frena a los demás: su entrega se reprograma con
+// backoff y, después de webhookMaxAttempts intentos, queda como fallida
+// Lo ejecuta el job programado "deliver_webhooks"; devuelve cuántas se entregaron
+func (s *webhookService) Deliver(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ListDue(ctx, time.Now(), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if delivery.Webhook == nil {
+			continue
+		}
+
+		status, sendErr := s.sender.Send(ctx, delivery.Webhook.URL, delivery.Webhook.Secret, delivery)
+		now := time.Now()
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		switch {
+		case sendErr == nil:
+			delivery.Status, delivery.DeliveredAt, delivery.LastError = domain.DeliveryDelivered, &now, ""
+			delivered++
+		case delivery.Attempts >= webhookMaxAttempts:
+			delivery.Status, delivery.LastError = domain.DeliveryFailed, truncate(sendErr.Error(), 500)
+			requestid.Logf(ctx, "❌ Webhook %s (%s) a %s descartado después de %d intentos: %v", delivery.Event, delivery.EventID, delivery.Webhook.URL, delivery.Attempts, sendErr)
+		default:
+			delivery.NextAttemptAt = now.Add(webhookRetryDelay(delivery.Attempts))
+			delivery.LastError = truncate(sendErr.Error(), 500)
+			requestid.Logf(ctx, "⚠️  Webhook %s (%s) a %s falló (intento %d), se reintenta a las %s: %v", delivery.Event, delivery.EventID, delivery.Webhook.URL, delivery.Attempts, delivery.NextAttemptAt.Format(time.TimeOnly), sendErr)
+		}
+
+		if err := s.repo.SaveAttempt(ctx, delivery); err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// webhookRetryDelay es la espera después del intento número attempt (1, 2, ...)
+func webhookRetryDelay(attempt int) time.Duration {
+	return webhookFirstRetry << (attempt - 1)
+}
+
+// PurgeDeliveries borra las entregas terminadas hace más de retention
+func (s *webhookService) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteFinishedBefore(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/domain"
+	"users-api/dto"
+	"users-api/repositories"
+
+	"shared/apperrors"
+)
+
+// mockWebhookRepository guarda webhooks y entregas en memoria
+type mockWebhookRepository struct {
+	repositories.WebhookRepository
+	webhooks   map[uint]*domain.Webhook
+	deliveries map[uint]*domain.WebhookDelivery
+}
+
+func newMockWebhookRepository() *mockWebhookRepository {
+	return &mockWebhookRepository{webhooks: map[uint]*domain.Webhook{}, deliveries: map[uint]*domain.WebhookDelivery{}}
+}
+
+func (m *mockWebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	webhook.ID = uint(len(m.webhooks) + 1)
+	m.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (m *mockWebhookRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var due []domain.WebhookDelivery
+	for id := uint(1); id <= uint(len(m.deliveries)); id++ {
+		d := m.deliveries[id]
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) {
+			copied := *d
+			copied.Webhook = m.webhooks[d.WebhookID]
+			due = append(due, copied)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockWebhookRepository) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	saved := *delivery
+	saved.Webhook = nil
+	m.deliveries[delivery.ID] = &saved
+	return nil
+}
+
+// fakeWebhookSender responde con el status configurado por URL
+type fakeWebhookSender struct {
+	status map[string]int
+	sent   []string
+}
+
+func (f *fakeWebhookSender) Send(ctx context.Context, url, secret string, delivery *domain.WebhookDelivery) (int, error) {
+	f.sent = append(f.sent, url+" "+delivery.EventID)
+	status := f.status[url]
+	if status < 200 || status > 299 {
+		return status, errors.New("webhook returned an error")
+	}
+	return status, nil
+}
+
+// Test: el secret se genera si no viene y la URL tiene que ser http(s) absoluta
+func TestWebhookCreate(t *testing.T) {
+	service := NewWebhookService(newMockWebhookRepository(), &fakeWebhookSender{})
+
+	created, err := service.Create(context.Background(), 7, dto.CreateWebhookRequest{
+		URL: "https://crm.example.com/hooks", Events: []string{"user.created", "user.created", "user.deleted"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(created.Secret) != 64 || created.Webhook.Secret != created.Secret || created.CreatedBy != 7 {
+		t.Errorf("Expected a generated secret and the admin, got %+v", created)
+	}
+	if len(created.Events) != 2 {
+		t.Errorf("Expected repeated events to be removed, got %v", created.Events)
+	}
+
+	for _, url := range []string{"ftp://crm.example.com", "/hooks", "https://"} {
+		_, err := service.Create(context.Background(), 7, dto.CreateWebhookRequest{URL: url, Events: []string{"user.created"}})
+		if !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Expected validation error for %q, got %v", url, err)
+		}
+	}
+}
+
+// Test: lo entregado queda como tal, lo que falla se reintenta con backoff y
+// después de webhookMaxAttempts intentos queda como fallido
+func TestWebhookDeliver(t *testing.T) {
+	repo := newMockWebhookRepository()
+	repo.webhooks[1] = &domain.Webhook{ID: 1, URL: "https://ok.example.com", Secret: "s1"}
+	repo.webhooks[2] = &domain.Webhook{ID: 2, URL: "https://down.example.com", Secret: "s2"}
+	past := time.Now().Add(-time.Second)
+	repo.deliveries[1] = &domain.WebhookDelivery{ID: 1, WebhookID: 1, EventID: "a", Status: domain.DeliveryPending, NextAttemptAt: past}
+	repo.deliveries[2] = &domain.WebhookDelivery{ID: 2, WebhookID: 2, EventID: "b", Status: domain.DeliveryPending, NextAttemptAt: past}
+	sender := &fakeWebhookSender{status: map[string]int{"https://ok.example.com": 204, "https://down.example.com": 503}}
+	service := NewWebhookService(repo, sender)
+
+	delivered, err := service.Deliver(context.Background())
+	if err != nil || delivered != 1 {
+		t.Fatalf("Expected one delivery, got %d, %v", delivered, err)
+	}
+	if ok := repo.deliveries[1]; ok.Status != domain.DeliveryDelivered || ok.DeliveredAt == nil || ok.ResponseStatus != 204 {
+		t.Errorf("Unexpected delivered state: %+v", ok)
+	}
+	down := repo.deliveries[2]
+	if down.Status != domain.DeliveryPending || down.Attempts != 1 || down.ResponseStatus != 503 || down.LastError == "" {
+		t.Errorf("Unexpected retry state: %+v", down)
+	}
+	if wait := time.Until(down.NextAttemptAt); wait < 50*time.Second || wait > time.Minute {
+		t.Errorf("Expected the first retry in a minute, got %s", wait)
+	}
+
+	// Todavía no toca: no se manda de nuevo
+	sender.sent = nil
+	service.Deliver(context.Background())
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no sends before the retry time, got %v", sender.sent)
+	}
+
+	// Último intento
+	down.Attempts, down.NextAttemptAt = webhookMaxAttempts-1, past
+	service.Deliver(context.Background())
+	if down := repo.deliveries[2]; down.Status != domain.DeliveryFailed || down.Attempts != webhookMaxAttempts {
+		t.Errorf("Expected the delivery to fail after %d attempts, got %+v", webhookMaxAttempts, down)
+	}
+}
+
+// Test: la espera entre reintentos se duplica
+func TestWebhookRetryDelay(t *testing.T) {
+	if webhookRetryDelay(1) != time.Minute || webhookRetryDelay(3) != 4*time.Minute {
+		t.Errorf("Unexpected delays: %s, %s", webhookRetryDelay(1), webhookRetryDelay(3))
+	}
+}
@@ -0,0 +1,179 @@
+// Package storage guarda archivos de los usuarios (ej: avatares) en un
+// bucket compatible con S3: AWS S3 o MinIO en desarrollo
+//
+// Las requests se firman con AWS Signature V4 a mano (sin el SDK): solo hacen
+// falta PUT y DELETE de objetos
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"shared/config"
+)
+
+// ObjectStore guarda y borra objetos por clave (ej: "avatars/7/ab12.jpg")
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL es la dirección pública del objeto
+	URL(key string) string
+}
+
+// S3Config es el bucket donde se guardan los archivos
+type S3Config struct {
+	Endpoint  string // ej: "http://minio:9000" o "https://s3.us-east-1.amazonaws.com"; "" = apagado
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // base de las URLs públicas (ej: un CDN); "" = Endpoint/Bucket
+}
+
+// Enabled indica si hay un bucket configurado
+func (c S3Config) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// ConfigFromEnv lee S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY,
+// S3_SECRET_KEY y S3_PUBLIC_URL
+func ConfigFromEnv(env *config.Env) S3Config {
+	cfg := S3Config{
+		Endpoint:  strings.TrimSuffix(env.String("S3_ENDPOINT", ""), "/"),
+		Region:    env.String("S3_REGION", "us-east-1"),
+		Bucket:    env.String("S3_BUCKET", "spotly-users"),
+		AccessKey: env.String("S3_ACCESS_KEY", ""),
+		SecretKey: env.String("S3_SECRET_KEY", ""),
+		PublicURL: strings.TrimSuffix(env.String("S3_PUBLIC_URL", ""), "/"),
+	}
+	env.Check(!cfg.Enabled() || (cfg.AccessKey != "" && cfg.SecretKey != ""), "S3_ENDPOINT requires S3_ACCESS_KEY and S3_SECRET_KEY")
+	return cfg
+}
+
+// s3Store habla con el bucket por HTTP (direcciones path-style:
+// <endpoint>/<bucket>/<key>, las que entiende MinIO)
+type s3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store crea el store sobre el bucket
+func NewS3Store(cfg S3Config) ObjectStore {
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+	return &s3Store{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}, now: time.Now}
+}
+
+// Put sube el objeto (si ya existe lo reemplaza)
+func (s *s3Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+	return s.do(ctx, http.MethodPut, key, contentType, body)
+}
+
+// Delete borra el objeto; borrar uno que no existe no es error
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, "", nil)
+}
+
+// URL arma la dirección pública del objeto
+func (s *s3Store) URL(key string) string {
+	return s.cfg.PublicURL + "/" + key
+}
+
+// do manda la request firmada y convierte las respuestas que no son 2xx en error
+func (s *s3Store) do(ctx context.Context, method, key, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.Endpoint+"/"+s.cfg.Bucket+"/"+key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 %s %s: %w", method, key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// sign agrega los headers de AWS Signature V4
+// Se firman host, content-type (si hay) y los x-amz-*; el body va con su sha256
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *s3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), day)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test: Put y Delete firman la request y usan direcciones path-style
+func TestS3Store(t *testing.T) {
+	var got []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r)
+		bodies = append(bodies, string(body))
+		if r.URL.Path == "/avatars/forbidden.jpg" {
+			http.Error(w, "AccessDenied", http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := NewS3Store(S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "avatars", AccessKey: "AK", SecretKey: "SK"})
+	store.(*s3Store).now = func() time.Time { return time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC) }
+
+	if err := store.Put(context.Background(), "users/1/a.jpg", "image/jpeg", []byte("data")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Delete(context.Background(), "users/1/a.jpg"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Put(context.Background(), "forbidden.jpg", "image/jpeg", nil); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the 403 as an error, got %v", err)
+	}
+
+	put := got[0]
+	if put.Method != http.MethodPut || put.URL.Path != "/avatars/users/1/a.jpg" || bodies[0] != "data" {
+		t.Errorf("Unexpected PUT: %s %s %q", put.Method, put.URL.Path, bodies[0])
+	}
+	if put.Header.Get("Content-Type") != "image/jpeg" || put.Header.Get("X-Amz-Date") != "20260102T150405Z" {
+		t.Errorf("Unexpected headers: %v", put.Header)
+	}
+	// sha256("data")
+	if put.Header.Get("X-Amz-Content-Sha256") != "3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7" {
+		t.Errorf("Unexpected payload hash %s", put.Header.Get("X-Amz-Content-Sha256"))
+	}
+	prefix := "AWS4-HMAC-SHA256 Credential=AK/20260102/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="
+	if auth := put.Header.Get("Authorization"); !strings.HasPrefix(auth, prefix) || len(auth) != len(prefix)+64 {
+		t.Errorf("Unexpected Authorization %q", auth)
+	}
+	if got[1].Method != http.MethodDelete || !strings.Contains(got[1].Header.Get("Authorization"), "SignedHeaders=host;x-amz-content-sha256;x-amz-date,") {
+		t.Errorf("Unexpected DELETE: %s %v", got[1].Method, got[1].Header)
+	}
+
+	if url := store.URL("users/1/a.jpg"); url != server.URL+"/avatars/users/1/a.jpg" {
+		t.Errorf("Unexpected URL %s", url)
+	}
+}
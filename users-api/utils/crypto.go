@@ -1,23 +1,243 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword hashea una contraseña usando bcrypt
-// Recibe: "mipassword123"
-// Devuelve: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
-func HashPassword(password string) (string, error) {
-	// bcrypt.DefaultCost = 10 (nivel de seguridad)
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// pepperedPrefix marca los hashes con pepper: "$sp1$<id del pepper>$<hash bcrypt>"
+// Los hashes bcrypt sin prefijo ("$2a$...") son los anteriores, sin pepper
+const pepperedPrefix = "$sp1$"
+
+// pepper es un secreto del servidor que se mezcla con la contraseña antes
+// de hashearla: con un dump de la base sola no alcanza para probar
+// contraseñas. No está en la base, viene del gestor de secretos
+type pepper struct {
+	id  string
+	key []byte
+}
+
+// peppers guarda el pepper actual (con el que se hashea) y los anteriores
+// (con los que solo se verifica, durante una rotación)
+var peppers struct {
+	current  *pepper
+	previous map[string][]byte
+}
+
+// ConfigurePepper define el pepper actual y, opcionalmente, los anteriores
+// (id => valor) que siguen sirviendo para verificar
+// Con current vacío no se usa pepper. Los ids no pueden tener "$" (ver
+// ValidPepperID). Se llama una vez al arrancar: cambiar un pepper con el
+// mismo id invalida todas las contraseñas hasheadas con él
+func ConfigurePepper(currentID, current string, previous map[string]string) {
+	peppers.current, peppers.previous = nil, nil
+	if current == "" {
+		return
+	}
+
+	peppers.current = &pepper{id: currentID, key: []byte(current)}
+	peppers.previous = make(map[string][]byte, len(previous))
+	for id, value := range previous {
+		if id != currentID && value != "" {
+			peppers.previous[id] = []byte(value)
+		}
+	}
+}
+
+// Límites de BCRYPT_COST: menos de 10 es débil y con más de 14 cada login
+// tarda más de un segundo
+const (
+	MinBcryptCost = 10
+	MaxBcryptCost = 14
+)
+
+// bcryptCost es el costo de los hashes nuevos
+var bcryptCost = bcrypt.DefaultCost
+
+// ConfigureBcryptCost define el costo de bcrypt de los hashes nuevos
+// (0 = bcrypt.DefaultCost). Los hashes con un costo menor se rehashean en el
+// próximo login (ver PasswordNeedsRehash)
+func ConfigureBcryptCost(cost int) {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	bcryptCost = cost
+}
+
+// Algoritmos de hash de contraseñas (PASSWORD_HASHER)
+const (
+	HasherBcrypt   = "bcrypt"
+	HasherArgon2id = "argon2id"
+)
+
+// passwordHasher es el algoritmo de los hashes nuevos
+var passwordHasher = HasherBcrypt
+
+// ConfigurePasswordHasher define el algoritmo de los hashes nuevos
+// ("" = bcrypt). Los hashes con el otro algoritmo siguen verificando y se
+// rehashean en el próximo login (ver PasswordNeedsRehash)
+func ConfigurePasswordHasher(hasher string) {
+	if hasher == "" {
+		hasher = HasherBcrypt
+	}
+	passwordHasher = hasher
+}
+
+// Parámetros de Argon2id (RFC 9106, segunda opción recomendada: 64 MiB y 3
+// pasadas). El hash los guarda en formato PHC, así que cambiarlos no rompe
+// los hashes viejos: se rehashean en el próximo login
+// "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>" (base64 sin padding)
+const (
+	argon2Prefix  = "$argon2id$"
+	argon2Memory  = 64 * 1024
+	argon2Time    = 3
+	argon2Threads = 2
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// argon2Params es el encabezado de los hashes con los parámetros actuales
+var argon2Params = fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$", argon2Prefix, argon2.Version, argon2Memory, argon2Time, argon2Threads)
+
+// hashArgon2id devuelve el hash Argon2id en formato PHC con un salt nuevo
+func hashArgon2id(input []byte) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey(input, salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return argon2Params + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(key), nil
+}
+
+// checkArgon2id verifica input contra un hash PHC de Argon2id, con los
+// parámetros que traiga el hash
+func checkArgon2id(input []byte, hash string) bool {
+	parts := strings.Split(hash, "$") // "", "argon2id", "v=19", "m=..,t=..,p=..", salt, hash
+	if len(parts) != 6 {
+		return false
+	}
+	var version int
+	var memory, passes uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &passes, &threads); err != nil || passes == 0 || threads == 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+
+	got := argon2.IDKey(input, salt, passes, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// hashInput hashea con el algoritmo configurado (bcrypt o Argon2id)
+func hashInput(input []byte) (string, error) {
+	if passwordHasher == HasherArgon2id {
+		return hashArgon2id(input)
+	}
+	bytes, err := bcrypt.GenerateFromPassword(input, bcryptCost)
 	return string(bytes), err
 }
 
+// ValidPepperID indica si el id sirve para el formato de hash (no vacío, sin "$")
+func ValidPepperID(id string) bool {
+	return id != "" && !strings.Contains(id, "$")
+}
+
+// pepperKey devuelve el pepper con ese id (actual o anterior)
+func pepperKey(id string) ([]byte, bool) {
+	if peppers.current != nil && peppers.current.id == id {
+		return peppers.current.key, true
+	}
+	key, ok := peppers.previous[id]
+	return key, ok
+}
+
+// applyPepper mezcla la contraseña con el pepper (HMAC-SHA256 en base64)
+// Además evita que bcrypt ignore lo que pasa de 72 bytes
+func applyPepper(key []byte, password string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// HashPassword hashea una contraseña con bcrypt o Argon2id (PASSWORD_HASHER)
+// Con pepper configurado devuelve "$sp1$<id>$<hash(HMAC(pepper, password))>"
+// Sin pepper: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
+// o "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>"
+// El algoritmo queda en el hash, así que CheckPasswordHash acepta cualquiera
+func HashPassword(password string) (string, error) {
+	if peppers.current == nil {
+		// bcryptCost = 10 por defecto (nivel de seguridad, ver BCRYPT_COST)
+		return hashInput([]byte(password))
+	}
+
+	hash, err := hashInput(applyPepper(peppers.current.key, password))
+	if err != nil {
+		return "", err
+	}
+	return pepperedPrefix + peppers.current.id + "$" + hash, nil
+}
+
 // CheckPasswordHash verifica si una contraseña coincide con el hash
 // Se usa en el login para verificar que la contraseña sea correcta
-// Recibe: "mipassword123" y el hash guardado en la BD
+// Acepta los dos formatos: con pepper (si el id es el actual o uno anterior
+// configurado) y sin pepper, y los dos algoritmos (bcrypt y Argon2id)
 // Devuelve: true si coincide, false si no
 func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	input := []byte(password)
+	if rest, ok := strings.CutPrefix(hash, pepperedPrefix); ok {
+		id, bcryptHash, found := strings.Cut(rest, "$")
+		key, known := pepperKey(id)
+		if !found || !known {
+			return false
+		}
+		input, hash = applyPepper(key, password), bcryptHash
+	}
+
+	if strings.HasPrefix(hash, argon2Prefix) {
+		return checkArgon2id(input, hash)
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), input)
 	return err == nil
 }
+
+// PasswordNeedsRehash indica si el hash no está en el formato actual: sin
+// pepper cuando hay uno configurado, con un pepper anterior, con otro
+// algoritmo (ej: bcrypt con PASSWORD_HASHER=argon2id), con otros parámetros
+// de Argon2id o con un costo de bcrypt menor al configurado
+// El login lo usa para migrar el hash apenas tiene la contraseña en claro
+func PasswordNeedsRehash(hash string) bool {
+	if peppers.current != nil {
+		rest, ok := strings.CutPrefix(hash, pepperedPrefix+peppers.current.id+"$")
+		if !ok {
+			return true
+		}
+		hash = rest
+	}
+
+	if passwordHasher == HasherArgon2id {
+		return !strings.HasPrefix(hash, argon2Params)
+	}
+	if strings.HasPrefix(hash, argon2Prefix) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost < bcryptCost
+}
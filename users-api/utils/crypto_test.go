@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Test: con pepper el hash lleva el prefijo y sin el pepper no verifica
+func TestHashPassword_Pepper(t *testing.T) {
+	t.Cleanup(func() { ConfigurePepper("", "", nil) })
+
+	legacy, _ := HashPassword("secreta123")
+	ConfigurePepper("1", "pepper-uno", nil)
+
+	hash, err := HashPassword("secreta123")
+	if err != nil || !strings.HasPrefix(hash, "$sp1$1$$2a$") {
+		t.Fatalf("Expected a peppered hash, got %q, %v", hash, err)
+	}
+	if !CheckPasswordHash("secreta123", hash) || CheckPasswordHash("otra", hash) {
+		t.Error("Expected the peppered hash to verify only the right password")
+	}
+	if PasswordNeedsRehash(hash) {
+		t.Error("Expected the current format not to need a rehash")
+	}
+
+	// Los hashes sin pepper siguen valiendo, pero hay que migrarlos
+	if !CheckPasswordHash("secreta123", legacy) || !PasswordNeedsRehash(legacy) {
+		t.Error("Expected the legacy hash to verify and need a rehash")
+	}
+
+	// Sin el pepper (ej: se perdió la configuración) no verifica
+	ConfigurePepper("", "", nil)
+	if CheckPasswordHash("secreta123", hash) {
+		t.Error("Expected the peppered hash to fail without the pepper")
+	}
+}
+
+// Test: rotación: el pepper anterior verifica y el hash se marca para migrar
+func TestHashPassword_PepperRotation(t *testing.T) {
+	t.Cleanup(func() { ConfigurePepper("", "", nil) })
+
+	ConfigurePepper("1", "pepper-uno", nil)
+	old, _ := HashPassword("secreta123")
+
+	ConfigurePepper("2", "pepper-dos", map[string]string{"1": "pepper-uno"})
+	if !CheckPasswordHash("secreta123", old) || !PasswordNeedsRehash(old) {
+		t.Error("Expected the previous pepper to verify and need a rehash")
+	}
+
+	ConfigurePepper("2", "pepper-dos", nil)
+	if CheckPasswordHash("secreta123", old) {
+		t.Error("Expected an unknown pepper id to fail")
+	}
+}
+
+// Test: subir BCRYPT_COST marca para rehashear los hashes con el costo anterior
+func TestPasswordNeedsRehash_Cost(t *testing.T) {
+	t.Cleanup(func() { ConfigureBcryptCost(0) })
+
+	old, _ := HashPassword("secreta123")
+	ConfigureBcryptCost(MinBcryptCost + 1)
+	if !PasswordNeedsRehash(old) {
+		t.Error("Expected a hash with a lower cost to need a rehash")
+	}
+
+	hash, _ := HashPassword("secreta123")
+	if PasswordNeedsRehash(hash) || !CheckPasswordHash("secreta123", hash) {
+		t.Error("Expected the new hash to use the configured cost")
+	}
+}
+
+// Test: con Argon2id los hashes nuevos lo usan y los bcrypt se migran
+func TestHashPassword_Argon2id(t *testing.T) {
+	t.Cleanup(func() { ConfigurePasswordHasher(""); ConfigurePepper("", "", nil) })
+
+	legacy, _ := HashPassword("secreta123")
+	ConfigurePasswordHasher(HasherArgon2id)
+
+	hash, err := HashPassword("secreta123")
+	if err != nil || !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Fatalf("Expected an Argon2id hash, got %q, %v", hash, err)
+	}
+	if !CheckPasswordHash("secreta123", hash) || CheckPasswordHash("otra", hash) {
+		t.Error("Expected the Argon2id hash to verify only the right password")
+	}
+	if PasswordNeedsRehash(hash) {
+		t.Error("Expected the current format not to need a rehash")
+	}
+	if !CheckPasswordHash("secreta123", legacy) || !PasswordNeedsRehash(legacy) {
+		t.Error("Expected the bcrypt hash to verify and need a rehash")
+	}
+
+	// Otros parámetros (ej: los de antes de subir la memoria) verifican y se migran
+	if old := weakArgon2id("secreta123"); !CheckPasswordHash("secreta123", old) || !PasswordNeedsRehash(old) {
+		t.Error("Expected a hash with other parameters to verify and need a rehash")
+	}
+
+	// Con pepper el prefijo envuelve al hash Argon2id
+	ConfigurePepper("1", "pepper-uno", nil)
+	peppered, _ := HashPassword("secreta123")
+	if !strings.HasPrefix(peppered, "$sp1$1$$argon2id$") || !CheckPasswordHash("secreta123", peppered) {
+		t.Errorf("Expected a peppered Argon2id hash, got %q", peppered)
+	}
+
+	// Volver a bcrypt migra los Argon2id
+	ConfigurePepper("", "", nil)
+	ConfigurePasswordHasher(HasherBcrypt)
+	if !CheckPasswordHash("secreta123", hash) || !PasswordNeedsRehash(hash) {
+		t.Error("Expected the Argon2id hash to verify and need a rehash back to bcrypt")
+	}
+	if CheckPasswordHash("secreta123", "$argon2id$v=19$m=65536$roto") {
+		t.Error("Expected a malformed hash not to verify")
+	}
+}
+
+// weakArgon2id arma un hash Argon2id con parámetros más bajos que los actuales
+func weakArgon2id(password string) string {
+	salt := []byte("saltsaltsaltsalt")
+	key := argon2.IDKey([]byte(password), salt, 1, 1024, 1, 32)
+	return "$argon2id$v=19$m=1024,t=1,p=1$" + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(key)
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Plataformas de un dispositivo
+const (
+	PlatformDesktop = "desktop"
+	PlatformMobile  = "mobile"
+	PlatformTablet  = "tablet"
+	PlatformBot     = "bot"
+	PlatformUnknown = "unknown"
+)
+
+// Device es lo que se sabe de un dispositivo a partir de su user agent
+// Browser y OS van sin versión: una actualización del navegador no cambia
+// el dispositivo
+type Device struct {
+	Browser  string // "Chrome", "Firefox"... ("" si no se reconoce)
+	OS       string // "Windows", "iOS"... ("" si no se reconoce)
+	Platform string // desktop, mobile, tablet, bot o unknown
+}
+
+// Known indica si se reconoció el navegador y el sistema operativo
+func (d Device) Known() bool {
+	return d.Browser != "" && d.OS != ""
+}
+
+// String describe el dispositivo para mostrarlo ("Chrome (Windows)")
+func (d Device) String() string {
+	switch {
+	case d.Known():
+		return d.Browser + " (" + d.OS + ")"
+	case d.Browser != "":
+		return d.Browser
+	case d.OS != "":
+		return d.OS
+	}
+	return "unknown"
+}
+
+// Reglas de reconocimiento, en orden: el primer match gana
+// (Edge y Opera también dicen "Chrome/", Chrome también dice "Safari/")
+var (
+	browserRules = []struct{ token, name string }{
+		{"edg/", "Edge"}, {"edge/", "Edge"}, {"edga/", "Edge"}, {"edgios/", "Edge"},
+		{"opr/", "Opera"}, {"opera", "Opera"},
+		{"samsungbrowser/", "Samsung Internet"},
+		{"firefox/", "Firefox"}, {"fxios/", "Firefox"},
+		{"chrome/", "Chrome"}, {"crios/", "Chrome"},
+		{"safari/", "Safari"},
+	}
+	osRules = []struct{ token, name string }{
+		{"windows", "Windows"},
+		{"iphone", "iOS"}, {"ipad", "iOS"}, {"ipod", "iOS"},
+		{"android", "Android"},
+		{"cros", "ChromeOS"},
+		{"mac os x", "macOS"}, {"macintosh", "macOS"},
+		{"linux", "Linux"},
+	}
+	botTokens = []string{"bot", "spider", "crawl", "curl/", "wget/", "python-requests", "go-http-client", "postman"}
+)
+
+// ParseUserAgent reconoce navegador, sistema operativo y plataforma
+// No pretende ser exhaustivo: alcanza para distinguir los dispositivos de
+// un usuario y mostrarlos en la lista
+func ParseUserAgent(userAgent string) Device {
+	ua := strings.ToLower(userAgent)
+	device := Device{Platform: PlatformUnknown}
+
+	for _, rule := range browserRules {
+		if strings.Contains(ua, rule.token) {
+			device.Browser = rule.name
+			break
+		}
+	}
+	for _, rule := range osRules {
+		if strings.Contains(ua, rule.token) {
+			device.OS = rule.name
+			break
+		}
+	}
+
+	switch {
+	case containsAny(ua, botTokens):
+		device.Platform = PlatformBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		device.Platform = PlatformTablet
+	case strings.Contains(ua, "mobile") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		device.Platform = PlatformMobile
+	case device.OS != "":
+		device.Platform = PlatformDesktop
+	}
+	return device
+}
+
+// containsAny indica si s contiene alguno de los tokens
+func containsAny(s string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(s, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceFingerprint genera un identificador estable del dispositivo
+// Si se reconocen navegador y sistema es el hash de navegador, sistema y
+// plataforma (sin versiones); si no, el hash del user agent completo
+// (LegacyDeviceFingerprint), así dos clientes raros no se confunden
+// Devuelve el SHA-256 en hex (64 caracteres)
+func DeviceFingerprint(userAgent string) string {
+	device := ParseUserAgent(userAgent)
+	if !device.Known() {
+		return LegacyDeviceFingerprint(userAgent)
+	}
+	sum := sha256.Sum256([]byte("v2|" + device.Browser + "|" + device.OS + "|" + device.Platform))
+	return hex.EncodeToString(sum[:])
+}
+
+// LegacyDeviceFingerprint es el fingerprint anterior: el hash del user agent
+// completo. Los dispositivos guardados con él se migran en el próximo login
+func LegacyDeviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent))))
+	return hex.EncodeToString(sum[:])
+}
+
+// CountryCode valida un código de país ISO 3166-1 alfa-2 (ej: el header
+// CF-IPCountry de Cloudflare) y lo devuelve en mayúsculas
+// Devuelve "" si no es válido o es desconocido ("XX", "T1" de Tor)
+func CountryCode(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 2 || value == "XX" {
+		return ""
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+	}
+	return value
+}
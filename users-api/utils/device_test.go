@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+// Test: navegador, sistema y plataforma de user agents comunes
+func TestParseUserAgent(t *testing.T) {
+	tests := map[string]Device{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                         {"Chrome", "Windows", PlatformDesktop},
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":           {"Edge", "Windows", PlatformDesktop},
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_1) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15":                      {"Safari", "macOS", PlatformDesktop},
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1": {"Safari", "iOS", PlatformMobile},
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36":                   {"Chrome", "Android", PlatformMobile},
+		"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                          {"Chrome", "Android", PlatformTablet},
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0":                                                                  {"Firefox", "Linux", PlatformDesktop},
+		"curl/8.4.0": {"", "", PlatformBot},
+		"":           {"", "", PlatformUnknown},
+	}
+	for ua, want := range tests {
+		if got := ParseUserAgent(ua); got != want {
+			t.Errorf("ParseUserAgent(%q) = %#v; want %#v", ua, got, want)
+		}
+	}
+}
+
+// Test: el fingerprint no cambia con la versión del navegador, pero sí con el navegador
+func TestDeviceFingerprint(t *testing.T) {
+	chrome120 := DeviceFingerprint("Mozilla/5.0 (Windows NT 10.0) Chrome/120.0")
+	chrome121 := DeviceFingerprint("Mozilla/5.0 (Windows NT 10.0) Chrome/121.0")
+	firefox := DeviceFingerprint("Mozilla/5.0 (Windows NT 10.0; rv:121.0) Gecko/20100101 Firefox/121.0")
+
+	if chrome120 != chrome121 {
+		t.Error("Expected the same fingerprint across browser versions")
+	}
+	if chrome120 == firefox {
+		t.Error("Expected a different fingerprint for another browser")
+	}
+	// Clientes no reconocidos: hash del user agent completo
+	if DeviceFingerprint("curl/8.4.0") != LegacyDeviceFingerprint("curl/8.4.0") {
+		t.Error("Expected the legacy fingerprint for unknown agents")
+	}
+}
+
+// Test: códigos de país del header de geo
+func TestCountryCode(t *testing.T) {
+	for raw, want := range map[string]string{"ar": "AR", " US ": "US", "XX": "", "T1": "", "ARG": "", "": ""} {
+		if got := CountryCode(raw); got != want {
+			t.Errorf("CountryCode(%q) = %q; want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "strings"
+
+// stripPlusAddressing indica si "ana+promo@x.com" cuenta como "ana@x.com"
+// (EMAIL_STRIP_PLUS_ADDRESSING, ver ConfigureEmailNormalization)
+var stripPlusAddressing bool
+
+// ConfigureEmailNormalization define si NormalizeEmail saca el "+etiqueta"
+// Se llama una vez al arrancar; si cambia, UserService.NormalizeEmails
+// recalcula los emails normalizados guardados
+func ConfigureEmailNormalization(stripPlus bool) {
+	stripPlusAddressing = stripPlus
+}
+
+// CleanEmail saca los espacios y pasa el email a minúsculas
+// Es la forma en que se guarda el email (a donde se mandan los mails)
+// Ejemplo: " Ana.Perez@Gmail.com " -> "ana.perez@gmail.com"
+func CleanEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeEmail devuelve la forma con la que se comparan los emails: dos
+// emails con la misma forma son la misma cuenta
+// Además de CleanEmail, con plus addressing activado saca la etiqueta
+// Ejemplo: "Ana+Promo@Gmail.com" -> "ana@gmail.com"
+func NormalizeEmail(email string) string {
+	email = CleanEmail(email)
+	if !stripPlusAddressing {
+		return email
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	return local + domain
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "testing"
+
+// Test: mayúsculas y espacios no cambian el email; la etiqueta solo con plus addressing
+func TestNormalizeEmail(t *testing.T) {
+	t.Cleanup(func() { ConfigureEmailNormalization(false) })
+
+	tests := []struct {
+		email     string
+		stripPlus bool
+		want      string
+	}{
+		{" Foo@Bar.com ", false, "foo@bar.com"},
+		{"ana+promo@gmail.com", false, "ana+promo@gmail.com"},
+		{"Ana+Promo@Gmail.com", true, "ana@gmail.com"},
+		{"+solo@x.com", true, "+solo@x.com"},
+		{"sin-arroba", true, "sin-arroba"},
+	}
+	for _, tt := range tests {
+		ConfigureEmailNormalization(tt.stripPlus)
+		if got := NormalizeEmail(tt.email); got != tt.want {
+			t.Errorf("NormalizeEmail(%q) with stripPlus=%v = %q, want %q", tt.email, tt.stripPlus, got, tt.want)
+		}
+	}
+}
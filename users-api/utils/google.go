@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"shared/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GoogleCertsURL es el JWKS con las llaves con las que Google firma los ID tokens
+const GoogleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// ErrInvalidGoogleToken se devuelve para un ID token que no es de Google,
+// no es para nuestra app o ya venció
+var ErrInvalidGoogleToken = errors.New("invalid Google ID token")
+
+// GoogleIdentity son los datos de la cuenta de Google que trae el ID token
+type GoogleIdentity struct {
+	Subject       string // ID estable de la cuenta ("sub"); el email puede cambiar
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// googleClaims son los claims de un ID token de Google Sign-In
+type googleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	jwt.RegisteredClaims
+}
+
+// GoogleVerifier valida ID tokens de Google Sign-In (los que el frontend
+// recibe del botón de Google) para nuestros client IDs
+type GoogleVerifier struct {
+	clientIDs []string
+	keyFunc   jwt.Keyfunc
+	leeway    time.Duration
+}
+
+// NewGoogleVerifier crea el verificador con las llaves públicas de Google
+// clientIDs son los OAuth client IDs de la app (web, mobile); el token tiene
+// que estar emitido para alguno
+func NewGoogleVerifier(clientIDs []string, leeway time.Duration) *GoogleVerifier {
+	return NewGoogleVerifierWithKeys(clientIDs, auth.NewJWKS(GoogleCertsURL, time.Hour).Keyfunc, leeway)
+}
+
+// NewGoogleVerifierWithKeys es NewGoogleVerifier con otra fuente de llaves (tests)
+func NewGoogleVerifierWithKeys(clientIDs []string, keyFunc jwt.Keyfunc, leeway time.Duration) *GoogleVerifier {
+	return &GoogleVerifier{clientIDs: clientIDs, keyFunc: keyFunc, leeway: leeway}
+}
+
+// Verify valida firma (RS256), emisor, audiencia y vencimiento del ID token
+func (v *GoogleVerifier) Verify(idToken string) (*GoogleIdentity, error) {
+	claims := &googleClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, v.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidGoogleToken, err)
+	}
+
+	// Google usa los dos emisores indistintamente
+	if claims.Issuer != "accounts.google.com" && claims.Issuer != "https://accounts.google.com" {
+		return nil, ErrInvalidGoogleToken
+	}
+	if claims.Subject == "" || !v.forUs(claims.Audience) {
+		return nil, ErrInvalidGoogleToken
+	}
+
+	return &GoogleIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+// forUs indica si el token se emitió para alguno de nuestros client IDs
+func (v *GoogleVerifier) forUs(audience jwt.ClaimStrings) bool {
+	for _, aud := range audience {
+		for _, id := range v.clientIDs {
+			if aud == id {
+				return true
+			}
+		}
+	}
+	return false
+}
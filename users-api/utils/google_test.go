@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Test: se aceptan solo tokens RS256 de Google para nuestros client IDs
+func TestGoogleVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewGoogleVerifierWithKeys([]string{"web-client", "ios-client"}, func(*jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, 0)
+
+	sign := func(issuer, audience string, expiresIn time.Duration) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, &googleClaims{
+			Email:         "ana@gmail.com",
+			EmailVerified: true,
+			GivenName:     "Ana",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    issuer,
+				Subject:   "1234567890",
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			},
+		}).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	identity, err := verifier.Verify(sign("https://accounts.google.com", "ios-client", time.Hour))
+	if err != nil {
+		t.Fatalf("Expected a valid token, got %v", err)
+	}
+	if identity.Subject != "1234567890" || identity.Email != "ana@gmail.com" || !identity.EmailVerified || identity.FirstName != "Ana" {
+		t.Errorf("Unexpected identity: %+v", identity)
+	}
+
+	for name, token := range map[string]string{
+		"other audience": sign("accounts.google.com", "someone-else", time.Hour),
+		"other issuer":   sign("https://evil.example.com", "web-client", time.Hour),
+		"expired":        sign("accounts.google.com", "web-client", -time.Hour),
+		"garbage":        "not-a-token",
+	} {
+		if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidGoogleToken) {
+			t.Errorf("%s: expected ErrInvalidGoogleToken, got %v", name, err)
+		}
+	}
+}
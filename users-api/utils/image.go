@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// Límites de los avatares
+const (
+	MaxAvatarBytes  = 5 << 20 // 5 MB
+	MaxAvatarSide   = 4096    // píxeles por lado
+	AvatarThumbSize = 256     // la miniatura es cuadrada
+)
+
+var (
+	// ErrAvatarType es el error de un archivo que no es JPEG ni PNG
+	ErrAvatarType = errors.New("avatar must be a JPEG or PNG image")
+	// ErrAvatarDimensions es el error de una imagen más grande que MaxAvatarSide
+	ErrAvatarDimensions = errors.New("avatar must be at most 4096x4096 pixels")
+)
+
+// Avatar es una imagen de perfil ya validada y procesada
+type Avatar struct {
+	Image       []byte
+	Thumbnail   []byte
+	ContentType string // "image/jpeg" o "image/png" (el mismo en las dos)
+	Ext         string // "jpg" o "png"
+}
+
+// ProcessAvatar valida la imagen y genera la miniatura
+// El tipo sale del contenido (no del nombre ni del header del cliente) y las
+// dimensiones se leen antes de decodificar, así una imagen enorme no llega a
+// ocupar memoria. La original se vuelve a codificar: se descartan los
+// metadatos (ej: EXIF con la ubicación de la foto) y lo que venga pegado al archivo
+func ProcessAvatar(data []byte) (*Avatar, error) {
+	avatar := &Avatar{ContentType: http.DetectContentType(data)}
+	switch avatar.ContentType {
+	case "image/jpeg":
+		avatar.Ext = "jpg"
+	case "image/png":
+		avatar.Ext = "png"
+	default:
+		return nil, ErrAvatarType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrAvatarType
+	}
+	if cfg.Width > MaxAvatarSide || cfg.Height > MaxAvatarSide {
+		return nil, ErrAvatarDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrAvatarType
+	}
+
+	if avatar.Image, err = encodeImage(img, avatar.ContentType); err != nil {
+		return nil, err
+	}
+	if avatar.Thumbnail, err = encodeImage(thumbnail(img, AvatarThumbSize), avatar.ContentType); err != nil {
+		return nil, err
+	}
+	return avatar, nil
+}
+
+// encodeImage codifica en el formato original (PNG conserva la transparencia)
+func encodeImage(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	}
+	return buf.Bytes(), err
+}
+
+// thumbnail recorta el cuadrado central y lo achica a size×size promediando
+// los píxeles de cada bloque (una imagen más chica que size no se agranda)
+func thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	size = min(size, side)
+
+	dst := image.NewRGBA64(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0, sy1 := y0+y*side/size, y0+(y+1)*side/size
+		for x := 0; x < size; x++ {
+			sx0, sx1 := x0+x*side/size, x0+(x+1)*side/size
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodedImage(t *testing.T, width, height int, format string) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Test: la miniatura es el cuadrado central achicado, en el formato original
+func TestProcessAvatar(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		thumbSide   int
+	}{
+		{"png grande", encodedImage(t, 600, 400, "png"), "image/png", AvatarThumbSize},
+		{"jpeg chico", encodedImage(t, 100, 50, "jpeg"), "image/jpeg", 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avatar, err := ProcessAvatar(tt.data)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if avatar.ContentType != tt.contentType {
+				t.Errorf("Expected %s, got %s", tt.contentType, avatar.ContentType)
+			}
+			thumb, _, err := image.DecodeConfig(bytes.NewReader(avatar.Thumbnail))
+			if err != nil || thumb.Width != tt.thumbSide || thumb.Height != tt.thumbSide {
+				t.Errorf("Expected a %dx%d thumbnail, got %dx%d (%v)", tt.thumbSide, tt.thumbSide, thumb.Width, thumb.Height, err)
+			}
+		})
+	}
+}
+
+// Test: solo imágenes JPEG o PNG de hasta MaxAvatarSide por lado
+func TestProcessAvatar_Invalid(t *testing.T) {
+	if _, err := ProcessAvatar([]byte("<svg onload=alert(1)>")); !errors.Is(err, ErrAvatarType) {
+		t.Errorf("Expected ErrAvatarType, got %v", err)
+	}
+	if _, err := ProcessAvatar(encodedImage(t, MaxAvatarSide+1, 1, "png")); !errors.Is(err, ErrAvatarDimensions) {
+		t.Errorf("Expected ErrAvatarDimensions, got %v", err)
+	}
+}
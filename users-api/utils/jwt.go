@@ -1,75 +1,173 @@
 package utils
 
 import (
-	"errors"
-	"os"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
 	"time"
 
+	"shared/auth"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// DefaultJWTSecret es el secret por defecto (solo para desarrollo)
+const DefaultJWTSecret = "default-secret-change-in-production"
+
+// DefaultTokenTTL es cuánto dura el token de un login si no se configura
+const DefaultTokenTTL = 24 * time.Hour
+
 // Esta es la "llave secreta" para firmar los tokens
-// En producción debe estar en variables de entorno
-var jwtSecret = []byte(getJWTSecret())
+// main la reemplaza con JWT_SECRET llamando a ConfigureJWT
+// y puede rotar sin reiniciar (ver SetJWTSecret)
+var jwtSecret = auth.NewSecret(DefaultJWTSecret)
+
+// jwtConfig es la configuración con la que se emiten los tokens
+var jwtConfig = JWTConfig{
+	ClockSkew: 30 * time.Second,
+	Issuer:    auth.DefaultIssuer,
+	Audience:  auth.DefaultAudience,
+	TTL:       DefaultTokenTTL,
+}
+
+// validator es el validador compartido (shared/auth) que usan todos los servicios
+// Solo acepta HS256, exige iss y aud, y tolera JWT_CLOCK_SKEW_SECONDS de
+// diferencia de reloj
+var validator = newValidator()
 
 // Claims es la estructura de los datos que guardamos EN el token
-// Cuando el usuario hace login, le damos un token con esta info
-type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	UserType string `json:"user_type"`
-	jwt.RegisteredClaims
+// Se define en shared/auth para que todos los servicios lean lo mismo
+type Claims = auth.Claims
+
+// JWTConfig son los parámetros de los tokens (JWT_*)
+// La duración es por tipo de token: TTL es la del login; la de
+// impersonación la decide ImpersonationService (IMPERSONATION_TTL)
+type JWTConfig struct {
+	Secret    string
+	ClockSkew time.Duration
+	Issuer    string        // claim iss que se firma y se exige
+	Audience  string        // claim aud que se firma y se exige
+	TTL       time.Duration // cuánto dura el token de un login; 0 = DefaultTokenTTL
 }
 
-// getJWTSecret obtiene el secret desde variables de entorno
-// Si no existe, usa uno por defecto (solo para desarrollo)
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "default-secret-change-in-production"
+// ConfigureJWT define el secret, el emisor, la audiencia, la duración de los
+// tokens y la tolerancia de reloj
+// Se llama una vez al arrancar, con los valores ya leídos de la configuración
+func ConfigureJWT(cfg JWTConfig) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTokenTTL
 	}
-	return secret
+	jwtConfig = cfg
+	jwtSecret = auth.NewSecret(cfg.Secret).WithRotationGrace(cfg.TTL + cfg.ClockSkew)
+	validator = newValidator()
 }
 
-// GenerateToken genera un nuevo JWT token para un usuario
-// Se llama después del login exitoso
-func GenerateToken(userID uint, username, userType string) (string, error) {
-	// El token expira en 24 horas
-	expirationTime := time.Now().Add(24 * time.Hour)
+// newValidator arma el validador con la configuración actual
+func newValidator() *auth.Validator {
+	return auth.NewSecretValidator(jwtSecret, jwtConfig.ClockSkew).WithIssuer(jwtConfig.Issuer, jwtConfig.Audience)
+}
+
+// TokenTTL es cuánto dura el token de un login (el más largo que emitimos)
+func TokenTTL() time.Duration {
+	return jwtConfig.TTL
+}
+
+// SetJWTSecret rota el secret con el servicio corriendo (ej: desde Vault)
+// Los tokens nuevos se firman con el nuevo; los firmados con el anterior
+// siguen valiendo hasta vencer (TTL + tolerancia de reloj desde la rotación)
+// Un secret de menos de 32 caracteres se rechaza y sigue el actual
+func SetJWTSecret(secret string) {
+	if err := jwtSecret.Set(secret); err != nil {
+		log.Printf("❌ JWT_SECRET rotado rechazado: %v", err)
+	}
+}
+
+// TokenUser son los datos del usuario que van en el token
+type TokenUser struct {
+	ID       uint
+	Username string
+	UserType string
+	Locale   string
+	Timezone string
+}
+
+// claims arma los claims de un token del usuario emitido en now que vence en expiresAt
+// El jti (ID) identifica al token para poder revocarlo (logout)
+func (u TokenUser) claims(now, expiresAt time.Time) (*Claims, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
 
-	// Creamos los "claims" (datos que va a tener el token)
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		UserType: userType,
+		UserID:   u.ID,
+		Username: u.Username,
+		UserType: u.UserType,
+		Locale:   u.Locale,
+		Timezone: u.Timezone,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        id,
+			Issuer:    jwtConfig.Issuer,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-
-	// Creamos el token y lo firmamos con nuestro secret
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	if jwtConfig.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{jwtConfig.Audience}
+	}
+	return claims, nil
 }
 
-// ValidateToken valida un JWT token y retorna los claims
-// Se usa en el middleware para verificar que el usuario esté autenticado
-func ValidateToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
+// GenerateToken genera un nuevo JWT token para un usuario
+// Se llama después del login exitoso; dura TokenTTL (JWT_EXPIRATION)
+func GenerateToken(user TokenUser) (string, error) {
+	now := time.Now()
+	claims, err := user.claims(now, now.Add(jwtConfig.TTL))
+	if err != nil {
+		return "", err
+	}
+	return signToken(claims)
+}
 
-	// Parseamos el token y verificamos la firma
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+// GenerateImpersonationToken genera un token para que un admin actúe como
+// otro usuario: tiene los datos del usuario, el admin en impersonator_id y
+// dura solo ttl. Devuelve también el vencimiento para mostrarlo
+func GenerateImpersonationToken(user TokenUser, impersonatorID uint, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expirationTime := now.Add(ttl)
 
+	claims, err := user.claims(now, expirationTime)
 	if err != nil {
-		return nil, err
+		return "", time.Time{}, err
 	}
+	claims.ImpersonatorID = &impersonatorID
+
+	token, err := signToken(claims)
+	return token, expirationTime, err
+}
 
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+// newTokenID genera un ID aleatorio para el claim jti (128 bits en hex)
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(b), nil
+}
 
-	return claims, nil
+// signToken firma los claims con nuestro secret
+func signToken(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret.Bytes())
+}
+
+// Validator devuelve el validador configurado (lo usan los middlewares de auth)
+func Validator() *auth.Validator {
+	return validator
+}
+
+// ValidateToken valida un JWT token y retorna los claims
+// Se usa en el middleware para verificar que el usuario esté autenticado
+func ValidateToken(tokenString string) (*Claims, error) {
+	return validator.Validate(tokenString)
 }
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+	_ "time/tzdata" // base de zonas IANA embebida: la imagen de Docker no la trae
+
+	"golang.org/x/text/language"
+)
+
+// Errores de un locale o una zona horaria que no existen
+var (
+	ErrInvalidLocale   = errors.New("locale must be a BCP 47 language tag, e.g. es-AR")
+	ErrInvalidTimezone = errors.New("timezone must be an IANA time zone, e.g. America/Argentina/Cordoba")
+)
+
+// NormalizeLocale valida un tag BCP 47 contra el registro de IANA y lo
+// devuelve en su forma canónica
+// Ejemplo: "es_ar" -> "es-AR"; "xx" o "español" dan ErrInvalidLocale
+func NormalizeLocale(raw string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(raw))
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// NormalizeTimezone valida una zona de la base de IANA
+// Ejemplo: "America/Argentina/Cordoba"; "UTC" vale, "Local" y "-03:00" no
+func NormalizeTimezone(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" || name == "Local" {
+		return "", ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", ErrInvalidTimezone
+	}
+	return name, nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import "testing"
+
+// Test: tags BCP 47 aceptados (en forma canónica) y rechazados
+func TestNormalizeLocale(t *testing.T) {
+	valid := map[string]string{
+		"es":      "es",
+		"es-AR":   "es-AR",
+		"es_ar":   "es-AR",
+		" en-US ": "en-US",
+		"pt-BR":   "pt-BR",
+	}
+	for raw, want := range valid {
+		got, err := NormalizeLocale(raw)
+		if err != nil || got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "und", "xx", "español", "es-ZZZZ1", "en--US"} {
+		if got, err := NormalizeLocale(raw); err == nil {
+			t.Errorf("Expected %q to be rejected, got %q", raw, got)
+		}
+	}
+}
+
+// Test: solo zonas de la base de IANA
+func TestNormalizeTimezone(t *testing.T) {
+	for _, raw := range []string{"America/Argentina/Cordoba", "Europe/Madrid", "UTC"} {
+		if got, err := NormalizeTimezone(raw); err != nil || got != raw {
+			t.Errorf("NormalizeTimezone(%q) = %q, %v", raw, got, err)
+		}
+	}
+	for _, raw := range []string{"", "Local", "-03:00", "America/Cordoba_Nueva", "../etc/passwd"} {
+		if got, err := NormalizeTimezone(raw); err == nil {
+			t.Errorf("Expected %q to be rejected, got %q", raw, got)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"net"
+	"strings"
+)
+
+// IPPrefix reduce una IP a su red: /24 para IPv4 y /48 para IPv6
+// Dos logins desde la misma casa u oficina suelen compartir el prefijo
+// Ejemplo: "181.46.12.200" -> "181.46.12.0/24"
+func IPPrefix(ip string) string {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return ""
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		mask := net.CIDRMask(24, 32)
+		return (&net.IPNet{IP: v4.Mask(mask), Mask: mask}).String()
+	}
+
+	mask := net.CIDRMask(48, 128)
+	return (&net.IPNet{IP: parsed.Mask(mask), Mask: mask}).String()
+}
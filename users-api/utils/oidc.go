@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"shared/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidOIDCToken se devuelve para un ID token que no es del proveedor,
+// no es para nuestra app o ya venció
+var ErrInvalidOIDCToken = errors.New("invalid OIDC ID token")
+
+// discoveryRetry limita cuán seguido se reintenta el discovery si falló
+// (un proveedor caído no genera una request por cada login)
+const discoveryRetry = 30 * time.Second
+
+// OIDCProviderConfig es un proveedor OpenID Connect (Keycloak, Auth0, Azure AD...)
+// Issuer es la URL base: el discovery se lee de Issuer + /.well-known/openid-configuration
+type OIDCProviderConfig struct {
+	Name         string // como aparece en la ruta y en users.provider (ej: "keycloak")
+	DisplayName  string // para el botón del frontend (ej: "Keycloak")
+	Issuer       string
+	ClientID     string
+	ClientSecret string // para canjear el code (el único login OIDC que se acepta)
+
+	// TrustEmail toma el email como verificado aunque el token no traiga
+	// email_verified (IdPs corporativos donde los emails los carga un admin)
+	TrustEmail bool
+}
+
+// OIDCDiscovery son los campos del documento de discovery que se usan
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// ParseOIDCDiscovery lee un documento de discovery y comprueba que sea del
+// issuer esperado (la spec pide que coincida exacto) y traiga lo necesario
+func ParseOIDCDiscovery(issuer string, r io.Reader) (*OIDCDiscovery, error) {
+	var doc OIDCDiscovery
+	if err := json.NewDecoder(io.LimitReader(r, 1<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid discovery document: %w", err)
+	}
+	if doc.Issuer != issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", doc.Issuer, issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document without authorization_endpoint or jwks_uri")
+	}
+	return &doc, nil
+}
+
+// OIDCIdentity son los datos de la cuenta que trae el ID token
+type OIDCIdentity struct {
+	Subject       string // ID estable de la cuenta en el proveedor ("sub")
+	Email         string
+	EmailVerified bool // false si el proveedor no manda email_verified (ej: Azure AD), salvo con TrustEmail
+	FirstName     string
+	LastName      string
+}
+
+// oidcClaims son los claims estándar de un ID token
+type oidcClaims struct {
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	GivenName       string `json:"given_name"`
+	FamilyName      string `json:"family_name"`
+	AuthorizedParty string `json:"azp"`
+	Nonce           string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// OIDCProvider habla con un proveedor OpenID Connect
+// El discovery y las llaves se descargan en el primer uso, así un proveedor
+// caído no impide arrancar (solo falla el login con ese proveedor)
+type OIDCProvider struct {
+	cfg    OIDCProviderConfig
+	leeway time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *OIDCDiscovery
+	keyFunc   jwt.Keyfunc
+	failedAt  time.Time
+	lastErr   error
+}
+
+// NewOIDCProvider crea el proveedor; leeway es la tolerancia de reloj del token
+func NewOIDCProvider(cfg OIDCProviderConfig, leeway time.Duration) *OIDCProvider {
+	return &OIDCProvider{cfg: cfg, leeway: leeway, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// NewOIDCProviderWithKeys es NewOIDCProvider con el discovery y las llaves
+// ya resueltos (tests)
+func NewOIDCProviderWithKeys(cfg OIDCProviderConfig, discovery *OIDCDiscovery, keyFunc jwt.Keyfunc, leeway time.Duration) *OIDCProvider {
+	p := NewOIDCProvider(cfg, leeway)
+	p.discovery, p.keyFunc = discovery, keyFunc
+	return p
+}
+
+// Config devuelve la configuración del proveedor
+func (p *OIDCProvider) Config() OIDCProviderConfig {
+	return p.cfg
+}
+
+// Discover devuelve el documento de discovery (descargado una sola vez)
+func (p *OIDCProvider) Discover(ctx context.Context) (*OIDCDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	if p.lastErr != nil && time.Since(p.failedAt) < discoveryRetry {
+		return nil, p.lastErr
+	}
+
+	doc, err := p.fetchDiscovery(ctx)
+	if err != nil {
+		p.failedAt, p.lastErr = time.Now(), err
+		return nil, err
+	}
+	p.discovery = doc
+	p.keyFunc = auth.NewJWKS(doc.JWKSURI, time.Hour).Keyfunc
+	return doc, nil
+}
+
+// fetchDiscovery pide el documento de discovery al proveedor
+func (p *OIDCProvider) fetchDiscovery(ctx context.Context) (*OIDCDiscovery, error) {
+	endpoint := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching OIDC discovery of %s: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching OIDC discovery of %s: status %d", p.cfg.Name, resp.StatusCode)
+	}
+	return ParseOIDCDiscovery(p.cfg.Issuer, resp.Body)
+}
+
+// Exchange canjea el authorization code por el ID token en el token endpoint
+// (client_secret_basic). codeVerifier es el de PKCE; "" si no se usó
+func (p *OIDCProvider) Exchange(ctx context.Context, code, redirectURI, codeVerifier string) (string, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	if doc.TokenEndpoint == "" || p.cfg.ClientSecret == "" {
+		return "", fmt.Errorf("provider %s does not support the code flow", p.cfg.Name)
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	if codeVerifier != "" {
+		form.Set("code_verifier", codeVerifier)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchanging code with %s: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("exchanging code with %s: status %d: %w", p.cfg.Name, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.IDToken == "" {
+		return "", fmt.Errorf("exchanging code with %s: status %d: %s %s", p.cfg.Name, resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	return body.IDToken, nil
+}
+
+// Verify valida firma (RS256 con las llaves del jwks_uri), emisor, audiencia,
+// vencimiento y, si se pasa, el nonce del ID token
+// Solo se usa con el ID token que devuelve Exchange: uno que manda el cliente
+// no prueba nada sin un nonce atado a la sesión
+func (p *OIDCProvider) Verify(ctx context.Context, idToken, nonce string) (*OIDCIdentity, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &oidcClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, p.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithLeeway(p.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidOIDCToken, err)
+	}
+
+	// Con varias audiencias el token tiene que haberse pedido para nosotros
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, ErrInvalidOIDCToken
+	}
+	if claims.Subject == "" || (nonce != "" && claims.Nonce != nonce) {
+		return nil, ErrInvalidOIDCToken
+	}
+
+	return &OIDCIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified || p.cfg.TrustEmail,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
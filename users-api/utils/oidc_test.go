@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Test: el discovery tiene que ser del issuer configurado y traer los endpoints
+func TestParseOIDCDiscovery(t *testing.T) {
+	issuer := "https://sso.example.com/realms/spotly"
+	doc, err := ParseOIDCDiscovery(issuer, strings.NewReader(`{
+		"issuer": "https://sso.example.com/realms/spotly",
+		"authorization_endpoint": "https://sso.example.com/realms/spotly/protocol/openid-connect/auth",
+		"token_endpoint": "https://sso.example.com/realms/spotly/protocol/openid-connect/token",
+		"jwks_uri": "https://sso.example.com/realms/spotly/protocol/openid-connect/certs",
+		"response_types_supported": ["code"]
+	}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if doc.TokenEndpoint == "" || !strings.HasSuffix(doc.JWKSURI, "/certs") {
+		t.Errorf("Unexpected discovery: %+v", doc)
+	}
+
+	for name, body := range map[string]string{
+		"other issuer": `{"issuer": "https://evil.example.com", "authorization_endpoint": "x", "jwks_uri": "y"}`,
+		"without jwks": `{"issuer": "https://sso.example.com/realms/spotly", "authorization_endpoint": "x"}`,
+		"not json":     `<html>`,
+	} {
+		if _, err := ParseOIDCDiscovery(issuer, strings.NewReader(body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// Test: se aceptan solo tokens RS256 del issuer para nuestro client ID (y nonce)
+func TestOIDCProvider_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := "https://sso.example.com/realms/spotly"
+	provider := NewOIDCProviderWithKeys(OIDCProviderConfig{Name: "keycloak", Issuer: issuer, ClientID: "spotly"},
+		&OIDCDiscovery{Issuer: issuer}, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil }, 0)
+
+	sign := func(iss string, audience []string, azp string, expiresIn time.Duration) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, &oidcClaims{
+			Email:           "ana@example.com",
+			EmailVerified:   true,
+			GivenName:       "Ana",
+			AuthorizedParty: azp,
+			Nonce:           "n-1",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    iss,
+				Subject:   "f3a1",
+				Audience:  audience,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			},
+		}).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	identity, err := provider.Verify(context.Background(), sign(issuer, []string{"spotly"}, "", time.Hour), "n-1")
+	if err != nil {
+		t.Fatalf("Expected a valid token, got %v", err)
+	}
+	if identity.Subject != "f3a1" || identity.Email != "ana@example.com" || !identity.EmailVerified || identity.FirstName != "Ana" {
+		t.Errorf("Unexpected identity: %+v", identity)
+	}
+	if _, err := provider.Verify(context.Background(), sign(issuer, []string{"spotly", "account"}, "spotly", time.Hour), ""); err != nil {
+		t.Errorf("Expected a token with several audiences and our azp to be valid, got %v", err)
+	}
+
+	for name, tc := range map[string]struct{ token, nonce string }{
+		"other audience": {sign(issuer, []string{"someone-else"}, "", time.Hour), ""},
+		"other azp":      {sign(issuer, []string{"spotly", "account"}, "account", time.Hour), ""},
+		"other issuer":   {sign("https://evil.example.com", []string{"spotly"}, "", time.Hour), ""},
+		"expired":        {sign(issuer, []string{"spotly"}, "", -time.Hour), ""},
+		"other nonce":    {sign(issuer, []string{"spotly"}, "", time.Hour), "n-2"},
+		"garbage":        {"not-a-token", ""},
+	} {
+		if _, err := provider.Verify(context.Background(), tc.token, tc.nonce); !errors.Is(err, ErrInvalidOIDCToken) {
+			t.Errorf("%s: expected ErrInvalidOIDCToken, got %v", name, err)
+		}
+	}
+}
+
+// Test: el discovery se descarga del issuer y el code se canjea con el client secret
+func TestOIDCProvider_DiscoverAndExchange(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 server.URL,
+				"authorization_endpoint": server.URL + "/authorize",
+				"token_endpoint":         server.URL + "/token",
+				"jwks_uri":               server.URL + "/jwks",
+			})
+		case "/token":
+			id, secret, _ := r.BasicAuth()
+			if id != "spotly" || secret != "s3cret" || r.FormValue("code") != "c-1" || r.FormValue("code_verifier") != "v-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"id_token": "the-id-token", "access_token": "x"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewOIDCProvider(OIDCProviderConfig{Name: "keycloak", Issuer: server.URL, ClientID: "spotly", ClientSecret: "s3cret"}, 0)
+	doc, err := provider.Discover(context.Background())
+	if err != nil || doc.AuthorizationEndpoint != server.URL+"/authorize" {
+		t.Fatalf("Unexpected discovery: %+v, %v", doc, err)
+	}
+
+	idToken, err := provider.Exchange(context.Background(), "c-1", "https://spotly.com/login/oidc", "v-1")
+	if err != nil || idToken != "the-id-token" {
+		t.Errorf("Expected the ID token, got %q, %v", idToken, err)
+	}
+	if _, err := provider.Exchange(context.Background(), "used-code", "https://spotly.com/login/oidc", "v-1"); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Expected the provider error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone es el error de un teléfono que no se puede llevar a E.164
+var ErrInvalidPhone = errors.New("phone must be in international format, e.g. +5493511234567")
+
+// NormalizePhone lleva un teléfono al formato E.164 ("+" y de 8 a 15 dígitos)
+// Acepta espacios, guiones, puntos y paréntesis, y el prefijo internacional
+// "00" en lugar de "+". Sin código de país no se puede normalizar: no hay un
+// país por defecto
+// Ejemplo: "+54 9 351 123-4567" -> "+5493511234567"
+func NormalizePhone(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case strings.HasPrefix(raw, "+"):
+		raw = raw[1:]
+	case strings.HasPrefix(raw, "00"):
+		raw = raw[2:]
+	default:
+		return "", ErrInvalidPhone
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	// E.164: hasta 15 dígitos y el código de país no empieza con 0
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	return "+" + number, nil
+}
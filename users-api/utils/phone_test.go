@@ -0,0 +1,25 @@
+package utils
+
+import "testing"
+
+// Test: formatos aceptados y rechazados
+func TestNormalizePhone(t *testing.T) {
+	valid := map[string]string{
+		"+5493511234567":       "+5493511234567",
+		"+54 9 351 123-4567":   "+5493511234567",
+		"0054 (351) 4.123.456": "+543514123456",
+		" +1 415 555 2671 ":    "+14155552671",
+	}
+	for raw, want := range valid {
+		got, err := NormalizePhone(raw)
+		if err != nil || got != want {
+			t.Errorf("NormalizePhone(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "3511234567", "+0123456789", "+54 351 abc", "+1234567", "+1234567890123456"} {
+		if got, err := NormalizePhone(raw); err == nil {
+			t.Errorf("Expected %q to be rejected, got %q", raw, got)
+		}
+	}
+}